@@ -7,137 +7,2556 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/runtime"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
 )
 
+// Defines values for BatchMode.
+const (
+	Atomic     BatchMode = "atomic"
+	BestEffort BatchMode = "bestEffort"
+)
+
+// Defines values for BatchOperationType.
+const (
+	Create BatchOperationType = "create"
+	Delete BatchOperationType = "delete"
+	Update BatchOperationType = "update"
+)
+
+// Defines values for DeliveryStatus.
+const (
+	DeliveryStatusDead      DeliveryStatus = "dead"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusPending   DeliveryStatus = "pending"
+)
+
+// Defines values for EventType.
+const (
+	ItemCreated  EventType = "ItemCreated"
+	ItemDeleted  EventType = "ItemDeleted"
+	ItemRestored EventType = "ItemRestored"
+	ItemRevised  EventType = "ItemRevised"
+	ItemUpdated  EventType = "ItemUpdated"
+)
+
+// Defines values for ExportFormat.
+const (
+	ExportFormatCsv         ExportFormat = "csv"
+	ExportFormatMerchantXml ExportFormat = "merchant-xml"
+	ExportFormatNdjson      ExportFormat = "ndjson"
+)
+
+// Defines values for HistoryOperation.
+const (
+	Created  HistoryOperation = "created"
+	Deleted  HistoryOperation = "deleted"
+	Restored HistoryOperation = "restored"
+	Revised  HistoryOperation = "revised"
+	Updated  HistoryOperation = "updated"
+)
+
+// Defines values for ImageSize.
+const (
+	Large    ImageSize = "large"
+	Medium   ImageSize = "medium"
+	Original ImageSize = "original"
+	Small    ImageSize = "small"
+)
+
+// Defines values for ImportFormat.
+const (
+	ImportFormatCsv    ImportFormat = "csv"
+	ImportFormatNdjson ImportFormat = "ndjson"
+)
+
+// Defines values for ImportStatus.
+const (
+	Completed ImportStatus = "completed"
+	Failed    ImportStatus = "failed"
+	Queued    ImportStatus = "queued"
+	Running   ImportStatus = "running"
+)
+
+// Defines values for ItemSort.
+const (
+	ItemSortId             ItemSort = "id"
+	ItemSortMinusId        ItemSort = "-id"
+	ItemSortMinusName      ItemSort = "-name"
+	ItemSortMinusPrice     ItemSort = "-price"
+	ItemSortMinusPriceCode ItemSort = "-priceCode"
+	ItemSortName           ItemSort = "name"
+	ItemSortPrice          ItemSort = "price"
+	ItemSortPriceCode      ItemSort = "priceCode"
+)
+
+// Defines values for PriceSource.
+const (
+	Base       PriceSource = "base"
+	Conversion PriceSource = "conversion"
+	PriceList  PriceSource = "priceList"
+	Scheduled  PriceSource = "scheduled"
+)
+
+// Defines values for ReservationStatus.
+const (
+	ReservationStatusCommitted ReservationStatus = "committed"
+	ReservationStatusExpired   ReservationStatus = "expired"
+	ReservationStatusPending   ReservationStatus = "pending"
+	ReservationStatusReleased  ReservationStatus = "released"
+)
+
+// BatchMode defines model for BatchMode.
+type BatchMode string
+
+// BatchOperation defines model for BatchOperation.
+type BatchOperation struct {
+	// ID of the updated or deleted item
+	Id *uint `json:"id,omitempty"`
+
+	// Updated or deleted item has to match the entity tags, as with If-Match header
+	IfMatch *string            `json:"ifMatch,omitempty"`
+	Item    *UpdateItemRequest `json:"item,omitempty"`
+
+	// Create requires all fields of the item, update requires ID and some of them, delete only ID
+	Op BatchOperationType `json:"op"`
+}
+
+// Create requires all fields of the item, update requires ID and some of them, delete only ID
+type BatchOperationType string
+
+// BatchRequest defines model for BatchRequest.
+type BatchRequest struct {
+	// Operations applied in order. Maximal number of operations can be lowered in service config.
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchResponse defines model for BatchResponse.
+type BatchResponse struct {
+	// Number of operations which were not applied
+	Failed int `json:"failed"`
+
+	// Results of operations in order of the request
+	Results []BatchResult `json:"results"`
+
+	// Number of applied operations
+	Succeeded int `json:"succeeded"`
+}
+
+// BatchResult defines model for BatchResult.
+type BatchResult struct {
+	Error *ErrorResponse `json:"error,omitempty"`
+
+	// Position of the operation in the request
+	Index int           `json:"index"`
+	Item  *ItemResponse `json:"item,omitempty"`
+
+	// HTTP status of the operation
+	Status int `json:"status"`
+}
+
+// CategoryRef defines model for CategoryRef.
+type CategoryRef struct {
+	// Unique ID of the category
+	Id uint `json:"id"`
+
+	// Name of the category
+	Name string `json:"name"`
+}
+
+// CategoryResponse defines model for CategoryResponse.
+type CategoryResponse struct {
+	// Unique ID of the category
+	Id uint `json:"id"`
+
+	// Name of the category
+	Name string `json:"name"`
+
+	// ID of the parent category, not set for root categories
+	ParentId *uint `json:"parentId,omitempty"`
+
+	// Path from the root category to this one
+	Path []CategoryRef `json:"path"`
+}
+
+// CategoryTreeNode defines model for CategoryTreeNode.
+type CategoryTreeNode struct {
+	// Subcategories ordered by name
+	Children []CategoryTreeNode `json:"children"`
+
+	// Unique ID of the category
+	Id uint `json:"id"`
+
+	// Name of the category
+	Name string `json:"name"`
+}
+
+// Price of the item in currency requested with currency parameter. Not returned when the item has no price in that currency and there is no exchange rate to convert it.
+type CurrencyPrice struct {
+	// Regular price in the currency, set only while lower scheduled price is in effect
+	CompareAtPrice *string `json:"compareAtPrice,omitempty"`
+
+	// Rate the item price was multiplied by, set only for converted prices
+	ExchangeRate *string `json:"exchangeRate,omitempty"`
+
+	// Price as a decimal number in major units of the currency
+	Price string `json:"price"`
+
+	// ISO 4217 code of the price currency
+	PriceCode string `json:"priceCode"`
+
+	// Where exchange rates come from, set only for converted prices
+	RateSource *string `json:"rateSource,omitempty"`
+
+	// Time exchange rates were published, set only for converted prices
+	RatesUpdatedAt *time.Time `json:"ratesUpdatedAt,omitempty"`
+
+	// Where the price comes from - base price of the item, its price list, price scheduled in the currency or conversion of the base price with exchange rates. Converted prices are rounded half away from zero to minor units of the currency.
+	Source PriceSource `json:"source"`
+}
+
+// CurrencyResponse defines model for CurrencyResponse.
+type CurrencyResponse struct {
+	// ISO 4217 alphabetic code
+	Code string `json:"code"`
+
+	// Number of digits after the decimal separator used in prices
+	Exponent int `json:"exponent"`
+
+	// Name of the currency
+	Name string `json:"name"`
+
+	// ISO 4217 numeric code
+	NumericCode string `json:"numericCode"`
+}
+
+// DeliveryResponse defines model for DeliveryResponse.
+type DeliveryResponse struct {
+	// Number of attempts made so far
+	Attempts    int        `json:"attempts"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty"`
+
+	// ID of the delivered event
+	EventId   uint64    `json:"eventId"`
+	EventType EventType `json:"eventType"`
+
+	// Unique ID of the delivery, sent in X-Catalog-Delivery header
+	Id            uint       `json:"id"`
+	LastAttemptAt *time.Time `json:"lastAttemptAt,omitempty"`
+
+	// Why the last attempt failed
+	LastError *string `json:"lastError,omitempty"`
+
+	// Response status of the last attempt, not set when no response was received
+	LastStatusCode *int `json:"lastStatusCode,omitempty"`
+
+	// Time of the next attempt, set only for pending deliveries
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+
+	// Only pending deliveries are attempted, dead ones exhausted all attempts
+	Status    DeliveryStatus `json:"status"`
+	WebhookId uint           `json:"webhookId"`
+}
+
+// Only pending deliveries are attempted, dead ones exhausted all attempts
+type DeliveryStatus string
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
+	// Name of the request field which caused the error, when it can be attributed to one
+	Field *string `json:"field,omitempty"`
+
 	// Error message
 	Message string `json:"message"`
 }
 
-// ItemResponse defines model for ItemResponse.
-type ItemResponse struct {
-	// Description of the item
-	Description *string `json:"description,omitempty"`
+// EventType defines model for EventType.
+type EventType string
+
+// ExchangeRatesRequest defines model for ExchangeRatesRequest.
+type ExchangeRatesRequest struct {
+	// ISO 4217 code of the currency rates are relative to
+	Base string `json:"base"`
+
+	// Number of units of the currency worth one unit of the base currency, keyed by ISO 4217 code
+	Rates ExchangeRatesRequest_Rates `json:"rates"`
+
+	// Time rates were published, time of the request when not set
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// Number of units of the currency worth one unit of the base currency, keyed by ISO 4217 code
+type ExchangeRatesRequest_Rates struct {
+	AdditionalProperties map[string]string `json:"-"`
+}
+
+// ExchangeRatesResponse defines model for ExchangeRatesResponse.
+type ExchangeRatesResponse struct {
+	// ISO 4217 code of the currency rates are relative to
+	Base string `json:"base"`
+
+	// Number of units of the currency worth one unit of the base currency, keyed by ISO 4217 code
+	Rates ExchangeRatesResponse_Rates `json:"rates"`
+
+	// Where rates come from, e.g. file they were loaded from or admin when set with the API
+	Source string `json:"source"`
+
+	// Time rates were published
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Number of units of the currency worth one unit of the base currency, keyed by ISO 4217 code
+type ExchangeRatesResponse_Rates struct {
+	AdditionalProperties map[string]string `json:"-"`
+}
+
+// ExportFormat defines model for ExportFormat.
+type ExportFormat string
+
+// FieldChange defines model for FieldChange.
+type FieldChange struct {
+	// Name of the changed field, as in ItemResponse
+	Field string `json:"field"`
+
+	// Value after the change, not set when the field has no value
+	New *string `json:"new,omitempty"`
+
+	// Value before the change, not set when the field had no value
+	Old *string `json:"old,omitempty"`
+}
+
+// Kind of change. Revision is a change of part of the item kept apart from its fields - prices, scheduledPrices, variants, categories or images.
+type HistoryOperation string
+
+// ImageSize defines model for ImageSize.
+type ImageSize string
+
+// ImageThumbnails defines model for ImageThumbnails.
+type ImageThumbnails struct {
+	// URL of the thumbnail with the longest edge of 1024 pixels
+	Large string `json:"large"`
+
+	// URL of the thumbnail with the longest edge of 480 pixels
+	Medium string `json:"medium"`
+
+	// URL of the thumbnail with the longest edge of 160 pixels
+	Small string `json:"small"`
+}
+
+// ImportFormat defines model for ImportFormat.
+type ImportFormat string
+
+// ImportJobResponse defines model for ImportJobResponse.
+type ImportJobResponse struct {
+	CreatedAt    time.Time `json:"createdAt"`
+	CreatedItems int       `json:"createdItems"`
+
+	// Why the job failed, or for running job why it was interrupted last time, e.g. as store was unavailable
+	Error      *string      `json:"error,omitempty"`
+	FinishedAt *time.Time   `json:"finishedAt,omitempty"`
+	Format     ImportFormat `json:"format"`
+
+	// Unique ID of the import job
+	Id uint `json:"id"`
+
+	// Number of rows processed so far
+	ProcessedRows int          `json:"processedRows"`
+	RejectedRows  int          `json:"rejectedRows"`
+	StartedAt     *time.Time   `json:"startedAt,omitempty"`
+	Status        ImportStatus `json:"status"`
+
+	// Number of rows which matched current state of their items
+	UnchangedItems int `json:"unchangedItems"`
+	UpdatedItems   int `json:"updatedItems"`
+}
+
+// ImportStatus defines model for ImportStatus.
+type ImportStatus string
+
+// ItemCategoriesRequest defines model for ItemCategoriesRequest.
+type ItemCategoriesRequest struct {
+	// IDs of categories the item is assigned to. Replaces current assignment.
+	CategoryIds []uint `json:"categoryIds"`
+}
+
+// ItemHistoryEntry defines model for ItemHistoryEntry.
+type ItemHistoryEntry struct {
+	// Who made the change, taken from X-Actor header of the request. "system" when not provided.
+	Actor     string    `json:"actor"`
+	ChangedAt time.Time `json:"changedAt"`
+
+	// Fields changed by the operation, empty when only the item was deleted or restored. Revision reports changed part of the item, e.g. prices, as a field without values.
+	Changes []FieldChange `json:"changes"`
+
+	// Kind of change. Revision is a change of part of the item kept apart from its fields - prices, scheduledPrices, variants, categories or images.
+	Operation HistoryOperation `json:"operation"`
+
+	// Version of the item after the change
+	Version uint `json:"version"`
+}
+
+// ItemImageRequest defines model for ItemImageRequest.
+type ItemImageRequest struct {
+	// Alternative text of the image, it is removed when not set
+	AltText *string `json:"altText,omitempty"`
+
+	// Position of the image among images of the item starting from 0, the first image is the main one
+	Position int `json:"position"`
+}
+
+// ItemImageResponse defines model for ItemImageResponse.
+type ItemImageResponse struct {
+	// Alternative text of the image
+	AltText *string `json:"altText,omitempty"`
+
+	// Content type of the original image
+	ContentType string `json:"contentType"`
+
+	// Height of the original image in pixels
+	Height int `json:"height"`
+
+	// Unique ID of the image
+	Id uint `json:"id"`
+
+	// Position of the image among images of the item starting from 0, the first image is the main one
+	Position int `json:"position"`
+
+	// Size of the original image in bytes
+	Size       int64           `json:"size"`
+	Thumbnails ImageThumbnails `json:"thumbnails"`
+
+	// URL of the original image
+	Url string `json:"url"`
+
+	// Width of the original image in pixels
+	Width int `json:"width"`
+}
+
+// ItemPage defines model for ItemPage.
+type ItemPage struct {
+	Items []ItemResponse `json:"items"`
+
+	// Link to the next page. Not set on the last page.
+	Next *string `json:"next,omitempty"`
+
+	// Cursor of the next page. Not set when there are no more items to list.
+	NextCursor *string `json:"nextCursor,omitempty"`
+
+	// Number of returned page. Not set when page was selected with cursor.
+	Page *int `json:"page,omitempty"`
+
+	// Maximal number of items on the page
+	PageSize int `json:"pageSize"`
+
+	// Link to the previous page. Not set on the first page and when page was selected with cursor.
+	Prev *string `json:"prev,omitempty"`
+
+	// Number of items matching the filters
+	TotalCount int64 `json:"totalCount"`
+}
+
+// ItemPricesRequest defines model for ItemPricesRequest.
+type ItemPricesRequest struct {
+	// Prices in currencies other than the one of the item, at most one per currency
+	Prices []Price `json:"prices"`
+}
+
+// ItemResponse defines model for ItemResponse.
+type ItemResponse struct {
+	// Number of units which can be reserved, returned when items are fetched, listed, searched or exported
+	Available *int64 `json:"available,omitempty"`
+
+	// Paths from the root category to every category the item is assigned to
+	Breadcrumbs *[][]CategoryRef `json:"breadcrumbs,omitempty"`
+
+	// Regular price of the item, set only while lower scheduled price is in effect, e.g. to be shown struck through during promotion
+	CompareAtPrice *string `json:"compareAtPrice,omitempty"`
+
+	// Price of the item in currency requested with currency parameter. Not returned when the item has no price in that currency and there is no exchange rate to convert it.
+	CurrencyPrice *CurrencyPrice `json:"currencyPrice,omitempty"`
+
+	// Time when the item was deleted, set only for deleted items
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+
+	// Description of the item
+	Description *string `json:"description,omitempty"`
+
+	// Current version of the item, to be used in If-Match and If-None-Match headers
+	Etag *string `json:"etag,omitempty"`
+
+	// Unique key of the item in supplier or ERP systems
+	ExternalId *string `json:"externalId,omitempty"`
+
+	// Unique ID of the item
+	Id *uint `json:"id,omitempty"`
+
+	// Images of the item ordered by position, the first one is the main image
+	Images *[]ItemImageResponse `json:"images,omitempty"`
+
+	// Name of the item
+	Name *string `json:"name,omitempty"`
+
+	// Price of the item as a decimal number in major units of the currency, with as many fractional digits as the currency uses, e.g. "12.50" for EUR and "1500" for JPY
+	Price *string `json:"price,omitempty"`
+
+	// ISO 4217 code of the price currency
+	PriceCode *string `json:"priceCode,omitempty"`
+
+	// Explicit prices in other currencies, returned only when fetching single item
+	Prices *[]Price `json:"prices,omitempty"`
+
+	// Variants of the item, returned only when fetching single item
+	Variants *[]VariantResponse `json:"variants,omitempty"`
+}
+
+// Field items are sorted by. Prefixed with '-' for descending order.
+type ItemSort string
+
+// NewCategoryRequest defines model for NewCategoryRequest.
+type NewCategoryRequest struct {
+	// Name of the category
+	Name string `json:"name"`
+
+	// ID of the parent category. Category is created in the root when not set.
+	ParentId *uint `json:"parentId,omitempty"`
+}
+
+// NewItemRequest defines model for NewItemRequest.
+type NewItemRequest struct {
+	// Description of the item
+	Description string `json:"description"`
+
+	// Unique key of the item in supplier or ERP systems, it can't be changed once the item is created
+	ExternalId *string `json:"externalId,omitempty"`
+
+	// Name of the item
+	Name string `json:"name"`
+
+	// Price of the item as a decimal number in major units of the currency, e.g. "12.50". It cannot have more fractional digits than the currency uses, e.g. none for JPY and 3 for BHD.
+	Price string `json:"price"`
+
+	// ISO 4217 code of the price currency, case insensitive. It has to be one of accepted currencies.
+	PriceCode string `json:"priceCode"`
+}
+
+// Price defines model for Price.
+type Price struct {
+	// Price as a decimal number in major units of the currency
+	Price string `json:"price"`
+
+	// ISO 4217 code of the price currency. Case insensitive.
+	PriceCode string `json:"priceCode"`
+}
+
+// Where the price comes from - base price of the item, its price list, price scheduled in the currency or conversion of the base price with exchange rates. Converted prices are rounded half away from zero to minor units of the currency.
+type PriceSource string
+
+// ReservationRequest defines model for ReservationRequest.
+type ReservationRequest struct {
+	// ID of the item
+	ItemId uint `json:"itemId"`
+
+	// Number of reserved units
+	Quantity int64 `json:"quantity"`
+
+	// Number of seconds after which reservation expires, 15 minutes when not set
+	TtlSeconds *int `json:"ttlSeconds,omitempty"`
+
+	// ID of the variant, stock of the item as a whole is reserved when not set
+	VariantId *uint `json:"variantId,omitempty"`
+}
+
+// ReservationResponse defines model for ReservationResponse.
+type ReservationResponse struct {
+	// Time when the reservation was created
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Time after which pending reservation expires
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// Unique ID of the reservation
+	Id uint `json:"id"`
+
+	// ID of the item
+	ItemId uint `json:"itemId"`
+
+	// Number of reserved units
+	Quantity int64 `json:"quantity"`
+
+	// Only pending reservations hold stock
+	Status ReservationStatus `json:"status"`
+
+	// ID of the variant, not set for stock of the item as a whole
+	VariantId *uint `json:"variantId,omitempty"`
+}
+
+// Only pending reservations hold stock
+type ReservationStatus string
+
+// ScheduledPriceRequest defines model for ScheduledPriceRequest.
+type ScheduledPriceRequest struct {
+	// Time the price stops being in effect, the price stays in effect indefinitely when not set
+	EndsAt *time.Time `json:"endsAt,omitempty"`
+
+	// Price as a decimal number in major units of the currency
+	Price string `json:"price"`
+
+	// ISO 4217 code of the price currency. Case insensitive.
+	PriceCode string `json:"priceCode"`
+
+	// Time the price takes effect
+	StartsAt time.Time `json:"startsAt"`
+}
+
+// ScheduledPriceResponse defines model for ScheduledPriceResponse.
+type ScheduledPriceResponse struct {
+	// Whether the price is in effect now
+	Active bool `json:"active"`
+
+	// Time the price stops being in effect, not set for prices which stay in effect indefinitely
+	EndsAt *time.Time `json:"endsAt,omitempty"`
+	Id     uint       `json:"id"`
+	ItemId uint       `json:"itemId"`
+
+	// Price as a decimal number in major units of the currency
+	Price string `json:"price"`
+
+	// ISO 4217 code of the price currency
+	PriceCode string `json:"priceCode"`
+
+	// Time the price takes effect
+	StartsAt time.Time `json:"startsAt"`
+}
+
+// SearchPage defines model for SearchPage.
+type SearchPage struct {
+	Items []SearchResult `json:"items"`
+
+	// Number of returned page
+	Page int `json:"page"`
+
+	// Maximal number of items on the page
+	PageSize int `json:"pageSize"`
+
+	// Number of items matching the query
+	TotalCount int64 `json:"totalCount"`
+}
+
+// SearchResult defines model for SearchResult.
+type SearchResult struct {
+	// HTML-escaped fragment of item description with matched words wrapped in <mark> tags
+	DescriptionHighlight string       `json:"descriptionHighlight"`
+	Item                 ItemResponse `json:"item"`
+
+	// HTML-escaped item name with matched words wrapped in <mark> tags
+	NameHighlight string `json:"nameHighlight"`
+
+	// Relevance of the item, higher is more relevant
+	Rank float64 `json:"rank"`
+}
+
+// StockAdjustmentRequest defines model for StockAdjustmentRequest.
+type StockAdjustmentRequest struct {
+	// Number of units added to stock, negative to remove units
+	Delta int64 `json:"delta"`
+
+	// ID of a variant, stock of the item as a whole is adjusted when not set
+	VariantId *uint `json:"variantId,omitempty"`
+}
+
+// StockResponse defines model for StockResponse.
+type StockResponse struct {
+	// Number of units which can be reserved
+	Available int64 `json:"available"`
+
+	// ID of the item
+	ItemId uint `json:"itemId"`
+
+	// Number of units in stock
+	OnHand int64 `json:"onHand"`
+
+	// Number of units held by pending reservations
+	Reserved int64 `json:"reserved"`
+
+	// ID of the variant, not set for stock of the item as a whole
+	VariantId *uint `json:"variantId,omitempty"`
+}
+
+// UpdateCategoryRequest defines model for UpdateCategoryRequest.
+type UpdateCategoryRequest struct {
+	// Name of the category
+	Name *string `json:"name,omitempty"`
+
+	// ID of the new parent category. 0 moves category to the root.
+	ParentId *uint `json:"parentId,omitempty"`
+}
+
+// UpdateItemRequest defines model for UpdateItemRequest.
+type UpdateItemRequest struct {
+	// Description of the item
+	Description *string `json:"description,omitempty"`
+
+	// Name of the item
+	Name *string `json:"name,omitempty"`
+
+	// Price of the item as a decimal number in major units of the currency, e.g. "12.50". It has to be updated together with priceCode.
+	Price *string `json:"price,omitempty"`
+
+	// ISO 4217 code of the price currency, case insensitive. It has to be one of accepted currencies and it has to be updated together with price.
+	PriceCode *string `json:"priceCode,omitempty"`
+}
+
+// VariantRequest defines model for VariantRequest.
+type VariantRequest struct {
+	// GTIN-8, GTIN-12 (UPC), GTIN-13 (EAN) or GTIN-14 number
+	Barcode *string `json:"barcode,omitempty"`
+
+	// Option values distinguishing the variant, e.g. size and colour
+	Options *map[string]string `json:"options,omitempty"`
+
+	// Price of the variant as a decimal number in major units of the currency, price of the item applies when not set. It has to be set together with priceCode.
+	Price *string `json:"price,omitempty"`
+
+	// ISO 4217 code of the variant price currency, case insensitive
+	PriceCode *string `json:"priceCode,omitempty"`
+
+	// Stock keeping unit code, unique in the catalog
+	Sku string `json:"sku"`
+}
+
+// VariantResponse defines model for VariantResponse.
+type VariantResponse struct {
+	// Number of units of the variant which can be reserved
+	Available *int64 `json:"available,omitempty"`
+
+	// GTIN of the variant
+	Barcode *string `json:"barcode,omitempty"`
+
+	// Unique ID of the variant
+	Id uint `json:"id"`
+
+	// Option values distinguishing the variant
+	Options map[string]string `json:"options"`
+
+	// Price of the variant as a decimal number in major units of the currency, price of the item applies when not set
+	Price *string `json:"price,omitempty"`
+
+	// ISO 4217 code of the variant price currency
+	PriceCode *string `json:"priceCode,omitempty"`
+
+	// Stock keeping unit code
+	Sku string `json:"sku"`
+}
+
+// WebhookRequest defines model for WebhookRequest.
+type WebhookRequest struct {
+	// Types of events delivered to the webhook
+	EventTypes []EventType `json:"eventTypes"`
+
+	// Secret requests are signed with. X-Catalog-Signature header holds "sha256=" followed by hex encoded HMAC-SHA256 of "<X-Catalog-Timestamp>.<body>".
+	Secret string `json:"secret"`
+
+	// Absolute http or https URL events are POSTed to. It cannot point at loopback, link-local or private addresses and redirects are not followed.
+	Url string `json:"url"`
+}
+
+// WebhookResponse defines model for WebhookResponse.
+type WebhookResponse struct {
+	CreatedAt  time.Time   `json:"createdAt"`
+	EventTypes []EventType `json:"eventTypes"`
+
+	// Unique ID of the webhook
+	Id  uint   `json:"id"`
+	Url string `json:"url"`
+}
+
+// CategoryId defines model for categoryId.
+type CategoryId = uint
+
+// Currency defines model for currency.
+type Currency = string
+
+// Cursor defines model for cursor.
+type Cursor = string
+
+// IncludeDescendants defines model for includeDescendants.
+type IncludeDescendants = bool
+
+// MaxPrice defines model for maxPrice.
+type MaxPrice = string
+
+// MinPrice defines model for minPrice.
+type MinPrice = string
+
+// NameFilter defines model for nameFilter.
+type NameFilter = string
+
+// Page defines model for page.
+type Page = int
+
+// PageSize defines model for pageSize.
+type PageSize = int
+
+// PriceCode defines model for priceCode.
+type PriceCode = string
+
+// ReservationId defines model for reservationId.
+type ReservationId = uint
+
+// VariantId defines model for variantId.
+type VariantId = uint
+
+// SetExchangeRatesJSONBody defines parameters for SetExchangeRates.
+type SetExchangeRatesJSONBody = ExchangeRatesRequest
+
+// GetDeletedItemsParams defines parameters for GetDeletedItems.
+type GetDeletedItemsParams struct {
+	// Number of elements to be returned. Default 100
+	PageSize *int `form:"pageSize,omitempty" json:"pageSize,omitempty"`
+
+	// Page number.
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+}
+
+// CreateCategoryJSONBody defines parameters for CreateCategory.
+type CreateCategoryJSONBody = NewCategoryRequest
+
+// UpdateCategoryByIDJSONBody defines parameters for UpdateCategoryByID.
+type UpdateCategoryByIDJSONBody = UpdateCategoryRequest
+
+// GetItemsParams defines parameters for GetItems.
+type GetItemsParams struct {
+	// Number of elements to be returned. Default 100
+	PageSize *PageSize `form:"pageSize,omitempty" json:"pageSize,omitempty"`
+
+	// Page number.
+	Page *Page `form:"page,omitempty" json:"page,omitempty"`
+
+	// Returns only items with price greater than or equal to provided value. Value is a decimal number in major units of the item currency, e.g. "12.50". Regular price is compared, even while a sale is in effect.
+	MinPrice *MinPrice `form:"minPrice,omitempty" json:"minPrice,omitempty"`
+
+	// Returns only items with price lower than or equal to provided value. Value is a decimal number in major units of the item currency, e.g. "12.50". Regular price is compared, even while a sale is in effect.
+	MaxPrice *MaxPrice `form:"maxPrice,omitempty" json:"maxPrice,omitempty"`
+
+	// Returns only items priced in provided currency. Case insensitive.
+	PriceCode *PriceCode `form:"priceCode,omitempty" json:"priceCode,omitempty"`
+
+	// Returns only items which name contains provided text. Case insensitive.
+	Name *NameFilter `form:"name,omitempty" json:"name,omitempty"`
+
+	// Field items are sorted by. Prefix with '-' for descending order. Default id. Prices are compared by amounts in major units of their currencies, the same way as by price filters, e.g. 9.99 USD is listed before 1000 JPY. Regular prices are sorted by, even while a sale is in effect and the item is listed with its sale price.
+	Sort *ItemSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Opaque cursor returned in X-Next-Cursor header of the previous page. Listing continues right after the last item of that page, regardless of items inserted or deleted in the meantime. Has to be used with the same filters and sort as the previous page and cannot be combined with page parameter.
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Returns only items assigned to the category.
+	CategoryId *CategoryId `form:"categoryId,omitempty" json:"categoryId,omitempty"`
+
+	// Includes items assigned to subcategories of the category selected with categoryId.
+	IncludeDescendants *IncludeDescendants `form:"includeDescendants,omitempty" json:"includeDescendants,omitempty"`
+
+	// Returns price of items also in provided currency, taken from the price list of the item or converted with current exchange rates. Case insensitive.
+	Currency *Currency `form:"currency,omitempty" json:"currency,omitempty"`
+}
+
+// CreateItemJSONBody defines parameters for CreateItem.
+type CreateItemJSONBody = NewItemRequest
+
+// FindItemByExternalIDParams defines parameters for FindItemByExternalID.
+type FindItemByExternalIDParams struct {
+	// Returns price of items also in provided currency, taken from the price list of the item or converted with current exchange rates. Case insensitive.
+	Currency *Currency `form:"currency,omitempty" json:"currency,omitempty"`
+
+	// ETag of cached item response. If it matches current one, item is not returned.
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
+// UpsertItemByExternalIDJSONBody defines parameters for UpsertItemByExternalID.
+type UpsertItemByExternalIDJSONBody = NewItemRequest
+
+// StreamItemEventsParams defines parameters for StreamItemEvents.
+type StreamItemEventsParams struct {
+	// Message ID of the last received event, stream starts right after it. Only events published within outbox retention (a week by default) are replayed.
+	LastEventID *uint64 `json:"Last-Event-ID,omitempty"`
+}
+
+// ExportItemsParams defines parameters for ExportItems.
+type ExportItemsParams struct {
+	// Format of the export
+	Format ExportFormat `form:"format" json:"format"`
+
+	// ID of the last received item, export continues after it
+	Cursor *uint `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Returns only items with price greater than or equal to provided value. Value is a decimal number in major units of the item currency, e.g. "12.50". Regular price is compared, even while a sale is in effect.
+	MinPrice *MinPrice `form:"minPrice,omitempty" json:"minPrice,omitempty"`
+
+	// Returns only items with price lower than or equal to provided value. Value is a decimal number in major units of the item currency, e.g. "12.50". Regular price is compared, even while a sale is in effect.
+	MaxPrice *MaxPrice `form:"maxPrice,omitempty" json:"maxPrice,omitempty"`
+
+	// Returns only items priced in provided currency. Case insensitive.
+	PriceCode *PriceCode `form:"priceCode,omitempty" json:"priceCode,omitempty"`
+
+	// Returns only items which name contains provided text. Case insensitive.
+	Name *NameFilter `form:"name,omitempty" json:"name,omitempty"`
+
+	// Returns only items assigned to the category.
+	CategoryId *CategoryId `form:"categoryId,omitempty" json:"categoryId,omitempty"`
+
+	// Includes items assigned to subcategories of the category selected with categoryId.
+	IncludeDescendants *IncludeDescendants `form:"includeDescendants,omitempty" json:"includeDescendants,omitempty"`
+}
+
+// SearchItemsParams defines parameters for SearchItems.
+type SearchItemsParams struct {
+	// Full-text query
+	Q string `form:"q" json:"q"`
+
+	// Number of elements to be returned. Default 100
+	PageSize *PageSize `form:"pageSize,omitempty" json:"pageSize,omitempty"`
+
+	// Page number.
+	Page *Page `form:"page,omitempty" json:"page,omitempty"`
+}
+
+// DeleteItemByIDParams defines parameters for DeleteItemByID.
+type DeleteItemByIDParams struct {
+	// ETag of the item version expected to be deleted
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// FindItemByIDParams defines parameters for FindItemByID.
+type FindItemByIDParams struct {
+	// Returns price of items also in provided currency, taken from the price list of the item or converted with current exchange rates. Case insensitive.
+	Currency *Currency `form:"currency,omitempty" json:"currency,omitempty"`
+
+	// ETag of cached item response. If it matches current one, item is not returned.
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
+// UpdateItemByIDJSONBody defines parameters for UpdateItemByID.
+type UpdateItemByIDJSONBody = UpdateItemRequest
+
+// UpdateItemByIDParams defines parameters for UpdateItemByID.
+type UpdateItemByIDParams struct {
+	// ETag of the item version expected to be updated
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// SetItemCategoriesJSONBody defines parameters for SetItemCategories.
+type SetItemCategoriesJSONBody = ItemCategoriesRequest
+
+// GetItemSnapshotParams defines parameters for GetItemSnapshot.
+type GetItemSnapshotParams struct {
+	// Point in time the item is returned as of
+	At time.Time `form:"at" json:"at"`
+}
+
+// CreateItemImageParams defines parameters for CreateItemImage.
+type CreateItemImageParams struct {
+	// Alternative text of the image
+	AltText *string `form:"altText,omitempty" json:"altText,omitempty"`
+}
+
+// UpdateItemImageByIDJSONBody defines parameters for UpdateItemImageByID.
+type UpdateItemImageByIDJSONBody = ItemImageRequest
+
+// SetItemPricesJSONBody defines parameters for SetItemPrices.
+type SetItemPricesJSONBody = ItemPricesRequest
+
+// CreateScheduledPriceJSONBody defines parameters for CreateScheduledPrice.
+type CreateScheduledPriceJSONBody = ScheduledPriceRequest
+
+// GetStockParams defines parameters for GetStock.
+type GetStockParams struct {
+	// ID of a variant, stock of the item as a whole is used when not set
+	VariantId *VariantId `form:"variantId,omitempty" json:"variantId,omitempty"`
+}
+
+// AdjustStockJSONBody defines parameters for AdjustStock.
+type AdjustStockJSONBody = StockAdjustmentRequest
+
+// CreateVariantJSONBody defines parameters for CreateVariant.
+type CreateVariantJSONBody = VariantRequest
+
+// UpdateVariantByIDJSONBody defines parameters for UpdateVariantByID.
+type UpdateVariantByIDJSONBody = VariantRequest
+
+// BatchItemsJSONBody defines parameters for BatchItems.
+type BatchItemsJSONBody = BatchRequest
+
+// BatchItemsParams defines parameters for BatchItems.
+type BatchItemsParams struct {
+	// How failures of operations are handled. Default atomic.
+	Mode *BatchMode `form:"mode,omitempty" json:"mode,omitempty"`
+}
+
+// GetUpcomingPricesParams defines parameters for GetUpcomingPrices.
+type GetUpcomingPricesParams struct {
+	// Number of elements to be returned. Default 100
+	PageSize *PageSize `form:"pageSize,omitempty" json:"pageSize,omitempty"`
+
+	// Page number.
+	Page *Page `form:"page,omitempty" json:"page,omitempty"`
+}
+
+// CreateReservationJSONBody defines parameters for CreateReservation.
+type CreateReservationJSONBody = ReservationRequest
+
+// CreateWebhookJSONBody defines parameters for CreateWebhook.
+type CreateWebhookJSONBody = WebhookRequest
+
+// GetDeadLettersParams defines parameters for GetDeadLetters.
+type GetDeadLettersParams struct {
+	// Number of elements to be returned. Default 100
+	PageSize *PageSize `form:"pageSize,omitempty" json:"pageSize,omitempty"`
+
+	// Page number.
+	Page *Page `form:"page,omitempty" json:"page,omitempty"`
+}
+
+// GetWebhookDeliveriesParams defines parameters for GetWebhookDeliveries.
+type GetWebhookDeliveriesParams struct {
+	// Returns only deliveries with provided status
+	Status *DeliveryStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Number of elements to be returned. Default 100
+	PageSize *PageSize `form:"pageSize,omitempty" json:"pageSize,omitempty"`
+
+	// Page number.
+	Page *Page `form:"page,omitempty" json:"page,omitempty"`
+}
+
+// GetItemsPageParams defines parameters for GetItemsPage.
+type GetItemsPageParams struct {
+	// Number of elements to be returned. Default 100
+	PageSize *PageSize `form:"pageSize,omitempty" json:"pageSize,omitempty"`
+
+	// Page number.
+	Page *Page `form:"page,omitempty" json:"page,omitempty"`
+
+	// Returns only items with price greater than or equal to provided value. Value is a decimal number in major units of the item currency, e.g. "12.50". Regular price is compared, even while a sale is in effect.
+	MinPrice *MinPrice `form:"minPrice,omitempty" json:"minPrice,omitempty"`
+
+	// Returns only items with price lower than or equal to provided value. Value is a decimal number in major units of the item currency, e.g. "12.50". Regular price is compared, even while a sale is in effect.
+	MaxPrice *MaxPrice `form:"maxPrice,omitempty" json:"maxPrice,omitempty"`
+
+	// Returns only items priced in provided currency. Case insensitive.
+	PriceCode *PriceCode `form:"priceCode,omitempty" json:"priceCode,omitempty"`
+
+	// Returns only items which name contains provided text. Case insensitive.
+	Name *NameFilter `form:"name,omitempty" json:"name,omitempty"`
+
+	// Field items are sorted by. Prefix with '-' for descending order. Default id. Prices are compared by amounts in major units of their currencies, the same way as by price filters, e.g. 9.99 USD is listed before 1000 JPY. Regular prices are sorted by, even while a sale is in effect and the item is listed with its sale price.
+	Sort *ItemSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Opaque cursor returned in X-Next-Cursor header of the previous page. Listing continues right after the last item of that page, regardless of items inserted or deleted in the meantime. Has to be used with the same filters and sort as the previous page and cannot be combined with page parameter.
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Returns only items assigned to the category.
+	CategoryId *CategoryId `form:"categoryId,omitempty" json:"categoryId,omitempty"`
+
+	// Includes items assigned to subcategories of the category selected with categoryId.
+	IncludeDescendants *IncludeDescendants `form:"includeDescendants,omitempty" json:"includeDescendants,omitempty"`
+
+	// Returns price of items also in provided currency, taken from the price list of the item or converted with current exchange rates. Case insensitive.
+	Currency *Currency `form:"currency,omitempty" json:"currency,omitempty"`
+}
+
+// SetExchangeRatesJSONRequestBody defines body for SetExchangeRates for application/json ContentType.
+type SetExchangeRatesJSONRequestBody = SetExchangeRatesJSONBody
+
+// CreateCategoryJSONRequestBody defines body for CreateCategory for application/json ContentType.
+type CreateCategoryJSONRequestBody = CreateCategoryJSONBody
+
+// UpdateCategoryByIDJSONRequestBody defines body for UpdateCategoryByID for application/json ContentType.
+type UpdateCategoryByIDJSONRequestBody = UpdateCategoryByIDJSONBody
+
+// CreateItemJSONRequestBody defines body for CreateItem for application/json ContentType.
+type CreateItemJSONRequestBody = CreateItemJSONBody
+
+// UpsertItemByExternalIDJSONRequestBody defines body for UpsertItemByExternalID for application/json ContentType.
+type UpsertItemByExternalIDJSONRequestBody = UpsertItemByExternalIDJSONBody
+
+// UpdateItemByIDJSONRequestBody defines body for UpdateItemByID for application/json ContentType.
+type UpdateItemByIDJSONRequestBody = UpdateItemByIDJSONBody
+
+// SetItemCategoriesJSONRequestBody defines body for SetItemCategories for application/json ContentType.
+type SetItemCategoriesJSONRequestBody = SetItemCategoriesJSONBody
+
+// UpdateItemImageByIDJSONRequestBody defines body for UpdateItemImageByID for application/json ContentType.
+type UpdateItemImageByIDJSONRequestBody = UpdateItemImageByIDJSONBody
+
+// SetItemPricesJSONRequestBody defines body for SetItemPrices for application/json ContentType.
+type SetItemPricesJSONRequestBody = SetItemPricesJSONBody
+
+// CreateScheduledPriceJSONRequestBody defines body for CreateScheduledPrice for application/json ContentType.
+type CreateScheduledPriceJSONRequestBody = CreateScheduledPriceJSONBody
+
+// AdjustStockJSONRequestBody defines body for AdjustStock for application/json ContentType.
+type AdjustStockJSONRequestBody = AdjustStockJSONBody
+
+// CreateVariantJSONRequestBody defines body for CreateVariant for application/json ContentType.
+type CreateVariantJSONRequestBody = CreateVariantJSONBody
+
+// UpdateVariantByIDJSONRequestBody defines body for UpdateVariantByID for application/json ContentType.
+type UpdateVariantByIDJSONRequestBody = UpdateVariantByIDJSONBody
+
+// BatchItemsJSONRequestBody defines body for BatchItems for application/json ContentType.
+type BatchItemsJSONRequestBody = BatchItemsJSONBody
+
+// CreateReservationJSONRequestBody defines body for CreateReservation for application/json ContentType.
+type CreateReservationJSONRequestBody = CreateReservationJSONBody
+
+// CreateWebhookJSONRequestBody defines body for CreateWebhook for application/json ContentType.
+type CreateWebhookJSONRequestBody = CreateWebhookJSONBody
+
+// Getter for additional properties for ExchangeRatesRequest_Rates. Returns the specified
+// element and whether it was found
+func (a ExchangeRatesRequest_Rates) Get(fieldName string) (value string, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for ExchangeRatesRequest_Rates
+func (a *ExchangeRatesRequest_Rates) Set(fieldName string, value string) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]string)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for ExchangeRatesRequest_Rates to handle AdditionalProperties
+func (a *ExchangeRatesRequest_Rates) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]string)
+		for fieldName, fieldBuf := range object {
+			var fieldVal string
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for ExchangeRatesRequest_Rates to handle AdditionalProperties
+func (a ExchangeRatesRequest_Rates) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for ExchangeRatesResponse_Rates. Returns the specified
+// element and whether it was found
+func (a ExchangeRatesResponse_Rates) Get(fieldName string) (value string, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for ExchangeRatesResponse_Rates
+func (a *ExchangeRatesResponse_Rates) Set(fieldName string, value string) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]string)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for ExchangeRatesResponse_Rates to handle AdditionalProperties
+func (a *ExchangeRatesResponse_Rates) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]string)
+		for fieldName, fieldBuf := range object {
+			var fieldVal string
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for ExchangeRatesResponse_Rates to handle AdditionalProperties
+func (a ExchangeRatesResponse_Rates) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Swagger documentation
+	// (GET /api-docs)
+	GetApiDocs(ctx echo.Context) error
+	// Returns exchange rates
+	// (GET /api/v1/admin/exchange-rates)
+	GetExchangeRates(ctx echo.Context) error
+	// Replaces exchange rates
+	// (PUT /api/v1/admin/exchange-rates)
+	SetExchangeRates(ctx echo.Context) error
+	// Returns deleted items
+	// (GET /api/v1/admin/items/trash)
+	GetDeletedItems(ctx echo.Context, params GetDeletedItemsParams) error
+	// Restores deleted item by ID
+	// (POST /api/v1/admin/items/{id}/restore)
+	RestoreItemByID(ctx echo.Context, id uint) error
+	// Returns category tree
+	// (GET /api/v1/categories)
+	GetCategories(ctx echo.Context) error
+	// Create new category
+	// (POST /api/v1/categories)
+	CreateCategory(ctx echo.Context) error
+	// Removes a category by ID
+	// (DELETE /api/v1/categories/{id})
+	DeleteCategoryByID(ctx echo.Context, id uint) error
+	// Returns a category by ID
+	// (GET /api/v1/categories/{id})
+	FindCategoryByID(ctx echo.Context, id uint) error
+	// Updates a category by ID
+	// (PUT /api/v1/categories/{id})
+	UpdateCategoryByID(ctx echo.Context, id uint) error
+	// Returns accepted currencies
+	// (GET /api/v1/currencies)
+	GetCurrencies(ctx echo.Context) error
+	// Imports items from a file
+	// (POST /api/v1/imports)
+	CreateImport(ctx echo.Context) error
+	// Returns status of an import job
+	// (GET /api/v1/imports/{importId})
+	FindImportByID(ctx echo.Context, importId uint) error
+	// Downloads report of rejected rows
+	// (GET /api/v1/imports/{importId}/rejections)
+	GetImportRejections(ctx echo.Context, importId uint) error
+	// Returns all items
+	// (GET /api/v1/items)
+	GetItems(ctx echo.Context, params GetItemsParams) error
+	// Create new item
+	// (POST /api/v1/items)
+	CreateItem(ctx echo.Context) error
+	// Returns an item by external ID
+	// (GET /api/v1/items/by-external-id/{externalId})
+	FindItemByExternalID(ctx echo.Context, externalId string, params FindItemByExternalIDParams) error
+	// Creates or updates an item by external ID
+	// (PUT /api/v1/items/by-external-id/{externalId})
+	UpsertItemByExternalID(ctx echo.Context, externalId string) error
+	// Streams item changes
+	// (GET /api/v1/items/events)
+	StreamItemEvents(ctx echo.Context, params StreamItemEventsParams) error
+	// Exports the whole catalog
+	// (GET /api/v1/items/export)
+	ExportItems(ctx echo.Context, params ExportItemsParams) error
+	// Searches items
+	// (GET /api/v1/items/search)
+	SearchItems(ctx echo.Context, params SearchItemsParams) error
+	// Removes an item by ID
+	// (DELETE /api/v1/items/{id})
+	DeleteItemByID(ctx echo.Context, id uint, params DeleteItemByIDParams) error
+	// Returns an item by ID
+	// (GET /api/v1/items/{id})
+	FindItemByID(ctx echo.Context, id uint, params FindItemByIDParams) error
+	// Updates an item by ID
+	// (PUT /api/v1/items/{id})
+	UpdateItemByID(ctx echo.Context, id uint, params UpdateItemByIDParams) error
+	// Assigns an item to categories
+	// (PUT /api/v1/items/{id}/categories)
+	SetItemCategories(ctx echo.Context, id uint) error
+	// Returns change history of an item
+	// (GET /api/v1/items/{id}/history)
+	GetItemHistory(ctx echo.Context, id uint) error
+	// Returns an item as of a point in time
+	// (GET /api/v1/items/{id}/history/snapshot)
+	GetItemSnapshot(ctx echo.Context, id uint, params GetItemSnapshotParams) error
+	// Returns images of an item
+	// (GET /api/v1/items/{id}/images)
+	GetItemImages(ctx echo.Context, id uint) error
+	// Uploads an image of an item
+	// (POST /api/v1/items/{id}/images)
+	CreateItemImage(ctx echo.Context, id uint, params CreateItemImageParams) error
+	// Removes an image by ID
+	// (DELETE /api/v1/items/{id}/images/{imageId})
+	DeleteItemImageByID(ctx echo.Context, id uint, imageId uint) error
+	// Returns an image by ID
+	// (GET /api/v1/items/{id}/images/{imageId})
+	FindItemImageByID(ctx echo.Context, id uint, imageId uint) error
+	// Replaces alternative text and position of an image by ID
+	// (PUT /api/v1/items/{id}/images/{imageId})
+	UpdateItemImageByID(ctx echo.Context, id uint, imageId uint) error
+	// Returns file of an image in requested size
+	// (GET /api/v1/items/{id}/images/{imageId}/{size})
+	GetItemImageFile(ctx echo.Context, id uint, imageId uint, size ImageSize) error
+	// Returns price list of an item
+	// (GET /api/v1/items/{id}/prices)
+	GetItemPrices(ctx echo.Context, id uint) error
+	// Replaces price list of an item
+	// (PUT /api/v1/items/{id}/prices)
+	SetItemPrices(ctx echo.Context, id uint) error
+	// Returns scheduled prices of an item
+	// (GET /api/v1/items/{id}/scheduled-prices)
+	GetScheduledPrices(ctx echo.Context, id uint) error
+	// Schedules price of an item
+	// (POST /api/v1/items/{id}/scheduled-prices)
+	CreateScheduledPrice(ctx echo.Context, id uint) error
+	// Removes a scheduled price by ID
+	// (DELETE /api/v1/items/{id}/scheduled-prices/{scheduledPriceId})
+	DeleteScheduledPriceByID(ctx echo.Context, id uint, scheduledPriceId uint) error
+	// Returns stock level of an item
+	// (GET /api/v1/items/{id}/stock)
+	GetStock(ctx echo.Context, id uint, params GetStockParams) error
+	// Adjusts stock level of an item
+	// (POST /api/v1/items/{id}/stock/adjustments)
+	AdjustStock(ctx echo.Context, id uint) error
+	// Returns variants of an item
+	// (GET /api/v1/items/{id}/variants)
+	GetVariants(ctx echo.Context, id uint) error
+	// Create new variant
+	// (POST /api/v1/items/{id}/variants)
+	CreateVariant(ctx echo.Context, id uint) error
+	// Removes a variant by ID
+	// (DELETE /api/v1/items/{id}/variants/{variantId})
+	DeleteVariantByID(ctx echo.Context, id uint, variantId uint) error
+	// Returns a variant by ID
+	// (GET /api/v1/items/{id}/variants/{variantId})
+	FindVariantByID(ctx echo.Context, id uint, variantId uint) error
+	// Replaces a variant by ID
+	// (PUT /api/v1/items/{id}/variants/{variantId})
+	UpdateVariantByID(ctx echo.Context, id uint, variantId uint) error
+	// Creates, updates and deletes items in bulk
+	// (POST /api/v1/items:batch)
+	BatchItems(ctx echo.Context, params BatchItemsParams) error
+	// Returns upcoming price changes
+	// (GET /api/v1/prices/upcoming)
+	GetUpcomingPrices(ctx echo.Context, params GetUpcomingPricesParams) error
+	// Reserves stock
+	// (POST /api/v1/reservations)
+	CreateReservation(ctx echo.Context) error
+	// Returns a reservation by ID
+	// (GET /api/v1/reservations/{id})
+	FindReservationByID(ctx echo.Context, id ReservationId) error
+	// Commits a reservation
+	// (POST /api/v1/reservations/{id}/commit)
+	CommitReservation(ctx echo.Context, id ReservationId) error
+	// Releases a reservation
+	// (POST /api/v1/reservations/{id}/release)
+	ReleaseReservation(ctx echo.Context, id ReservationId) error
+	// Returns webhooks
+	// (GET /api/v1/webhooks)
+	GetWebhooks(ctx echo.Context) error
+	// Subscribes a webhook to events
+	// (POST /api/v1/webhooks)
+	CreateWebhook(ctx echo.Context) error
+	// Returns dead letters
+	// (GET /api/v1/webhooks/dead-letters)
+	GetDeadLetters(ctx echo.Context, params GetDeadLettersParams) error
+	// Deletes a webhook by ID
+	// (DELETE /api/v1/webhooks/{webhookId})
+	DeleteWebhook(ctx echo.Context, webhookId uint) error
+	// Returns a webhook by ID
+	// (GET /api/v1/webhooks/{webhookId})
+	FindWebhookByID(ctx echo.Context, webhookId uint) error
+	// Returns deliveries of a webhook
+	// (GET /api/v1/webhooks/{webhookId}/deliveries)
+	GetWebhookDeliveries(ctx echo.Context, webhookId uint, params GetWebhookDeliveriesParams) error
+	// Redelivers an event
+	// (POST /api/v1/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver)
+	RedeliverDelivery(ctx echo.Context, webhookId uint, deliveryId uint) error
+	// Returns page of items
+	// (GET /api/v2/items)
+	GetItemsPage(ctx echo.Context, params GetItemsPageParams) error
+	// Health endpoint
+	// (GET /healtz)
+	GetHealtz(ctx echo.Context) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// GetApiDocs converts echo context to params.
+func (w *ServerInterfaceWrapper) GetApiDocs(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetApiDocs(ctx)
+	return err
+}
+
+// GetExchangeRates converts echo context to params.
+func (w *ServerInterfaceWrapper) GetExchangeRates(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetExchangeRates(ctx)
+	return err
+}
+
+// SetExchangeRates converts echo context to params.
+func (w *ServerInterfaceWrapper) SetExchangeRates(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.SetExchangeRates(ctx)
+	return err
+}
+
+// GetDeletedItems converts echo context to params.
+func (w *ServerInterfaceWrapper) GetDeletedItems(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetDeletedItemsParams
+	// ------------- Optional query parameter "pageSize" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pageSize", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pageSize: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetDeletedItems(ctx, params)
+	return err
+}
+
+// RestoreItemByID converts echo context to params.
+func (w *ServerInterfaceWrapper) RestoreItemByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.RestoreItemByID(ctx, id)
+	return err
+}
+
+// GetCategories converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCategories(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetCategories(ctx)
+	return err
+}
+
+// CreateCategory converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateCategory(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateCategory(ctx)
+	return err
+}
+
+// DeleteCategoryByID converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteCategoryByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteCategoryByID(ctx, id)
+	return err
+}
+
+// FindCategoryByID converts echo context to params.
+func (w *ServerInterfaceWrapper) FindCategoryByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.FindCategoryByID(ctx, id)
+	return err
+}
+
+// UpdateCategoryByID converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateCategoryByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateCategoryByID(ctx, id)
+	return err
+}
+
+// GetCurrencies converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCurrencies(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetCurrencies(ctx)
+	return err
+}
+
+// CreateImport converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateImport(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateImport(ctx)
+	return err
+}
+
+// FindImportByID converts echo context to params.
+func (w *ServerInterfaceWrapper) FindImportByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "importId" -------------
+	var importId uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "importId", runtime.ParamLocationPath, ctx.Param("importId"), &importId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter importId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.FindImportByID(ctx, importId)
+	return err
+}
+
+// GetImportRejections converts echo context to params.
+func (w *ServerInterfaceWrapper) GetImportRejections(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "importId" -------------
+	var importId uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "importId", runtime.ParamLocationPath, ctx.Param("importId"), &importId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter importId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetImportRejections(ctx, importId)
+	return err
+}
+
+// GetItems converts echo context to params.
+func (w *ServerInterfaceWrapper) GetItems(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetItemsParams
+	// ------------- Optional query parameter "pageSize" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pageSize", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pageSize: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "minPrice" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "minPrice", ctx.QueryParams(), &params.MinPrice)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter minPrice: %s", err))
+	}
+
+	// ------------- Optional query parameter "maxPrice" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "maxPrice", ctx.QueryParams(), &params.MaxPrice)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter maxPrice: %s", err))
+	}
+
+	// ------------- Optional query parameter "priceCode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "priceCode", ctx.QueryParams(), &params.PriceCode)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter priceCode: %s", err))
+	}
+
+	// ------------- Optional query parameter "name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "name", ctx.QueryParams(), &params.Name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "categoryId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "categoryId", ctx.QueryParams(), &params.CategoryId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter categoryId: %s", err))
+	}
+
+	// ------------- Optional query parameter "includeDescendants" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeDescendants", ctx.QueryParams(), &params.IncludeDescendants)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter includeDescendants: %s", err))
+	}
+
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", ctx.QueryParams(), &params.Currency)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter currency: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetItems(ctx, params)
+	return err
+}
+
+// CreateItem converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateItem(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateItem(ctx)
+	return err
+}
+
+// FindItemByExternalID converts echo context to params.
+func (w *ServerInterfaceWrapper) FindItemByExternalID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "externalId" -------------
+	var externalId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "externalId", runtime.ParamLocationPath, ctx.Param("externalId"), &externalId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter externalId: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params FindItemByExternalIDParams
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", ctx.QueryParams(), &params.Currency)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter currency: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-None-Match", runtime.ParamLocationHeader, valueList[0], &IfNoneMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.FindItemByExternalID(ctx, externalId, params)
+	return err
+}
+
+// UpsertItemByExternalID converts echo context to params.
+func (w *ServerInterfaceWrapper) UpsertItemByExternalID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "externalId" -------------
+	var externalId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "externalId", runtime.ParamLocationPath, ctx.Param("externalId"), &externalId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter externalId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpsertItemByExternalID(ctx, externalId)
+	return err
+}
+
+// StreamItemEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) StreamItemEvents(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamItemEventsParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID uint64
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Last-Event-ID, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Last-Event-ID", runtime.ParamLocationHeader, valueList[0], &LastEventID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Last-Event-ID: %s", err))
+		}
+
+		params.LastEventID = &LastEventID
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.StreamItemEvents(ctx, params)
+	return err
+}
+
+// ExportItems converts echo context to params.
+func (w *ServerInterfaceWrapper) ExportItems(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportItemsParams
+	// ------------- Required query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "minPrice" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "minPrice", ctx.QueryParams(), &params.MinPrice)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter minPrice: %s", err))
+	}
+
+	// ------------- Optional query parameter "maxPrice" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "maxPrice", ctx.QueryParams(), &params.MaxPrice)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter maxPrice: %s", err))
+	}
+
+	// ------------- Optional query parameter "priceCode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "priceCode", ctx.QueryParams(), &params.PriceCode)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter priceCode: %s", err))
+	}
+
+	// ------------- Optional query parameter "name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "name", ctx.QueryParams(), &params.Name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// ------------- Optional query parameter "categoryId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "categoryId", ctx.QueryParams(), &params.CategoryId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter categoryId: %s", err))
+	}
+
+	// ------------- Optional query parameter "includeDescendants" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeDescendants", ctx.QueryParams(), &params.IncludeDescendants)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter includeDescendants: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ExportItems(ctx, params)
+	return err
+}
+
+// SearchItems converts echo context to params.
+func (w *ServerInterfaceWrapper) SearchItems(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchItemsParams
+	// ------------- Required query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "q", ctx.QueryParams(), &params.Q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter q: %s", err))
+	}
+
+	// ------------- Optional query parameter "pageSize" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pageSize", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pageSize: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.SearchItems(ctx, params)
+	return err
+}
+
+// DeleteItemByID converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteItemByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteItemByIDParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteItemByID(ctx, id, params)
+	return err
+}
+
+// FindItemByID converts echo context to params.
+func (w *ServerInterfaceWrapper) FindItemByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params FindItemByIDParams
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", ctx.QueryParams(), &params.Currency)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter currency: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-None-Match", runtime.ParamLocationHeader, valueList[0], &IfNoneMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.FindItemByID(ctx, id, params)
+	return err
+}
+
+// UpdateItemByID converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateItemByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateItemByIDParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateItemByID(ctx, id, params)
+	return err
+}
+
+// SetItemCategories converts echo context to params.
+func (w *ServerInterfaceWrapper) SetItemCategories(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.SetItemCategories(ctx, id)
+	return err
+}
+
+// GetItemHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) GetItemHistory(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetItemHistory(ctx, id)
+	return err
+}
+
+// GetItemSnapshot converts echo context to params.
+func (w *ServerInterfaceWrapper) GetItemSnapshot(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetItemSnapshotParams
+	// ------------- Required query parameter "at" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "at", ctx.QueryParams(), &params.At)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter at: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetItemSnapshot(ctx, id, params)
+	return err
+}
+
+// GetItemImages converts echo context to params.
+func (w *ServerInterfaceWrapper) GetItemImages(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetItemImages(ctx, id)
+	return err
+}
+
+// CreateItemImage converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateItemImage(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateItemImageParams
+	// ------------- Optional query parameter "altText" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "altText", ctx.QueryParams(), &params.AltText)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter altText: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateItemImage(ctx, id, params)
+	return err
+}
+
+// DeleteItemImageByID converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteItemImageByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "imageId" -------------
+	var imageId uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "imageId", runtime.ParamLocationPath, ctx.Param("imageId"), &imageId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter imageId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteItemImageByID(ctx, id, imageId)
+	return err
+}
+
+// FindItemImageByID converts echo context to params.
+func (w *ServerInterfaceWrapper) FindItemImageByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "imageId" -------------
+	var imageId uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "imageId", runtime.ParamLocationPath, ctx.Param("imageId"), &imageId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter imageId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.FindItemImageByID(ctx, id, imageId)
+	return err
+}
+
+// UpdateItemImageByID converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateItemImageByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "imageId" -------------
+	var imageId uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "imageId", runtime.ParamLocationPath, ctx.Param("imageId"), &imageId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter imageId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateItemImageByID(ctx, id, imageId)
+	return err
+}
+
+// GetItemImageFile converts echo context to params.
+func (w *ServerInterfaceWrapper) GetItemImageFile(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "imageId" -------------
+	var imageId uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "imageId", runtime.ParamLocationPath, ctx.Param("imageId"), &imageId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter imageId: %s", err))
+	}
+
+	// ------------- Path parameter "size" -------------
+	var size ImageSize
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "size", runtime.ParamLocationPath, ctx.Param("size"), &size)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter size: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetItemImageFile(ctx, id, imageId, size)
+	return err
+}
+
+// GetItemPrices converts echo context to params.
+func (w *ServerInterfaceWrapper) GetItemPrices(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetItemPrices(ctx, id)
+	return err
+}
+
+// SetItemPrices converts echo context to params.
+func (w *ServerInterfaceWrapper) SetItemPrices(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.SetItemPrices(ctx, id)
+	return err
+}
+
+// GetScheduledPrices converts echo context to params.
+func (w *ServerInterfaceWrapper) GetScheduledPrices(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetScheduledPrices(ctx, id)
+	return err
+}
+
+// CreateScheduledPrice converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateScheduledPrice(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateScheduledPrice(ctx, id)
+	return err
+}
+
+// DeleteScheduledPriceByID converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteScheduledPriceByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "scheduledPriceId" -------------
+	var scheduledPriceId uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "scheduledPriceId", runtime.ParamLocationPath, ctx.Param("scheduledPriceId"), &scheduledPriceId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scheduledPriceId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteScheduledPriceByID(ctx, id, scheduledPriceId)
+	return err
+}
+
+// GetStock converts echo context to params.
+func (w *ServerInterfaceWrapper) GetStock(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStockParams
+	// ------------- Optional query parameter "variantId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "variantId", ctx.QueryParams(), &params.VariantId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter variantId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetStock(ctx, id, params)
+	return err
+}
+
+// AdjustStock converts echo context to params.
+func (w *ServerInterfaceWrapper) AdjustStock(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.AdjustStock(ctx, id)
+	return err
+}
+
+// GetVariants converts echo context to params.
+func (w *ServerInterfaceWrapper) GetVariants(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetVariants(ctx, id)
+	return err
+}
+
+// CreateVariant converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateVariant(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateVariant(ctx, id)
+	return err
+}
+
+// DeleteVariantByID converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteVariantByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "variantId" -------------
+	var variantId uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "variantId", runtime.ParamLocationPath, ctx.Param("variantId"), &variantId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter variantId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteVariantByID(ctx, id, variantId)
+	return err
+}
+
+// FindVariantByID converts echo context to params.
+func (w *ServerInterfaceWrapper) FindVariantByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "variantId" -------------
+	var variantId uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "variantId", runtime.ParamLocationPath, ctx.Param("variantId"), &variantId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter variantId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.FindVariantByID(ctx, id, variantId)
+	return err
+}
+
+// UpdateVariantByID converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateVariantByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uint
 
-	// Unique ID of the item
-	Id *uint `json:"id,omitempty"`
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
 
-	// Name of the item
-	Name *string `json:"name,omitempty"`
+	// ------------- Path parameter "variantId" -------------
+	var variantId uint
 
-	// Price of the item
-	Price *float64 `json:"price,omitempty"`
+	err = runtime.BindStyledParameterWithLocation("simple", false, "variantId", runtime.ParamLocationPath, ctx.Param("variantId"), &variantId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter variantId: %s", err))
+	}
 
-	// Currency of the price
-	PriceCode *string `json:"priceCode,omitempty"`
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateVariantByID(ctx, id, variantId)
+	return err
 }
 
-// NewItemRequest defines model for NewItemRequest.
-type NewItemRequest struct {
-	// Description of the item
-	Description string `json:"description"`
+// BatchItems converts echo context to params.
+func (w *ServerInterfaceWrapper) BatchItems(ctx echo.Context) error {
+	var err error
 
-	// Name of the item
-	Name string `json:"name"`
+	// Parameter object where we will unmarshal all parameters from the context
+	var params BatchItemsParams
+	// ------------- Optional query parameter "mode" -------------
 
-	// Price of the item
-	Price float64 `json:"price"`
+	err = runtime.BindQueryParameter("form", true, false, "mode", ctx.QueryParams(), &params.Mode)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter mode: %s", err))
+	}
 
-	// Currency of the price
-	PriceCode string `json:"priceCode"`
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.BatchItems(ctx, params)
+	return err
 }
 
-// UpdateItemRequest defines model for UpdateItemRequest.
-type UpdateItemRequest struct {
-	// Description of the item
-	Description *string `json:"description,omitempty"`
+// GetUpcomingPrices converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUpcomingPrices(ctx echo.Context) error {
+	var err error
 
-	// Name of the item
-	Name *string `json:"name,omitempty"`
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUpcomingPricesParams
+	// ------------- Optional query parameter "pageSize" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pageSize", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pageSize: %s", err))
+	}
 
-	// Price of the item
-	Price *float64 `json:"price,omitempty"`
+	// ------------- Optional query parameter "page" -------------
 
-	// Currency of the price
-	PriceCode *string `json:"priceCode,omitempty"`
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetUpcomingPrices(ctx, params)
+	return err
 }
 
-// GetItemsParams defines parameters for GetItems.
-type GetItemsParams struct {
-	// Number of elements to be returned. Default 100
-	PageSize *int `form:"pageSize,omitempty" json:"pageSize,omitempty"`
+// CreateReservation converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateReservation(ctx echo.Context) error {
+	var err error
 
-	// Page number.
-	Page *int `form:"page,omitempty" json:"page,omitempty"`
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateReservation(ctx)
+	return err
 }
 
-// CreateItemJSONBody defines parameters for CreateItem.
-type CreateItemJSONBody = NewItemRequest
+// FindReservationByID converts echo context to params.
+func (w *ServerInterfaceWrapper) FindReservationByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id ReservationId
 
-// UpdateItemByIDJSONBody defines parameters for UpdateItemByID.
-type UpdateItemByIDJSONBody = UpdateItemRequest
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
 
-// CreateItemJSONRequestBody defines body for CreateItem for application/json ContentType.
-type CreateItemJSONRequestBody = CreateItemJSONBody
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.FindReservationByID(ctx, id)
+	return err
+}
 
-// UpdateItemByIDJSONRequestBody defines body for UpdateItemByID for application/json ContentType.
-type UpdateItemByIDJSONRequestBody = UpdateItemByIDJSONBody
+// CommitReservation converts echo context to params.
+func (w *ServerInterfaceWrapper) CommitReservation(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id ReservationId
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Swagger documentation
-	// (GET /api-docs)
-	GetApiDocs(ctx echo.Context) error
-	// Returns all items
-	// (GET /api/v1/items)
-	GetItems(ctx echo.Context, params GetItemsParams) error
-	// Create new item
-	// (POST /api/v1/items)
-	CreateItem(ctx echo.Context) error
-	// Removes an item by ID
-	// (DELETE /api/v1/items/{id})
-	DeleteItemByID(ctx echo.Context, id uint) error
-	// Returns an item by ID
-	// (GET /api/v1/items/{id})
-	FindItemByID(ctx echo.Context, id uint) error
-	// Updates an item by ID
-	// (PUT /api/v1/items/{id})
-	UpdateItemByID(ctx echo.Context, id uint) error
-	// Health endpoint
-	// (GET /healtz)
-	GetHealtz(ctx echo.Context) error
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CommitReservation(ctx, id)
+	return err
 }
 
-// ServerInterfaceWrapper converts echo contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler ServerInterface
+// ReleaseReservation converts echo context to params.
+func (w *ServerInterfaceWrapper) ReleaseReservation(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id ReservationId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ReleaseReservation(ctx, id)
+	return err
 }
 
-// GetApiDocs converts echo context to params.
-func (w *ServerInterfaceWrapper) GetApiDocs(ctx echo.Context) error {
+// GetWebhooks converts echo context to params.
+func (w *ServerInterfaceWrapper) GetWebhooks(ctx echo.Context) error {
 	var err error
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetApiDocs(ctx)
+	err = w.Handler.GetWebhooks(ctx)
 	return err
 }
 
-// GetItems converts echo context to params.
-func (w *ServerInterfaceWrapper) GetItems(ctx echo.Context) error {
+// CreateWebhook converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateWebhook(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateWebhook(ctx)
+	return err
+}
+
+// GetDeadLetters converts echo context to params.
+func (w *ServerInterfaceWrapper) GetDeadLetters(ctx echo.Context) error {
 	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetItemsParams
+	var params GetDeadLettersParams
 	// ------------- Optional query parameter "pageSize" -------------
 
 	err = runtime.BindQueryParameter("form", true, false, "pageSize", ctx.QueryParams(), &params.PageSize)
@@ -153,64 +2572,190 @@ func (w *ServerInterfaceWrapper) GetItems(ctx echo.Context) error {
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetItems(ctx, params)
+	err = w.Handler.GetDeadLetters(ctx, params)
 	return err
 }
 
-// CreateItem converts echo context to params.
-func (w *ServerInterfaceWrapper) CreateItem(ctx echo.Context) error {
+// DeleteWebhook converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteWebhook(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "webhookId" -------------
+	var webhookId uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "webhookId", runtime.ParamLocationPath, ctx.Param("webhookId"), &webhookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter webhookId: %s", err))
+	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.CreateItem(ctx)
+	err = w.Handler.DeleteWebhook(ctx, webhookId)
 	return err
 }
 
-// DeleteItemByID converts echo context to params.
-func (w *ServerInterfaceWrapper) DeleteItemByID(ctx echo.Context) error {
+// FindWebhookByID converts echo context to params.
+func (w *ServerInterfaceWrapper) FindWebhookByID(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "id" -------------
-	var id uint
+	// ------------- Path parameter "webhookId" -------------
+	var webhookId uint
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "webhookId", runtime.ParamLocationPath, ctx.Param("webhookId"), &webhookId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter webhookId: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.DeleteItemByID(ctx, id)
+	err = w.Handler.FindWebhookByID(ctx, webhookId)
 	return err
 }
 
-// FindItemByID converts echo context to params.
-func (w *ServerInterfaceWrapper) FindItemByID(ctx echo.Context) error {
+// GetWebhookDeliveries converts echo context to params.
+func (w *ServerInterfaceWrapper) GetWebhookDeliveries(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "id" -------------
-	var id uint
+	// ------------- Path parameter "webhookId" -------------
+	var webhookId uint
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "webhookId", runtime.ParamLocationPath, ctx.Param("webhookId"), &webhookId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter webhookId: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetWebhookDeliveriesParams
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "pageSize" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pageSize", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pageSize: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.FindItemByID(ctx, id)
+	err = w.Handler.GetWebhookDeliveries(ctx, webhookId, params)
 	return err
 }
 
-// UpdateItemByID converts echo context to params.
-func (w *ServerInterfaceWrapper) UpdateItemByID(ctx echo.Context) error {
+// RedeliverDelivery converts echo context to params.
+func (w *ServerInterfaceWrapper) RedeliverDelivery(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "id" -------------
-	var id uint
+	// ------------- Path parameter "webhookId" -------------
+	var webhookId uint
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "webhookId", runtime.ParamLocationPath, ctx.Param("webhookId"), &webhookId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter webhookId: %s", err))
+	}
+
+	// ------------- Path parameter "deliveryId" -------------
+	var deliveryId uint
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "deliveryId", runtime.ParamLocationPath, ctx.Param("deliveryId"), &deliveryId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter deliveryId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.RedeliverDelivery(ctx, webhookId, deliveryId)
+	return err
+}
+
+// GetItemsPage converts echo context to params.
+func (w *ServerInterfaceWrapper) GetItemsPage(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetItemsPageParams
+	// ------------- Optional query parameter "pageSize" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pageSize", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pageSize: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "minPrice" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "minPrice", ctx.QueryParams(), &params.MinPrice)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter minPrice: %s", err))
+	}
+
+	// ------------- Optional query parameter "maxPrice" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "maxPrice", ctx.QueryParams(), &params.MaxPrice)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter maxPrice: %s", err))
+	}
+
+	// ------------- Optional query parameter "priceCode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "priceCode", ctx.QueryParams(), &params.PriceCode)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter priceCode: %s", err))
+	}
+
+	// ------------- Optional query parameter "name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "name", ctx.QueryParams(), &params.Name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "categoryId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "categoryId", ctx.QueryParams(), &params.CategoryId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter categoryId: %s", err))
+	}
+
+	// ------------- Optional query parameter "includeDescendants" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeDescendants", ctx.QueryParams(), &params.IncludeDescendants)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter includeDescendants: %s", err))
+	}
+
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", ctx.QueryParams(), &params.Currency)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter currency: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.UpdateItemByID(ctx, id)
+	err = w.Handler.GetItemsPage(ctx, params)
 	return err
 }
 
@@ -252,11 +2797,64 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	}
 
 	router.GET(baseURL+"/api-docs", wrapper.GetApiDocs)
+	router.GET(baseURL+"/api/v1/admin/exchange-rates", wrapper.GetExchangeRates)
+	router.PUT(baseURL+"/api/v1/admin/exchange-rates", wrapper.SetExchangeRates)
+	router.GET(baseURL+"/api/v1/admin/items/trash", wrapper.GetDeletedItems)
+	router.POST(baseURL+"/api/v1/admin/items/:id/restore", wrapper.RestoreItemByID)
+	router.GET(baseURL+"/api/v1/categories", wrapper.GetCategories)
+	router.POST(baseURL+"/api/v1/categories", wrapper.CreateCategory)
+	router.DELETE(baseURL+"/api/v1/categories/:id", wrapper.DeleteCategoryByID)
+	router.GET(baseURL+"/api/v1/categories/:id", wrapper.FindCategoryByID)
+	router.PUT(baseURL+"/api/v1/categories/:id", wrapper.UpdateCategoryByID)
+	router.GET(baseURL+"/api/v1/currencies", wrapper.GetCurrencies)
+	router.POST(baseURL+"/api/v1/imports", wrapper.CreateImport)
+	router.GET(baseURL+"/api/v1/imports/:importId", wrapper.FindImportByID)
+	router.GET(baseURL+"/api/v1/imports/:importId/rejections", wrapper.GetImportRejections)
 	router.GET(baseURL+"/api/v1/items", wrapper.GetItems)
 	router.POST(baseURL+"/api/v1/items", wrapper.CreateItem)
+	router.GET(baseURL+"/api/v1/items/by-external-id/:externalId", wrapper.FindItemByExternalID)
+	router.PUT(baseURL+"/api/v1/items/by-external-id/:externalId", wrapper.UpsertItemByExternalID)
+	router.GET(baseURL+"/api/v1/items/events", wrapper.StreamItemEvents)
+	router.GET(baseURL+"/api/v1/items/export", wrapper.ExportItems)
+	router.GET(baseURL+"/api/v1/items/search", wrapper.SearchItems)
 	router.DELETE(baseURL+"/api/v1/items/:id", wrapper.DeleteItemByID)
 	router.GET(baseURL+"/api/v1/items/:id", wrapper.FindItemByID)
 	router.PUT(baseURL+"/api/v1/items/:id", wrapper.UpdateItemByID)
+	router.PUT(baseURL+"/api/v1/items/:id/categories", wrapper.SetItemCategories)
+	router.GET(baseURL+"/api/v1/items/:id/history", wrapper.GetItemHistory)
+	router.GET(baseURL+"/api/v1/items/:id/history/snapshot", wrapper.GetItemSnapshot)
+	router.GET(baseURL+"/api/v1/items/:id/images", wrapper.GetItemImages)
+	router.POST(baseURL+"/api/v1/items/:id/images", wrapper.CreateItemImage)
+	router.DELETE(baseURL+"/api/v1/items/:id/images/:imageId", wrapper.DeleteItemImageByID)
+	router.GET(baseURL+"/api/v1/items/:id/images/:imageId", wrapper.FindItemImageByID)
+	router.PUT(baseURL+"/api/v1/items/:id/images/:imageId", wrapper.UpdateItemImageByID)
+	router.GET(baseURL+"/api/v1/items/:id/images/:imageId/:size", wrapper.GetItemImageFile)
+	router.GET(baseURL+"/api/v1/items/:id/prices", wrapper.GetItemPrices)
+	router.PUT(baseURL+"/api/v1/items/:id/prices", wrapper.SetItemPrices)
+	router.GET(baseURL+"/api/v1/items/:id/scheduled-prices", wrapper.GetScheduledPrices)
+	router.POST(baseURL+"/api/v1/items/:id/scheduled-prices", wrapper.CreateScheduledPrice)
+	router.DELETE(baseURL+"/api/v1/items/:id/scheduled-prices/:scheduledPriceId", wrapper.DeleteScheduledPriceByID)
+	router.GET(baseURL+"/api/v1/items/:id/stock", wrapper.GetStock)
+	router.POST(baseURL+"/api/v1/items/:id/stock/adjustments", wrapper.AdjustStock)
+	router.GET(baseURL+"/api/v1/items/:id/variants", wrapper.GetVariants)
+	router.POST(baseURL+"/api/v1/items/:id/variants", wrapper.CreateVariant)
+	router.DELETE(baseURL+"/api/v1/items/:id/variants/:variantId", wrapper.DeleteVariantByID)
+	router.GET(baseURL+"/api/v1/items/:id/variants/:variantId", wrapper.FindVariantByID)
+	router.PUT(baseURL+"/api/v1/items/:id/variants/:variantId", wrapper.UpdateVariantByID)
+	router.POST(baseURL+"/api/v1/items:batch", wrapper.BatchItems)
+	router.GET(baseURL+"/api/v1/prices/upcoming", wrapper.GetUpcomingPrices)
+	router.POST(baseURL+"/api/v1/reservations", wrapper.CreateReservation)
+	router.GET(baseURL+"/api/v1/reservations/:id", wrapper.FindReservationByID)
+	router.POST(baseURL+"/api/v1/reservations/:id/commit", wrapper.CommitReservation)
+	router.POST(baseURL+"/api/v1/reservations/:id/release", wrapper.ReleaseReservation)
+	router.GET(baseURL+"/api/v1/webhooks", wrapper.GetWebhooks)
+	router.POST(baseURL+"/api/v1/webhooks", wrapper.CreateWebhook)
+	router.GET(baseURL+"/api/v1/webhooks/dead-letters", wrapper.GetDeadLetters)
+	router.DELETE(baseURL+"/api/v1/webhooks/:webhookId", wrapper.DeleteWebhook)
+	router.GET(baseURL+"/api/v1/webhooks/:webhookId", wrapper.FindWebhookByID)
+	router.GET(baseURL+"/api/v1/webhooks/:webhookId/deliveries", wrapper.GetWebhookDeliveries)
+	router.POST(baseURL+"/api/v1/webhooks/:webhookId/deliveries/:deliveryId/redeliver", wrapper.RedeliverDelivery)
+	router.GET(baseURL+"/api/v2/items", wrapper.GetItemsPage)
 	router.GET(baseURL+"/healtz", wrapper.GetHealtz)
 
 }
@@ -264,22 +2862,208 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+yXS4/jNgzHv4rA9ujm0aIX37qTPnJZFDPY02IOisU4KqzHUFIG3oG/eyHJedrZpIvZ",
-	"xaDtzZElkub/R1J5gcooazRq76B8AVdtUPH0+CuRoXt01miHccGSsUheYnqt0DlepxcCXUXSemk0lPkc",
-	"270uwLcWoQTnSeoauq4AwqcgCQWUH/dmHrsClh7VZYcnXs6dLg6/mFkzv0EmPaqh+wKkGB7/oOVTQLZc",
-	"nB1eG1LcQwlBan8wJrXHGila01yN5OA9V3gtDkuyGjn6Z1y+FIYwYdUcJVUHtcpxJGt3RoxYvAtEqKt2",
-	"ZzQ7HgrTFfAen7MITwGd/3oa/Iuydopz+rDixNQu5mNvkfYPVnCP/6f7H0IaMy712gxNPCBtY1iUW4hc",
-	"NcjWsRVxzWupa1ZxzxtTp4BdtC19E43f5XUoYIvksrH5ZDaZxZiNRc2thBJ+SksFWO43SZ4pt/IHYar0",
-	"o0Y/DOkefSDt2MMzr2skJkwVFGrPezKi2Ol5KaCE39H/YuUiGoxU5UaYjP84m41876jRroCf8+7KaI86",
-	"RcWtbWSVNkz/cpmm3Orj0/eEayjhu+lhFkz7QTA9nQIp+2Ptng47CnBBKU7t5RC7IuVuup1PsxbX8sd1",
-	"Fo2tyajER6/lZCyJy15fy4kr9EgOyo8D+hOVETdsMMbmmDdsFfGJPlFM2ALXPDSezWdRdhlPPQWkFnYV",
-	"BZbX+CA/RVAP6RT5GJTz2awAJbVUQUE5H46PrhgUFq+R5YKZfMbnBX9XvD2OU3UzJ3utPgfMyRDv9lFw",
-	"It6O8ZPEusTPXv+m6as2dhHjRlC5I+Qe96gwqS8zkvcuc9+i3H3fGdG+WtWczdHudEx4CtgNtJi/mvdT",
-	"CcZTfpTxt9QwsjJM43MeK4NWMX2RosvqN+hxbCzGdZchOG8XbMUdCmZ0WlwumAvxQ1EMEMlmYqbetcvF",
-	"tWaSL4479LxhfXR9BceJcShgKeCchuNyvnbnvFTGIxrnKMTbkvgeldkeFeqqZctFDPGWCXDDAPhNavHF",
-	"uq3RV5tvKtt/veLPBd7jYMMIDvnSPNblby/tw8X7ixAJ6fhXZOT1x9Hwr8ZNE+kb85kT+8a61TlwPZ5x",
-	"LG2QN/7T0d11cBH9I++46TLf/3mRjiW7m/YskGRrw1ALayI96UMc0naHbaAGSphC99j9HQAA//815Snf",
-	"1hEAAA==",
+	"H4sIAAAAAAAC/+x9/XMbN7Lgv4Livaq3uRtKsuPksq66uvJaTqzdxPGz7N19t/ZdgTMgiXgIMABGEtdP",
+	"//tVdwMzmCFmOJRlidnol8SSMPhoNPr749Mk16u1VkI5O3n6abIUvBAG//mjVB/h/4WwuZFrJ7WaPMXf",
+	"WuY0c0vBlLhyjKuCrY24kLqybM0XwjJuWSHmUomCScXefP+cfff4u+8m2cTmS7HiMKvbrMXk6cQ6I9Vi",
+	"cn2dTf4+fSWu3PR5Zaw22wvT75meNyvDYkfslXbMCscul0LB34xg3AimNFtpI5h0YoUbLqV1Rzv38FY7",
+	"Xk6f60q57T28qlYzgXugWVfc5UupFriluSwdgC6xglROLISZXMMaa274SjgP5Zw7sdBmc1ZsL/dGuMoo",
+	"y7QqN35Fbq1cAFz9DYTP4WASvvm1EmYzySaKr2DlaPp4X3NtVtxNnk4qqdwkm6ykkqtqNXn6KNveczbJ",
+	"K2OEyjf9W1wbmYsGMLy0Gq5+bfSFLETBwgwZc/yjUGxu9AoPQB/C3YSrhRmYNizX6kIYJwp2Kd3Sz+CY",
+	"uMqXXC0EM9wJe8SecyuYVFYoK528EEfvVR8swiliSKy5c8LA6P/7j2fT/8On//zw6evrf5tkCezIe1Dz",
+	"5zX/tRKM/swMgoRQv4XTjB5XOGfrzRyxH6V1gEq5Vk6qSlhm5GLpGJ87YfCDklvnoQMzcHoAGTNiwU1R",
+	"CmubCwCAIOy0YYUohaP9wDQrwZWTK3HEXnJ8GDPBKhvADCMsX9XojM/bauPgUW9tG/+ac6W0g2lyvZpJ",
+	"FabCATW2D94LgDW+lZVUPwq1cMsYI6ObkCovq0KcCpsLVXBPvNq3ckZjbOLl2GrmH4YUNtxHeCrMilLk",
+	"Dd7VL6jvjSU2E5+lEHNelW7ydM5LK+rTzLQuBVd0HCdW59okKM73UpRFOIEReBOiYLPNEXttxFxe0Sb/",
+	"ffrvbI5XjXsAPNKmEOaIndLqTBbwhcwFzQNUnxucifEVUDvAGbbiv2jDKiVdAIs04e1KYbMGPS75BjBi",
+	"tvEv2KNLxsTR4oj98eiPf2Tvzk+ZtPi2YSEx10awRycnJ+zPr//ziL0Ri6rkhq2bXdWny5i4EIpdLmUp",
+	"GGeWlwKmkoqJ+VzkxHRqYtEsgsCAzeMXOHM/3sFqrZv6NyPmk6eT/3bc8MRj+qs9PgtXBPe14lcIy1Ek",
+	"m94CkTl9iW+ZK3iX4teKl4CONZW84GUljthf4X9wKs4KkcsVL5kizpO8IgJCQ2HxBt5PHj0++ubk/aQD",
+	"aJg2XP4uKPeDrgZADyn9w8l//ePR9I8f3r8v/vtXf3j//uj9++J/fPW/02R1JdWNoLkwgrt/EXhKdXvw",
+	"hDm/x+c4DqJLmS8ZfIS8h0tlGwg6ceUSPLbnGPi/Fh3nV4GOP/7mJNtJ14FlbG/6NTASurG+lfHDJNV9",
+	"tFO6gW/P5T/FkLwnSrECeuD5ZeDxDX19dHIysDWcPr29k5PdGwTUeK6LcS8ERxdJ6Wv8TTZL3lRYMsIK",
+	"c8FhnynZ9uwUwMpZNCxsZc3dstmJLCYw2a+VNKKYPHWmEkOSbAJ8F9xIrtzQLvyQjFmn848tOsCBbFwu",
+	"Nb1lEpRA01CkdvTAr1lzr81eh9Eoz/wJ1Iuf/L0LBQjyjwl3eiXzSTaZCetezOfauMmHrQvI6OOf18IQ",
+	"bOH2jF4L46TAyWUvOODo1brgXfHRidUk23mGbCLnP8Ha29O/S0/KliSHojaFqwvlpNswxxc2Y9wT/bP5",
+	"FOf1YvQkcWbc4g5GTpsAdv5G/FoJ6+BDvd71WRucb2Hl6+sYMf8Bk3zYAjyO3FZmkXUx/zXoSyWbg6jX",
+	"4kGZv4Zm3Nmpl8dXwg9cZR6WRALOTidZjSo5rjLJJjTNJJvQ0H58CSDZwhYdzmNT6k/4G+PrdSmJ+Hj5",
+	"8yd+FTNcPWfNVKA5sJkXi+grIAYyR040lwukTkDU9rudCYloZ/TloxNPYsPP9eG5MXyTuMb6qB8auNi1",
+	"VlZsA2bOZSmKIdYRnZdYLRwWyYeHVvIRGWGrMqXWvKE/dGYOAA/oY/xF7gM/mnlyXW/Hwyeb2CrPhSiG",
+	"zxnuPoJfkhzHsA6njJfIAkxj6COv7MJeGKPNrqO9gEH1/aHuWIirhJChgSNqFSBYHyPozQ1IEyRvBOEh",
+	"ktPswzruqsQFv3z79jWjP27tZTdE6XT17ADD516DfSPm2zBMsYF3SoI9o+EGQQceRf6JA27hCV+JxHQp",
+	"uSE6TBE4avscfY/xsA6Dpj4xJHjA9zSmniYLggXq80br+k9wxjFbRvEpIUO7ZWN1i+fdkC1RghApxhKM",
+	"GKeudxDU5hb95uLLfGuEeKWLxGXmS1kWRqjts5y3zTemEN6S4RfZ6wT1BhJ07zfyOLIGVghaL+33KNWv",
+	"g6m2sZ+oWkMIVK5tdc03kR0PDe61kTNY3RtJTumgGSuyUtZzeKONQUla6bYpF7DQm3yZ9KpyByFIzX7m",
+	"eq0FLb2cttWo8fCmUEYi/ZxsMYANRVWKotHma3U99aLDlt9wl9oBniMAg2a85JatqtJJ4o6zeCfzlpkb",
+	"x9skHRm6ST7arhEZwNNLpHXMs/Of2ZPHj/4ny3UhGuu1zIdnhEs915VJ7fxviAVtUz6YUQSSqBtACGfw",
+	"+sWzhB31rVxtrYdy2LqaldIuRbF70fp1wypTJ1citRNbH3mI+uDVeeh0H/baW4JaOjgNjZ93PxvMh6+R",
+	"l+slnwknc7zRNJrThofkvUIuALca/0TAQSuAWDhtSFuWqgHhjQjiAI6paiWMzHfgrR/Vc9oO9P2geOYI",
+	"HpE4cipKeSGGxBHunFitnR2CYhjDVrwQzGo25yYJKFLmPHKPQ8WCdrjfR+Jip8hSz4v2TtdlfN8+SR4A",
+	"xwZ1eFBirweOZcF+Q0halSPH23PueKkX03BNjdlgN5cGT9szuph9QAefvQg6SZfgbRofnr9z5vWcnpnO",
+	"UYLvM/sRznX0hHj2RpD05ipmwkfAkozIhbzoUT7Bu946f4KYxo74eskWCV17T5S/HbkPEa11oyFECVdL",
+	"kILvLsVsqfVHQt8RpsEteaqZoHkJMerWW8ua1x2/zZgwnPcoeD8DgLaBgx4wPymwo0LwgmklLBNXS16h",
+	"TAZ2omjdYOjxk02iJ4//5kXS1NNWibdNGmCIGqbJXkokm5U3a+QcyT38GRXzjBBPumDm4c4ZOasc+WBJ",
+	"2dja20pYm3QC4KZZ+PMuKh7GwX28iAlPABlo42SFA1DBT1568D+dkoHS//RGWKdN9OOFtKIHuJGEaHvt",
+	"aTNux0pajWjOnccSI0oO1nvm9CQbb5X3YhKypqJAawcvX7f2dSOfU9bL3ZLiJ7vUxi0BAfDv4c8Akkhc",
+	"/yg2pNS1gNIsrme/iBytVdUOsS8t7Tm5jc4dw/4YWtXBO7zXAOcP2+jQ9+ZuEx/2v/XDuk87qDRs6Qro",
+	"np2DSueWYkP3XGpeiAIHMG0YL1ZS0e0iQwyBLs9en6XgdROEugV0qU8e74BwaK2N+95P39Cw3F5Msokq",
+	"frFoGVwJA7jmplerMkmaMJjkOaLjjag+YXJBVB8dM1KxllkzsagSl9uzkke+UR1o5o7IQkF1wGC8WQHd",
+	"+ak1dFn0reHDTkYtUgws0rk4/AQv56UE1rBpedraG/mLVAWAkNaH+IELadGwDJo7/Rb+vuamHQT3Uawd",
+	"4/hrxGR4e95LNPU6VdZYMF77X3jXo81Yy0jG5IovhCXDSttDVDQYV/uI4F+m4XpmgOOdrSIHephZG7mQ",
+	"ipeA1SteloifhaxWE5BuzUL0T/V2Wa1misvSbmMpfbqtEbz5MUDOha+bZ15qtRDWMVEQoB+dPH7C1vJK",
+	"lDYtguA+P3ORJ9+dDKxBMPncc3zbv0QHX/vuAGE+grqkLws+/LOeDVgi9lda/SdnwYabUCSHVaxf9Mxr",
+	"VhmgPZrRK6VA2IY/XS43TDrUgWBOY6q1EwWpTrAnz1C4ZYj8OLBS/ILLks9K8V6lNj2XCrnAPged1xAf",
+	"9BrFtzNWH5b4ERx3nMfA6FxYK4o3+nLQWGH0pWX16CFrhRG/YAxlmHF7hHXc7Ika47RCglijE1bK860a",
+	"pQaPR8oMxiLUkTMO9WzRhESShyF1cE9He9E3pXT600e6ZftGOo+is8jWCTvQ76qnLfhE7/3XSlRE6+m1",
+	"wId6tQ7MIDhlU4QAdKma1/TqPE0kbSpa9xTlyohlxeGdUfAu8M91yXNh68uhv66Ecq2Agd2IP+i2irf7",
+	"wZ/SM/sXypnN9gF57tKESZN1LxZCojD4v0+fwYed8HCvk0Dkod1YJ1bvJ41+EiK7jpIUlJBhL6KLn9ie",
+	"+GNbS36zTdsfnTGxWrsNbQwNP/WtXWISCGIPkOEgS0TijxGAiM3kXfnHU+Ig6KCfwxscpFvqypGs5kWa",
+	"UU6/WAJO+Pt0LMQNzbMl9EGYmTA2Kf/9lf7QjirriL4j6HQHPcNy8a4zj4IxDjSXG5AYhazeV8pL91Zc",
+	"JVSfZ6UTRnk9E6x+NbPBLATp4KEasdIX2yFy3TjQbfeTj77YHZeBy0HMulrQv9thu8hWgNfj0zrJvIBv",
+	"rPNfSqIsKy6Vt0HV0ZcnO4Feb7MDy14PwI2AmXyhWrnIjNUJJ6M/MvgqzBQE8P4plwISTBIBKPj79Dzo",
+	"zemInHEczDjphI9C+PvFiu3d2GSMMCg+/bCabVzb7N3vH3EttWdYwmlrSSB2mGFtYjc2XMoiFTfyN/j1",
+	"DXAhJefU19lG57B2jZEe1HSqFmDCs3vNUyaMmgWM4gXdeKwuM1DJhwv5j630x3YSolaNIwb/kjaJXLkv",
+	"nuaYCEZaDIa41yEdifXgV8jRO7lRuNmjnjCkvqj67ZBQ2ryH3bqFoPGMRlwMX0gnpa5zKfTe67S1vQ7W",
+	"gNFBgujn5YfuJAbdx+PF+iiTINpF/SZQTurl694Hnw4isVEMEJqLAMEosQbfvRJtwYw7ttLW4R/WwsQ+",
+	"+lFvDxfdKYH7LYcDDrDZoJ0PXQnZroOvSlEaB8QbiyLrxDM1+XZzgXpg5hPMMmYFN/mSZFqBdllRjLnS",
+	"bDIzghe5qVYzmw7PswPxeQJd2M1v0rpRDP7PD+Pb/nm/CKwWxuwdfeWlf8q3sUt9qZh1pso/Mrc0ulos",
+	"WVHBmwRdaKVhA2nTTN6NhhuER2vwdW0M7XUFtMPfIp2n4wuP8x3Gu8FbC3bXP21+ikGdmkc4vkiyGlSf",
+	"L7b1kyzOC5aqyb0Awnk2n77SSrSyMWw6igiF3PKsXyr8KDbdUERbYSC5gRf24s1rRtpvcoFx4ubovBWU",
+	"GxOmiW15Moo5DUJNLFNq1ZYog9A1Wi5pqxUp4WRn2FQfLqzHRoXuH1eYEfOEgEeuNmxueE7exjpWzLbG",
+	"A3rZbn4lPpcX794gqr2fPPrmJPzyz6//873qPdKtxi72McsXV+tS5tKFpGWpPKuMc6RrXuLpnVDERoBY",
+	"WakWZX05n8Mr67w2m3J90V/aT/qW9+UX6cfSa8+3b5jZLoodue2RHwvVi2kcET31/w/BlNNUVOW0+SFl",
+	"13wlLhsG2SNW7RXQvVc67I3SByDDk/6Ficlk+K0zWECqiC0zR/ubnerwx1fiMk6i2wLMrfGu22QimQ9H",
+	"+ncqV+ENj1rloiVSNW7R6MZCpu7QjR0oWe5msJ+5ULRjyS8EKZDb1LoW/1P0WgGP81QZSfXX+NOfXp76",
+	"0P2bBRJ9HikHl3cnvxnOuqzrnHhFhue5QK9fQ7aP9gmkSr2JtrSWJaK44dXUUmhCObvVAP97gX86v/ym",
+	"YB2A4HBeQbQxvRJerZpSQFJCNQEANmWIMv/vRjfpZJI0tYliqTmaHLnWVpmiTj4BxW3pShUCQk/KOeNQ",
+	"UgW3+k9hNCZES9V3w+0wDh9GhDNDHSGfdI7bJ2Ob322Szb1pUvB7qTlAapgRjRazf604pncP26JIMafD",
+	"p/TroZIJ2cS58lzkWhWD/l5LQ7wzhiwDUT0C0O+lAYr36Bu4iwqjvrYcG7SN7759squSw4hKBADI0bUI",
+	"aiD1hS2OjHumq40u5kMXKUaFdwxpxzFUQUluGOzIpAS6id6F4hsMkdWJmxy93ijFsl26YoR+eWhvKBmU",
+	"MSLCIsKNJsxiP9yOk1yH8PwG6FxMsgROR8EVDTJ1QyO2DzYcvB9hgGVLXRZ0lmRkfq5XK+lCYF0puBVF",
+	"vZl0XMV5K7SvlzYLVfS/jIYXWqfXls0EbDyyssUD+CausSUVFnGUTgR1cb/Q6OxBrGkCl4wbc0cQFWKb",
+	"9NMbRBQnkwfD8h8SSNVrU8/hoEkRyzsHRMJqy5S+nGzXuMs+E0ljcuHlJyL2gLM9KLsnsd+Hfu8e+5vM",
+	"2L1DNG1T6UGkzQIqIvai++U23L80U3/Nk73cpXfkAb2x7zFUp/ocz+OgA7IFzSFb0Eu5WJY9cSdvf/px",
+	"KmzO15i6wRcroVw4EIvGkoYVQjQvtSksuzR8vSZl7X11cvJ1vuLmI/5LYB2pm5aL6kYJgK4/9gy4b4XV",
+	"Mm9nw4anSjK/EaW44Kqr1i7lAui0tGTfMTSq/V51NSsjVCN8TGLBxK/ehUCWvlzECZCFnhW/VNbBTQ5Y",
+	"CkvHd3tueVH46q0wb8aUWISkJx92to+ke5tF4Tie8bMVMYJDDbkv5+4eB6FbVFe0eslVsXu3UtUC9IgN",
+	"1sfZOe9SlOSrS8jtt4Qud6XdBJbpQRpBIYvwApCI8lkP3oGhxOW2E+OEwXu2nRJF5MEY5bSoz38nPorf",
+	"jtm/MYWHCo9OL0igbyrrggT227TioydCjjzm1hF3WKevs0nt8+xN6zbp4is/vD17Nf0uY/j/R4/ZH969",
+	"fv5V+PFr9ocXz159xbTxv3jib7hzA+/fF5++u/4v+N+jx9mjJ9dfJaGv13Whxr6E3+gdj3jG3XKP8A8f",
+	"e88KKltfSVsLmzUZRPyDUE68llyXujITXz272c2jk24OcDa5mi701P9yxdf/oL182O/d+H3c6Omstx8g",
+	"OhTbBuAOXlrhDuI5hYPvelZ7GjE+VonoZ+RuH4VYw+0DMHEnGavIWhp8J1SMJbngyfSPR/9v+uHTo+zb",
+	"JyO8QrCND/FTvAUpqQO3zxCaBglAZ50bG56b70eIXruJwS2998lv9hnfTlRR+tV97ksaZ0mBGZu7hufx",
+	"Nypk0280DkVREjZu/DUcCwfZqN6TF8N8lZyxkUKtgk4DhXiziRW5EQm1+hx/H3LifMAQRb0CnT2K6j2d",
+	"y4XirjIi5NOBXd5CFt2SP/7m2/+FwWQlxJ+iSrAUV0wogHTBXv707Pn0/OWzx998C6d/PyHFvJkbzGDW",
+	"8dUa/yCO6O8zXWzoF+8nROdbYvI3bf76bargRCp749nM6rJygi2dWzNt8P+WQVKHvxeAwuufz9/6vMgm",
+	"qmOtJTwYx0qt1zMO2nIp1cdpqXNeMrKhXnAnQKc2wlovOBlRSCNyPzNMFCC1fayTJ9/twkzK3ogQrb7f",
+	"FoLeYu54G6n3x80b1f9sHsNuauwvesSLTgAv9lXBN1LNdeqpUAVtX+1LzkqK01lxxRfYYIiQuclhlg54",
+	"5OR5zaPrTMbJo6OToxNiI0LxtZw8nXyNv6JKrgjdY76W00Ln+MNCuP4mBeeXfLEQhhU6r1ZCueA6rRMY",
+	"QVOc/CDcs7U8hQmziT8FXejjk5PEeZOTXmeTb2i0zzSCfyLtz3HAMVY1ePppZPeXThHp6y0miQOYaUZk",
+	"E1utVtxs+rd4nSHsji8eHWOBmuMQMzKtC/YMgrNTzpKqbzVFVL2fpDYIk1SDAV9YezWOZAVGyht/V+pO",
+	"WoWMem7mdmCdrJiUgnn7/A3ws8mTkyd3d/evdLKyqK9BpA1aA9sYkb5ClDqq5I37jPfOOmEJRQmOfq06",
+	"DarcHLE3dZEoyr/OmNVUKAl+RxVmcqMtwg8mIVZAUc1SWcdVLmwXqUJJkiiRWRrKVjNiboRdEsdoY9F5",
+	"CouQo/9JF5svhUC+ycP1dbeNyPVBIjHedEFIfIcE7Exd8FIWWxjZQdskHiZIGZKcY2e4Xe6kYzF5wpcT",
+	"kmV8czlmnSxLr4t5JMbsMyNyoVy5qT/A7Isk9fLl/M5qf1bTg/Efh9n1506bIH34zKdwCwm32yh5GudM",
+	"tcj74bD2gMPt/K6+9/BJFtfHHolR4NU2+S5wgPU5ZfQuuG09CxDON8KxdWUWodBdZGjZfgN+UriDP23O",
+	"Tne9Ae8Do1RI8rDRrr9Ij6YPX5ASt7EuQfjggFGls6gD7ou3fLGznEf4NJj4+9vLXt+1YPI2qrUfIyix",
+	"b1vlS+gXdGDvyeN+a78zbGwUv6qoJ8Yu9gI1c5vhZEZyRois213Dsw92LmelVAuSmjrdJZLMpal9NLkL",
+	"Orq7c8U20J/XjjQjxIHT0jzeqy8D4vp6aMFthg8yRhYwXpYbuuem0StmCIkr32RXq8RN0oTPG2/nlxBO",
+	"Ezllo0TTR7e2g602OkPo0laq7kEeDdd3WKhKqIK+62aDSfKEPN9HuYhkzxBBru4GjZHBV4ry+oN47AuA",
+	"1vl9Mmhh7V7GTS9mX4lpG81JrAo3vIcwEAcC3IkQ8CTx6BvExOPdubJfb4DMo5XyW/jjPWwBPI+t6z80",
+	"er6F2Z6RZzs4dvMBh5qnoZ14bwerbST/Xqrit4DiJ4dA1u/9+RyeDJLC2R7THOBG6wMwtiPiS8ecZlyR",
+	"MY2CnKIE7YZWw3Ag+YUwmI1Y1K3ktzG7HdZ1YLh9+/JSOortjq15v12R6eFxR4+bcCn1uGPRrQ4m26lZ",
+	"NmEA9TdeWvOxI3Ur7izWI3OIB0rqkc3Sd6JHdhuJjdEjm5PuoqDb0XktOFONattvBvuPSlQAUBzXuLFQ",
+	"Anh+/ldq+UAFZ4Kr3+hLLPkNQ16d/vn851etUfgLCk5hWvmaWqVU4ggnzHVZrRSp/SFW36v9W6FT5eYp",
+	"aypTsD9ow+zH6qsMR2dxskIIQgGhvg4pwQ+Cu+2rLBSRiTYgF4rK5b7AXcLJpGX4wKmHtkJOAWWxUF1Y",
+	"W+Hrp+Dvw97Y2Sl6fAiKIWLHwpEQMnzBpWKFFhZqYpCHmRUV4Rbkrf8krZVYcoXNSq4+xkfDaBXb+o03",
+	"itWKNtzZEQuUCQtsw+lCgeq6kq91er0O26PNZswKSjqi0ZhkSTWDj9hrXZb+bz4NB2q7V8rJ0leirWtX",
+	"I0Zg8eqUT4o0OaqJPVrlv5qqYvvRbXv1nbhyx1BHf3DcCFb2+PbMoVtF+xOvHJ9e0S4jH9lFf9R5T6uL",
+	"qOJo6+NdRtF74JKA/sghH319d4t/LyljxGnNqAPDQTFIQo6WyYF7QG1T7uNP9I+z4jrilNtqGE26n9Mh",
+	"xp2UrOoXPmCvw5hndlYf8/4UsmgPB66SNa0O2xgyjJrHDfPYKc+BEEAMhiQGoy+ziMtnvvo88FvfX65m",
+	"2RBOwyU2GYEGI8lGGeTcDqyvJRIafXnE3gTuSF7e4NZ7RPlEOeRaBg4JO/UyD+yGODNIMjbF434Qjq75",
+	"TQOL39pTHM1Ku1fbER0eHtjWAzvVlwoiiQKIKLvZC2iAye0nFlSLYROeipnIoGcasDMdlpE6cjPkuA6x",
+	"uM5GjR0zbiVVqLe4eyy/Gj22CSYfMRie1vdYLXrMaBlKLI4YS3WtR42su5+M2oPKy6oQp7Xdyo7cDcXI",
+	"H2b8yVk77qQtAkv1sW89P+wYx1xnk79PX4krN20qvg991B6MX7+FVPtpnfY//HU8+F6Fa0fFFy0QlOhd",
+	"9xgLyjKEzux096q6yGMvSfFKnU9b/zJe3Dix9I49uGNDWg7Qxx85TuEWtznL8WwzDSLXVBbHnxr563o3",
+	"12GQqBLFuJAtIjKF1NYPKLbHbd21xhsR0ByY9iZR5NSLsJudygwED1FHKzQkyfhOjtjZHJYmK1PTyEor",
+	"kdVFSEFaqCMOg/BVNzH34lerFvegon2X5Pj2kHdEPNa5M+AjdHyBqT4gIicqmreSfnyzMctL0ao4bzXc",
+	"iu+XxHhpNeWJcRzKfHy0NkyowoaEm2SddL9r7IFtRNMaK+zMG6i4CVapYQvJ1ylfeAtSbM1ts4mt+uyw",
+	"IIXRelyb3Gcw2vArPVCPoKpj0Tp7HSQDN6lzn1CzGio4qGjtVa74+kOPT7PLatOkFEHm48F9V1p/wuh6",
+	"MXiVl0bwguIVpMso2cRXHOdOr2SOyj3GWuMjzLUKJLFO+St0j4X6LL0Q9wZivzVpWSnmjtW9En2pABs/",
+	"SSC5UuUGdwLG9wbs3VbhkPQXXr+mwn5Nw3t4bMJFhQiQ0PvaTpCu+KIBJEzcih4OsxpR2UAdui5gK4xL",
+	"MKRDEXXumFtgi1Zf50Gb0N01IEO1hhuAv47hJgNNMXZS6TuX8dqlVL/Y2bJxFv+62vy4QOh7UEtI5Dw8",
+	"eRhliip4p3sYzZakTFnAvULxuTOCryyDhFBhpudw85jtWucEBkknw4oqwaS5FoYSjInuA4mECwzNO+hP",
+	"5OaEv1lcpm6DEoaF2c5OkdDSV9ySD7bgjoMPjz6xRHPJw4ofo2U0znKu2+xndX/eDSvkfC5MY2Gir0La",
+	"Ogh5WHZbkQEQiDKluyF7wrMtBTduJrgDYQwzfY7Y81IS38n9pyH+7Edu3RQBOD07rT3OIhcSYn38ZkGo",
+	"lyiI+WBymi6kNxETA7cpkKWar3oYwkkLaf3CnkXZpa7KotlPYjPJXDucEqgE3fkudeWn5r70vOlh58/n",
+	"LzALO/WSsMFGkZQCCLGpWBzXQ6K+MtywVExXbqavmBHw5uBC/sDZpRAfAdF9otJX3jm8LvkmsL6k2tM6",
+	"/qTX1JwucTjS2IznmNKB97Q6E/DrZ+Yf6n0THlj+Dn2dIREdhKFl5fAhFfpSdZOjPZmKCVKK2GGvtZ3E",
+	"rjYkJXvvYb8e0zRZlgaoRFZHIawxQ55EyhCowFGnmnlN3ftEc62stA47gyu+tkvtomppaJRiub0gibdo",
+	"O5H6IkSyJjwkQ33zdR03UpeyCf4mP9YXyiW5lFqvoQnB6nBqEaRJ8tOA+svzj0eM4hdIUPb8phMRsxIG",
+	"bsNNr1Yl3OEPWoO8/pP/NXsulBOGvTk/Z3NBfZ29Jup1W0O+L5SJcyKqFBNk2eKfck1VNxDcf4MhdMEY",
+	"qREq8tPbgxP6ZvFkv8lbNJ3PNP3JH7rw3gyGZYlDFAjEoDTN/uMxRthq5Vt5cYp16aOBoRCb7w6ZoLov",
+	"8BCjskyppX8TMOMDUFJ5nXV/+H7FbzgXGeam9VKZpUPHzcK9AM2QqhK2Jvc9myXgTPZy//1L+mU+34Oy",
+	"nxlubFBS65tVeWsxTFud0oje1Gb9+3RExA6IQxL/CUokMVM5Wc8+EhyQOo/ulU2vsAKlaUXokSliXpXl",
+	"FK6WSkPXCfVUIzgIr/8Bf0NDFe7yUsx8/1NmN8rxK1/i79dKw02vl4aHUkJU41hcIVI3jdyO2E+tIsjc",
+	"iJ2FkOEPtYF+GSoM26AJwBQYPnkprWBx/eWm1EVtUVEUccEtDkzKzXi+cRS8DcMeevjrWIPd7oKq19kX",
+	"8Il/SVt/VK09KSDCX5EBl87ef9w8Xhw8FxQDcamDJRwEuxBvnqAWu9IOKQvQet9IJ0gDu1kVoRz92Wmw",
+	"VRc9yYQ3rirgd/dFElKyPo9cbYQPdjBxtSZ9m8p80KaKAZfbbm/bhzGlq86orj2thqGoj+8Q64MBcaUL",
+	"OZeiYFaqvPFQtfxJsSvpQJMMVatUQDYqPGlEdFLj+r0BemN71TvG7gd/84O/+UD9zQft1B3I8Xy3LnpC",
+	"j8YzyqYa/Y0oCTkHDoxR0qZugVF+qbTRQ3dcNgDct+qQ/3Kkr+1Brrh5rqjqK0EUlfRqVyMaLuHYjG21",
+	"nKZqH1TJN1U4EcDaqjM0nn78pvK92+fc6/H2sawI4vdWXrFOjS60IFELUyTvPhsBAHKgeQjPfMGbiO3F",
+	"pU3ST28prdNms7tYLqXRilybQhRe5mo3zNJlIay3fZFPqA4eCkIa+p8hnKeAj8jStRTqiL2kbWwH80gb",
+	"eW+c1hmJeXXZ1I33b0dl/AxPl0/1iRJ+qUMhAXcRte+P/EI5qmowJnKfecy4x9S6A35r4Vn4d7Bs0Ddg",
+	"zPB7Ow5uz50Prx0aGFxoiPmYD8cdWxt9IeFVOrkSA5h/Hta8f9TfLs+KJfelwjO0OHttu+YAhR4rMXfj",
+	"9jPUYfNwtOh7eWmFLBrGyrRp10x1zC3hPwC6g1ZFOaW4snWMUH2vUa74YmQJTBoaq5VR/mmI3sp83ICx",
+	"aBwC9IVfrLhUNEFv+t4Z7eR3xJTwxHvlk9ENPPCjgVewhaX9CWHPioJeDXxCD1wwoTBSe2uaIwZdNJra",
+	"FPCJBPLgyKDhyyta5mGRsT+/fvFDxl6/+gGlvB/Ovg+TciPq4jZH7O2yWs0UBq1IxeyKl2XGVqKQ1Qo/",
+	"xCoPvgka1pUp2rvImihAKHUnrGOiIMn00bcnGXvy3QnO8+jk8RO2lleitEfsmX/BYA3Ehg7ckQP38Ynf",
+	"5UCpk4C6h8hFn5UYJ0VtXcGzGoOqj3WW7q24cpMht+qNrU46dyIdBFgfbiYVN5tknybc9vFCzm/66S9r",
+	"sbjpt2u196d3nsXYoaE9NLMvB3dcGZpuUfx7j0Z/p3xEBZbYgfNh0gKoLJxKw6y42jDP2++fS9xphZyz",
+	"QJoPtETOuzWVSqgZz26ViS4SapLwhTgbWQ1YBfZAfiSspVUztUaTMoLZpZwjKzpzFkM+iUXVZgjyTUDY",
+	"58LAlR4NOPER+ns7KO6IOTRrxtygsyjB+A4qEQfKdD9liGn1gxXm2ki85ZRPu9gfkO8uemHsy3IfMLtH",
+	"WW9jdtLpcy4cKN/bMm39PdaSiooFY4porYz7Llw76H6TR7Xm1jWB3LQCzB5NLlRPSmnBH1jAnXi3/AO8",
+	"N6/0jQjAfSRrrvjigfyk+69t0RSsLhtSMPV8i0CNk0yPP4GhYnedFcq2lBAlW8YqDPzFm0NalhE9R3NK",
+	"bEGJfs6Yaw1tUT0YC0YY+vGIfY8CrhIXwnjnxVaYd4iAU4WYSyWdKDcDTgW8cJj1d03vtlaGIPKk9aW9",
+	"uKUWdzdLTELQU7T6CLHnN2hMSZGWuu7sA1XrCFUAmRbtwsQP5JMYRfTPXjcIdSUd0TA37nlruwVZfBHu",
+	"ulx5u2j7UG9coCOvaQ+/EwdISLDb6fTAgayUmF304PjoRf51A6eO82NHQ+C9UBoqz8QfMMc/Clhc5KIQ",
+	"KhdMA2Olv/mevyF9q9vjeClUx88dniqanLhr3ky6F/DhvZovI/DTGb+gxP8FX+w9dyMmRIx6JTzQjpQ6",
+	"0EM80swS1i6qUhTTkWwTrAoZ4zlqG9RsIteUlh+mSpGfiHliIkOSc56HGX5f7LN97H2CCM67IH/gqkM1",
+	"4xMIujOwIIA4vKsOT+U1MWJzbRiniK9LqQp96ROi10avNHUixUweKHEBwq1bhoFoobNZd4NNRb3k0m2u",
+	"SnUzaD6LjLvka4rgoa8pQdrXzui0TJcWk4iOGJH7UCAFnrf1nWJC+RqgK9YXLbLdeiO4Un+0QRvR/3X5",
+	"fPdB30td4D6q0sfja+S7f/5OWPxAw+IU720ytCdnP/5kWxix5XtOuYHbSHTgjoAu/Uyv3oXCHXiHz7fo",
+	"+v34ibv7OHSP8RZDHLRfW6fzjzvlVxzFSnEhyghRsYAtsjhoq2Ykh4KHwFD9D2eFL+Oalltx5YN4GDuy",
+	"rOvjfOFSHwCQQUzEW7hfaVWb+qoPvtlRCmcH38ExL36prFuFoqA9bRR8sjn1GIK5KwVPAIvBqYKKMZaO",
+	"e0mW8pQKATUzqTgKruX4R6zc9q71rW9oWxi9ZjNR6sutVZaixCXWQmHivBFWmAsEczJk9Rke6VAe25eS",
+	"HeF4z+rLuyfX8I0e8D2IjA2WHxYNudMW8HQXl1gZNnpu9JwElDLiykm3ObAET7y6fYmbh/a4LJcwuCfP",
+	"JdXc4wfh/hqW+J2Yn/x597E7BRA92JuG2HYC/XYlsIRvqFW8T1Y5/8s7bBNN8Q8V9ZDwkbyh9AhwXI1z",
+	"sVyvZr5iWeuLtaDA8n77jL/Wf13mWqP6vVhkth5a78O6f8bq8fAQEg7ulJX+5R1qguvm9dSdI6woDrZ9",
+	"Vn1dw1zz+FOtAI4w/nhkPHSrz0VNtRKr1ue9AzNP83jvx7zz10PXZoNZJzC53ZkADyh4J6kAN+ZMD9i9",
+	"lQywjd3rKoHdFGP/gN+HKPKdPIh89/2wH6S+3WH/XUrTlf2eYp+SfuvvMziBsKwmTE1LFOiQEbrxrXQh",
+	"mJAYPAhWlWg4QgpnKZg2vuUdpCtTqMKKGnhkTFH5EJx1Jqx7MZ9r4/zMZEsOk2KNrDClQoeMvlSo38Jk",
+	"+FsRGgByLJ1KGQiwP4uNR6rS+cZP3Ynr6jsYw/jy7dvX2GuwwoQrMqBRBQXb9CT0RCmlNf8J4DuqOvxL",
+	"fYnbrwyFnXRgCAbzEuIvTqn1kQf9UU+RA4DbZGxcPW7yJ/jii9FYXOKeKKxfu/8xvfEV5VtQ38JvRGyP",
+	"tFSJinAQ+1N2UdbqlahH5w3aEIICptwXDccX7701iON1AYHm7Li5x4/v7gZ+rp+gngegz6jxBAIMc3aQ",
+	"RGCnRgL8EUtenA9jwisChuFHs5nIeWUF+XDpNvzLfvL4ib+Rg2v0l0Vt/gqfox96dgDaVeXHFlX3gRwh",
+	"4nK3rzsV8Fb3xIoCwnxV6WBUnFeuMiLbDtpkzyju00+HGMaxuyl8DZTMd64pstD6z0ePe0cjFB6og8U3",
+	"ffmnPwj3zp+xLxL04Dpf3Gek5+FpQXVQcOv6W8gce3v7ZZSXGuoFeq/0ULiGJ3oQhImNv6qG5ORLkX/U",
+	"lUOSEtYMzXghRVCvqGcvm4m5NpggLa7W0sALbbrJ0CZmIgfq35Tx5AsuVb9NPVryC/XkjVa4J8N2aweD",
+	"nLiG/r1rOxH6/Y5dx680kG5dLZYRRlMg1YHRFbgtYcPeeuhI3eim16AY4WDa6LKDWUSrfekgqs9+VXeI",
+	"0fEmDt5AF91hQnXewqdj4g/9PCoYtuuAC+IUWEUQ8dXzJq1yEfUmlpZRQtV2FMJzXLHNOh7w9Evi6Z1S",
+	"3XgXvtdOiIHTppE+Ds3diEjZeUA7no4RpeBW9L+dnzDltPNyOsJV/HyCLEfNXrkqtEo9oDe07sMLenhB",
+	"B8WCECuHntClmC21/mh7hZgfhPtbGHMXKqtfbB9dNezvwHXUGtS9MVlvxEJazISDGqJOe5OJ72mv51Gt",
+	"+s06lKf5+fwtWK9e+BYTqJABjvqOLk2bf5Eb4TJmhWB/nz6nOK7puVwoDtYX3wfniH1PVm8fdS39MkY4",
+	"I5tkfIKQ5CVaWPR8XtcSK6izIi9YKRyeBekomIG4c2K1BkkFSvT0arD+Or+Q9lrj171orlvY3YvN96+x",
+	"enw9sLy0agZjZ0jU/A4B4+iNJEnbMWDj1GPjThtmhPfeehnmCa/xaskrrDQR43QGYVF1A5dknO+p4MWP",
+	"fhf/IvbFU5+asQ+1Pm0AfNj0OiZiacT65P+1o6wulFyusRazsOmzDO2JQY7oENzC6PVa9NbLbYjkmCCO",
+	"sGI6oKI+xR3ErAXqFjeevUtxMqx/oEaD0Bi5oW27Y9b8kfaI6bkndDi5fzb6gGnb5qkOpu0gdMcNndqT",
+	"l3ox1peCDauiQKkrlzc+bmmohKznrSNYq4d1w1oO6SFkfeDBPrkRjBAUtYhP7uSeiIz6j+NQJfDpc/rs",
+	"MHv537XE8UANUjJPS/ptdIDxVOH4k//35gwNcv6nfpNcU2rCD8XgLhK/wlTec+opgijIUMeMXCwd45d8",
+	"E3WyuVzqUkTJv7XaWZv5Urrnm7DRgGEHTULC4kWz2cTqzUUcLivfftC9D3hz/89XR+no92eNrOFR9zn3",
+	"SsShERUPKyxZj+w/IiSPj2uCP5xYq3z8EPUnjXIQLw0HFcnzTb7wSYhsJRwvuOMQPpSLtSOly/KVYM2L",
+	"ZtyyVvTqQJVm+zrZserWOejucSupQinF3WP51eixGDbzXBejBgN1+R5Ldo0ZDcA918aNGZtXxupRs/re",
+	"vEDaxuyBosROhc2FKjhaikbtxpd9/NJtMRC/egI3LKB2bxcqqT72LeCHHeOY62zy9+krceWmzwnGOz5q",
+	"D8av32rHy+lzXSm3++t48PX1fZkwqbBchkXlgHZHRCJ6yz01cX1/JaJSMAaOV7p/DrlKXtKINLp023OY",
+	"C5ljtymcd7npbATnWjKhCuzGSedEv6WnP5UpJ08nx5PrD9f/fwB2Lx6sSj8BAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
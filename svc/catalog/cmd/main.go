@@ -16,6 +16,16 @@ func main() {
 
 	a := app.NewApp()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if len(os.Args) != 3 {
+			log.Fatalln("usage: catalog migrate up|down|status")
+		}
+		if err := a.Migrate(ctx, os.Args[2], os.Stdout); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
 	go func(a *app.App) {
 		if err := a.Run(); err != nil {
 			log.Println(err)
@@ -4,29 +4,147 @@ import (
 	"context"
 	"fmt"
 	"github.com/deepmap/oapi-codegen/pkg/middleware"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/konrad945/eCommerce/svc/catalog/api"
 	"github.com/konrad945/eCommerce/svc/catalog/internal/handler"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/importer"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/media"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/outbox"
 	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/webhook"
 	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/jaeger"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
 	"time"
 )
 
+const (
+	storeBackendPostgres = "postgres"
+	storeBackendMemory   = "memory"
+
+	eventsPublisherStdout = "stdout"
+	eventsPublisherFile   = "file"
+
+	// importsPath is where files are uploaded to be imported
+	importsPath = "/api/v1/imports"
+	// exportPath is where the whole catalog is exported
+	exportPath = "/api/v1/items/export"
+
+	// mediaDeletionBatchSize is number of removed images whose files are deleted at once
+	mediaDeletionBatchSize = 100
+)
+
+// imagesPathPattern matches paths images of items are uploaded to
+var imagesPathPattern = regexp.MustCompile(`^/api/v1/items/[^/]+/images$`)
+
+func init() {
+	// imported files are validated by the importer row by row, the request validator only checks they are present
+	openapi3filter.RegisterBodyDecoder("text/csv", openapi3filter.FileBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("application/x-ndjson", openapi3filter.FileBodyDecoder)
+	// type of uploaded images is detected from their content by the media library
+	for _, contentType := range []string{"image/jpeg", "image/png", "image/gif", "application/octet-stream"} {
+		openapi3filter.RegisterBodyDecoder(contentType, openapi3filter.FileBodyDecoder)
+	}
+}
+
+var (
+	_ catalogStore = (*store.CatalogStore)(nil)
+	_ catalogStore = (*store.MemoryStore)(nil)
+)
+
+// catalogStore is a set of methods which every store backend has to provide
+type catalogStore interface {
+	handler.CatalogStore
+	trashPurger
+	reservationReleaser
+	eventRelayer
+	eventPruner
+	webhook.Store
+	importer.Store
+	mediaDeleter
+	CreateDeliveries(ctx context.Context, event store.Event, payload string) (int64, error)
+}
+
 type config struct {
-	Port              int    `envconfig:"HTTP_PORT" default:"8080"`
-	JaegerHost        string `envconfig:"JAEGER_HOST" default:"http://localhost"`
-	JaegerPort        int    `envconfig:"JAEGER_PORT" default:"14268"`
-	DbCredentialsPath string `envconfig:"DB_CREDENTIALS_PATH" default:"/vault/secrets/db-creds"`
+	Port              int           `envconfig:"HTTP_PORT" default:"8080"`
+	JaegerHost        string        `envconfig:"JAEGER_HOST" default:"http://localhost"`
+	JaegerPort        int           `envconfig:"JAEGER_PORT" default:"14268"`
+	StoreBackend      string        `envconfig:"STORE_BACKEND" default:"postgres"`
+	DbCredentialsPath string        `envconfig:"DB_CREDENTIALS_PATH" default:"/vault/secrets/db-creds"`
+	DbMigrateOnStart  bool          `envconfig:"DB_MIGRATE_ON_START" default:"true"`
+	DbQueryTimeout    time.Duration `envconfig:"DB_QUERY_TIMEOUT" default:"5s"`
+	TrashRetention    time.Duration `envconfig:"TRASH_RETENTION" default:"720h"`
+	TrashPurgePeriod  time.Duration `envconfig:"TRASH_PURGE_PERIOD" default:"1h"`
+	// ReservationReleasePeriod is how often stock held by expired reservations is made available again
+	ReservationReleasePeriod time.Duration `envconfig:"RESERVATION_RELEASE_PERIOD" default:"1m"`
+	// Currencies is a comma separated list of ISO 4217 codes items can be priced in, all currencies when empty
+	Currencies []string `envconfig:"CURRENCIES"`
+	// ExchangeRatesPath is a JSON file with exchange rates loaded on start. They replace stored rates which were
+	// published earlier, prices are not converted until rates are loaded or set.
+	ExchangeRatesPath string `envconfig:"EXCHANGE_RATES_PATH"`
+	// ExchangeRatesRefreshPeriod is how often rates set through other instances are loaded from the store
+	ExchangeRatesRefreshPeriod time.Duration `envconfig:"EXCHANGE_RATES_REFRESH_PERIOD" default:"1m"`
+	// EventsPublisher is where domain events are published to, stdout or file
+	EventsPublisher string `envconfig:"EVENTS_PUBLISHER" default:"stdout"`
+	// EventsFilePath is a file events are appended to as JSON lines by file publisher
+	EventsFilePath string `envconfig:"EVENTS_FILE_PATH" default:"events.ndjson"`
+	// OutboxRelayPeriod is how often pending events are relayed from the outbox to the publisher
+	OutboxRelayPeriod    time.Duration `envconfig:"OUTBOX_RELAY_PERIOD" default:"1s"`
+	OutboxRelayBatchSize int           `envconfig:"OUTBOX_RELAY_BATCH_SIZE" default:"100"`
+	// OutboxRetention is how long published events are kept in the outbox, e.g. to be replayed to event streams
+	OutboxRetention   time.Duration `envconfig:"OUTBOX_RETENTION" default:"168h"`
+	OutboxPrunePeriod time.Duration `envconfig:"OUTBOX_PRUNE_PERIOD" default:"1h"`
+	// WebhookDeliveryPeriod is how often due deliveries of events are POSTed to webhooks
+	WebhookDeliveryPeriod time.Duration `envconfig:"WEBHOOK_DELIVERY_PERIOD" default:"5s"`
+	// WebhookMaxAttempts is number of attempts after which delivery is moved to dead letters
+	WebhookMaxAttempts int           `envconfig:"WEBHOOK_MAX_ATTEMPTS" default:"8"`
+	WebhookBackoff     time.Duration `envconfig:"WEBHOOK_BACKOFF" default:"10s"`
+	WebhookMaxBackoff  time.Duration `envconfig:"WEBHOOK_MAX_BACKOFF" default:"1h"`
+	WebhookTimeout     time.Duration `envconfig:"WEBHOOK_TIMEOUT" default:"10s"`
+	WebhookBatchSize   int           `envconfig:"WEBHOOK_BATCH_SIZE" default:"20"`
+	// ItemsBatchMaxSize lowers maximal number of operations in items batch set in openapi.yaml when not zero
+	ItemsBatchMaxSize uint64 `envconfig:"ITEMS_BATCH_MAX_SIZE"`
+	// ImportPeriod is how often queued import jobs are looked for
+	ImportPeriod time.Duration `envconfig:"IMPORT_PERIOD" default:"5s"`
+	// ImportLease is how long running import job is not claimed by other instances after its progress was recorded
+	ImportLease       time.Duration `envconfig:"IMPORT_LEASE" default:"1m"`
+	ImportBatchSize   int           `envconfig:"IMPORT_BATCH_SIZE" default:"100"`
+	ImportMaxAttempts int           `envconfig:"IMPORT_MAX_ATTEMPTS" default:"5"`
+	ImportRetryDelay  time.Duration `envconfig:"IMPORT_RETRY_DELAY" default:"1m"`
+	// ImportMaxSize limits size of uploaded import files, e.g. 32M
+	ImportMaxSize string `envconfig:"IMPORT_MAX_SIZE" default:"32M"`
+	// MerchantItemURL is a template of item page links in merchant feed with {id} placeholder, links are not set
+	// when empty
+	MerchantItemURL string `envconfig:"MERCHANT_ITEM_URL"`
+	// MediaDir is a directory images of items and their thumbnails are kept in
+	MediaDir string `envconfig:"MEDIA_DIR" default:"media"`
+	// MediaBaseURL is prepended to paths of image files, e.g. to serve them through CDN, they are relative when empty
+	MediaBaseURL string `envconfig:"MEDIA_BASE_URL"`
+	// MediaMaxSize limits size of uploaded images, e.g. 10M
+	MediaMaxSize string `envconfig:"MEDIA_MAX_SIZE" default:"10M"`
+	// MediaMaxDecodes limits number of uploaded images decoded at once to make thumbnails, as every one of them can
+	// take up to 200MB of memory
+	MediaMaxDecodes int `envconfig:"MEDIA_MAX_DECODES" default:"2"`
+	// MediaDeletionPeriod is how often files of removed images are deleted
+	MediaDeletionPeriod time.Duration `envconfig:"MEDIA_DELETION_PERIOD" default:"1m"`
 }
 
 type App struct {
 	e *echo.Echo
+	// events are streamed to clients of item events endpoint
+	events *outbox.Broker
 }
 
 // NewApp setups an App struct
@@ -36,7 +154,8 @@ func NewApp() *App {
 	e.HidePort = true
 
 	return &App{
-		e: e,
+		e:      e,
+		events: outbox.NewBroker(),
 	}
 }
 
@@ -44,9 +163,9 @@ func NewApp() *App {
 func (a *App) Run() error {
 	logger := logrus.New()
 
-	var conf config
-	if err := envconfig.Process("", &conf); err != nil {
-		return fmt.Errorf("error while processing env variables: %w", err)
+	conf, err := loadConfig()
+	if err != nil {
+		return err
 	}
 
 	tp, err := traceProvider(conf)
@@ -70,23 +189,258 @@ func (a *App) Run() error {
 	if err != nil {
 		return fmt.Errorf("error while getting swagger documentation: %w", err)
 	}
+	if err := limitBatchSize(swagger, conf.ItemsBatchMaxSize); err != nil {
+		return err
+	}
+	// uploads are limited before the validator, which reads whole request body
+	a.e.Use(limitUploads(conf.ImportMaxSize))
+	a.e.Use(limitImageUploads(conf.MediaMaxSize))
+	a.e.Use(compressExports())
 	a.e.Use(middleware.OapiRequestValidator(swagger))
+	a.e.Use(handler.ActorMiddleware)
 
-	cStore, err := store.NewCatalogStore(conf.DbCredentialsPath)
+	currencies, err := money.NewCurrencySet(conf.Currencies...)
 	if err != nil {
-		return fmt.Errorf("error while create store: %w", err)
+		return fmt.Errorf("error while loading accepted currencies: %w", err)
+	}
+	cStore, err := newStore(ctx, conf, logger)
+	if err != nil {
+		return err
 	}
+	exchange, err := newExchange(ctx, conf, cStore)
+	if err != nil {
+		return err
+	}
+
+	go runPeriodically(ctx, conf.ExchangeRatesRefreshPeriod, refreshRates(cStore, exchange, logger))
+
+	go runPeriodically(ctx, conf.TrashPurgePeriod, purgeTrash(cStore, conf.TrashRetention, logger))
+	go runPeriodically(ctx, conf.ReservationReleasePeriod, releaseReservations(cStore, logger))
+
+	publisher, closePublisher, err := newPublisher(conf)
+	if err != nil {
+		return err
+	}
+	defer closePublisher()
+	publisher = outbox.NewMultiPublisher(publisher, webhook.NewDispatcher(cStore))
+	go runPeriodically(ctx, conf.OutboxRelayPeriod,
+		relayEvents(cStore, publisher, a.events, conf.OutboxRelayBatchSize, logger))
+	go runPeriodically(ctx, conf.OutboxPrunePeriod, pruneEvents(cStore, conf.OutboxRetention, logger))
+
+	worker := webhook.NewWorker(cStore, webhook.NewClient(), webhook.Config{
+		MaxAttempts: conf.WebhookMaxAttempts,
+		Backoff:     conf.WebhookBackoff,
+		MaxBackoff:  conf.WebhookMaxBackoff,
+		Timeout:     conf.WebhookTimeout,
+		BatchSize:   conf.WebhookBatchSize,
+	}, logger)
+	go runPeriodically(ctx, conf.WebhookDeliveryPeriod, worker.Deliver)
+
+	importWorker := importer.NewWorker(cStore, currencies, importer.Config{
+		Lease:       conf.ImportLease,
+		BatchSize:   conf.ImportBatchSize,
+		MaxAttempts: conf.ImportMaxAttempts,
+		RetryDelay:  conf.ImportRetryDelay,
+	}, logger)
+	go runPeriodically(ctx, conf.ImportPeriod, importWorker.Import)
+
+	mediaStorage, err := media.NewFileStorage(conf.MediaDir)
+	if err != nil {
+		return err
+	}
+	library := media.NewLibrary(mediaStorage, conf.MediaMaxDecodes)
+	go runPeriodically(ctx, conf.MediaDeletionPeriod, deleteMedia(cStore, library, mediaDeletionBatchSize, logger))
 
-	api.RegisterHandlers(a.e, handler.NewHandler(logger, cStore))
+	h := handler.NewHandler(logger, cStore, currencies, exchange, a.events).WithItemURL(conf.MerchantItemURL).
+		WithMedia(library, conf.MediaBaseURL)
+	api.RegisterHandlers(a.e, h)
 
 	return a.e.Start(fmt.Sprintf(":%d", conf.Port))
 }
 
-// Shutdown performs graceful shutdown
+// Shutdown performs graceful shutdown. Event streams are ended first, as server waits for all requests to finish.
 func (a *App) Shutdown() error {
+	a.events.Close()
 	return a.e.Shutdown(context.Background())
 }
 
+// Migrate runs schema migration command (up, down or status) and writes its outcome to out
+func (a *App) Migrate(ctx context.Context, command string, out io.Writer) error {
+	conf, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if conf.StoreBackend != storeBackendPostgres {
+		return fmt.Errorf("migrations are supported only by %s store backend", storeBackendPostgres)
+	}
+
+	cStore, err := store.NewCatalogStore(conf.DbCredentialsPath, conf.DbQueryTimeout)
+	if err != nil {
+		return fmt.Errorf("error while create store: %w", err)
+	}
+	migrator, err := cStore.Migrator()
+	if err != nil {
+		return fmt.Errorf("error while creating migrator: %w", err)
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range applied {
+			fmt.Fprintf(out, "applied %04d_%s\n", m.Version, m.Name)
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+	case "down":
+		m, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "rolled back %04d_%s\n", m.Version, m.Name)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(out, "%04d_%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected one of: up, down, status", command)
+	}
+	return nil
+}
+
+// newStore creates catalog store backend selected in config
+func newStore(ctx context.Context, conf config, logger *logrus.Logger) (catalogStore, error) {
+	switch conf.StoreBackend {
+	case storeBackendMemory:
+		logger.Warnf("using in-memory store, data will be lost on shutdown")
+		return store.NewMemoryStore(), nil
+	case storeBackendPostgres:
+	default:
+		return nil, fmt.Errorf("unknown store backend %q, expected one of: %s, %s", conf.StoreBackend, storeBackendPostgres, storeBackendMemory)
+	}
+
+	cStore, err := store.NewCatalogStore(conf.DbCredentialsPath, conf.DbQueryTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("error while create store: %w", err)
+	}
+
+	if conf.DbMigrateOnStart {
+		migrator, err := cStore.Migrator()
+		if err != nil {
+			return nil, fmt.Errorf("error while creating migrator: %w", err)
+		}
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error while migrating db: %w", err)
+		}
+		for _, m := range applied {
+			logger.Infof("applied migration %04d_%s", m.Version, m.Name)
+		}
+	}
+	return cStore, nil
+}
+
+// newExchange creates exchange with rates kept in the store. Rates loaded from file set in config are stored first
+// when they were published later than stored ones.
+func newExchange(ctx context.Context, conf config, s catalogStore) (*money.Exchange, error) {
+	stored, err := s.GetExchangeRates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while loading exchange rates: %w", err)
+	}
+	if conf.ExchangeRatesPath == "" {
+		return money.NewExchange(stored), nil
+	}
+	rates, err := money.LoadRates(conf.ExchangeRatesPath)
+	if err != nil {
+		return nil, fmt.Errorf("error while loading exchange rates: %w", err)
+	}
+	if stored.Base != "" && !rates.UpdatedAt.After(stored.UpdatedAt) {
+		return money.NewExchange(stored), nil
+	}
+	if err := s.SetExchangeRates(ctx, rates); err != nil {
+		return nil, fmt.Errorf("error while loading exchange rates: %w", err)
+	}
+	return money.NewExchange(rates), nil
+}
+
+// newPublisher creates publisher of domain events selected in config and function releasing its resources
+func newPublisher(conf config) (outbox.Publisher, func(), error) {
+	switch conf.EventsPublisher {
+	case eventsPublisherStdout:
+		return outbox.NewWriterPublisher(os.Stdout), func() {}, nil
+	case eventsPublisherFile:
+		publisher, f, err := outbox.NewFilePublisher(conf.EventsFilePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return publisher, func() { f.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown events publisher %q, expected one of: %s, %s", conf.EventsPublisher,
+			eventsPublisherStdout, eventsPublisherFile)
+	}
+}
+
+// limitBatchSize lowers maximal number of operations in items batch validated against swagger, unless size is zero
+func limitBatchSize(swagger *openapi3.T, size uint64) error {
+	if size == 0 {
+		return nil
+	}
+	schema := swagger.Components.Schemas["BatchRequest"]
+	if schema == nil || schema.Value.Properties["operations"] == nil {
+		return fmt.Errorf("error while limiting batch size: operations of batch request are not documented")
+	}
+	operations := schema.Value.Properties["operations"].Value
+	if operations.MaxItems != nil && *operations.MaxItems < size {
+		return fmt.Errorf("batch size cannot exceed %d set in openapi.yaml", *operations.MaxItems)
+	}
+	operations.MaxItems = &size
+	return nil
+}
+
+// limitUploads rejects files uploaded for import which are larger than maxSize, e.g. 32M
+func limitUploads(maxSize string) echo.MiddlewareFunc {
+	return echomiddleware.BodyLimitWithConfig(echomiddleware.BodyLimitConfig{
+		Skipper: func(c echo.Context) bool { return c.Request().URL.Path != importsPath },
+		Limit:   maxSize,
+	})
+}
+
+// limitImageUploads rejects images uploaded to items which are larger than maxSize, e.g. 10M
+func limitImageUploads(maxSize string) echo.MiddlewareFunc {
+	return echomiddleware.BodyLimitWithConfig(echomiddleware.BodyLimitConfig{
+		Skipper: func(c echo.Context) bool {
+			return c.Request().Method != http.MethodPost || !imagesPathPattern.MatchString(c.Request().URL.Path)
+		},
+		Limit: maxSize,
+	})
+}
+
+// compressExports compresses exports for clients which accept gzip encoding
+func compressExports() echo.MiddlewareFunc {
+	return echomiddleware.GzipWithConfig(echomiddleware.GzipConfig{
+		Skipper: func(c echo.Context) bool { return c.Request().URL.Path != exportPath },
+	})
+}
+
+func loadConfig() (config, error) {
+	var conf config
+	if err := envconfig.Process("", &conf); err != nil {
+		return config{}, fmt.Errorf("error while processing env variables: %w", err)
+	}
+	return conf, nil
+}
+
 func traceProvider(conf config) (*trace.TracerProvider, error) {
 	exporter, err := jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(fmt.Sprintf("%s:%d/api/traces", conf.JaegerHost, conf.JaegerPort))))
 	if err != nil {
@@ -0,0 +1,217 @@
+package app
+
+import (
+	"compress/gzip"
+	"github.com/deepmap/oapi-codegen/pkg/middleware"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLimitBatchSize(t *testing.T) {
+	swagger, err := api.GetSwagger()
+	require.NoError(t, err)
+	schema := swagger.Components.Schemas["BatchRequest"].Value
+	batch := func(size int) map[string]interface{} {
+		ops := make([]interface{}, size)
+		for i := range ops {
+			ops[i] = map[string]interface{}{"op": "delete", "id": float64(i + 1)}
+		}
+		return map[string]interface{}{"operations": ops}
+	}
+
+	require.NoError(t, limitBatchSize(swagger, 0))
+	assert.NoError(t, schema.VisitJSON(batch(1000)), "limit of openapi.yaml is kept")
+	assert.Error(t, schema.VisitJSON(batch(1001)))
+
+	require.NoError(t, limitBatchSize(swagger, 2))
+	assert.NoError(t, schema.VisitJSON(batch(2)))
+	assert.Error(t, schema.VisitJSON(batch(3)))
+
+	assert.Error(t, limitBatchSize(swagger, 5000), "limit of openapi.yaml cannot be raised")
+}
+
+func TestImportUploads(t *testing.T) {
+	swagger, err := api.GetSwagger()
+	require.NoError(t, err)
+	e := echo.New()
+	e.Use(limitUploads("64B"))
+	e.Use(middleware.OapiRequestValidator(swagger))
+	var uploaded string
+	e.POST(importsPath, func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		uploaded = string(body)
+		return c.NoContent(http.StatusAccepted)
+	})
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "CSV file",
+			contentType:    "text/csv",
+			body:           "externalId,name,price,priceCode\n",
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "NDJSON file",
+			contentType:    "application/x-ndjson",
+			body:           `{"externalId":"SUP-1"}`,
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "Too large file",
+			contentType:    "text/csv",
+			body:           strings.Repeat("a", 65),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Unsupported content type",
+			contentType:    "application/xml",
+			body:           "<items/>",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			uploaded = ""
+			req := httptest.NewRequest(http.MethodPost, importsPath, strings.NewReader(test.body))
+			req.Header.Set(echo.HeaderContentType, test.contentType)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, test.expectedStatus, rec.Code, rec.Body.String())
+			if test.expectedStatus == http.StatusAccepted {
+				assert.Equal(t, test.body, uploaded, "file is passed on by the validator")
+			}
+		})
+	}
+}
+
+func TestImageUploads(t *testing.T) {
+	swagger, err := api.GetSwagger()
+	require.NoError(t, err)
+	e := echo.New()
+	e.Use(limitImageUploads("1K"))
+	e.Use(middleware.OapiRequestValidator(swagger))
+	e.POST("/api/v1/items/:id/images", func(c echo.Context) error {
+		return c.NoContent(http.StatusCreated)
+	})
+	e.PUT("/api/v1/items/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "PNG image",
+			method:         http.MethodPost,
+			path:           "/api/v1/items/1/images",
+			contentType:    "image/png",
+			body:           "\x89PNG\r\n\x1a\n",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Image of unknown type",
+			method:         http.MethodPost,
+			path:           "/api/v1/items/1/images",
+			contentType:    "application/octet-stream",
+			body:           "\x89PNG\r\n\x1a\n",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Too large image",
+			method:         http.MethodPost,
+			path:           "/api/v1/items/1/images",
+			contentType:    "image/png",
+			body:           strings.Repeat("a", 1025),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Unsupported content type",
+			method:         http.MethodPost,
+			path:           "/api/v1/items/1/images",
+			contentType:    "image/svg+xml",
+			body:           "<svg/>",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Other requests are not limited",
+			method:      http.MethodPut,
+			path:        "/api/v1/items/1",
+			contentType: "application/json",
+			body: `{"name":"Shirt","description":"` + strings.Repeat("a", 1025) +
+				`","price":"12.50","priceCode":"EUR"}`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			req.Header.Set(echo.HeaderContentType, test.contentType)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestCompressExports(t *testing.T) {
+	e := echo.New()
+	e.Use(compressExports())
+	for _, path := range []string{exportPath, "/api/v1/items"} {
+		e.GET(path, func(c echo.Context) error {
+			return c.String(http.StatusOK, "id,name\n1,Shirt\n")
+		})
+	}
+	tests := []struct {
+		name             string
+		path             string
+		acceptEncoding   string
+		expectedEncoding string
+	}{
+		{name: "Export is compressed", path: exportPath, acceptEncoding: "gzip", expectedEncoding: "gzip"},
+		{name: "Export without gzip support", path: exportPath},
+		{name: "Other endpoints are not compressed", path: "/api/v1/items", acceptEncoding: "gzip"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			req.Header.Set(echo.HeaderAcceptEncoding, test.acceptEncoding)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, test.expectedEncoding, rec.Header().Get(echo.HeaderContentEncoding))
+			body := io.Reader(rec.Body)
+			if test.expectedEncoding == "gzip" {
+				zr, err := gzip.NewReader(rec.Body)
+				require.NoError(t, err)
+				body = zr
+			}
+			content, err := io.ReadAll(body)
+			require.NoError(t, err)
+			assert.Equal(t, "id,name\n1,Shirt\n", string(content))
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package app
+
+import (
+	"context"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/outbox"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// trashPurger is implemented by stores which keep deleted items in trash
+type trashPurger interface {
+	PurgeDeletedItems(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+// reservationReleaser is implemented by stores which hold stock for reservations until they expire
+type reservationReleaser interface {
+	ReleaseExpiredReservations(ctx context.Context, now time.Time) (int64, error)
+}
+
+// eventRelayer is implemented by stores which emit domain events through the outbox
+type eventRelayer interface {
+	RelayEvents(ctx context.Context, limit int, publish func(ctx context.Context, event store.Event) error) (int, error)
+}
+
+// eventPruner is implemented by stores which keep published events in the outbox
+type eventPruner interface {
+	PruneEvents(ctx context.Context, publishedBefore time.Time) (int64, error)
+}
+
+// ratesLoader is implemented by stores which keep exchange rates shared by all instances
+type ratesLoader interface {
+	GetExchangeRates(ctx context.Context) (money.Rates, error)
+}
+
+// mediaDeleter is implemented by stores which queue blobs of removed images for deletion from media storage
+type mediaDeleter interface {
+	GetMediaDeletions(ctx context.Context, limit int) ([]store.MediaDeletion, error)
+	CompleteMediaDeletion(ctx context.Context, blobKey string) error
+}
+
+// imageRemover removes all files of an image, e.g. media.Library
+type imageRemover interface {
+	Remove(ctx context.Context, key string) error
+}
+
+// runPeriodically calls job every interval until ctx is done
+func runPeriodically(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			job(ctx)
+		}
+	}
+}
+
+// purgeTrash returns job permanently removing items which stayed in trash longer than retention
+func purgeTrash(s trashPurger, retention time.Duration, logger *logrus.Logger) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		purged, err := s.PurgeDeletedItems(ctx, time.Now().Add(-retention))
+		if err != nil {
+			logger.Errorf("error while purging trash: %s", err)
+			return
+		}
+		if purged > 0 {
+			logger.Infof("purged %d items deleted more than %s ago", purged, retention)
+		}
+	}
+}
+
+// releaseReservations returns job making stock held by expired reservations available again
+func releaseReservations(s reservationReleaser, logger *logrus.Logger) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		released, err := s.ReleaseExpiredReservations(ctx, time.Now())
+		if err != nil {
+			logger.Errorf("error while releasing expired reservations: %s", err)
+			return
+		}
+		if released > 0 {
+			logger.Infof("released %d expired reservations", released)
+		}
+	}
+}
+
+// pruneEvents returns job removing events published longer than retention ago from the outbox
+func pruneEvents(s eventPruner, retention time.Duration, logger *logrus.Logger) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		pruned, err := s.PruneEvents(ctx, time.Now().Add(-retention))
+		if err != nil {
+			logger.Errorf("error while pruning events: %s", err)
+			return
+		}
+		if pruned > 0 {
+			logger.Infof("pruned %d events published more than %s ago", pruned, retention)
+		}
+	}
+}
+
+// refreshRates returns job replacing exchange rates of exchange with ones kept in the store, so rates set through
+// other instances are picked up
+func refreshRates(s ratesLoader, exchange *money.Exchange, logger *logrus.Logger) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		rates, err := s.GetExchangeRates(ctx)
+		if err != nil {
+			logger.Errorf("error while refreshing exchange rates: %s", err)
+			return
+		}
+		if rates.Base != "" {
+			exchange.SetRates(rates)
+		}
+	}
+}
+
+// relayEvents returns job publishing events from the outbox in batches until there are no more pending ones. Failed
+// event is retried on the next run, before any later event. Events are passed to streams only once the relay marking
+// them as published is committed, so streams replaying events from the store before they are passed don't miss any.
+func relayEvents(s eventRelayer, publisher, streams outbox.Publisher, batchSize int,
+	logger *logrus.Logger) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		for {
+			var published []store.Event
+			relayed, err := s.RelayEvents(ctx, batchSize, func(ctx context.Context, event store.Event) error {
+				if err := publisher.Publish(ctx, event); err != nil {
+					return err
+				}
+				published = append(published, event)
+				return nil
+			})
+			for _, event := range published[:relayed] {
+				if err := streams.Publish(ctx, event); err != nil {
+					logger.Errorf("error while streaming event: %s", err)
+				}
+			}
+			if err != nil {
+				logger.Errorf("error while relaying events: %s", err)
+				return
+			}
+			if relayed < batchSize {
+				return
+			}
+		}
+	}
+}
+
+// deleteMedia returns job removing files of removed images from media storage in batches until there are no more
+// queued ones. Failed removals are retried on the next run.
+func deleteMedia(s mediaDeleter, library imageRemover, batchSize int, logger *logrus.Logger) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		for {
+			deletions, err := s.GetMediaDeletions(ctx, batchSize)
+			if err != nil {
+				logger.Errorf("error while getting media deletions: %s", err)
+				return
+			}
+			failed := false
+			for _, deletion := range deletions {
+				if err := library.Remove(ctx, deletion.BlobKey); err != nil {
+					logger.Errorf("error while deleting media: %s", err)
+					failed = true
+					continue
+				}
+				if err := s.CompleteMediaDeletion(ctx, deletion.BlobKey); err != nil {
+					logger.Errorf("error while deleting media: %s", err)
+					failed = true
+				}
+			}
+			// failed deletions are returned first again, so the next batch is left for the next run
+			if failed || len(deletions) < batchSize {
+				return
+			}
+		}
+	}
+}
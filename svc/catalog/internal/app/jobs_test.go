@@ -0,0 +1,282 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/outbox"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunPeriodically(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+	done := make(chan struct{})
+
+	go func() {
+		runPeriodically(ctx, time.Millisecond, func(ctx context.Context) {
+			if atomic.AddInt32(&calls, 1) == 3 {
+				cancel()
+			}
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	case <-time.After(time.Second):
+		t.Fatal("job runner did not stop after context was canceled")
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	purger := &mockTrashPurger{}
+
+	purgeTrash(purger, time.Hour, logrus.New())(context.Background())
+
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), purger.deletedBefore, time.Second)
+}
+
+func TestReleaseReservations(t *testing.T) {
+	releaser := &mockReservationReleaser{}
+
+	releaseReservations(releaser, logrus.New())(context.Background())
+
+	assert.WithinDuration(t, time.Now(), releaser.now, time.Second)
+}
+
+func TestPruneEvents(t *testing.T) {
+	pruner := &mockEventPruner{}
+
+	pruneEvents(pruner, time.Hour, logrus.New())(context.Background())
+
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), pruner.publishedBefore, time.Second)
+}
+
+func TestRefreshRates(t *testing.T) {
+	stored, err := money.ParseRates("EUR", map[string]string{"USD": "1.07"}, "admin", time.Now())
+	require.NoError(t, err)
+	exchange := money.NewExchange(money.Rates{})
+
+	refreshRates(&mockRatesLoader{}, exchange, logrus.New())(context.Background())
+	assert.Empty(t, exchange.Rates().Base, "rates are kept when none are stored")
+	refreshRates(&mockRatesLoader{rates: stored}, exchange, logrus.New())(context.Background())
+	assert.Equal(t, stored, exchange.Rates())
+	refreshRates(&mockRatesLoader{err: errors.New("some err")}, exchange, logrus.New())(context.Background())
+	assert.Equal(t, stored, exchange.Rates(), "rates are kept on error")
+}
+
+func TestRelayEvents(t *testing.T) {
+	tests := []struct {
+		name              string
+		pending           int
+		failAt            int
+		failCommit        bool
+		expectedCalls     int
+		expectedIDs       []uint64
+		expectedStreamIDs []uint64
+	}{
+		{
+			name:          "No pending events",
+			expectedCalls: 1,
+		},
+		{
+			name:              "Batches are relayed until none is full",
+			pending:           5,
+			expectedCalls:     3,
+			expectedIDs:       []uint64{1, 2, 3, 4, 5},
+			expectedStreamIDs: []uint64{1, 2, 3, 4, 5},
+		},
+		{
+			name:              "Relaying stops on error",
+			pending:           5,
+			failAt:            2,
+			expectedCalls:     1,
+			expectedIDs:       []uint64{1},
+			expectedStreamIDs: []uint64{1},
+		},
+		{
+			name:          "Published events are not streamed when relay isn't committed",
+			pending:       2,
+			failCommit:    true,
+			expectedCalls: 1,
+			expectedIDs:   []uint64{1, 2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			relayer := &mockEventRelayer{pending: test.pending, failAt: test.failAt, failCommit: test.failCommit}
+			publisher := outbox.NewMemoryPublisher()
+			streams := outbox.NewMemoryPublisher()
+			streams.Subscribe(func(event store.Event) {
+				assert.LessOrEqual(t, event.ID, uint64(relayer.committed), "event is streamed before it is committed")
+			})
+
+			relayEvents(relayer, publisher, streams, 2, logrus.New())(context.Background())
+
+			assert.Equal(t, test.expectedCalls, relayer.calls)
+			var ids []uint64
+			for _, event := range publisher.Events() {
+				ids = append(ids, event.ID)
+			}
+			assert.Equal(t, test.expectedIDs, ids)
+			var streamIDs []uint64
+			for _, event := range streams.Events() {
+				streamIDs = append(streamIDs, event.ID)
+			}
+			assert.Equal(t, test.expectedStreamIDs, streamIDs)
+		})
+	}
+}
+
+func TestDeleteMedia(t *testing.T) {
+	tests := []struct {
+		name            string
+		queued          []string
+		failing         string
+		expectedRemoved []string
+		expectedQueued  []string
+	}{
+		{
+			name: "No queued deletions",
+		},
+		{
+			name:            "Batches are deleted until none is full",
+			queued:          []string{"images/aa/a", "images/bb/b", "images/cc/c", "images/dd/d"},
+			expectedRemoved: []string{"images/aa/a", "images/bb/b", "images/cc/c", "images/dd/d"},
+		},
+		{
+			name:            "Failed deletion is retried on the next run",
+			queued:          []string{"images/aa/a", "images/bb/b", "images/cc/c"},
+			failing:         "images/aa/a",
+			expectedRemoved: []string{"images/bb/b"},
+			expectedQueued:  []string{"images/aa/a", "images/cc/c"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deleter := &mockMediaDeleter{queued: test.queued}
+			remover := &mockImageRemover{failing: test.failing}
+
+			deleteMedia(deleter, remover, 2, logrus.New())(context.Background())
+
+			assert.Equal(t, test.expectedRemoved, remover.removed)
+			assert.ElementsMatch(t, test.expectedQueued, deleter.queued)
+		})
+	}
+}
+
+type mockTrashPurger struct {
+	deletedBefore time.Time
+}
+
+func (m *mockTrashPurger) PurgeDeletedItems(_ context.Context, deletedBefore time.Time) (int64, error) {
+	m.deletedBefore = deletedBefore
+	return 1, nil
+}
+
+type mockReservationReleaser struct {
+	now time.Time
+}
+
+func (m *mockReservationReleaser) ReleaseExpiredReservations(_ context.Context, now time.Time) (int64, error) {
+	m.now = now
+	return 1, nil
+}
+
+type mockEventPruner struct {
+	publishedBefore time.Time
+}
+
+func (m *mockEventPruner) PruneEvents(_ context.Context, publishedBefore time.Time) (int64, error) {
+	m.publishedBefore = publishedBefore
+	return 0, nil
+}
+
+// mockEventRelayer relays events with consecutive IDs, failing at event with failAt ID. Published events are committed
+// when RelayEvents returns, unless failCommit is set.
+type mockEventRelayer struct {
+	pending    int
+	failAt     int
+	failCommit bool
+	published  int
+	committed  int
+	calls      int
+}
+
+func (m *mockEventRelayer) RelayEvents(ctx context.Context, limit int, publish func(ctx context.Context, event store.Event) error) (int, error) {
+	m.calls++
+	relayed, err := m.publish(ctx, limit, publish)
+	if m.failCommit {
+		m.published = m.committed
+		return 0, errors.New("commit failed")
+	}
+	m.committed = m.published
+	return relayed, err
+}
+
+func (m *mockEventRelayer) publish(ctx context.Context, limit int, publish func(ctx context.Context, event store.Event) error) (int, error) {
+	var relayed int
+	for ; relayed < limit && m.published < m.pending; relayed++ {
+		id := m.published + 1
+		if id == m.failAt {
+			return relayed, errors.New("some error")
+		}
+		if err := publish(ctx, store.Event{ID: uint64(id)}); err != nil {
+			return relayed, err
+		}
+		m.published++
+	}
+	return relayed, nil
+}
+
+type mockRatesLoader struct {
+	rates money.Rates
+	err   error
+}
+
+func (m *mockRatesLoader) GetExchangeRates(context.Context) (money.Rates, error) {
+	return m.rates, m.err
+}
+
+type mockMediaDeleter struct {
+	queued []string
+}
+
+func (m *mockMediaDeleter) GetMediaDeletions(_ context.Context, limit int) ([]store.MediaDeletion, error) {
+	var deletions []store.MediaDeletion
+	for i := 0; i < limit && i < len(m.queued); i++ {
+		deletions = append(deletions, store.MediaDeletion{BlobKey: m.queued[i]})
+	}
+	return deletions, nil
+}
+
+func (m *mockMediaDeleter) CompleteMediaDeletion(_ context.Context, blobKey string) error {
+	for i, key := range m.queued {
+		if key == blobKey {
+			m.queued = append(m.queued[:i], m.queued[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+type mockImageRemover struct {
+	failing string
+	removed []string
+}
+
+func (m *mockImageRemover) Remove(_ context.Context, key string) error {
+	if key == m.failing {
+		return errors.New("permission denied")
+	}
+	m.removed = append(m.removed, key)
+	return nil
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/ptr"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// errOperationNotApplied is result of valid operation of atomic batch which was not applied because of other one
+var errOperationNotApplied = errors.New("operation was not applied, as other operation of the batch failed")
+
+// BatchItems applies create, update and delete operations of items. In atomic mode all of them are applied in a
+// single transaction, in best-effort mode every operation is applied on its own.
+func (h *handler) BatchItems(ctx echo.Context, params api.BatchItemsParams) error {
+	var req api.BatchRequest
+	if err := ctx.Bind(&req); err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+
+	ops := make([]store.ItemOperation, len(req.Operations))
+	// errs hold errors of operations which could not be mapped to store operations
+	errs := make([]error, len(req.Operations))
+	for i, op := range req.Operations {
+		ops[i], errs[i] = h.mapBatchOperationToItemOperation(ctx.Request().Context(), op)
+	}
+	if ptr.ValueOr(params.Mode, api.Atomic) == api.BestEffort {
+		return h.applyBestEffort(ctx, ops, errs)
+	}
+	return h.applyAtomic(ctx, ops, errs)
+}
+
+// applyAtomic applies all operations in a single transaction, unless any of them is invalid
+func (h *handler) applyAtomic(ctx echo.Context, ops []store.ItemOperation, errs []error) error {
+	invalid := false
+	for _, err := range errs {
+		invalid = invalid || err != nil
+	}
+	if !invalid {
+		items, err := h.store.ApplyItemOperations(ctx.Request().Context(), ops)
+		var opErr store.OperationError
+		switch {
+		case err == nil:
+			resp := api.BatchResponse{Results: make([]api.BatchResult, 0, len(ops)), Succeeded: len(ops)}
+			for i, op := range ops {
+				resp.Results = append(resp.Results, operationResult(i, op, items[i]))
+			}
+			return ctx.JSON(http.StatusOK, resp)
+		case errors.As(err, &opErr):
+			errs[opErr.Index] = opErr.Err
+		default:
+			return h.writeErrorResponse(ctx, fmt.Errorf("error while applying item operations: %w", err))
+		}
+	}
+
+	resp := api.BatchResponse{Results: make([]api.BatchResult, 0, len(ops)), Failed: len(ops)}
+	for i, err := range errs {
+		if err != nil {
+			resp.Results = append(resp.Results, h.failedResult(i, err))
+			continue
+		}
+		notApplied := mapErrorToErrorResponse(errOperationNotApplied)
+		resp.Results = append(resp.Results,
+			api.BatchResult{Index: i, Status: http.StatusFailedDependency, Error: &notApplied})
+	}
+	return ctx.JSON(http.StatusUnprocessableEntity, resp)
+}
+
+// applyBestEffort applies every valid operation in its own transaction
+func (h *handler) applyBestEffort(ctx echo.Context, ops []store.ItemOperation, errs []error) error {
+	resp := api.BatchResponse{Results: make([]api.BatchResult, 0, len(ops))}
+	for i, op := range ops {
+		err := errs[i]
+		if err == nil {
+			var items []store.Item
+			items, err = h.store.ApplyItemOperations(ctx.Request().Context(), []store.ItemOperation{op})
+			var opErr store.OperationError
+			if errors.As(err, &opErr) {
+				err = opErr.Err
+			}
+			if err == nil {
+				resp.Results = append(resp.Results, operationResult(i, op, items[0]))
+				resp.Succeeded++
+				continue
+			}
+		}
+		resp.Results = append(resp.Results, h.failedResult(i, err))
+		resp.Failed++
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// failedResult returns result of operation at index which failed with err
+func (h *handler) failedResult(index int, err error) api.BatchResult {
+	h.log.Errorf("error in operation %d: %s", index, err)
+	resp := mapErrorToErrorResponse(err)
+	return api.BatchResult{Index: index, Status: errorStatus(err), Error: &resp}
+}
+
+// operationResult returns result of applied operation at index with the same status and item as a single request
+func operationResult(index int, op store.ItemOperation, item store.Item) api.BatchResult {
+	switch op.Type {
+	case store.OperationCreate:
+		resp := mapItemModelToItemResponse(item)
+		return api.BatchResult{Index: index, Status: http.StatusCreated, Item: &resp}
+	case store.OperationUpdate:
+		resp := mapItemModelToItemResponse(item)
+		return api.BatchResult{Index: index, Status: http.StatusOK, Item: &resp}
+	default:
+		return api.BatchResult{Index: index, Status: http.StatusOK}
+	}
+}
+
+func (h *handler) mapBatchOperationToItemOperation(ctx context.Context, op api.BatchOperation) (store.ItemOperation, error) {
+	item := ptr.ValueOr(op.Item, api.UpdateItemRequest{})
+	price, priceCode, err := parsePrice(item.Price, item.PriceCode, h.currencies)
+	if err != nil {
+		return store.ItemOperation{}, err
+	}
+	id := ptr.ValueOr(op.Id, 0)
+	ifVersion, err := h.expectedVersion(ctx, id, op.IfMatch)
+	if err != nil {
+		return store.ItemOperation{}, err
+	}
+	return store.ItemOperation{
+		Type: store.OperationType(op.Op),
+		ID:   id,
+		Item: store.Item{
+			Name:        item.Name,
+			Description: item.Description,
+			Price:       price,
+			PriceCode:   priceCode,
+		},
+		IfVersion: ifVersion,
+	}, nil
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBatchItems(t *testing.T) {
+	s := store.NewMemoryStore()
+	e := echo.New()
+	api.RegisterHandlers(e, NewHandler(logrus.New(), s, allCurrencies, noRates, nil))
+	// batch sends operations through the router, as the path contains a colon
+	batch := func(mode string, ops ...api.BatchOperation) (int, api.BatchResponse) {
+		body, err := json.Marshal(api.BatchRequest{Operations: ops})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/items:batch?mode="+mode, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		var resp api.BatchResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp), rec.Body.String())
+		return rec.Code, resp
+	}
+	create := func(name string) api.BatchOperation {
+		return api.BatchOperation{Op: api.Create, Item: &api.UpdateItemRequest{
+			Name: v2p(name), Description: v2p("desc"), Price: v2p("10"), PriceCode: v2p("eur"),
+		}}
+	}
+
+	t.Run("Atomic batch is applied", func(t *testing.T) {
+		code, resp := batch("atomic", create("Shirt"), create("Hat"))
+
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 2, resp.Succeeded)
+		require.Len(t, resp.Results, 2)
+		assert.Equal(t, http.StatusCreated, resp.Results[1].Status)
+		assert.Equal(t, "Hat", *resp.Results[1].Item.Name)
+		assert.Equal(t, "EUR", *resp.Results[1].Item.PriceCode)
+	})
+
+	t.Run("Atomic batch is rolled back on failure", func(t *testing.T) {
+		code, resp := batch("atomic",
+			api.BatchOperation{Op: api.Update, Id: v2p(uint(1)), Item: &api.UpdateItemRequest{Name: v2p("T-shirt")}},
+			api.BatchOperation{Op: api.Delete, Id: v2p(uint(1)), IfMatch: v2p(`"1"`)},
+		)
+
+		require.Equal(t, http.StatusUnprocessableEntity, code)
+		assert.Equal(t, 2, resp.Failed)
+		assert.Equal(t, http.StatusFailedDependency, resp.Results[0].Status)
+		assert.Nil(t, resp.Results[0].Item)
+		assert.Equal(t, http.StatusPreconditionFailed, resp.Results[1].Status)
+		item, err := s.GetItem(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Shirt", *item.Name)
+	})
+
+	t.Run("Invalid operation fails atomic batch before it is applied", func(t *testing.T) {
+		invalid := create("Scarf")
+		invalid.Item.PriceCode = v2p("XXX")
+
+		code, resp := batch("atomic", create("Scarf"), invalid, api.BatchOperation{Op: api.Delete})
+
+		require.Equal(t, http.StatusUnprocessableEntity, code)
+		assert.Equal(t, http.StatusFailedDependency, resp.Results[0].Status)
+		assert.Equal(t, http.StatusBadRequest, resp.Results[1].Status)
+		assert.Equal(t, "priceCode", *resp.Results[1].Error.Field)
+		assert.Equal(t, http.StatusFailedDependency, resp.Results[2].Status, "delete without ID fails in store")
+	})
+
+	t.Run("Best-effort batch applies operations independently", func(t *testing.T) {
+		code, resp := batch("bestEffort",
+			api.BatchOperation{Op: api.Update, Id: v2p(uint(1)), Item: &api.UpdateItemRequest{Name: v2p("T-shirt")}},
+			api.BatchOperation{Op: api.Delete, Id: v2p(uint(100))},
+			api.BatchOperation{Op: api.Delete},
+			api.BatchOperation{Op: api.Delete, Id: v2p(uint(2)), IfMatch: v2p(`"1"`)},
+		)
+
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 2, resp.Succeeded)
+		assert.Equal(t, 2, resp.Failed)
+		statuses := []int{}
+		for _, result := range resp.Results {
+			statuses = append(statuses, result.Status)
+		}
+		assert.Equal(t, []int{http.StatusOK, http.StatusNotFound, http.StatusBadRequest, http.StatusOK}, statuses)
+		assert.Equal(t, "T-shirt", *resp.Results[0].Item.Name)
+		assert.Equal(t, `"2"`, *resp.Results[0].Item.Etag)
+	})
+
+	t.Run("Single item requests are still routed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/items", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/ptr"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// GetCategories returns all categories from the underlying store as a tree
+func (h *handler) GetCategories(ctx echo.Context) error {
+	categories, err := h.store.GetCategories(ctx.Request().Context())
+	if err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while getting categories: %w", err))
+	}
+
+	children := map[uint][]store.Category{}
+	for _, category := range categories {
+		parent := ptr.ValueOr(category.ParentID, 0)
+		children[parent] = append(children[parent], category)
+	}
+	return ctx.JSON(http.StatusOK, categoryTree(children, 0))
+}
+
+// CreateCategory handles creation of a new category in the underlying store
+func (h *handler) CreateCategory(ctx echo.Context) error {
+	var newCategory api.NewCategoryRequest
+	if err := ctx.Bind(&newCategory); err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+
+	category, err := h.store.CreateCategory(ctx.Request().Context(), store.Category{
+		Name:     &newCategory.Name,
+		ParentID: newCategory.ParentId,
+	})
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return h.writeCategoryResponse(ctx, http.StatusCreated, category.ID)
+}
+
+// FindCategoryByID returns category with ID from the underlying store
+func (h *handler) FindCategoryByID(ctx echo.Context, id uint) error {
+	return h.writeCategoryResponse(ctx, http.StatusOK, id)
+}
+
+// UpdateCategoryByID renames category with ID or moves it to another parent in the underlying store
+func (h *handler) UpdateCategoryByID(ctx echo.Context, id uint) error {
+	var category api.UpdateCategoryRequest
+	if err := ctx.Bind(&category); err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+
+	if _, err := h.store.UpdateCategory(ctx.Request().Context(), id, store.Category{
+		Name:     category.Name,
+		ParentID: category.ParentId,
+	}); err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return h.writeCategoryResponse(ctx, http.StatusOK, id)
+}
+
+// DeleteCategoryByID deletes category with ID from the underlying store
+func (h *handler) DeleteCategoryByID(ctx echo.Context, id uint) error {
+	if err := h.store.DeleteCategory(ctx.Request().Context(), id); err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// SetItemCategories replaces categories item with ID is assigned to in the underlying store
+func (h *handler) SetItemCategories(ctx echo.Context, id uint) error {
+	var req api.ItemCategoriesRequest
+	if err := ctx.Bind(&req); err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+
+	if err := h.store.SetItemCategories(ctx.Request().Context(), id, req.CategoryIds); err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// writeCategoryResponse writes category with ID along with its path from the root category
+func (h *handler) writeCategoryResponse(ctx echo.Context, code int, id uint) error {
+	path, err := h.store.GetCategoryPath(ctx.Request().Context(), id)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+
+	category := path[len(path)-1]
+	return ctx.JSON(code, api.CategoryResponse{
+		Id:       category.ID,
+		Name:     *category.Name,
+		ParentId: category.ParentID,
+		Path:     mapCategoriesToCategoryRefs(path),
+	})
+}
+
+// itemResponses maps items to responses which include breadcrumbs of categories items are assigned to, prices
+// scheduled for now, available stock and, when currency is set, price in that currency. Detailed responses include
+// price lists and variants too.
+func (h *handler) itemResponses(ctx context.Context, items []store.Item, currency *string,
+	detailed bool) ([]api.ItemResponse, error) {
+	resp, _, err := h.itemResponsesWithState(ctx, items, currency, detailed)
+	return resp, err
+}
+
+// itemState holds stock levels and sales in effect item responses were made with, keyed by item ID
+type itemState struct {
+	levels map[uint][]store.Stock
+	sales  map[uint]map[string]store.ScheduledPrice
+}
+
+// itemResponsesWithState maps items to responses like itemResponses and returns state of items they were made with
+func (h *handler) itemResponsesWithState(ctx context.Context, items []store.Item, currency *string,
+	detailed bool) ([]api.ItemResponse, itemState, error) {
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	breadcrumbs, err := h.store.GetItemBreadcrumbs(ctx, ids)
+	if err != nil {
+		return nil, itemState{}, err
+	}
+	sales, err := h.activeSales(ctx, ids)
+	if err != nil {
+		return nil, itemState{}, err
+	}
+	images, err := h.store.GetItemImages(ctx, ids)
+	if err != nil {
+		return nil, itemState{}, err
+	}
+	var prices []store.ItemPrice
+	if detailed || currency != nil {
+		if prices, err = h.store.GetItemPrices(ctx, ids); err != nil {
+			return nil, itemState{}, err
+		}
+	}
+	levels, err := h.store.GetStockLevels(ctx, ids)
+	if err != nil {
+		return nil, itemState{}, err
+	}
+	itemLevels := map[uint][]store.Stock{}
+	for _, level := range levels {
+		itemLevels[level.ItemID] = append(itemLevels[level.ItemID], level)
+	}
+	// rates are read once, so all items are converted with the same ones
+	rates := h.exchange.Rates()
+	itemPrices := map[uint][]store.ItemPrice{}
+	for _, price := range prices {
+		itemPrices[price.ItemID] = append(itemPrices[price.ItemID], price)
+	}
+
+	resp := make([]api.ItemResponse, 0, len(items))
+	for _, item := range items {
+		paths := make([][]api.CategoryRef, 0, len(breadcrumbs[item.ID]))
+		for _, path := range breadcrumbs[item.ID] {
+			paths = append(paths, mapCategoriesToCategoryRefs(path))
+		}
+		itemResp := mapItemModelToItemResponse(item)
+		itemResp.Breadcrumbs = &paths
+		itemImages := mapItemImagesToItemImageResponses(images[item.ID], h.mediaURL)
+		itemResp.Images = &itemImages
+		applySale(&itemResp, item, sales[item.ID])
+		if detailed {
+			priceResps := mapItemPricesToPrices(itemPrices[item.ID])
+			itemResp.Prices = &priceResps
+			variants, err := h.store.GetVariants(ctx, item.ID)
+			if err != nil {
+				return nil, itemState{}, err
+			}
+			variantResps := mapVariantsToVariantResponses(variants)
+			itemResp.Variants = &variantResps
+		}
+		setAvailability(&itemResp, itemLevels[item.ID])
+		if currency != nil {
+			itemResp.CurrencyPrice = resolvePrice(item, listPrice(itemPrices[item.ID], *currency), sales[item.ID], rates,
+				*currency)
+		}
+		resp = append(resp, itemResp)
+	}
+	return resp, itemState{levels: itemLevels, sales: sales}, nil
+}
+
+// categoryTree returns subtree of categories below parent with provided ID
+func categoryTree(children map[uint][]store.Category, parent uint) []api.CategoryTreeNode {
+	nodes := make([]api.CategoryTreeNode, 0, len(children[parent]))
+	for _, category := range children[parent] {
+		nodes = append(nodes, api.CategoryTreeNode{
+			Id:       category.ID,
+			Name:     *category.Name,
+			Children: categoryTree(children, category.ID),
+		})
+	}
+	return nodes
+}
+
+func mapCategoriesToCategoryRefs(categories []store.Category) []api.CategoryRef {
+	refs := make([]api.CategoryRef, 0, len(categories))
+	for _, category := range categories {
+		refs = append(refs, api.CategoryRef{Id: category.ID, Name: *category.Name})
+	}
+	return refs
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newJSONContext returns echo context of request with body encoded as JSON
+func newJSONContext(t *testing.T, method, target string, body interface{}) (echo.Context, *httptest.ResponseRecorder) {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+	req := httptest.NewRequest(method, target, bytes.NewReader(reqBody))
+	req.Header.Add("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(req, rec), rec
+}
+
+func TestCategories(t *testing.T) {
+	h := NewHandler(logrus.New(), store.NewMemoryStore(), allCurrencies, noRates, nil)
+	createCategory := func(name string, parentID *uint) api.CategoryResponse {
+		ctx, rec := newJSONContext(t, http.MethodPost, "/api/v1/categories",
+			api.NewCategoryRequest{Name: name, ParentId: parentID})
+		require.NoError(t, h.CreateCategory(ctx))
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var resp api.CategoryResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		return resp
+	}
+	clothes := createCategory("Clothes", nil)
+	shirts := createCategory("Shirts", &clothes.Id)
+	sale := createCategory("Sale", nil)
+	assert.Equal(t, []api.CategoryRef{{Id: clothes.Id, Name: "Clothes"}, {Id: shirts.Id, Name: "Shirts"}}, shirts.Path)
+	assert.Equal(t, &clothes.Id, shirts.ParentId)
+
+	t.Run("Tree is returned", func(t *testing.T) {
+		ctx, rec := newJSONContext(t, http.MethodGet, "/api/v1/categories", nil)
+		require.NoError(t, h.GetCategories(ctx))
+
+		var tree []api.CategoryTreeNode
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&tree))
+		assert.Equal(t, []api.CategoryTreeNode{
+			{Id: clothes.Id, Name: "Clothes", Children: []api.CategoryTreeNode{
+				{Id: shirts.Id, Name: "Shirts", Children: []api.CategoryTreeNode{}},
+			}},
+			{Id: sale.Id, Name: "Sale", Children: []api.CategoryTreeNode{}},
+		}, tree)
+	})
+
+	t.Run("Invalid parent is rejected", func(t *testing.T) {
+		ctx, rec := newJSONContext(t, http.MethodPut, "/api/v1/categories", api.UpdateCategoryRequest{ParentId: &shirts.Id})
+		require.NoError(t, h.UpdateCategoryByID(ctx, clothes.Id))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		ctx, rec = newJSONContext(t, http.MethodPost, "/api/v1/categories",
+			api.NewCategoryRequest{Name: "Orphan", ParentId: v2p(uint(100))})
+		require.NoError(t, h.CreateCategory(ctx))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Category with children is not deleted", func(t *testing.T) {
+		ctx, rec := newJSONContext(t, http.MethodDelete, "/api/v1/categories", nil)
+		require.NoError(t, h.DeleteCategoryByID(ctx, clothes.Id))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("Item responses include breadcrumbs", func(t *testing.T) {
+		ctx, rec := newJSONContext(t, http.MethodPost, "/api/v1/items",
+			api.NewItemRequest{Name: "Shirt", Description: "desc", Price: "10", PriceCode: "EUR"})
+		require.NoError(t, h.CreateItem(ctx))
+		var item api.ItemResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+
+		ctx, rec = newJSONContext(t, http.MethodPut, "/api/v1/items/categories",
+			api.ItemCategoriesRequest{CategoryIds: []uint{shirts.Id, sale.Id}})
+		require.NoError(t, h.SetItemCategories(ctx, *item.Id))
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		ctx, rec = newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+		require.NoError(t, h.FindItemByID(ctx, *item.Id, api.FindItemByIDParams{}))
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+		assert.Equal(t, &[][]api.CategoryRef{shirts.Path, {{Id: sale.Id, Name: "Sale"}}}, item.Breadcrumbs)
+
+		ctx, rec = newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+		require.NoError(t, h.GetItems(ctx, api.GetItemsParams{CategoryId: &clothes.Id, IncludeDescendants: v2p(true)}))
+		var items []api.ItemResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&items))
+		require.Len(t, items, 1)
+		assert.Equal(t, item.Id, items[0].Id)
+
+		ctx, rec = newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+		require.NoError(t, h.GetItems(ctx, api.GetItemsParams{CategoryId: &clothes.Id}))
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&items))
+		assert.Empty(t, items)
+	})
+}
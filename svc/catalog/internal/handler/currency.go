@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// GetCurrencies returns currencies items can be priced in
+func (h *handler) GetCurrencies(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, mapCurrenciesToCurrencyResponses(h.currencies.List()))
+}
+
+func mapCurrenciesToCurrencyResponses(currencies []money.Currency) []api.CurrencyResponse {
+	resp := make([]api.CurrencyResponse, 0, len(currencies))
+	for _, currency := range currencies {
+		resp = append(resp, api.CurrencyResponse{
+			Code:        currency.Code,
+			NumericCode: currency.Numeric,
+			Exponent:    currency.Exponent,
+			Name:        currency.Name,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestGetCurrencies(t *testing.T) {
+	currencies, err := money.NewCurrencySet("USD", "EUR")
+	require.NoError(t, err)
+	ctx, rec := newJSONContext(t, http.MethodGet, "/api/v1/currencies", nil)
+
+	require.NoError(t, NewHandler(logrus.New(), &mockCatalogStore{}, currencies, noRates, nil).GetCurrencies(ctx))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp []api.CurrencyResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, []api.CurrencyResponse{
+		{Code: "EUR", NumericCode: "978", Exponent: 2, Name: "Euro"},
+		{Code: "USD", NumericCode: "840", Exponent: 2, Name: "US Dollar"},
+	}, resp)
+}
+
+func TestCreateItem_priceCode(t *testing.T) {
+	currencies, err := money.NewCurrencySet("EUR", "JPY")
+	require.NoError(t, err)
+	h := NewHandler(logrus.New(), store.NewMemoryStore(), currencies, noRates, nil)
+	tests := []struct {
+		name              string
+		priceCode         string
+		expectedStatus    int
+		expectedPriceCode string
+	}{
+		{
+			name:              "Successful - code is upper-cased",
+			priceCode:         "jpy",
+			expectedStatus:    http.StatusCreated,
+			expectedPriceCode: "JPY",
+		},
+		{
+			name:           "Bad Request - not an ISO 4217 code",
+			priceCode:      "BAN",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Bad Request - currency is not accepted",
+			priceCode:      "GBP",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, rec := newJSONContext(t, http.MethodPost, "/api/v1/items",
+				api.NewItemRequest{Name: "Shirt", Description: "desc", Price: "10", PriceCode: test.priceCode})
+			require.NoError(t, h.CreateItem(ctx))
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			if test.expectedStatus == http.StatusCreated {
+				var item api.ItemResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+				assert.Equal(t, test.expectedPriceCode, *item.PriceCode)
+				return
+			}
+			var resp api.ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, v2p("priceCode"), resp.Field)
+		})
+	}
+}
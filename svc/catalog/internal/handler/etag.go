@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	headerETag = "ETag"
+	anyETag    = "*"
+	weakPrefix = "W/"
+)
+
+// formatETag returns strong entity tag representing item version
+func formatETag(version uint) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// itemETag returns strong entity tag of fetched item. Item version is followed by IDs of sales in effect, e.g. "3-12",
+// so the tag changes also when a sale starts or ends without the item being modified.
+func itemETag(version uint, sales map[string]store.ScheduledPrice) string {
+	if len(sales) == 0 {
+		return formatETag(version)
+	}
+	ids := make([]uint, 0, len(sales))
+	for _, sale := range sales {
+		ids = append(ids, sale.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	var b strings.Builder
+	fmt.Fprintf(&b, `"%d`, version)
+	for _, id := range ids {
+		fmt.Fprintf(&b, "-%d", id)
+	}
+	b.WriteString(`"`)
+	return b.String()
+}
+
+// parseETags parses comma separated list of entity tags from If-Match or If-None-Match header into item versions.
+// Sales following the version in tags of fetched items are ignored, as only the version is compared. Weak tags are
+// accepted only when weak is true, as If-Match requires strong comparison.
+func parseETags(header string, weak bool) (versions []uint, any bool) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == anyETag {
+			return nil, true
+		}
+		if strings.HasPrefix(tag, weakPrefix) {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, weakPrefix)
+		}
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		version, _, _ := strings.Cut(tag[1:len(tag)-1], "-")
+		parsed, err := strconv.ParseUint(version, 10, 64)
+		if err != nil || parsed == 0 {
+			continue
+		}
+		versions = append(versions, uint(parsed))
+	}
+	return versions, false
+}
+
+// noneMatch reports if representation with etag should be returned for request with If-None-Match header. Tags are
+// compared weakly, as If-None-Match requires.
+func noneMatch(ifNoneMatch *string, etag string) bool {
+	if ifNoneMatch == nil {
+		return true
+	}
+	opaque := strings.TrimPrefix(etag, weakPrefix)
+	for _, tag := range strings.Split(*ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == anyETag || strings.TrimPrefix(tag, weakPrefix) == opaque {
+			return false
+		}
+	}
+	return true
+}
+
+// expectedVersion translates If-Match header into item version expected by the store. Zero means that any version
+// is accepted. When header lists more than one tag, current version of the item is checked against all of them.
+func (h *handler) expectedVersion(ctx context.Context, id uint, ifMatch *string) (uint, error) {
+	if ifMatch == nil {
+		return 0, nil
+	}
+	versions, any := parseETags(*ifMatch, false)
+	switch {
+	case any:
+		return 0, nil
+	case len(versions) == 0:
+		return 0, store.ErrVersionMismatch
+	case len(versions) == 1:
+		return versions[0], nil
+	}
+	item, err := h.store.GetItem(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if !containsVersion(versions, item.Version) {
+		return 0, store.ErrVersionMismatch
+	}
+	return item.Version, nil
+}
+
+func containsVersion(versions []uint, version uint) bool {
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
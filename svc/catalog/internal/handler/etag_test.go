@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseETags(t *testing.T) {
+	tests := []struct {
+		name             string
+		header           string
+		weak             bool
+		expectedVersions []uint
+		expectedAny      bool
+	}{
+		{
+			name:             "Single strong tag",
+			header:           `"3"`,
+			expectedVersions: []uint{3},
+		},
+		{
+			name:             "List of tags",
+			header:           `"3", "5"`,
+			expectedVersions: []uint{3, 5},
+		},
+		{
+			name:        "Any tag",
+			header:      `"3", *`,
+			expectedAny: true,
+		},
+		{
+			name:   "Weak tag rejected for strong comparison",
+			header: `W/"3"`,
+		},
+		{
+			name:             "Weak tag accepted for weak comparison",
+			header:           `W/"3"`,
+			weak:             true,
+			expectedVersions: []uint{3},
+		},
+		{
+			name:             "Sales following version ignored",
+			header:           `"3-12-15", "4"`,
+			expectedVersions: []uint{3, 4},
+		},
+		{
+			name:             "Malformed tags skipped",
+			header:           `3, "abc", "0", "7"`,
+			expectedVersions: []uint{7},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			versions, any := parseETags(test.header, test.weak)
+
+			assert.Equal(t, test.expectedVersions, versions)
+			assert.Equal(t, test.expectedAny, any)
+		})
+	}
+}
+
+func TestItemETag(t *testing.T) {
+	tests := []struct {
+		name         string
+		sales        map[string]store.ScheduledPrice
+		expectedETag string
+	}{
+		{
+			name:         "No sales",
+			expectedETag: `"3"`,
+		},
+		{
+			name: "Sales sorted by ID",
+			sales: map[string]store.ScheduledPrice{
+				"EUR": {ID: 15},
+				"USD": {ID: 12},
+			},
+			expectedETag: `"3-12-15"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedETag, itemETag(3, test.sales))
+		})
+	}
+}
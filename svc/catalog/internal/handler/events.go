@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/ptr"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// eventStreamHeartbeat is how often idle event stream is kept alive with a comment
+	eventStreamHeartbeat = 15 * time.Second
+	// eventStreamBuffer is number of events buffered per stream, clients which fall further behind are disconnected
+	eventStreamBuffer = 64
+	// eventReplayBatchSize is number of missed events read from the store at once
+	eventReplayBatchSize = 100
+)
+
+// StreamItemEvents streams events of item changes as Server-Sent Events. Events missed since Last-Event-ID are
+// replayed from the store first. Stream ends when client disconnects, falls behind or broker is closed on shutdown.
+func (h *handler) StreamItemEvents(eCtx echo.Context, params api.StreamItemEventsParams) error {
+	ctx := eCtx.Request().Context()
+	// subscription starts before replay and events are passed to the broker only once they are committed, so every
+	// event is either replayed or received, events received twice are skipped by position
+	sub, err := h.events.Subscribe(eventStreamBuffer)
+	if err != nil {
+		return h.writeErrorResponse(eCtx, err)
+	}
+	defer sub.Close()
+
+	lastPosition := ptr.ValueOr(params.LastEventID, 0)
+	var missed []store.Event
+	if params.LastEventID != nil {
+		if missed, err = h.store.GetEvents(ctx, lastPosition, eventReplayBatchSize); err != nil {
+			return h.writeErrorResponse(eCtx, fmt.Errorf("error while getting missed events: %w", err))
+		}
+	}
+
+	w := eCtx.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	// disables response buffering of reverse proxies
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+
+	for len(missed) > 0 {
+		for _, event := range missed {
+			if err := writeEvent(w, event); err != nil {
+				return nil
+			}
+			lastPosition = event.Position
+		}
+		w.Flush()
+		if len(missed) < eventReplayBatchSize {
+			break
+		}
+		if missed, err = h.store.GetEvents(ctx, lastPosition, eventReplayBatchSize); err != nil {
+			// response is already sent, client reconnects and replay continues from the last event it received
+			h.log.Errorf("error while getting missed events: %s", err)
+			return nil
+		}
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+			if event.Position <= lastPosition {
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return nil
+			}
+			lastPosition = event.Position
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		}
+		w.Flush()
+	}
+}
+
+// writeEvent writes event as Server-Sent Events message named after event type, with stream position of the event as
+// message ID
+func writeEvent(w io.Writer, event store.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error while encoding event with id %d: %w", event.ID, err)
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Position, event.Type, data)
+	return err
+}
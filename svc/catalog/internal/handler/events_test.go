@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/outbox"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readMessage reads lines of the next Server-Sent Events message or comment
+func readMessage(t *testing.T, r *bufio.Reader) []string {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		if line == "\n" {
+			return lines
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+}
+
+// readEvent reads lines of the next Server-Sent Events message, skipping heartbeats
+func readEvent(t *testing.T, r *bufio.Reader) []string {
+	for {
+		if msg := readMessage(t, r); !strings.HasPrefix(msg[0], ":") {
+			return msg
+		}
+	}
+}
+
+func TestStreamItemEvents(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemoryStore()
+	broker := outbox.NewBroker()
+	h := NewHandler(logrus.New(), s, allCurrencies, noRates, broker)
+	h.heartbeat = 50 * time.Millisecond
+	e := echo.New()
+	api.RegisterHandlers(e, h)
+	server := httptest.NewServer(e)
+	defer server.Close()
+	relay := func() {
+		_, err := s.RelayEvents(ctx, 10, broker.Publish)
+		require.NoError(t, err)
+	}
+
+	first, err := s.CreateItem(ctx, store.Item{Name: v2p("Shirt"), Description: v2p("desc"), Price: v2p(int64(100)),
+		PriceCode: v2p("EUR")})
+	require.NoError(t, err)
+	_, err = s.UpdateItem(ctx, first.ID, store.Item{Name: v2p("T-shirt")}, 0)
+	require.NoError(t, err)
+	relay()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/items/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	r := bufio.NewReader(resp.Body)
+
+	t.Run("Missed events are replayed", func(t *testing.T) {
+		msg := readEvent(t, r)
+		require.Len(t, msg, 3)
+		assert.Equal(t, "id: 2", msg[0])
+		assert.Equal(t, "event: ItemUpdated", msg[1])
+		assert.Contains(t, msg[2], `"name":"T-shirt"`)
+	})
+
+	t.Run("Live events are streamed", func(t *testing.T) {
+		require.NoError(t, s.DeleteItem(ctx, first.ID, 0))
+		relay()
+
+		msg := readEvent(t, r)
+		require.Len(t, msg, 3)
+		assert.Equal(t, []string{"id: 3", "event: ItemDeleted"}, msg[:2])
+	})
+
+	t.Run("Idle stream is kept alive with heartbeats", func(t *testing.T) {
+		assert.Equal(t, []string{": heartbeat"}, readMessage(t, r))
+	})
+
+	t.Run("Stream ends on shutdown", func(t *testing.T) {
+		broker.Close()
+
+		for {
+			if _, err := r.ReadString('\n'); err != nil {
+				break
+			}
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/items/events", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
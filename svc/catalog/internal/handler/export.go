@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/ptr"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// exportBatchSize is number of items read from the store at once while exporting
+	exportBatchSize = 500
+	// merchantNamespace is XML namespace of Google Merchant Center product attributes
+	merchantNamespace = "http://base.google.com/ns/1.0"
+	// maxAdditionalImages is number of images besides the main one a product of merchant feed can have
+	maxAdditionalImages = 10
+)
+
+var errUnsupportedFormat = errors.New("unsupported export format")
+
+// ExportItems streams all items matching the filters in requested format. Items are written batch by batch as they
+// are read from the store, so the whole catalog is never held in memory.
+func (h *handler) ExportItems(eCtx echo.Context, params api.ExportItemsParams) error {
+	ctx, span := otel.Tracer("").Start(eCtx.Request().Context(), "ExportItems")
+	defer span.End()
+
+	w := eCtx.Response()
+	enc, err := h.newItemEncoder(params.Format, w)
+	if err != nil {
+		return h.writeErrorResponse(eCtx, err)
+	}
+	query := store.ExportQuery{
+		ItemFilter: store.ItemFilter{
+			MinPrice:           params.MinPrice,
+			MaxPrice:           params.MaxPrice,
+			PriceCode:          params.PriceCode,
+			Name:               params.Name,
+			CategoryID:         params.CategoryId,
+			IncludeDescendants: ptr.ValueOr(params.IncludeDescendants, false),
+		},
+		AfterID:   ptr.ValueOr(params.Cursor, 0),
+		BatchSize: exportBatchSize,
+	}
+
+	started := false
+	start := func() error {
+		w.Header().Set(echo.HeaderContentType, enc.contentType())
+		w.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="items.%s"`, enc.extension()))
+		w.WriteHeader(http.StatusOK)
+		started = true
+		return enc.begin()
+	}
+	err = h.store.ExportItems(ctx, query, func(items []store.Item) error {
+		resp, err := h.exportedItems(ctx, items)
+		if err != nil {
+			return err
+		}
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := enc.encode(resp); err != nil {
+			return err
+		}
+		w.Flush()
+		return nil
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil {
+		err = enc.end()
+	}
+	if err != nil && !started {
+		return h.writeErrorResponse(eCtx, fmt.Errorf("error while exporting items: %w", err))
+	}
+	if err != nil {
+		// connection is aborted, so the client notices incomplete export and can resume it from the last item
+		h.log.Errorf("error while exporting items: %s", err)
+		panic(http.ErrAbortHandler)
+	}
+	w.Flush()
+	return nil
+}
+
+// exportedItems maps batch of exported items to responses with their breadcrumbs, current prices and availability
+func (h *handler) exportedItems(ctx context.Context, items []store.Item) ([]exportedItem, error) {
+	resp, state, err := h.itemResponsesWithState(ctx, items, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := make([]exportedItem, 0, len(resp))
+	for i := range resp {
+		inStock := false
+		for _, level := range state.levels[*resp[i].Id] {
+			inStock = inStock || level.Available() > 0
+		}
+		exported = append(exported, exportedItem{ItemResponse: resp[i], inStock: inStock})
+	}
+	return exported, nil
+}
+
+// exportedItem is an item response with its availability across all variants
+type exportedItem struct {
+	api.ItemResponse
+	inStock bool
+}
+
+// regularPrice returns price of the item when it is not on sale
+func (i exportedItem) regularPrice() string {
+	return ptr.ValueOr(i.CompareAtPrice, ptr.ValueOr(i.Price, ""))
+}
+
+// salePrice returns price of the item on sale, or empty string when it is not on sale
+func (i exportedItem) salePrice() string {
+	if i.CompareAtPrice == nil {
+		return ""
+	}
+	return ptr.ValueOr(i.Price, "")
+}
+
+// itemEncoder writes exported items in a single format
+type itemEncoder interface {
+	contentType() string
+	extension() string
+	// begin writes what precedes the items, e.g. CSV header
+	begin() error
+	encode(items []exportedItem) error
+	// end writes what follows the items and flushes buffered output
+	end() error
+}
+
+// newItemEncoder returns encoder writing items to w in provided format
+func (h *handler) newItemEncoder(format api.ExportFormat, w io.Writer) (itemEncoder, error) {
+	switch format {
+	case api.ExportFormatCsv:
+		return &csvItemEncoder{w: csv.NewWriter(w)}, nil
+	case api.ExportFormatNdjson:
+		return &ndjsonItemEncoder{enc: json.NewEncoder(w)}, nil
+	case api.ExportFormatMerchantXml:
+		return &merchantItemEncoder{w: w, enc: xml.NewEncoder(w), itemURL: h.itemURL, linkImages: h.mediaURL != ""}, nil
+	}
+	return nil, fieldError{field: "format", err: fmt.Errorf("%w %q", errUnsupportedFormat, format)}
+}
+
+// csvItemEncoder writes items as CSV rows with columns the importer accepts, so the file can be imported back
+type csvItemEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvItemEncoder) contentType() string { return "text/csv; charset=utf-8" }
+
+func (e *csvItemEncoder) extension() string { return "csv" }
+
+func (e *csvItemEncoder) begin() error {
+	return e.w.Write([]string{"id", "externalId", "name", "description", "price", "priceCode", "salePrice", "available"})
+}
+
+func (e *csvItemEncoder) encode(items []exportedItem) error {
+	for _, item := range items {
+		err := e.w.Write([]string{
+			strconv.FormatUint(uint64(*item.Id), 10),
+			ptr.ValueOr(item.ExternalId, ""),
+			ptr.ValueOr(item.Name, ""),
+			ptr.ValueOr(item.Description, ""),
+			item.regularPrice(),
+			ptr.ValueOr(item.PriceCode, ""),
+			item.salePrice(),
+			strconv.FormatInt(ptr.ValueOr(item.Available, 0), 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvItemEncoder) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// ndjsonItemEncoder writes every item as JSON on a separate line
+type ndjsonItemEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonItemEncoder) contentType() string { return "application/x-ndjson" }
+
+func (e *ndjsonItemEncoder) extension() string { return "ndjson" }
+
+func (e *ndjsonItemEncoder) begin() error { return nil }
+
+func (e *ndjsonItemEncoder) encode(items []exportedItem) error {
+	for _, item := range items {
+		if err := e.enc.Encode(item.ItemResponse); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *ndjsonItemEncoder) end() error { return nil }
+
+// merchantItem is a product of Google Merchant Center feed
+type merchantItem struct {
+	XMLName          xml.Name `xml:"item"`
+	ID               string   `xml:"g:id"`
+	Title            string   `xml:"g:title"`
+	Description      string   `xml:"g:description"`
+	Link             string   `xml:"g:link,omitempty"`
+	ImageLink        string   `xml:"g:image_link,omitempty"`
+	AdditionalImages []string `xml:"g:additional_image_link"`
+	Availability     string   `xml:"g:availability"`
+	Price            string   `xml:"g:price"`
+	SalePrice        string   `xml:"g:sale_price,omitempty"`
+	ProductType      string   `xml:"g:product_type,omitempty"`
+	IdentifierExists string   `xml:"g:identifier_exists"`
+}
+
+// merchantItemEncoder writes items as Google Merchant Center RSS feed
+type merchantItemEncoder struct {
+	w   io.Writer
+	enc *xml.Encoder
+	// itemURL is a template of item page links with {id} placeholder, links are not set when it is empty
+	itemURL string
+	// linkImages is set when images have absolute URLs, which feeds require
+	linkImages bool
+}
+
+func (e *merchantItemEncoder) contentType() string { return "application/xml; charset=utf-8" }
+
+func (e *merchantItemEncoder) extension() string { return "xml" }
+
+func (e *merchantItemEncoder) begin() error {
+	_, err := io.WriteString(e.w, xml.Header+`<rss version="2.0" xmlns:g="`+merchantNamespace+`"><channel>`+
+		`<title>Catalog</title><description>Catalog items</description>`)
+	return err
+}
+
+func (e *merchantItemEncoder) encode(items []exportedItem) error {
+	for _, item := range items {
+		id := strconv.FormatUint(uint64(*item.Id), 10)
+		product := merchantItem{
+			ID:               id,
+			Title:            ptr.ValueOr(item.Name, ""),
+			Description:      ptr.ValueOr(item.Description, ""),
+			Availability:     "out_of_stock",
+			Price:            merchantPrice(item.regularPrice(), item.PriceCode),
+			SalePrice:        merchantPrice(item.salePrice(), item.PriceCode),
+			IdentifierExists: "no",
+		}
+		if e.itemURL != "" {
+			product.Link = strings.ReplaceAll(e.itemURL, "{id}", id)
+		}
+		if item.inStock {
+			product.Availability = "in_stock"
+		}
+		if e.linkImages && item.Images != nil {
+			for i, image := range *item.Images {
+				if i == 0 {
+					product.ImageLink = image.Url
+				} else if i <= maxAdditionalImages {
+					product.AdditionalImages = append(product.AdditionalImages, image.Url)
+				}
+			}
+		}
+		if item.Breadcrumbs != nil && len(*item.Breadcrumbs) > 0 {
+			product.ProductType = productType((*item.Breadcrumbs)[0])
+		}
+		if err := e.enc.Encode(product); err != nil {
+			return err
+		}
+	}
+	return e.enc.Flush()
+}
+
+func (e *merchantItemEncoder) end() error {
+	_, err := io.WriteString(e.w, "</channel></rss>\n")
+	return err
+}
+
+// merchantPrice formats price as amount followed by currency code, e.g. "12.50 EUR"
+func merchantPrice(price string, priceCode *string) string {
+	if price == "" {
+		return ""
+	}
+	return price + " " + ptr.ValueOr(priceCode, "")
+}
+
+// productType formats category path as product type, e.g. "Clothing > Shirts"
+func productType(path []api.CategoryRef) string {
+	names := make([]string, 0, len(path))
+	for _, category := range path {
+		names = append(names, category.Name)
+	}
+	return strings.Join(names, " > ")
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// interruptedStore fails export after the first batch was exported
+type interruptedStore struct {
+	*store.MemoryStore
+}
+
+func (s *interruptedStore) ExportItems(ctx context.Context, query store.ExportQuery, export func([]store.Item) error) error {
+	return s.MemoryStore.ExportItems(ctx, query, func(items []store.Item) error {
+		if err := export(items); err != nil {
+			return err
+		}
+		return errors.New("connection reset")
+	})
+}
+
+func TestExportItems(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemoryStore()
+	h := NewHandler(logrus.New(), s, allCurrencies, noRates, nil).WithItemURL("https://shop.example.com/items/{id}")
+	shirt, err := s.CreateItem(ctx, store.Item{ExternalID: v2p("SUP-1"), Name: v2p("Shirt, blue"),
+		Description: v2p("Cotton"), Price: v2p(int64(1250)), PriceCode: v2p("EUR")})
+	require.NoError(t, err)
+	hat, err := s.CreateItem(ctx, store.Item{Name: v2p("Hat"), Description: v2p("Wool"), Price: v2p(int64(500)),
+		PriceCode: v2p("EUR")})
+	require.NoError(t, err)
+	_, err = s.CreateItem(ctx, store.Item{Name: v2p("Socks"), Description: v2p(""), Price: v2p(int64(1500)),
+		PriceCode: v2p("JPY")})
+	require.NoError(t, err)
+	clothing, err := s.CreateCategory(ctx, store.Category{Name: v2p("Clothing")})
+	require.NoError(t, err)
+	shirts, err := s.CreateCategory(ctx, store.Category{Name: v2p("Shirts"), ParentID: &clothing.ID})
+	require.NoError(t, err)
+	require.NoError(t, s.SetItemCategories(ctx, shirt.ID, []uint{shirts.ID}))
+	_, err = s.AdjustStock(ctx, store.StockKey{ItemID: shirt.ID}, 3)
+	require.NoError(t, err)
+	_, err = s.CreateScheduledPrice(ctx, shirt.ID, store.ScheduledPrice{Currency: "EUR", Price: 1000,
+		StartsAt: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+
+	t.Run("CSV has regular price and sale price", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/export", nil)
+
+		require.NoError(t, h.ExportItems(eCtx, api.ExportItemsParams{Format: api.ExportFormatCsv}))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="items.csv"`, rec.Header().Get("Content-Disposition"))
+		assert.Equal(t, "id,externalId,name,description,price,priceCode,salePrice,available\n"+
+			"1,SUP-1,\"Shirt, blue\",Cotton,12.50,EUR,10.00,3\n"+
+			"2,,Hat,Wool,5.00,EUR,,0\n"+
+			"3,,Socks,,1500,JPY,,0\n", rec.Body.String())
+	})
+
+	t.Run("NDJSON is filtered and resumed from cursor", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/export", nil)
+
+		require.NoError(t, h.ExportItems(eCtx, api.ExportItemsParams{
+			Format: api.ExportFormatNdjson, PriceCode: v2p("EUR"), Cursor: &shirt.ID,
+		}))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+		require.Len(t, lines, 1)
+		var item api.ItemResponse
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &item))
+		assert.Equal(t, hat.ID, *item.Id)
+	})
+
+	t.Run("Merchant feed", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/export", nil)
+
+		require.NoError(t, h.ExportItems(eCtx, api.ExportItemsParams{
+			Format: api.ExportFormatMerchantXml, CategoryId: &clothing.ID, IncludeDescendants: v2p(true),
+		}))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var feed struct {
+			Items []struct {
+				ID           string `xml:"id"`
+				Title        string `xml:"title"`
+				Link         string `xml:"link"`
+				Availability string `xml:"availability"`
+				Price        string `xml:"price"`
+				SalePrice    string `xml:"sale_price"`
+				ProductType  string `xml:"product_type"`
+			} `xml:"channel>item"`
+		}
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &feed))
+		require.Len(t, feed.Items, 1)
+		product := feed.Items[0]
+		assert.Equal(t, "1", product.ID)
+		assert.Equal(t, "Shirt, blue", product.Title)
+		assert.Equal(t, "https://shop.example.com/items/1", product.Link)
+		assert.Equal(t, "in_stock", product.Availability)
+		assert.Equal(t, "12.50 EUR", product.Price)
+		assert.Equal(t, "10.00 EUR", product.SalePrice)
+		assert.Equal(t, "Clothing > Shirts", product.ProductType)
+	})
+
+	t.Run("Empty export", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/export", nil)
+
+		require.NoError(t, h.ExportItems(eCtx, api.ExportItemsParams{Format: api.ExportFormatCsv, Name: v2p("coat")}))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "id,externalId,name,description,price,priceCode,salePrice,available\n", rec.Body.String())
+	})
+
+	t.Run("Invalid filter", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/export", nil)
+
+		require.NoError(t, h.ExportItems(eCtx, api.ExportItemsParams{Format: api.ExportFormatCsv, MinPrice: v2p("x")}))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/export", nil)
+
+		require.NoError(t, h.ExportItems(eCtx, api.ExportItemsParams{Format: "xlsx"}))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestExportItems_merchantImages(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemoryStore()
+	item, err := s.CreateItem(ctx, store.Item{Name: v2p("Hat"), Description: v2p("Wool"), Price: v2p(int64(500)),
+		PriceCode: v2p("EUR")})
+	require.NoError(t, err)
+	for _, key := range []string{"images/aa/a", "images/bb/b"} {
+		_, err := s.CreateItemImage(ctx, item.ID, store.ItemImage{BlobKey: key, ContentType: "image/jpeg"})
+		require.NoError(t, err)
+	}
+	h := NewHandler(logrus.New(), s, allCurrencies, noRates, nil).WithMedia(nil, "https://cdn.example.com")
+	eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/export", nil)
+
+	require.NoError(t, h.ExportItems(eCtx, api.ExportItemsParams{Format: api.ExportFormatMerchantXml}))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var feed struct {
+		Items []struct {
+			ImageLink        string   `xml:"image_link"`
+			AdditionalImages []string `xml:"additional_image_link"`
+		} `xml:"channel>item"`
+	}
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &feed))
+	require.Len(t, feed.Items, 1)
+	assert.Equal(t, "https://cdn.example.com/api/v1/items/1/images/1/original", feed.Items[0].ImageLink)
+	assert.Equal(t, []string{"https://cdn.example.com/api/v1/items/1/images/2/original"}, feed.Items[0].AdditionalImages)
+}
+
+func TestExportItems_storeError(t *testing.T) {
+	h := NewHandler(logrus.New(), &mockCatalogStore{err: errors.New("connection refused")}, allCurrencies, noRates, nil)
+	eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/export", nil)
+
+	require.NoError(t, h.ExportItems(eCtx, api.ExportItemsParams{Format: api.ExportFormatNdjson}))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestExportItems_interrupted(t *testing.T) {
+	s := &interruptedStore{MemoryStore: store.NewMemoryStore()}
+	_, err := s.CreateItem(context.Background(), store.Item{Name: v2p("Hat"), Description: v2p("Wool"),
+		Price: v2p(int64(500)), PriceCode: v2p("EUR")})
+	require.NoError(t, err)
+	h := NewHandler(logrus.New(), s, allCurrencies, noRates, nil)
+	eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/export", nil)
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		_ = h.ExportItems(eCtx, api.ExportItemsParams{Format: api.ExportFormatMerchantXml})
+	}, "connection is aborted")
+	assert.Contains(t, rec.Body.String(), "<g:title>Hat</g:title>")
+	assert.NotContains(t, rec.Body.String(), "</rss>")
+}
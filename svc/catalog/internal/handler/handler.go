@@ -1,9 +1,14 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/media"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/outbox"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/ptr"
 	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
@@ -12,25 +17,114 @@ import (
 	"gorm.io/gorm"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 var _ api.ServerInterface = (*handler)(nil)
 
+// statusClientClosedRequest is returned when client aborted the request before it was handled
+const statusClientClosedRequest = 499
+
 type CatalogStore interface {
-	CreateItem(item store.Item) (store.Item, error)
-	DeleteItem(id uint) error
-	GetItem(id uint) (store.Item, error)
-	GetItems(pageSize, page int) (users []store.Item, err error)
-	UpdateItem(id uint, item store.Item) error
+	CreateItem(ctx context.Context, item store.Item) (store.Item, error)
+	DeleteItem(ctx context.Context, id uint, ifVersion uint) error
+	GetItem(ctx context.Context, id uint) (store.Item, error)
+	GetItems(ctx context.Context, query store.ItemQuery) (users []store.Item, err error)
+	CountItems(ctx context.Context, filter store.ItemFilter) (int64, error)
+	SearchItems(ctx context.Context, query store.SearchQuery) ([]store.SearchResult, int64, error)
+	UpdateItem(ctx context.Context, id uint, item store.Item, ifVersion uint) (store.Item, error)
+	GetDeletedItems(ctx context.Context, pageSize, page int) ([]store.Item, error)
+	RestoreItem(ctx context.Context, id uint) (store.Item, error)
+	CreateCategory(ctx context.Context, category store.Category) (store.Category, error)
+	GetCategories(ctx context.Context) ([]store.Category, error)
+	GetCategoryPath(ctx context.Context, id uint) ([]store.Category, error)
+	UpdateCategory(ctx context.Context, id uint, category store.Category) (store.Category, error)
+	DeleteCategory(ctx context.Context, id uint) error
+	SetItemCategories(ctx context.Context, itemID uint, categoryIDs []uint) error
+	GetItemBreadcrumbs(ctx context.Context, itemIDs []uint) (map[uint][][]store.Category, error)
+	CreateVariant(ctx context.Context, itemID uint, variant store.Variant) (store.Variant, error)
+	GetVariants(ctx context.Context, itemID uint) ([]store.Variant, error)
+	GetVariant(ctx context.Context, itemID, id uint) (store.Variant, error)
+	UpdateVariant(ctx context.Context, itemID, id uint, variant store.Variant) (store.Variant, error)
+	DeleteVariant(ctx context.Context, itemID, id uint) error
+	GetStock(ctx context.Context, key store.StockKey) (store.Stock, error)
+	GetStockLevels(ctx context.Context, itemIDs []uint) ([]store.Stock, error)
+	AdjustStock(ctx context.Context, key store.StockKey, delta int64) (store.Stock, error)
+	Reserve(ctx context.Context, key store.StockKey, quantity int64, ttl time.Duration) (store.Reservation, error)
+	GetReservation(ctx context.Context, id uint) (store.Reservation, error)
+	CommitReservation(ctx context.Context, id uint) (store.Reservation, error)
+	ReleaseReservation(ctx context.Context, id uint) (store.Reservation, error)
+	SetItemPrices(ctx context.Context, itemID uint, prices []store.ItemPrice) ([]store.ItemPrice, error)
+	GetItemPrices(ctx context.Context, itemIDs []uint) ([]store.ItemPrice, error)
+	SetExchangeRates(ctx context.Context, rates money.Rates) error
+	GetExchangeRates(ctx context.Context) (money.Rates, error)
+	CreateScheduledPrice(ctx context.Context, itemID uint, price store.ScheduledPrice) (store.ScheduledPrice, error)
+	GetScheduledPrices(ctx context.Context, itemID uint) ([]store.ScheduledPrice, error)
+	DeleteScheduledPrice(ctx context.Context, itemID, id uint) error
+	GetActivePrices(ctx context.Context, itemIDs []uint, at time.Time) ([]store.ScheduledPrice, error)
+	GetUpcomingPrices(ctx context.Context, after time.Time, pageSize, page int) ([]store.ScheduledPrice, error)
+	GetItemHistory(ctx context.Context, itemID uint) ([]store.ItemChange, error)
+	GetItemAsOf(ctx context.Context, itemID uint, at time.Time) (store.Item, error)
+	CreateWebhook(ctx context.Context, webhook store.Webhook) (store.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]store.Webhook, error)
+	GetWebhook(ctx context.Context, id uint) (store.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uint) error
+	GetDeliveries(ctx context.Context, query store.DeliveryQuery) ([]store.WebhookDelivery, error)
+	RedeliverDelivery(ctx context.Context, webhookID, id uint) (store.WebhookDelivery, error)
+	GetEvents(ctx context.Context, afterPosition uint64, limit int) ([]store.Event, error)
+	ApplyItemOperations(ctx context.Context, ops []store.ItemOperation) ([]store.Item, error)
+	GetItemByExternalID(ctx context.Context, externalID string) (store.Item, error)
+	UpsertItemByExternalID(ctx context.Context, item store.Item) (store.Item, store.UpsertOutcome, error)
+	ExportItems(ctx context.Context, query store.ExportQuery, export func(items []store.Item) error) error
+	CreateImportJob(ctx context.Context, format store.ImportFormat, data []byte) (store.ImportJob, error)
+	GetImportJob(ctx context.Context, id uint) (store.ImportJob, error)
+	GetImportRejections(ctx context.Context, jobID uint, pageSize, page int) ([]store.ImportRejection, error)
+	CreateItemImage(ctx context.Context, itemID uint, image store.ItemImage) (store.ItemImage, error)
+	GetItemImages(ctx context.Context, itemIDs []uint) (map[uint][]store.ItemImage, error)
+	GetItemImage(ctx context.Context, itemID, id uint) (store.ItemImage, error)
+	UpdateItemImage(ctx context.Context, itemID, id uint, altText *string, position int) (store.ItemImage, error)
+	DeleteItemImage(ctx context.Context, itemID, id uint) error
 }
 
 type handler struct {
 	log   *logrus.Logger
 	store CatalogStore
+	// currencies are the ones items can be priced in
+	currencies *money.CurrencySet
+	// exchange holds rates prices are converted with to currencies items have no explicit price in
+	exchange *money.Exchange
+	// events are item events published by the outbox relay, streamed to clients
+	events *outbox.Broker
+	// heartbeat is how often idle event streams are kept alive
+	heartbeat time.Duration
+	// itemURL is a template of links to item pages in merchant feeds, with {id} placeholder
+	itemURL string
+	// media keeps uploaded images of items and their thumbnails
+	media *media.Library
+	// mediaURL is prepended to paths of image files, e.g. to serve them through CDN
+	mediaURL string
+	now      func() time.Time
+}
+
+func NewHandler(log *logrus.Logger, store CatalogStore, currencies *money.CurrencySet, exchange *money.Exchange,
+	events *outbox.Broker) *handler {
+	return &handler{store: store, log: log, currencies: currencies, exchange: exchange, events: events,
+		heartbeat: eventStreamHeartbeat, now: time.Now}
 }
 
-func NewHandler(log *logrus.Logger, store CatalogStore) *handler {
-	return &handler{store: store, log: log}
+// WithItemURL sets template of links to item pages in merchant feeds, e.g. https://shop.example.com/items/{id}
+func (h *handler) WithItemURL(template string) *handler {
+	h.itemURL = template
+	return h
+}
+
+// WithMedia sets library images of items are kept in and base URL of their files, e.g. https://cdn.example.com.
+// Image files are linked relative to the catalog when baseURL is empty.
+func (h *handler) WithMedia(library *media.Library, baseURL string) *handler {
+	h.media = library
+	h.mediaURL = strings.TrimSuffix(baseURL, "/")
+	return h
 }
 
 // GetHealtz handles liveliness and readiness probes
@@ -51,31 +145,103 @@ func (h *handler) GetApiDocs(ctx echo.Context) error {
 	return ctx.JSONBlob(http.StatusOK, json)
 }
 
-// GetItems returns items from underlying store
+// GetItems returns filtered and sorted items from underlying store
 func (h *handler) GetItems(eCtx echo.Context, params api.GetItemsParams) error {
-	_, span := otel.Tracer("").Start(eCtx.Request().Context(), "GetItems")
+	ctx, span := otel.Tracer("").Start(eCtx.Request().Context(), "GetItems")
 	defer span.End()
 
-	page := nvl(params.Page, 1)
-	pageSize := nvl(params.PageSize, 100)
+	page, err := h.listItems(ctx, eCtx, params)
+	if err != nil {
+		return h.writeErrorResponse(eCtx, fmt.Errorf("error while getting items: %w", err))
+	}
+	return eCtx.JSON(http.StatusOK, page.Items)
+}
+
+// GetItemsPage returns filtered and sorted items from underlying store wrapped with pagination metadata
+func (h *handler) GetItemsPage(eCtx echo.Context, params api.GetItemsPageParams) error {
+	ctx, span := otel.Tracer("").Start(eCtx.Request().Context(), "GetItemsPage")
+	defer span.End()
 
-	items, err := h.store.GetItems(pageSize, page)
+	page, err := h.listItems(ctx, eCtx, api.GetItemsParams(params))
 	if err != nil {
 		return h.writeErrorResponse(eCtx, fmt.Errorf("error while getting items: %w", err))
 	}
-	return eCtx.JSON(http.StatusOK, items)
+	return eCtx.JSON(http.StatusOK, page)
+}
+
+// SearchItems returns items matching full-text query from underlying store, most relevant first
+func (h *handler) SearchItems(eCtx echo.Context, params api.SearchItemsParams) error {
+	ctx, span := otel.Tracer("").Start(eCtx.Request().Context(), "SearchItems")
+	defer span.End()
+
+	query := store.SearchQuery{
+		Text:     params.Q,
+		PageSize: ptr.ValueOr(params.PageSize, 100),
+		Page:     ptr.ValueOr(params.Page, 1),
+	}
+	results, total, err := h.store.SearchItems(ctx, query)
+	if err != nil {
+		return h.writeErrorResponse(eCtx, fmt.Errorf("error while searching items: %w", err))
+	}
+
+	items := make([]store.Item, 0, len(results))
+	for _, result := range results {
+		items = append(items, result.Item)
+	}
+	itemResps, err := h.itemResponses(ctx, items, nil, false)
+	if err != nil {
+		return h.writeErrorResponse(eCtx, fmt.Errorf("error while searching items: %w", err))
+	}
+
+	resp := api.SearchPage{
+		Items:      make([]api.SearchResult, 0, len(results)),
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalCount: total,
+	}
+	for i, result := range results {
+		resp.Items = append(resp.Items, api.SearchResult{
+			Item:                 itemResps[i],
+			Rank:                 result.Rank,
+			NameHighlight:        result.NameHighlight,
+			DescriptionHighlight: result.DescriptionHighlight,
+		})
+	}
+	return eCtx.JSON(http.StatusOK, resp)
 }
 
-// FindItemByID returns item with ID from the underlying store
-func (h *handler) FindItemByID(eCtx echo.Context, id uint) error {
-	_, span := otel.Tracer("").Start(eCtx.Request().Context(), "GetItem")
+// FindItemByID returns item with ID along with its variants, price list and available stock from the underlying store. Item is not returned if it
+// matches If-None-Match header.
+func (h *handler) FindItemByID(eCtx echo.Context, id uint, params api.FindItemByIDParams) error {
+	ctx, span := otel.Tracer("").Start(eCtx.Request().Context(), "GetItem")
 	span.SetAttributes(attribute.Key("itemID").String(strconv.Itoa(int(id))))
 	defer span.End()
-	item, err := h.store.GetItem(id)
+	currency, err := h.requestedCurrency(params.Currency)
+	if err != nil {
+		return h.writeErrorResponse(eCtx, err)
+	}
+	item, err := h.store.GetItem(ctx, id)
+	if err != nil {
+		return h.writeErrorResponse(eCtx, err)
+	}
+	return h.writeItem(ctx, eCtx, item, currency, params.IfNoneMatch)
+}
+
+// writeItem responds with item along with its variants, price list and available stock, unless it matches
+// ifNoneMatch ETag. ETag holds item version followed by sales in effect, so it changes when a sale starts or ends.
+func (h *handler) writeItem(ctx context.Context, eCtx echo.Context, item store.Item, currency *string,
+	ifNoneMatch *string) error {
+	resp, state, err := h.itemResponsesWithState(ctx, []store.Item{item}, currency, true)
 	if err != nil {
 		return h.writeErrorResponse(eCtx, err)
 	}
-	return eCtx.JSON(http.StatusOK, item)
+
+	etag := itemETag(item.Version, state.sales[item.ID])
+	eCtx.Response().Header().Set(headerETag, etag)
+	if !noneMatch(ifNoneMatch, etag) {
+		return eCtx.NoContent(http.StatusNotModified)
+	}
+	return eCtx.JSON(http.StatusOK, resp[0])
 }
 
 // CreateItem handles creation of a new item in the underlying store
@@ -85,63 +251,209 @@ func (h *handler) CreateItem(ctx echo.Context) error {
 		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
 	}
 
-	item, err := h.store.CreateItem(mapItemToItemModel(newItem))
+	model, err := mapItemToItemModel(newItem, h.currencies)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	item, err := h.store.CreateItem(ctx.Request().Context(), model)
 	if err != nil {
 		return h.writeErrorResponse(ctx, err)
 	}
 
-	return ctx.JSON(http.StatusCreated, item)
+	ctx.Response().Header().Set(headerETag, formatETag(item.Version))
+	return ctx.JSON(http.StatusCreated, mapItemModelToItemResponse(item))
 }
 
-// DeleteItemByID deletes item by ID from the underlying store
-func (h *handler) DeleteItemByID(ctx echo.Context, id uint) error {
-	if err := h.store.DeleteItem(id); err != nil {
+// DeleteItemByID deletes item by ID from the underlying store. When If-Match header is set, item is deleted only
+// if its version matches.
+func (h *handler) DeleteItemByID(ctx echo.Context, id uint, params api.DeleteItemByIDParams) error {
+	ifVersion, err := h.expectedVersion(ctx.Request().Context(), id, params.IfMatch)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	if err := h.store.DeleteItem(ctx.Request().Context(), id, ifVersion); err != nil {
 		return h.writeErrorResponse(ctx, err)
 	}
 	return ctx.NoContent(http.StatusOK)
 }
 
-// UpdateItemByID updates item with ID in the underlying store
-func (h *handler) UpdateItemByID(ctx echo.Context, id uint) error {
+// UpdateItemByID updates item with ID in the underlying store. When If-Match header is set, item is updated only
+// if its version matches.
+func (h *handler) UpdateItemByID(ctx echo.Context, id uint, params api.UpdateItemByIDParams) error {
 	var item api.UpdateItemRequest
 	if err := ctx.Bind(&item); err != nil {
 		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
 	}
 
-	if err := h.store.UpdateItem(id, store.Item{
+	price, priceCode, err := parsePrice(item.Price, item.PriceCode, h.currencies)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	ifVersion, err := h.expectedVersion(ctx.Request().Context(), id, params.IfMatch)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	updated, err := h.store.UpdateItem(ctx.Request().Context(), id, store.Item{
 		Name:        item.Name,
 		Description: item.Description,
-		Price:       item.Price,
-		PriceCode:   item.PriceCode,
-	}); err != nil {
+		Price:       price,
+		PriceCode:   priceCode,
+	}, ifVersion)
+	if err != nil {
 		return h.writeErrorResponse(ctx, err)
 	}
 
-	return ctx.NoContent(http.StatusOK)
+	ctx.Response().Header().Set(headerETag, formatETag(updated.Version))
+	return ctx.JSON(http.StatusOK, mapItemModelToItemResponse(updated))
+}
+
+// GetDeletedItems returns items from the underlying store trash
+func (h *handler) GetDeletedItems(ctx echo.Context, params api.GetDeletedItemsParams) error {
+	page := ptr.ValueOr(params.Page, 1)
+	pageSize := ptr.ValueOr(params.PageSize, 100)
+
+	items, err := h.store.GetDeletedItems(ctx.Request().Context(), pageSize, page)
+	if err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while getting deleted items: %w", err))
+	}
+
+	resp := make([]api.ItemResponse, 0, len(items))
+	for _, item := range items {
+		resp = append(resp, mapItemModelToItemResponse(item))
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// RestoreItemByID restores deleted item with ID in the underlying store
+func (h *handler) RestoreItemByID(ctx echo.Context, id uint) error {
+	item, err := h.store.RestoreItem(ctx.Request().Context(), id)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+
+	ctx.Response().Header().Set(headerETag, formatETag(item.Version))
+	return ctx.JSON(http.StatusOK, mapItemModelToItemResponse(item))
 }
 
 func (h *handler) writeErrorResponse(ctx echo.Context, err error) error {
 	h.log.Errorf(err.Error())
+	return ctx.JSON(errorStatus(err), mapErrorToErrorResponse(err))
+}
+
+// errorStatus returns HTTP status of the response to request which failed with err
+func errorStatus(err error) int {
 	code := http.StatusInternalServerError
-	if errors.Is(err, gorm.ErrRecordNotFound) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, media.ErrBlobNotFound):
 		code = http.StatusNotFound
+	case errors.Is(err, store.ErrInvalidItem), errors.Is(err, store.ErrInvalidPageParams),
+		errors.Is(err, store.ErrInvalidSort), errors.Is(err, store.ErrInvalidCursor),
+		errors.Is(err, store.ErrInvalidSearchQuery), errors.Is(err, store.ErrInvalidOperation):
+		code = http.StatusBadRequest
+	case errors.Is(err, store.ErrInvalidCategory), errors.Is(err, store.ErrInvalidVariant),
+		errors.Is(err, store.ErrInvalidStock), errors.Is(err, money.ErrInvalidAmount),
+		errors.Is(err, money.ErrInvalidCurrency), errors.Is(err, store.ErrInvalidPrice),
+		errors.Is(err, money.ErrInvalidRates), errors.Is(err, store.ErrInvalidWebhook),
+		errors.Is(err, store.ErrInvalidImport), errors.Is(err, errUnsupportedFormat),
+		errors.Is(err, store.ErrInvalidImage), errors.Is(err, media.ErrUnsupportedImage):
+		code = http.StatusBadRequest
+	case errors.Is(err, store.ErrCategoryHasChildren), errors.Is(err, store.ErrVariantConflict),
+		errors.Is(err, store.ErrExternalIDConflict):
+		code = http.StatusConflict
+	case errors.Is(err, store.ErrInsufficientStock), errors.Is(err, store.ErrReservationNotPending),
+		errors.Is(err, store.ErrReservationExpired), errors.Is(err, store.ErrDeliveryPending):
+		code = http.StatusConflict
+	case errors.Is(err, store.ErrVersionMismatch):
+		code = http.StatusPreconditionFailed
+	case errors.Is(err, outbox.ErrBrokerClosed):
+		code = http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		code = http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		code = statusClientClosedRequest
+	}
+	return code
+}
+
+func mapErrorToErrorResponse(err error) api.ErrorResponse {
+	resp := api.ErrorResponse{Message: err.Error()}
+	var fErr fieldError
+	if errors.As(err, &fErr) {
+		resp.Field = &fErr.field
 	}
-	return ctx.JSON(code, api.ErrorResponse{Message: err.Error()})
+	return resp
+}
+
+// fieldError attributes error to the request field which caused it
+type fieldError struct {
+	field string
+	err   error
+}
+
+func (e fieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.field, e.err)
 }
 
-func mapItemToItemModel(newItem api.NewItemRequest) store.Item {
+func (e fieldError) Unwrap() error {
+	return e.err
+}
+
+func mapItemToItemModel(newItem api.NewItemRequest, currencies *money.CurrencySet) (store.Item, error) {
+	price, priceCode, err := parsePrice(&newItem.Price, &newItem.PriceCode, currencies)
+	if err != nil {
+		return store.Item{}, err
+	}
 	return store.Item{
+		ExternalID:  newItem.ExternalId,
 		Name:        &newItem.Name,
 		Description: &newItem.Description,
-		Price:       &newItem.Price,
-		PriceCode:   &newItem.PriceCode,
+		Price:       price,
+		PriceCode:   priceCode,
+	}, nil
+}
+
+func mapItemModelToItemResponse(item store.Item) api.ItemResponse {
+	etag := formatETag(item.Version)
+	resp := api.ItemResponse{
+		Id:          &item.ID,
+		ExternalId:  item.ExternalID,
+		Name:        item.Name,
+		Description: item.Description,
+		Price:       formatPrice(item.Price, item.PriceCode),
+		PriceCode:   item.PriceCode,
+		Etag:        &etag,
+	}
+	if item.DeletedAt.Valid {
+		resp.DeletedAt = &item.DeletedAt.Time
+	}
+	return resp
+}
+
+// parsePrice converts decimal price in major units of the currency into minor units and normalizes the currency
+// code. Price without currency, and the other way around, is left for the store to reject.
+func parsePrice(price, priceCode *string, currencies *money.CurrencySet) (*int64, *string, error) {
+	if priceCode != nil {
+		code, err := currencies.Normalize(*priceCode)
+		if err != nil {
+			return nil, nil, fieldError{field: "priceCode", err: err}
+		}
+		priceCode = &code
+	}
+	if price == nil {
+		return nil, priceCode, nil
+	}
+	amount, err := money.Parse(*price, ptr.ValueOr(priceCode, ""))
+	if err != nil {
+		return nil, nil, fieldError{field: "price", err: err}
 	}
+	return &amount.Minor, priceCode, nil
 }
 
-// nvl returns a if a is not nil or else return b
-func nvl(a *int, b int) int {
-	if a == nil {
-		return b
+// formatPrice formats price in minor units as decimal string in major units of the currency
+func formatPrice(price *int64, priceCode *string) *string {
+	if price == nil {
+		return nil
 	}
-	return *a
+	formatted := money.Amount{Minor: *price, Currency: ptr.ValueOr(priceCode, "")}.String()
+	return &formatted
 }
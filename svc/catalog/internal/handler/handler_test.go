@@ -2,10 +2,12 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
 	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
@@ -15,14 +17,21 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
+// allCurrencies accepts every ISO 4217 currency
+var allCurrencies, _ = money.NewCurrencySet()
+
+// noRates is an exchange without any rates loaded
+var noRates = money.NewExchange(money.Rates{})
+
 func TestGetHealtz(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/healtz", nil)
 	rec := httptest.NewRecorder()
 	ctx := echo.New().NewContext(req, rec)
 
-	err := NewHandler(logrus.New(), &mockCatalogStore{}).GetHealtz(ctx)
+	err := NewHandler(logrus.New(), &mockCatalogStore{}, allCurrencies, noRates, nil).GetHealtz(ctx)
 
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
@@ -33,7 +42,7 @@ func TestGetApiDocs(t *testing.T) {
 	rec := httptest.NewRecorder()
 	ctx := echo.New().NewContext(req, rec)
 
-	err := NewHandler(logrus.New(), &mockCatalogStore{}).GetApiDocs(ctx)
+	err := NewHandler(logrus.New(), &mockCatalogStore{}, allCurrencies, noRates, nil).GetApiDocs(ctx)
 
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
@@ -48,7 +57,11 @@ func TestGetItems(t *testing.T) {
 		err              error
 		expectedPage     int
 		expectedPageSize int
+		expectedFilter   store.ItemFilter
+		expectedSort     string
+		expectedCursor   string
 		expectedStatus   int
+		expectNextCursor bool
 	}{
 		{
 			name:             "Successful - default values taken for query params",
@@ -68,6 +81,58 @@ func TestGetItems(t *testing.T) {
 			expectedPage:     2,
 			expectedPageSize: 2,
 			expectedStatus:   http.StatusOK,
+			expectNextCursor: true,
+		},
+		{
+			name: "Successful - cursor passed to store",
+			queryParams: api.GetItemsParams{
+				PageSize: v2p(2),
+				Cursor:   v2p("cursor"),
+			},
+			storeResponse:    []store.Item{{ID: 3}},
+			expectedPage:     1,
+			expectedPageSize: 2,
+			expectedCursor:   "cursor",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name: "Bad Request - cursor combined with page",
+			queryParams: api.GetItemsParams{
+				Page:   v2p(2),
+				Cursor: v2p("cursor"),
+			},
+			err:            store.ErrInvalidCursor,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Successful - filters and sort passed to store",
+			queryParams: api.GetItemsParams{
+				MinPrice:  v2p("1.5"),
+				MaxPrice:  v2p("10"),
+				PriceCode: v2p("eur"),
+				Name:      v2p("shirt"),
+				Sort:      v2p(api.ItemSort("-price")),
+			},
+			storeResponse:    []store.Item{{ID: 1}},
+			expectedPage:     1,
+			expectedPageSize: 100,
+			expectedFilter: store.ItemFilter{
+				MinPrice:  v2p("1.5"),
+				MaxPrice:  v2p("10"),
+				PriceCode: v2p("eur"),
+				Name:      v2p("shirt"),
+			},
+			expectedSort:   "-price",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:             "Bad Request - invalid sort",
+			queryParams:      api.GetItemsParams{Sort: v2p(api.ItemSort("description"))},
+			err:              store.ErrInvalidSort,
+			expectedPage:     1,
+			expectedPageSize: 100,
+			expectedSort:     "description",
+			expectedStatus:   http.StatusBadRequest,
 		},
 		{
 			name:             "Internal Error - error from store",
@@ -92,6 +157,9 @@ func TestGetItems(t *testing.T) {
 				t:                t,
 				expectedPage:     test.expectedPage,
 				expectedPageSize: test.expectedPageSize,
+				expectedFilter:   test.expectedFilter,
+				expectedSort:     test.expectedSort,
+				expectedCursor:   test.expectedCursor,
 				getItemsResponse: test.storeResponse,
 				err:              test.err}
 
@@ -99,10 +167,11 @@ func TestGetItems(t *testing.T) {
 			rec := httptest.NewRecorder()
 			ctx := echo.New().NewContext(req, rec)
 
-			err := NewHandler(logrus.New(), mockStore).GetItems(ctx, test.queryParams)
+			err := NewHandler(logrus.New(), mockStore, allCurrencies, noRates, nil).GetItems(ctx, test.queryParams)
 			require.NoError(t, err)
 
 			assert.Equal(t, rec.Code, test.expectedStatus)
+			assert.Equal(t, test.expectNextCursor, rec.Header().Get(headerNextCursor) != "")
 			if test.expectedStatus != http.StatusOK {
 				var respMsg api.ErrorResponse
 				err = json.NewDecoder(rec.Body).Decode(&respMsg)
@@ -114,9 +183,77 @@ func TestGetItems(t *testing.T) {
 	}
 }
 
+func TestSearchItems(t *testing.T) {
+	tests := []struct {
+		name           string
+		queryParams    api.SearchItemsParams
+		storeResponse  []store.SearchResult
+		err            error
+		expectedQuery  store.SearchQuery
+		expectedStatus int
+	}{
+		{
+			name:        "Successful - results returned with highlights",
+			queryParams: api.SearchItemsParams{Q: "sweater", PageSize: v2p(10), Page: v2p(2)},
+			storeResponse: []store.SearchResult{{
+				Item:                 store.Item{ID: 1, Name: v2p("Wool sweater")},
+				Rank:                 0.6,
+				NameHighlight:        "Wool <mark>sweater</mark>",
+				DescriptionHighlight: "Warm",
+			}},
+			expectedQuery:  store.SearchQuery{Text: "sweater", PageSize: 10, Page: 2},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Bad Request - no words in query",
+			queryParams:    api.SearchItemsParams{Q: "-"},
+			err:            store.ErrInvalidSearchQuery,
+			expectedQuery:  store.SearchQuery{Text: "-", PageSize: 100, Page: 1},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Internal Error - error from store",
+			queryParams:    api.SearchItemsParams{Q: "sweater"},
+			err:            errors.New("some error"),
+			expectedQuery:  store.SearchQuery{Text: "sweater", PageSize: 100, Page: 1},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockStore := &mockCatalogStore{
+				t:              t,
+				expectedSearch: test.expectedQuery,
+				searchResponse: test.storeResponse,
+				countResponse:  int64(len(test.storeResponse)),
+				err:            test.err}
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/items/search", nil)
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(req, rec)
+
+			err := NewHandler(logrus.New(), mockStore, allCurrencies, noRates, nil).SearchItems(ctx, test.queryParams)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+			var resp api.SearchPage
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, test.expectedQuery.Page, resp.Page)
+			assert.Equal(t, int64(len(test.storeResponse)), resp.TotalCount)
+			require.Len(t, resp.Items, len(test.storeResponse))
+			assert.Equal(t, test.storeResponse[0].NameHighlight, resp.Items[0].NameHighlight)
+			assert.Equal(t, test.storeResponse[0].Rank, resp.Items[0].Rank)
+			assert.Equal(t, test.storeResponse[0].Name, resp.Items[0].Item.Name)
+		})
+	}
+}
+
 func TestFindItemByID(t *testing.T) {
 	tests := []struct {
 		name           string
+		ifNoneMatch    *string
 		err            error
 		expectedStatus int
 	}{
@@ -124,6 +261,16 @@ func TestFindItemByID(t *testing.T) {
 			name:           "Successful",
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "Successful - If-None-Match with stale version",
+			ifNoneMatch:    v2p(`"1"`),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Successful - If-None-Match with current version",
+			ifNoneMatch:    v2p(`W/"1", "2"`),
+			expectedStatus: http.StatusNotModified,
+		},
 		{
 			name:           "Unsuccessful - no record in db",
 			err:            gorm.ErrRecordNotFound,
@@ -134,19 +281,37 @@ func TestFindItemByID(t *testing.T) {
 			err:            errors.New("some error"),
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name:           "Unsuccessful - query timeout",
+			err:            fmt.Errorf("error while getting item with id 1: %w", context.DeadlineExceeded),
+			expectedStatus: http.StatusGatewayTimeout,
+		},
+		{
+			name:           "Unsuccessful - client aborted request",
+			err:            fmt.Errorf("error while getting item with id 1: %w", context.Canceled),
+			expectedStatus: statusClientClosedRequest,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			mockStore := &mockCatalogStore{t: t, expectedID: 1, err: test.err}
+			mockStore := &mockCatalogStore{t: t, expectedID: 1, err: test.err, findItemResponse: store.Item{Version: 2}}
 			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/items/1"), nil)
 			rec := httptest.NewRecorder()
 			ctx := echo.New().NewContext(req, rec)
 
-			err := NewHandler(logrus.New(), mockStore).FindItemByID(ctx, 1)
+			err := NewHandler(logrus.New(), mockStore, allCurrencies, noRates, nil).FindItemByID(ctx, 1, api.FindItemByIDParams{IfNoneMatch: test.ifNoneMatch})
 			require.NoError(t, err)
 
 			assert.Equal(t, test.expectedStatus, rec.Code)
-			if test.expectedStatus != http.StatusOK {
+			if test.err == nil {
+				assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+			}
+			if test.expectedStatus == http.StatusOK {
+				var resp api.ItemResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, `"2"`, *resp.Etag)
+			}
+			if test.err != nil {
 				var respMsg api.ErrorResponse
 				err = json.NewDecoder(rec.Body).Decode(&respMsg)
 				require.NoError(t, err)
@@ -158,14 +323,41 @@ func TestFindItemByID(t *testing.T) {
 
 func TestDeleteItem(t *testing.T) {
 	tests := []struct {
-		name           string
-		err            error
-		expectedStatus int
+		name              string
+		ifMatch           *string
+		err               error
+		expectedIfVersion uint
+		expectedStatus    int
+		expectedMsg       string
 	}{
 		{
 			name:           "Successful",
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:              "Successful - If-Match with expected version",
+			ifMatch:           v2p(`"3"`),
+			expectedIfVersion: 3,
+			expectedStatus:    http.StatusOK,
+		},
+		{
+			name:           "Successful - If-Match with any version",
+			ifMatch:        v2p("*"),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:              "Unsuccessful - version mismatch",
+			ifMatch:           v2p(`"3"`),
+			err:               store.ErrVersionMismatch,
+			expectedIfVersion: 3,
+			expectedStatus:    http.StatusPreconditionFailed,
+		},
+		{
+			name:           "Unsuccessful - weak If-Match never matches",
+			ifMatch:        v2p(`W/"3"`),
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedMsg:    store.ErrVersionMismatch.Error(),
+		},
 		{
 			name:           "Unsuccessful - no record in db",
 			err:            gorm.ErrRecordNotFound,
@@ -179,12 +371,12 @@ func TestDeleteItem(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			mockStore := &mockCatalogStore{t: t, expectedID: 1, err: test.err}
+			mockStore := &mockCatalogStore{t: t, expectedID: 1, err: test.err, expectedIfVersion: test.expectedIfVersion}
 			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/items/1"), nil)
 			rec := httptest.NewRecorder()
 			ctx := echo.New().NewContext(req, rec)
 
-			err := NewHandler(logrus.New(), mockStore).DeleteItemByID(ctx, 1)
+			err := NewHandler(logrus.New(), mockStore, allCurrencies, noRates, nil).DeleteItemByID(ctx, 1, api.DeleteItemByIDParams{IfMatch: test.ifMatch})
 			require.NoError(t, err)
 
 			assert.Equal(t, test.expectedStatus, rec.Code)
@@ -192,13 +384,23 @@ func TestDeleteItem(t *testing.T) {
 				var respMsg api.ErrorResponse
 				err = json.NewDecoder(rec.Body).Decode(&respMsg)
 				require.NoError(t, err)
-				assert.Contains(t, respMsg.Message, test.err.Error())
+				if test.err != nil {
+					assert.Contains(t, respMsg.Message, test.err.Error())
+				} else {
+					assert.Contains(t, respMsg.Message, test.expectedMsg)
+				}
 			}
 		})
 	}
 }
 
 func TestCreateItem(t *testing.T) {
+	newItem := api.NewItemRequest{
+		Description: "someDesc",
+		Name:        "someName",
+		Price:       "50.5",
+		PriceCode:   "EUR",
+	}
 	tests := []struct {
 		name           string
 		newItem        api.NewItemRequest
@@ -206,27 +408,35 @@ func TestCreateItem(t *testing.T) {
 		expectedStatus int
 	}{
 		{
-			name: "Successful",
-			newItem: api.NewItemRequest{
-				Description: "someDesc",
-				Name:        "someName",
-				Price:       50,
-				PriceCode:   "EUR",
-			},
+			name:           "Successful",
+			newItem:        newItem,
 			expectedStatus: http.StatusCreated,
 		},
 		{
 			name:           "Unsuccessful - store internal error",
+			newItem:        newItem,
 			err:            errors.New("some error"),
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name:           "Unsuccessful - invalid item",
+			newItem:        newItem,
+			err:            fmt.Errorf("%w: name should be at most 250 characters long", store.ErrInvalidItem),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unsuccessful - price more precise than currency",
+			newItem:        api.NewItemRequest{Name: "someName", Description: "someDesc", Price: "50.5", PriceCode: "JPY"},
+			err:            money.ErrInvalidAmount,
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mockStore := &mockCatalogStore{t: t, err: test.err, expectedItem: store.Item{
 				Name:        &test.newItem.Name,
 				Description: &test.newItem.Description,
-				Price:       &test.newItem.Price,
+				Price:       v2p(int64(5050)),
 				PriceCode:   &test.newItem.PriceCode,
 			}}
 			body, err := json.Marshal(test.newItem)
@@ -236,7 +446,7 @@ func TestCreateItem(t *testing.T) {
 			rec := httptest.NewRecorder()
 			ctx := echo.New().NewContext(req, rec)
 
-			err = NewHandler(logrus.New(), mockStore).CreateItem(ctx)
+			err = NewHandler(logrus.New(), mockStore, allCurrencies, noRates, nil).CreateItem(ctx)
 			require.NoError(t, err)
 
 			assert.Equal(t, test.expectedStatus, rec.Code)
@@ -252,18 +462,43 @@ func TestCreateItem(t *testing.T) {
 
 func TestUpdateItem(t *testing.T) {
 	tests := []struct {
-		name           string
-		updateItemReq  api.UpdateItemRequest
-		err            error
-		expectedItem   store.Item
-		expectedStatus int
+		name              string
+		updateItemReq     api.UpdateItemRequest
+		ifMatch           *string
+		err               error
+		expectedItem      store.Item
+		expectedIfVersion uint
+		expectedStatus    int
 	}{
 		{
 			name:           "Successful",
-			updateItemReq:  api.UpdateItemRequest{Price: v2p(float64(50)), PriceCode: v2p("EUR")},
-			expectedItem:   store.Item{Price: v2p(float64(50)), PriceCode: v2p("EUR")},
+			updateItemReq:  api.UpdateItemRequest{Price: v2p("50"), PriceCode: v2p("JPY")},
+			expectedItem:   store.Item{Price: v2p(int64(50)), PriceCode: v2p("JPY")},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "Unsuccessful - price is not a decimal number",
+			updateItemReq:  api.UpdateItemRequest{Price: v2p("5e1"), PriceCode: v2p("EUR")},
+			err:            money.ErrInvalidAmount,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:              "Successful - If-Match with expected version",
+			updateItemReq:     api.UpdateItemRequest{Name: v2p("name")},
+			ifMatch:           v2p(`"4"`),
+			expectedItem:      store.Item{Name: v2p("name")},
+			expectedIfVersion: 4,
+			expectedStatus:    http.StatusOK,
+		},
+		{
+			name:              "Unsuccessful - version mismatch",
+			updateItemReq:     api.UpdateItemRequest{Name: v2p("name")},
+			ifMatch:           v2p(`"4"`),
+			err:               fmt.Errorf("error while updating item: %w", store.ErrVersionMismatch),
+			expectedItem:      store.Item{Name: v2p("name")},
+			expectedIfVersion: 4,
+			expectedStatus:    http.StatusPreconditionFailed,
+		},
 		{
 			name:           "Unsuccessful - no record in db",
 			err:            gorm.ErrRecordNotFound,
@@ -277,7 +512,7 @@ func TestUpdateItem(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			mockStore := &mockCatalogStore{t: t, err: test.err, expectedItem: test.expectedItem, expectedID: 1}
+			mockStore := &mockCatalogStore{t: t, err: test.err, expectedItem: test.expectedItem, expectedID: 1, expectedIfVersion: test.expectedIfVersion}
 			body, err := json.Marshal(test.updateItemReq)
 			require.NoError(t, err)
 			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/items/1"), bytes.NewReader(body))
@@ -285,10 +520,13 @@ func TestUpdateItem(t *testing.T) {
 			rec := httptest.NewRecorder()
 			ctx := echo.New().NewContext(req, rec)
 
-			err = NewHandler(logrus.New(), mockStore).UpdateItemByID(ctx, 1)
+			err = NewHandler(logrus.New(), mockStore, allCurrencies, noRates, nil).UpdateItemByID(ctx, 1, api.UpdateItemByIDParams{IfMatch: test.ifMatch})
 			require.NoError(t, err)
 
 			assert.Equal(t, test.expectedStatus, rec.Code)
+			if test.expectedStatus == http.StatusOK {
+				assert.Equal(t, formatETag(test.expectedIfVersion+1), rec.Header().Get("ETag"))
+			}
 			if test.expectedStatus != http.StatusOK {
 				var respMsg api.ErrorResponse
 				err = json.NewDecoder(rec.Body).Decode(&respMsg)
@@ -299,46 +537,361 @@ func TestUpdateItem(t *testing.T) {
 	}
 }
 
+func TestGetDeletedItems(t *testing.T) {
+	deletedAt := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		storeResponse  []store.Item
+		err            error
+		expectedStatus int
+	}{
+		{
+			name:           "Successful",
+			storeResponse:  []store.Item{{ID: 1, DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Internal Error - error from store",
+			err:            errors.New("some error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockStore := &mockCatalogStore{
+				t:                t,
+				expectedPage:     1,
+				expectedPageSize: 100,
+				getItemsResponse: test.storeResponse,
+				err:              test.err}
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/items/trash", nil)
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(req, rec)
+
+			err := NewHandler(logrus.New(), mockStore, allCurrencies, noRates, nil).GetDeletedItems(ctx, api.GetDeletedItemsParams{})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			if test.expectedStatus == http.StatusOK {
+				var resp []api.ItemResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				require.Len(t, resp, 1)
+				assert.Equal(t, deletedAt, *resp[0].DeletedAt)
+			}
+		})
+	}
+}
+
+func TestRestoreItemByID(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{
+			name:           "Successful",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unsuccessful - item not in trash",
+			err:            fmt.Errorf("error while restoring item with id 1: %w", gorm.ErrRecordNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockStore := &mockCatalogStore{t: t, expectedID: 1, err: test.err, findItemResponse: store.Item{Version: 3}}
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/items/1/restore", nil)
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(req, rec)
+
+			err := NewHandler(logrus.New(), mockStore, allCurrencies, noRates, nil).RestoreItemByID(ctx, 1)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			if test.expectedStatus == http.StatusOK {
+				assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
 type mockCatalogStore struct {
-	t                *testing.T
-	expectedID       uint
-	expectedPage     int
-	expectedPageSize int
-	expectedItem     store.Item
-	err              error
-	getItemsResponse []store.Item
-	findItemResponse store.Item
+	t                 *testing.T
+	expectedID        uint
+	expectedPage      int
+	expectedPageSize  int
+	expectedFilter    store.ItemFilter
+	expectedSort      string
+	expectedCursor    string
+	expectedItem      store.Item
+	countResponse     int64
+	expectedSearch    store.SearchQuery
+	searchResponse    []store.SearchResult
+	breadcrumbs       map[uint][][]store.Category
+	expectedIfVersion uint
+	err               error
+	getItemsResponse  []store.Item
+	findItemResponse  store.Item
 }
 
-func (m *mockCatalogStore) CreateItem(item store.Item) (store.Item, error) {
+func (m *mockCatalogStore) CreateItem(_ context.Context, item store.Item) (store.Item, error) {
 	assert.Equal(m.t, m.expectedItem, item)
 	item.ID = 1
 	return item, m.err
 }
 
-func (m *mockCatalogStore) DeleteItem(id uint) error {
+func (m *mockCatalogStore) DeleteItem(_ context.Context, id uint, ifVersion uint) error {
 	assert.Equal(m.t, m.expectedID, id)
+	assert.Equal(m.t, m.expectedIfVersion, ifVersion)
 	return m.err
 }
 
-func (m *mockCatalogStore) GetItem(id uint) (store.Item, error) {
+func (m *mockCatalogStore) GetItem(_ context.Context, id uint) (store.Item, error) {
 	assert.Equal(m.t, m.expectedID, id)
-	return store.Item{ID: 1}, m.err
+	item := m.findItemResponse
+	item.ID = 1
+	return item, m.err
+}
+
+func (m *mockCatalogStore) CountItems(_ context.Context, filter store.ItemFilter) (int64, error) {
+	assert.Equal(m.t, m.expectedFilter, filter)
+	return m.countResponse, nil
+}
+
+func (m *mockCatalogStore) CreateCategory(context.Context, store.Category) (store.Category, error) {
+	return store.Category{}, m.err
+}
+
+func (m *mockCatalogStore) GetCategories(context.Context) ([]store.Category, error) {
+	return nil, m.err
+}
+
+func (m *mockCatalogStore) GetCategoryPath(context.Context, uint) ([]store.Category, error) {
+	return nil, m.err
+}
+
+func (m *mockCatalogStore) UpdateCategory(context.Context, uint, store.Category) (store.Category, error) {
+	return store.Category{}, m.err
+}
+
+func (m *mockCatalogStore) DeleteCategory(context.Context, uint) error {
+	return m.err
+}
+
+func (m *mockCatalogStore) SetItemCategories(context.Context, uint, []uint) error {
+	return m.err
+}
+
+func (m *mockCatalogStore) GetItemBreadcrumbs(context.Context, []uint) (map[uint][][]store.Category, error) {
+	return m.breadcrumbs, nil
+}
+
+func (m *mockCatalogStore) CreateVariant(context.Context, uint, store.Variant) (store.Variant, error) {
+	return store.Variant{}, m.err
+}
+
+func (m *mockCatalogStore) GetVariants(context.Context, uint) ([]store.Variant, error) {
+	return nil, nil
+}
+
+func (m *mockCatalogStore) GetVariant(context.Context, uint, uint) (store.Variant, error) {
+	return store.Variant{}, m.err
+}
+
+func (m *mockCatalogStore) UpdateVariant(context.Context, uint, uint, store.Variant) (store.Variant, error) {
+	return store.Variant{}, m.err
+}
+
+func (m *mockCatalogStore) DeleteVariant(context.Context, uint, uint) error {
+	return m.err
+}
+
+func (m *mockCatalogStore) GetStock(context.Context, store.StockKey) (store.Stock, error) {
+	return store.Stock{}, m.err
+}
+
+func (m *mockCatalogStore) GetStockLevels(context.Context, []uint) ([]store.Stock, error) {
+	return nil, nil
+}
+
+func (m *mockCatalogStore) AdjustStock(context.Context, store.StockKey, int64) (store.Stock, error) {
+	return store.Stock{}, m.err
+}
+
+func (m *mockCatalogStore) Reserve(context.Context, store.StockKey, int64, time.Duration) (store.Reservation, error) {
+	return store.Reservation{}, m.err
+}
+
+func (m *mockCatalogStore) GetReservation(context.Context, uint) (store.Reservation, error) {
+	return store.Reservation{}, m.err
+}
+
+func (m *mockCatalogStore) CommitReservation(context.Context, uint) (store.Reservation, error) {
+	return store.Reservation{}, m.err
+}
+
+func (m *mockCatalogStore) ReleaseReservation(context.Context, uint) (store.Reservation, error) {
+	return store.Reservation{}, m.err
+}
+
+func (m *mockCatalogStore) SetItemPrices(context.Context, uint, []store.ItemPrice) ([]store.ItemPrice, error) {
+	return nil, m.err
+}
+
+func (m *mockCatalogStore) GetItemPrices(context.Context, []uint) ([]store.ItemPrice, error) {
+	return nil, nil
+}
+
+func (m *mockCatalogStore) SetExchangeRates(context.Context, money.Rates) error {
+	return m.err
+}
+
+func (m *mockCatalogStore) GetExchangeRates(context.Context) (money.Rates, error) {
+	return money.Rates{}, m.err
 }
 
-func (m *mockCatalogStore) GetItems(pageSize, page int) ([]store.Item, error) {
+func (m *mockCatalogStore) CreateScheduledPrice(context.Context, uint, store.ScheduledPrice) (store.ScheduledPrice, error) {
+	return store.ScheduledPrice{}, m.err
+}
+
+func (m *mockCatalogStore) GetScheduledPrices(context.Context, uint) ([]store.ScheduledPrice, error) {
+	return nil, m.err
+}
+
+func (m *mockCatalogStore) DeleteScheduledPrice(context.Context, uint, uint) error {
+	return m.err
+}
+
+func (m *mockCatalogStore) GetActivePrices(context.Context, []uint, time.Time) ([]store.ScheduledPrice, error) {
+	return nil, nil
+}
+
+func (m *mockCatalogStore) GetUpcomingPrices(context.Context, time.Time, int, int) ([]store.ScheduledPrice, error) {
+	return nil, m.err
+}
+
+func (m *mockCatalogStore) GetItemHistory(context.Context, uint) ([]store.ItemChange, error) {
+	return nil, m.err
+}
+
+func (m *mockCatalogStore) GetItemAsOf(context.Context, uint, time.Time) (store.Item, error) {
+	return store.Item{}, m.err
+}
+
+func (m *mockCatalogStore) ApplyItemOperations(context.Context, []store.ItemOperation) ([]store.Item, error) {
+	return nil, m.err
+}
+
+func (m *mockCatalogStore) GetItemByExternalID(context.Context, string) (store.Item, error) {
+	return store.Item{}, m.err
+}
+
+func (m *mockCatalogStore) UpsertItemByExternalID(context.Context, store.Item) (store.Item, store.UpsertOutcome, error) {
+	return store.Item{}, "", m.err
+}
+
+func (m *mockCatalogStore) CreateItemImage(context.Context, uint, store.ItemImage) (store.ItemImage, error) {
+	return store.ItemImage{}, m.err
+}
+
+func (m *mockCatalogStore) GetItemImages(context.Context, []uint) (map[uint][]store.ItemImage, error) {
+	return nil, m.err
+}
+
+func (m *mockCatalogStore) GetItemImage(context.Context, uint, uint) (store.ItemImage, error) {
+	return store.ItemImage{}, m.err
+}
+
+func (m *mockCatalogStore) UpdateItemImage(context.Context, uint, uint, *string, int) (store.ItemImage, error) {
+	return store.ItemImage{}, m.err
+}
+
+func (m *mockCatalogStore) DeleteItemImage(context.Context, uint, uint) error {
+	return m.err
+}
+
+func (m *mockCatalogStore) ExportItems(context.Context, store.ExportQuery, func([]store.Item) error) error {
+	return m.err
+}
+
+func (m *mockCatalogStore) CreateImportJob(context.Context, store.ImportFormat, []byte) (store.ImportJob, error) {
+	return store.ImportJob{}, m.err
+}
+
+func (m *mockCatalogStore) GetImportJob(context.Context, uint) (store.ImportJob, error) {
+	return store.ImportJob{}, m.err
+}
+
+func (m *mockCatalogStore) GetImportRejections(context.Context, uint, int, int) ([]store.ImportRejection, error) {
+	return nil, m.err
+}
+
+func (m *mockCatalogStore) GetEvents(context.Context, uint64, int) ([]store.Event, error) {
+	return nil, m.err
+}
+
+func (m *mockCatalogStore) CreateWebhook(_ context.Context, webhook store.Webhook) (store.Webhook, error) {
+	return webhook, m.err
+}
+
+func (m *mockCatalogStore) GetWebhooks(context.Context) ([]store.Webhook, error) {
+	return nil, m.err
+}
+
+func (m *mockCatalogStore) GetWebhook(context.Context, uint) (store.Webhook, error) {
+	return store.Webhook{}, m.err
+}
+
+func (m *mockCatalogStore) DeleteWebhook(context.Context, uint) error {
+	return m.err
+}
+
+func (m *mockCatalogStore) GetDeliveries(context.Context, store.DeliveryQuery) ([]store.WebhookDelivery, error) {
+	return nil, m.err
+}
+
+func (m *mockCatalogStore) RedeliverDelivery(context.Context, uint, uint) (store.WebhookDelivery, error) {
+	return store.WebhookDelivery{}, m.err
+}
+
+func (m *mockCatalogStore) SearchItems(_ context.Context, query store.SearchQuery) ([]store.SearchResult, int64, error) {
+	assert.Equal(m.t, m.expectedSearch, query)
+	return m.searchResponse, m.countResponse, m.err
+}
+
+func (m *mockCatalogStore) GetItems(_ context.Context, query store.ItemQuery) ([]store.Item, error) {
+	assert.Equal(m.t, m.expectedPageSize, query.PageSize)
+	assert.Equal(m.t, m.expectedPage, query.Page)
+	assert.Equal(m.t, m.expectedFilter, query.ItemFilter)
+	assert.Equal(m.t, m.expectedSort, query.Sort)
+	assert.Equal(m.t, m.expectedCursor, query.Cursor)
+	return m.getItemsResponse, m.err
+}
+
+func (m *mockCatalogStore) UpdateItem(_ context.Context, id uint, item store.Item, ifVersion uint) (store.Item, error) {
+	assert.Equal(m.t, m.expectedID, id)
+	assert.Equal(m.t, m.expectedItem, item)
+	assert.Equal(m.t, m.expectedIfVersion, ifVersion)
+	item.ID = id
+	item.Version = ifVersion + 1
+	return item, m.err
+}
+
+func (m *mockCatalogStore) GetDeletedItems(_ context.Context, pageSize, page int) ([]store.Item, error) {
 	assert.Equal(m.t, m.expectedPageSize, pageSize)
 	assert.Equal(m.t, m.expectedPage, page)
 	return m.getItemsResponse, m.err
 }
 
-func (m *mockCatalogStore) UpdateItem(id uint, item store.Item) error {
+func (m *mockCatalogStore) RestoreItem(_ context.Context, id uint) (store.Item, error) {
 	assert.Equal(m.t, m.expectedID, id)
-	assert.Equal(m.t, m.expectedItem, item)
-	return m.err
+	item := m.findItemResponse
+	item.ID = id
+	return item, m.err
 }
 
-func v2p[V int | float64 | string](val V) *V {
+func v2p[V int | int64 | uint | float64 | bool | ~string | time.Time](val V) *V {
 	return &val
 }
 
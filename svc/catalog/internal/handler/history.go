@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// headerActor identifies who makes the request, changes made with it are attributed to that actor in item history
+const headerActor = "X-Actor"
+
+// ActorMiddleware attributes changes made while handling the request to actor from X-Actor header
+func ActorMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(eCtx echo.Context) error {
+		if actor := eCtx.Request().Header.Get(headerActor); actor != "" {
+			req := eCtx.Request()
+			eCtx.SetRequest(req.WithContext(store.WithActor(req.Context(), actor)))
+		}
+		return next(eCtx)
+	}
+}
+
+// GetItemHistory returns recorded changes of the item with ID with fields every change modified
+func (h *handler) GetItemHistory(ctx echo.Context, id uint) error {
+	changes, err := h.store.GetItemHistory(ctx.Request().Context(), id)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapItemChangesToHistoryEntries(changes))
+}
+
+// GetItemSnapshot returns the item with ID as it was at requested time
+func (h *handler) GetItemSnapshot(ctx echo.Context, id uint, params api.GetItemSnapshotParams) error {
+	item, err := h.store.GetItemAsOf(ctx.Request().Context(), id, params.At)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapItemModelToItemResponse(item))
+}
+
+// snapshotField is a value of the item field as returned in ItemResponse
+type snapshotField struct {
+	name  string
+	value *string
+}
+
+// snapshotFields returns fields of the snapshot in the order they are reported in history
+func snapshotFields(snapshot store.ItemSnapshot) []snapshotField {
+	return []snapshotField{
+		{name: "name", value: snapshot.Name},
+		{name: "description", value: snapshot.Description},
+		{name: "price", value: formatPrice(snapshot.Price, snapshot.PriceCode)},
+		{name: "priceCode", value: snapshot.PriceCode},
+	}
+}
+
+// diffSnapshots returns fields which differ between snapshots, every field set in current one when there is no
+// previous snapshot. Part of the item changed by revision is returned as a field without values.
+func diffSnapshots(previous *store.ItemSnapshot, current store.ItemSnapshot) []api.FieldChange {
+	var before []snapshotField
+	if previous != nil {
+		before = snapshotFields(*previous)
+	}
+	changes := []api.FieldChange{}
+	for i, field := range snapshotFields(current) {
+		var old *string
+		if before != nil {
+			old = before[i].value
+		}
+		if sameValue(old, field.value) {
+			continue
+		}
+		changes = append(changes, api.FieldChange{Field: field.name, Old: old, New: field.value})
+	}
+	if current.Part != "" {
+		changes = append(changes, api.FieldChange{Field: string(current.Part)})
+	}
+	return changes
+}
+
+func sameValue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func mapItemChangesToHistoryEntries(changes []store.ItemChange) []api.ItemHistoryEntry {
+	resp := make([]api.ItemHistoryEntry, 0, len(changes))
+	var previous *store.ItemSnapshot
+	for i, change := range changes {
+		resp = append(resp, api.ItemHistoryEntry{
+			Version:   change.Version,
+			Operation: api.HistoryOperation(change.Operation),
+			Actor:     change.Actor,
+			ChangedAt: change.ChangedAt.UTC(),
+			Changes:   diffSnapshots(previous, change.Snapshot),
+		})
+		previous = &changes[i].Snapshot
+	}
+	return resp
+}
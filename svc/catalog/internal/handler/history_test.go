@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestItemHistory(t *testing.T) {
+	h := NewHandler(logrus.New(), store.NewMemoryStore(), allCurrencies, noRates, nil)
+	// call runs handler the way the router does, with actor from X-Actor header
+	call := func(method string, body interface{}, actor string, handle func(ctx echo.Context) error) *httptest.ResponseRecorder {
+		ctx, rec := newJSONContext(t, method, "/api/v1/items", body)
+		if actor != "" {
+			ctx.Request().Header.Set(headerActor, actor)
+		}
+		require.NoError(t, ActorMiddleware(handle)(ctx))
+		return rec
+	}
+
+	rec := call(http.MethodPost, api.NewItemRequest{Name: "Shirt", Description: "desc", Price: "20", PriceCode: "EUR"},
+		"alice", h.CreateItem)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var item api.ItemResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+	itemID := *item.Id
+	afterCreate := time.Now()
+	rec = call(http.MethodPatch, api.UpdateItemRequest{Price: v2p("15.5"), PriceCode: v2p("EUR")}, "bob",
+		func(ctx echo.Context) error { return h.UpdateItemByID(ctx, itemID, api.UpdateItemByIDParams{}) })
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = call(http.MethodPut, api.ItemPricesRequest{Prices: []api.Price{{Price: "13.99", PriceCode: "USD"}}}, "bob",
+		func(ctx echo.Context) error { return h.SetItemPrices(ctx, itemID) })
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = call(http.MethodDelete, nil, "",
+		func(ctx echo.Context) error { return h.DeleteItemByID(ctx, itemID, api.DeleteItemByIDParams{}) })
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	t.Run("History holds field changes with actors", func(t *testing.T) {
+		rec := call(http.MethodGet, nil, "", func(ctx echo.Context) error { return h.GetItemHistory(ctx, itemID) })
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp []api.ItemHistoryEntry
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		require.Len(t, resp, 4)
+
+		assert.Equal(t, api.Created, resp[0].Operation)
+		assert.Equal(t, "alice", resp[0].Actor)
+		assert.Equal(t, []api.FieldChange{
+			{Field: "name", New: v2p("Shirt")},
+			{Field: "description", New: v2p("desc")},
+			{Field: "price", New: v2p("20.00")},
+			{Field: "priceCode", New: v2p("EUR")},
+		}, resp[0].Changes)
+		assert.Equal(t, api.Updated, resp[1].Operation)
+		assert.Equal(t, "bob", resp[1].Actor)
+		assert.Equal(t, uint(2), resp[1].Version)
+		assert.Equal(t, []api.FieldChange{{Field: "price", Old: v2p("20.00"), New: v2p("15.50")}}, resp[1].Changes)
+		assert.Equal(t, api.Revised, resp[2].Operation)
+		assert.Equal(t, uint(3), resp[2].Version)
+		assert.Equal(t, []api.FieldChange{{Field: "prices"}}, resp[2].Changes)
+		assert.Equal(t, api.Deleted, resp[3].Operation)
+		assert.Equal(t, "system", resp[3].Actor)
+		assert.Empty(t, resp[3].Changes)
+	})
+
+	t.Run("Item is returned as of point in time", func(t *testing.T) {
+		rec := call(http.MethodGet, nil, "", func(ctx echo.Context) error {
+			return h.GetItemSnapshot(ctx, itemID, api.GetItemSnapshotParams{At: afterCreate})
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp api.ItemResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, "20.00", *resp.Price)
+		assert.Equal(t, `"1"`, *resp.Etag)
+		assert.Nil(t, resp.DeletedAt)
+
+	})
+
+	t.Run("Missing items are not found", func(t *testing.T) {
+		rec := call(http.MethodGet, nil, "", func(ctx echo.Context) error { return h.GetItemHistory(ctx, itemID+1) })
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		rec = call(http.MethodGet, nil, "", func(ctx echo.Context) error {
+			return h.GetItemSnapshot(ctx, itemID, api.GetItemSnapshotParams{At: afterCreate.Add(-time.Hour)})
+		})
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		rec = call(http.MethodGet, nil, "", func(ctx echo.Context) error {
+			return h.GetItemSnapshot(ctx, itemID, api.GetItemSnapshotParams{At: time.Now()})
+		})
+		assert.Equal(t, http.StatusNotFound, rec.Code, "item was deleted")
+	})
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/media"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+)
+
+// imageCacheControl lets clients and CDNs cache image files indefinitely, as file of image ID in a size never changes
+const imageCacheControl = "public, max-age=31536000, immutable"
+
+// GetItemImages returns images of the item with ID from the underlying store
+func (h *handler) GetItemImages(ctx echo.Context, id uint) error {
+	if _, err := h.store.GetItem(ctx.Request().Context(), id); err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	images, err := h.store.GetItemImages(ctx.Request().Context(), []uint{id})
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapItemImagesToItemImageResponses(images[id], h.mediaURL))
+}
+
+// CreateItemImage stores uploaded image with its thumbnails in media library and adds it to the item with ID
+func (h *handler) CreateItemImage(ctx echo.Context, id uint, params api.CreateItemImageParams) error {
+	data, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while reading request body: %w", err))
+	}
+	saved, err := h.media.Save(ctx.Request().Context(), data)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+
+	image, err := h.store.CreateItemImage(ctx.Request().Context(), id, store.ItemImage{
+		AltText:     params.AltText,
+		BlobKey:     saved.Key,
+		ContentType: saved.ContentType,
+		Width:       saved.Width,
+		Height:      saved.Height,
+		Size:        saved.Size,
+	})
+	if err != nil {
+		// request context may be already canceled, files of the image mustn't be left behind anyway
+		if rmErr := h.media.Remove(context.Background(), saved.Key); rmErr != nil {
+			h.log.Errorf("error while removing files of rejected image: %s", rmErr)
+		}
+		return h.writeErrorResponse(ctx, err)
+	}
+	ctx.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/items/%d/images/%d", id, image.ID))
+	return ctx.JSON(http.StatusCreated, mapItemImageToItemImageResponse(image, h.mediaURL))
+}
+
+// FindItemImageByID returns image with ID of the item from the underlying store
+func (h *handler) FindItemImageByID(ctx echo.Context, id uint, imageID uint) error {
+	image, err := h.store.GetItemImage(ctx.Request().Context(), id, imageID)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapItemImageToItemImageResponse(image, h.mediaURL))
+}
+
+// UpdateItemImageByID sets alternative text and position of image with ID of the item in the underlying store
+func (h *handler) UpdateItemImageByID(ctx echo.Context, id uint, imageID uint) error {
+	var req api.ItemImageRequest
+	if err := ctx.Bind(&req); err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+
+	image, err := h.store.UpdateItemImage(ctx.Request().Context(), id, imageID, req.AltText, req.Position)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapItemImageToItemImageResponse(image, h.mediaURL))
+}
+
+// DeleteItemImageByID deletes image with ID of the item from the underlying store, its files are removed from media
+// library in the background
+func (h *handler) DeleteItemImageByID(ctx echo.Context, id uint, imageID uint) error {
+	if err := h.store.DeleteItemImage(ctx.Request().Context(), id, imageID); err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// GetItemImageFile streams file of image with ID of the item in requested size from media library
+func (h *handler) GetItemImageFile(ctx echo.Context, id uint, imageID uint, size api.ImageSize) error {
+	image, err := h.store.GetItemImage(ctx.Request().Context(), id, imageID)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	r, err := h.media.Open(ctx.Request().Context(), image.BlobKey, media.Size(size))
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	defer r.Close()
+	ctx.Response().Header().Set(echo.HeaderCacheControl, imageCacheControl)
+	return ctx.Stream(http.StatusOK, media.ContentType(image.ContentType, media.Size(size)), r)
+}
+
+// imageURL returns URL of the file of image in provided size, relative to the catalog unless baseURL is set
+func imageURL(baseURL string, image store.ItemImage, size media.Size) string {
+	return fmt.Sprintf("%s/api/v1/items/%d/images/%d/%s", baseURL, image.ItemID, image.ID, size)
+}
+
+func mapItemImageToItemImageResponse(image store.ItemImage, baseURL string) api.ItemImageResponse {
+	return api.ItemImageResponse{
+		Id:          image.ID,
+		Position:    image.Position,
+		AltText:     image.AltText,
+		ContentType: image.ContentType,
+		Width:       image.Width,
+		Height:      image.Height,
+		Size:        image.Size,
+		Url:         imageURL(baseURL, image, media.SizeOriginal),
+		Thumbnails: api.ImageThumbnails{
+			Small:  imageURL(baseURL, image, media.SizeSmall),
+			Medium: imageURL(baseURL, image, media.SizeMedium),
+			Large:  imageURL(baseURL, image, media.SizeLarge),
+		},
+	}
+}
+
+func mapItemImagesToItemImageResponses(images []store.ItemImage, baseURL string) []api.ItemImageResponse {
+	resp := make([]api.ItemImageResponse, 0, len(images))
+	for _, image := range images {
+		resp = append(resp, mapItemImageToItemImageResponse(image, baseURL))
+	}
+	return resp
+}
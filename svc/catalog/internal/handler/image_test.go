@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/media"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"image"
+	"image/png"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+// pngImage returns w x h PNG image
+func pngImage(t *testing.T, w, h int) string {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))))
+	return buf.String()
+}
+
+// newMediaHandler returns handler keeping images in a temporary directory, which is returned too
+func newMediaHandler(t *testing.T, s CatalogStore) (*handler, string) {
+	dir := t.TempDir()
+	storage, err := media.NewFileStorage(dir)
+	require.NoError(t, err)
+	return NewHandler(logrus.New(), s, allCurrencies, noRates, nil).WithMedia(media.NewLibrary(storage, 1), ""), dir
+}
+
+// countFiles returns number of files in dir and its subdirectories
+func countFiles(t *testing.T, dir string) int {
+	files := 0
+	require.NoError(t, filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files++
+		}
+		return err
+	}))
+	return files
+}
+
+func TestCreateItemImage(t *testing.T) {
+	tests := []struct {
+		name           string
+		itemID         uint
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedFiles  int
+	}{
+		{
+			name:           "PNG image is stored with thumbnails",
+			itemID:         1,
+			contentType:    "image/png",
+			body:           pngImage(t, 640, 480),
+			expectedStatus: http.StatusCreated,
+			expectedFiles:  4,
+		},
+		{
+			name:           "Type of the image is detected from its content",
+			itemID:         1,
+			contentType:    "application/octet-stream",
+			body:           pngImage(t, 16, 16),
+			expectedStatus: http.StatusCreated,
+			expectedFiles:  4,
+		},
+		{
+			name:           "Content which isn't an image is rejected",
+			itemID:         1,
+			contentType:    "image/png",
+			body:           "<svg/>",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Files of image of missing item are removed",
+			itemID:         2,
+			contentType:    "image/png",
+			body:           pngImage(t, 16, 16),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := store.NewMemoryStore()
+			_, err := s.CreateItem(context.Background(), store.Item{Name: v2p("Shirt"), Description: v2p("Cotton"),
+				Price: v2p(int64(1250)), PriceCode: v2p("EUR")})
+			require.NoError(t, err)
+			h, dir := newMediaHandler(t, s)
+			eCtx, rec := newUploadContext(http.MethodPost, "/api/v1/items/1/images?altText=Front", test.contentType,
+				test.body)
+
+			require.NoError(t, h.CreateItemImage(eCtx, test.itemID, api.CreateItemImageParams{AltText: v2p("Front")}))
+
+			require.Equal(t, test.expectedStatus, rec.Code, rec.Body.String())
+			assert.Equal(t, test.expectedFiles, countFiles(t, dir))
+			if test.expectedStatus != http.StatusCreated {
+				return
+			}
+			var resp api.ItemImageResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, "/api/v1/items/1/images/1", rec.Header().Get(echo.HeaderLocation))
+			assert.Equal(t, "Front", *resp.AltText)
+			assert.Equal(t, "image/png", resp.ContentType)
+			assert.Equal(t, int64(len(test.body)), resp.Size)
+			assert.Equal(t, "/api/v1/items/1/images/1/original", resp.Url)
+			assert.Equal(t, "/api/v1/items/1/images/1/small", resp.Thumbnails.Small)
+		})
+	}
+}
+
+func TestItemImages(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemoryStore()
+	item, err := s.CreateItem(ctx, store.Item{Name: v2p("Shirt"), Description: v2p("Cotton"),
+		Price: v2p(int64(1250)), PriceCode: v2p("EUR")})
+	require.NoError(t, err)
+	h, _ := newMediaHandler(t, s)
+	h.WithMedia(h.media, "https://cdn.example.com/")
+	for _, size := range []int{2000, 100} {
+		eCtx, rec := newUploadContext(http.MethodPost, "/api/v1/items/1/images", "image/png", pngImage(t, size, size))
+		require.NoError(t, h.CreateItemImage(eCtx, item.ID, api.CreateItemImageParams{}))
+		require.Equal(t, http.StatusCreated, rec.Code)
+	}
+
+	t.Run("Image is moved", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodPut, "/api/v1/items/1/images/2",
+			api.ItemImageRequest{AltText: v2p("Back"), Position: 0})
+
+		require.NoError(t, h.UpdateItemImageByID(eCtx, item.ID, 2))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp api.ItemImageResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, 0, resp.Position)
+		assert.Equal(t, "Back", *resp.AltText)
+	})
+
+	t.Run("Images are listed in order", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/1/images", nil)
+
+		require.NoError(t, h.GetItemImages(eCtx, item.ID))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp []api.ItemImageResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		require.Len(t, resp, 2)
+		assert.Equal(t, uint(2), resp[0].Id)
+		assert.Equal(t, uint(1), resp[1].Id)
+		assert.Equal(t, "https://cdn.example.com/api/v1/items/1/images/1/large", resp[1].Thumbnails.Large)
+	})
+
+	t.Run("Images are embedded in item", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/1", nil)
+
+		require.NoError(t, h.FindItemByID(eCtx, item.ID, api.FindItemByIDParams{}))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp api.ItemResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		require.NotNil(t, resp.Images)
+		require.Len(t, *resp.Images, 2)
+		assert.Equal(t, "https://cdn.example.com/api/v1/items/1/images/2/original", (*resp.Images)[0].Url)
+	})
+
+	t.Run("Thumbnail is served", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/1/images/1/medium", nil)
+
+		require.NoError(t, h.GetItemImageFile(eCtx, item.ID, 1, api.Medium))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "image/png", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, imageCacheControl, rec.Header().Get(echo.HeaderCacheControl))
+		thumb, err := png.Decode(rec.Body)
+		require.NoError(t, err)
+		assert.Equal(t, image.Pt(480, 480), thumb.Bounds().Size())
+	})
+
+	t.Run("Image is deleted", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodDelete, "/api/v1/items/1/images/2", nil)
+
+		require.NoError(t, h.DeleteItemImageByID(eCtx, item.ID, 2))
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		deletions, err := s.GetMediaDeletions(ctx, 10)
+		require.NoError(t, err)
+		assert.Len(t, deletions, 1, "files are removed in the background")
+	})
+
+	t.Run("Missing image", func(t *testing.T) {
+		for name, handle := range map[string]func(echo.Context) error{
+			"image":  func(eCtx echo.Context) error { return h.FindItemImageByID(eCtx, item.ID, 2) },
+			"file":   func(eCtx echo.Context) error { return h.GetItemImageFile(eCtx, item.ID, 2, api.Original) },
+			"images": func(eCtx echo.Context) error { return h.GetItemImages(eCtx, item.ID+1) },
+		} {
+			eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/1/images/2", nil)
+
+			require.NoError(t, handle(eCtx))
+
+			assert.Equal(t, http.StatusNotFound, rec.Code, name)
+		}
+	})
+}
+
+func TestCreateItemImage_storeError(t *testing.T) {
+	h, dir := newMediaHandler(t, &mockCatalogStore{err: errors.New("connection refused")})
+	eCtx, rec := newUploadContext(http.MethodPost, "/api/v1/items/1/images", "image/png", pngImage(t, 16, 16))
+
+	require.NoError(t, h.CreateItemImage(eCtx, 1, api.CreateItemImageParams{}))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Zero(t, countFiles(t, dir), "files of the image are removed")
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/ptr"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// rejectionsPageSize is number of rejections read from the store at once while writing the report
+const rejectionsPageSize = 500
+
+// importFormats maps content types of uploaded files to their formats
+var importFormats = map[string]store.ImportFormat{
+	"text/csv":             store.ImportCSV,
+	"application/x-ndjson": store.ImportNDJSON,
+}
+
+// CreateImport queues import of items from the uploaded file, the file format is chosen by its content type
+func (h *handler) CreateImport(ctx echo.Context) error {
+	mediaType, _, err := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	if err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("%w: invalid content type: %s", store.ErrInvalidImport, err))
+	}
+	format, ok := importFormats[mediaType]
+	if !ok {
+		return h.writeErrorResponse(ctx, fmt.Errorf("%w: unsupported content type %q", store.ErrInvalidImport, mediaType))
+	}
+	data, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while reading request body: %w", err))
+	}
+
+	job, err := h.store.CreateImportJob(ctx.Request().Context(), format, data)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	ctx.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/imports/%d", job.ID))
+	return ctx.JSON(http.StatusAccepted, mapImportJobToImportJobResponse(job))
+}
+
+// FindImportByID returns import job with ID from the underlying store
+func (h *handler) FindImportByID(ctx echo.Context, importID uint) error {
+	job, err := h.store.GetImportJob(ctx.Request().Context(), importID)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapImportJobToImportJobResponse(job))
+}
+
+// GetImportRejections writes CSV report of rows rejected by import job with ID. Rejections are read from the store
+// page by page, so the report of a large file isn't held in memory.
+func (h *handler) GetImportRejections(ctx echo.Context, importID uint) error {
+	// the first page is read before the response is started, so missing job is still reported as an error
+	rejections, err := h.store.GetImportRejections(ctx.Request().Context(), importID, rejectionsPageSize, 1)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+
+	w := ctx.Response()
+	w.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	w.Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="import-%d-rejections.csv"`, importID))
+	w.WriteHeader(http.StatusOK)
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"row", "externalId", "field", "message"}); err != nil {
+		return err
+	}
+	for page := 1; ; page++ {
+		for _, rejection := range rejections {
+			err := cw.Write([]string{
+				strconv.Itoa(rejection.Row),
+				ptr.ValueOr(rejection.ExternalID, ""),
+				ptr.ValueOr(rejection.Field, ""),
+				rejection.Message,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+		w.Flush()
+		if len(rejections) < rejectionsPageSize {
+			return nil
+		}
+		rejections, err = h.store.GetImportRejections(ctx.Request().Context(), importID, rejectionsPageSize, page+1)
+		if err != nil {
+			// the response is already started, so the report can only be cut short
+			h.log.Errorf("error while writing rejections of import job %d: %s", importID, err)
+			return nil
+		}
+	}
+}
+
+func mapImportJobToImportJobResponse(job store.ImportJob) api.ImportJobResponse {
+	return api.ImportJobResponse{
+		Id:             job.ID,
+		Format:         api.ImportFormat(job.Format),
+		Status:         api.ImportStatus(job.Status),
+		ProcessedRows:  job.ProcessedRows,
+		CreatedItems:   job.CreatedItems,
+		UpdatedItems:   job.UpdatedItems,
+		UnchangedItems: job.UnchangedItems,
+		RejectedRows:   job.RejectedRows,
+		Error:          job.Error,
+		CreatedAt:      job.CreatedAt,
+		StartedAt:      job.StartedAt,
+		FinishedAt:     job.FinishedAt,
+	}
+}
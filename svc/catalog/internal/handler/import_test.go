@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newUploadContext(method, target, contentType, body string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, contentType)
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(req, rec), rec
+}
+
+func TestCreateImport(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedFormat api.ImportFormat
+	}{
+		{
+			name:           "CSV file is queued",
+			contentType:    "text/csv; charset=utf-8",
+			body:           "externalId,name,price,priceCode\nSUP-1,Shirt,10,EUR\n",
+			expectedStatus: http.StatusAccepted,
+			expectedFormat: api.ImportFormatCsv,
+		},
+		{
+			name:           "NDJSON file is queued",
+			contentType:    "application/x-ndjson",
+			body:           `{"externalId":"SUP-1","name":"Shirt","price":10,"priceCode":"EUR"}`,
+			expectedStatus: http.StatusAccepted,
+			expectedFormat: api.ImportFormatNdjson,
+		},
+		{
+			name:           "Empty file is rejected",
+			contentType:    "text/csv",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unsupported content type is rejected",
+			contentType:    "application/json",
+			body:           `[]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := NewHandler(logrus.New(), store.NewMemoryStore(), allCurrencies, noRates, nil)
+			eCtx, rec := newUploadContext(http.MethodPost, "/api/v1/imports", test.contentType, test.body)
+
+			require.NoError(t, h.CreateImport(eCtx))
+
+			require.Equal(t, test.expectedStatus, rec.Code)
+			if test.expectedStatus != http.StatusAccepted {
+				return
+			}
+			var resp api.ImportJobResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, test.expectedFormat, resp.Format)
+			assert.Equal(t, api.Queued, resp.Status)
+			assert.Equal(t, "/api/v1/imports/1", rec.Header().Get(echo.HeaderLocation))
+		})
+	}
+}
+
+func TestImportJobs(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemoryStore()
+	h := NewHandler(logrus.New(), s, allCurrencies, noRates, nil)
+	job, err := s.CreateImportJob(ctx, store.ImportCSV, []byte("externalId,name,price,priceCode\n"))
+	require.NoError(t, err)
+	// job completed by the worker, which rejected more rows than fit into a single page of the report
+	claimed, _, err := s.ClaimImportJob(ctx, time.Now().Add(time.Second), time.Minute)
+	require.NoError(t, err)
+	progress := store.ImportProgress{Status: store.ImportCompleted}
+	for row := 1; row <= rejectionsPageSize+1; row++ {
+		progress.Rejections = append(progress.Rejections, store.ImportRejection{Row: row, Message: "invalid, price"})
+	}
+	progress.Rejections[0].ExternalID = v2p("SUP-1")
+	progress.Rejections[0].Field = v2p("price")
+	progress.ProcessedRows = len(progress.Rejections)
+	progress.RejectedRows = len(progress.Rejections)
+	require.NoError(t, s.RecordImportProgress(ctx, job.ID, claimed.Claims, progress))
+
+	t.Run("Job is found by ID", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/imports/1", nil)
+
+		require.NoError(t, h.FindImportByID(eCtx, job.ID))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp api.ImportJobResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, api.Completed, resp.Status)
+		assert.Equal(t, rejectionsPageSize+1, resp.RejectedRows)
+		assert.NotNil(t, resp.FinishedAt)
+	})
+
+	t.Run("Rejections are reported as CSV", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/imports/1/rejections", nil)
+
+		require.NoError(t, h.GetImportRejections(eCtx, job.ID))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+		lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+		require.Len(t, lines, rejectionsPageSize+2, "header and all pages of rejections")
+		assert.Equal(t, "row,externalId,field,message", lines[0])
+		assert.Equal(t, `1,SUP-1,price,"invalid, price"`, lines[1])
+		assert.Equal(t, `501,,,"invalid, price"`, lines[len(lines)-1])
+	})
+
+	t.Run("Missing job", func(t *testing.T) {
+		for name, handle := range map[string]func(echo.Context, uint) error{
+			"status":     h.FindImportByID,
+			"rejections": h.GetImportRejections,
+		} {
+			eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/imports/2", nil)
+
+			require.NoError(t, handle(eCtx, 2))
+
+			assert.Equal(t, http.StatusNotFound, rec.Code, name)
+		}
+	})
+}
+
+func TestCreateImport_storeError(t *testing.T) {
+	h := NewHandler(logrus.New(), &mockCatalogStore{err: errors.New("connection refused")}, allCurrencies, noRates, nil)
+	eCtx, rec := newUploadContext(http.MethodPost, "/api/v1/imports", "text/csv", "externalId\n")
+
+	require.NoError(t, h.CreateImport(eCtx))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/ptr"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+// defaultReservationTTL is used for reservations created without ttlSeconds
+const defaultReservationTTL = 15 * time.Minute
+
+// GetStock returns stock level of the item with ID, or of its variant, from the underlying store
+func (h *handler) GetStock(ctx echo.Context, id uint, params api.GetStockParams) error {
+	stock, err := h.store.GetStock(ctx.Request().Context(),
+		store.StockKey{ItemID: id, VariantID: ptr.ValueOr(params.VariantId, 0)})
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapStockToStockResponse(stock))
+}
+
+// AdjustStock changes number of units on hand of the item with ID, or of its variant, in the underlying store
+func (h *handler) AdjustStock(ctx echo.Context, id uint) error {
+	var req api.StockAdjustmentRequest
+	if err := ctx.Bind(&req); err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+
+	key := store.StockKey{ItemID: id, VariantID: ptr.ValueOr(req.VariantId, 0)}
+	stock, err := h.store.AdjustStock(ctx.Request().Context(), key, req.Delta)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapStockToStockResponse(stock))
+}
+
+// CreateReservation handles reservation of stock in the underlying store
+func (h *handler) CreateReservation(ctx echo.Context) error {
+	var req api.ReservationRequest
+	if err := ctx.Bind(&req); err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+
+	ttl := defaultReservationTTL
+	if req.TtlSeconds != nil {
+		ttl = time.Duration(*req.TtlSeconds) * time.Second
+	}
+	key := store.StockKey{ItemID: req.ItemId, VariantID: ptr.ValueOr(req.VariantId, 0)}
+	reservation, err := h.store.Reserve(ctx.Request().Context(), key, req.Quantity, ttl)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusCreated, mapReservationToReservationResponse(reservation))
+}
+
+// FindReservationByID returns reservation with ID from the underlying store
+func (h *handler) FindReservationByID(ctx echo.Context, id api.ReservationId) error {
+	reservation, err := h.store.GetReservation(ctx.Request().Context(), id)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapReservationToReservationResponse(reservation))
+}
+
+// CommitReservation removes units held by reservation with ID from stock in the underlying store
+func (h *handler) CommitReservation(ctx echo.Context, id api.ReservationId) error {
+	reservation, err := h.store.CommitReservation(ctx.Request().Context(), id)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapReservationToReservationResponse(reservation))
+}
+
+// ReleaseReservation makes units held by reservation with ID available again in the underlying store
+func (h *handler) ReleaseReservation(ctx echo.Context, id api.ReservationId) error {
+	reservation, err := h.store.ReleaseReservation(ctx.Request().Context(), id)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapReservationToReservationResponse(reservation))
+}
+
+// setAvailability fills available units of the item and its variants from their stock levels
+func setAvailability(resp *api.ItemResponse, levels []store.Stock) {
+	available := map[uint]int64{}
+	for _, level := range levels {
+		available[level.VariantID] = level.Available()
+	}
+	itemAvailable := available[0]
+	resp.Available = &itemAvailable
+	if resp.Variants == nil {
+		return
+	}
+	for i := range *resp.Variants {
+		variantAvailable := available[(*resp.Variants)[i].Id]
+		(*resp.Variants)[i].Available = &variantAvailable
+	}
+}
+
+func mapStockToStockResponse(stock store.Stock) api.StockResponse {
+	resp := api.StockResponse{
+		ItemId:    stock.ItemID,
+		OnHand:    stock.OnHand,
+		Reserved:  stock.Reserved,
+		Available: stock.Available(),
+	}
+	if stock.VariantID != 0 {
+		resp.VariantId = &stock.VariantID
+	}
+	return resp
+}
+
+func mapReservationToReservationResponse(reservation store.Reservation) api.ReservationResponse {
+	resp := api.ReservationResponse{
+		Id:        reservation.ID,
+		ItemId:    reservation.ItemID,
+		Quantity:  reservation.Quantity,
+		Status:    api.ReservationStatus(reservation.Status),
+		ExpiresAt: reservation.ExpiresAt,
+		CreatedAt: reservation.CreatedAt,
+	}
+	if reservation.VariantID != 0 {
+		resp.VariantId = &reservation.VariantID
+	}
+	return resp
+}
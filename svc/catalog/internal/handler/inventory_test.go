@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestInventory(t *testing.T) {
+	h := NewHandler(logrus.New(), store.NewMemoryStore(), allCurrencies, noRates, nil)
+	ctx, rec := newJSONContext(t, http.MethodPost, "/api/v1/items",
+		api.NewItemRequest{Name: "Shirt", Description: "desc", Price: "10", PriceCode: "EUR"})
+	require.NoError(t, h.CreateItem(ctx))
+	var item api.ItemResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+	itemID := *item.Id
+
+	ctx, rec = newJSONContext(t, http.MethodPost, "/api/v1/items/stock/adjustments", api.StockAdjustmentRequest{Delta: 5})
+	require.NoError(t, h.AdjustStock(ctx, itemID))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var stock api.StockResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&stock))
+	assert.Equal(t, api.StockResponse{ItemId: itemID, OnHand: 5, Available: 5}, stock)
+
+	ctx, rec = newJSONContext(t, http.MethodPost, "/api/v1/reservations",
+		api.ReservationRequest{ItemId: itemID, Quantity: 3, TtlSeconds: v2p(60)})
+	require.NoError(t, h.CreateReservation(ctx))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var reservation api.ReservationResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&reservation))
+	assert.Equal(t, api.ReservationStatusPending, reservation.Status)
+	assert.Equal(t, time.Minute, reservation.ExpiresAt.Sub(reservation.CreatedAt))
+
+	tests := []struct {
+		name           string
+		req            api.ReservationRequest
+		expectedStatus int
+	}{
+		{
+			name:           "Conflict - not enough available stock",
+			req:            api.ReservationRequest{ItemId: itemID, Quantity: 3},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Bad Request - non-positive quantity",
+			req:            api.ReservationRequest{ItemId: itemID},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Not Found - no variant",
+			req:            api.ReservationRequest{ItemId: itemID, VariantId: v2p(uint(100)), Quantity: 1},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, rec := newJSONContext(t, http.MethodPost, "/api/v1/reservations", test.req)
+			require.NoError(t, h.CreateReservation(ctx))
+			assert.Equal(t, test.expectedStatus, rec.Code)
+		})
+	}
+
+	ctx, rec = newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+	require.NoError(t, h.FindItemByID(ctx, itemID, api.FindItemByIDParams{}))
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+	assert.Equal(t, v2p(int64(2)), item.Available)
+
+	ctx, rec = newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+	require.NoError(t, h.GetItems(ctx, api.GetItemsParams{}))
+	var items []api.ItemResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&items))
+	require.Len(t, items, 1)
+	assert.Equal(t, v2p(int64(2)), items[0].Available, "listed items carry availability")
+
+	ctx, rec = newJSONContext(t, http.MethodGet, "/api/v1/items/search", nil)
+	require.NoError(t, h.SearchItems(ctx, api.SearchItemsParams{Q: "shirt"}))
+	var results api.SearchPage
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&results))
+	require.Len(t, results.Items, 1)
+	assert.Equal(t, v2p(int64(2)), results.Items[0].Item.Available, "found items carry availability")
+
+	ctx, rec = newJSONContext(t, http.MethodPost, "/api/v1/items/stock/adjustments", api.StockAdjustmentRequest{Delta: -3})
+	require.NoError(t, h.AdjustStock(ctx, itemID))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	ctx, rec = newJSONContext(t, http.MethodPost, "/api/v1/reservations/commit", nil)
+	require.NoError(t, h.CommitReservation(ctx, reservation.Id))
+	require.Equal(t, http.StatusOK, rec.Code)
+	ctx, rec = newJSONContext(t, http.MethodPost, "/api/v1/reservations/release", nil)
+	require.NoError(t, h.ReleaseReservation(ctx, reservation.Id))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	ctx, rec = newJSONContext(t, http.MethodGet, "/api/v1/reservations", nil)
+	require.NoError(t, h.FindReservationByID(ctx, reservation.Id))
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&reservation))
+	assert.Equal(t, api.ReservationStatusCommitted, reservation.Status)
+
+	ctx, rec = newJSONContext(t, http.MethodGet, "/api/v1/items/stock", nil)
+	require.NoError(t, h.GetStock(ctx, itemID, api.GetStockParams{}))
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&stock))
+	assert.Equal(t, api.StockResponse{ItemId: itemID, OnHand: 2, Available: 2}, stock)
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/ptr"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// headerNextCursor carries cursor of the next page of listed items
+	headerNextCursor = "X-Next-Cursor"
+	// headerTotalCount carries number of items matching the filters
+	headerTotalCount = "X-Total-Count"
+	headerLink       = "Link"
+)
+
+// listItems returns page of items matching params along with pagination metadata. Pagination headers are set
+// on the response.
+func (h *handler) listItems(ctx context.Context, eCtx echo.Context, params api.GetItemsParams) (api.ItemPage, error) {
+	if params.Cursor != nil && params.Page != nil {
+		return api.ItemPage{}, fmt.Errorf("%w: cursor cannot be combined with page", store.ErrInvalidCursor)
+	}
+	currency, err := h.requestedCurrency(params.Currency)
+	if err != nil {
+		return api.ItemPage{}, err
+	}
+	query := store.ItemQuery{
+		ItemFilter: store.ItemFilter{
+			MinPrice:           params.MinPrice,
+			MaxPrice:           params.MaxPrice,
+			PriceCode:          params.PriceCode,
+			Name:               params.Name,
+			CategoryID:         params.CategoryId,
+			IncludeDescendants: ptr.ValueOr(params.IncludeDescendants, false),
+		},
+		Sort:     string(ptr.ValueOr(params.Sort, "")),
+		Cursor:   ptr.ValueOr(params.Cursor, ""),
+		PageSize: ptr.ValueOr(params.PageSize, 100),
+		Page:     ptr.ValueOr(params.Page, 1),
+	}
+
+	items, err := h.store.GetItems(ctx, query)
+	if err != nil {
+		return api.ItemPage{}, err
+	}
+	total, err := h.store.CountItems(ctx, query.ItemFilter)
+	if err != nil {
+		return api.ItemPage{}, err
+	}
+
+	resp, err := h.itemResponses(ctx, items, currency, false)
+	if err != nil {
+		return api.ItemPage{}, err
+	}
+	page := api.ItemPage{
+		Items:      resp,
+		PageSize:   query.PageSize,
+		TotalCount: total,
+	}
+
+	reqURL := *eCtx.Request().URL
+	nextCursor := store.NextCursor(query, items)
+	if nextCursor != "" {
+		page.NextCursor = &nextCursor
+	}
+	if query.Cursor == "" {
+		page.Page = &query.Page
+		if query.Page > 1 {
+			page.Prev = pageLink(reqURL, "page", strconv.Itoa(query.Page-1))
+		}
+		if int64(query.Page)*int64(query.PageSize) < total {
+			page.Next = pageLink(reqURL, "page", strconv.Itoa(query.Page+1))
+		}
+	} else if nextCursor != "" {
+		page.Next = pageLink(reqURL, "cursor", nextCursor)
+	}
+
+	header := eCtx.Response().Header()
+	header.Set(headerTotalCount, strconv.FormatInt(total, 10))
+	if nextCursor != "" {
+		header.Set(headerNextCursor, nextCursor)
+	}
+	if link := formatLinks(page.Next, page.Prev); link != "" {
+		header.Set(headerLink, link)
+	}
+	return page, nil
+}
+
+// pageLink returns link to the page selected with either page number or cursor. Remaining query params are kept.
+func pageLink(u url.URL, param, value string) *string {
+	query := u.Query()
+	query.Del("page")
+	query.Del("cursor")
+	query.Set(param, value)
+	u.RawQuery = query.Encode()
+	link := u.RequestURI()
+	return &link
+}
+
+// formatLinks returns value of Link header as defined in RFC 8288
+func formatLinks(next, prev *string) string {
+	var links []string
+	if next != nil {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, *next))
+	}
+	if prev != nil {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, *prev))
+	}
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestGetItemsPage(t *testing.T) {
+	tests := []struct {
+		name               string
+		target             string
+		queryParams        api.GetItemsPageParams
+		storeResponse      []store.Item
+		count              int64
+		expectedPage       int
+		expectedPageSize   int
+		expectedFilter     store.ItemFilter
+		expectedResp       api.ItemPage
+		expectedLinkHeader string
+	}{
+		{
+			name:             "First page - only next link",
+			target:           "/api/v2/items?pageSize=2&name=shirt",
+			queryParams:      api.GetItemsPageParams{PageSize: v2p(2), Name: v2p("shirt")},
+			storeResponse:    []store.Item{{ID: 1}, {ID: 2}},
+			count:            5,
+			expectedPage:     1,
+			expectedPageSize: 2,
+			expectedFilter:   store.ItemFilter{Name: v2p("shirt")},
+			expectedResp: api.ItemPage{
+				Page:       v2p(1),
+				PageSize:   2,
+				TotalCount: 5,
+				Next:       v2p("/api/v2/items?name=shirt&page=2&pageSize=2"),
+			},
+			expectedLinkHeader: `</api/v2/items?name=shirt&page=2&pageSize=2>; rel="next"`,
+		},
+		{
+			name:             "Middle page - next and prev links",
+			target:           "/api/v2/items?pageSize=2&page=2",
+			queryParams:      api.GetItemsPageParams{PageSize: v2p(2), Page: v2p(2)},
+			storeResponse:    []store.Item{{ID: 3}, {ID: 4}},
+			count:            5,
+			expectedPage:     2,
+			expectedPageSize: 2,
+			expectedResp: api.ItemPage{
+				Page:       v2p(2),
+				PageSize:   2,
+				TotalCount: 5,
+				Next:       v2p("/api/v2/items?page=3&pageSize=2"),
+				Prev:       v2p("/api/v2/items?page=1&pageSize=2"),
+			},
+			expectedLinkHeader: `</api/v2/items?page=3&pageSize=2>; rel="next", </api/v2/items?page=1&pageSize=2>; rel="prev"`,
+		},
+		{
+			name:             "Last page - only prev link",
+			target:           "/api/v2/items?pageSize=2&page=3",
+			queryParams:      api.GetItemsPageParams{PageSize: v2p(2), Page: v2p(3)},
+			storeResponse:    []store.Item{{ID: 5}},
+			count:            5,
+			expectedPage:     3,
+			expectedPageSize: 2,
+			expectedResp: api.ItemPage{
+				Page:       v2p(3),
+				PageSize:   2,
+				TotalCount: 5,
+				Prev:       v2p("/api/v2/items?page=2&pageSize=2"),
+			},
+			expectedLinkHeader: `</api/v2/items?page=2&pageSize=2>; rel="prev"`,
+		},
+		{
+			name:             "Empty catalog - no links",
+			target:           "/api/v2/items",
+			queryParams:      api.GetItemsPageParams{},
+			storeResponse:    []store.Item{},
+			expectedPage:     1,
+			expectedPageSize: 100,
+			expectedResp: api.ItemPage{
+				Page:     v2p(1),
+				PageSize: 100,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockStore := &mockCatalogStore{
+				t:                t,
+				expectedPage:     test.expectedPage,
+				expectedPageSize: test.expectedPageSize,
+				expectedFilter:   test.expectedFilter,
+				getItemsResponse: test.storeResponse,
+				countResponse:    test.count,
+			}
+			req := httptest.NewRequest(http.MethodGet, test.target, nil)
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(req, rec)
+
+			err := NewHandler(logrus.New(), mockStore, allCurrencies, noRates, nil).GetItemsPage(ctx, test.queryParams)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var resp api.ItemPage
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Len(t, resp.Items, len(test.storeResponse))
+			resp.Items = nil
+			resp.NextCursor = nil
+			assert.Equal(t, test.expectedResp, resp)
+			assert.Equal(t, test.expectedLinkHeader, rec.Header().Get(headerLink))
+			assert.Equal(t, strconv.FormatInt(test.expectedResp.TotalCount, 10), rec.Header().Get(headerTotalCount))
+		})
+	}
+}
+
+func TestGetItemsPage_cursor(t *testing.T) {
+	query := store.ItemQuery{PageSize: 2, Page: 1}
+	items := []store.Item{{ID: 3}, {ID: 4}}
+	nextCursor := store.NextCursor(query, items)
+	mockStore := &mockCatalogStore{
+		t:                t,
+		expectedPage:     1,
+		expectedPageSize: 2,
+		expectedCursor:   "prev",
+		getItemsResponse: items,
+		countResponse:    10,
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/items?pageSize=2&cursor=prev", nil)
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(req, rec)
+
+	err := NewHandler(logrus.New(), mockStore, allCurrencies, noRates, nil).GetItemsPage(ctx, api.GetItemsPageParams{PageSize: v2p(2), Cursor: v2p("prev")})
+	require.NoError(t, err)
+
+	var resp api.ItemPage
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Nil(t, resp.Page)
+	assert.Nil(t, resp.Prev)
+	assert.Equal(t, &nextCursor, resp.NextCursor)
+	assert.Equal(t, "/api/v2/items?cursor="+nextCursor+"&pageSize=2", *resp.Next)
+	assert.Equal(t, nextCursor, rec.Header().Get(headerNextCursor))
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/ptr"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+// ratesSourceAdmin is the source of exchange rates set with the admin API
+const ratesSourceAdmin = "admin"
+
+// GetItemPrices returns explicit prices of the item with ID in other currencies
+func (h *handler) GetItemPrices(eCtx echo.Context, id uint) error {
+	ctx := eCtx.Request().Context()
+	if _, err := h.store.GetItem(ctx, id); err != nil {
+		return h.writeErrorResponse(eCtx, err)
+	}
+	prices, err := h.store.GetItemPrices(ctx, []uint{id})
+	if err != nil {
+		return h.writeErrorResponse(eCtx, fmt.Errorf("error while getting prices of item with id %d: %w", id, err))
+	}
+	return eCtx.JSON(http.StatusOK, mapItemPricesToPrices(prices))
+}
+
+// SetItemPrices replaces explicit prices of the item with ID in other currencies
+func (h *handler) SetItemPrices(eCtx echo.Context, id uint) error {
+	var req api.ItemPricesRequest
+	if err := eCtx.Bind(&req); err != nil {
+		return h.writeErrorResponse(eCtx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+
+	prices := make([]store.ItemPrice, 0, len(req.Prices))
+	for _, price := range req.Prices {
+		minor, code, err := parsePrice(&price.Price, &price.PriceCode, h.currencies)
+		if err != nil {
+			return h.writeErrorResponse(eCtx, err)
+		}
+		prices = append(prices, store.ItemPrice{Currency: *code, Price: *minor})
+	}
+	prices, err := h.store.SetItemPrices(eCtx.Request().Context(), id, prices)
+	if err != nil {
+		return h.writeErrorResponse(eCtx, err)
+	}
+	return eCtx.JSON(http.StatusOK, mapItemPricesToPrices(prices))
+}
+
+// GetExchangeRates returns exchange rates prices are converted with
+func (h *handler) GetExchangeRates(eCtx echo.Context) error {
+	rates, err := h.store.GetExchangeRates(eCtx.Request().Context())
+	if err != nil {
+		return h.writeErrorResponse(eCtx, err)
+	}
+	if rates.Base == "" {
+		return eCtx.JSON(http.StatusNotFound, api.ErrorResponse{Message: "no exchange rates were loaded"})
+	}
+	return eCtx.JSON(http.StatusOK, mapRatesToExchangeRatesResponse(rates))
+}
+
+// SetExchangeRates replaces exchange rates prices are converted with. Other instances pick them up from the store on
+// their next refresh.
+func (h *handler) SetExchangeRates(eCtx echo.Context) error {
+	var req api.ExchangeRatesRequest
+	if err := eCtx.Bind(&req); err != nil {
+		return h.writeErrorResponse(eCtx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+
+	rates, err := money.ParseRates(req.Base, req.Rates.AdditionalProperties, ratesSourceAdmin,
+		ptr.ValueOr(req.UpdatedAt, time.Now()).UTC())
+	if err != nil {
+		return h.writeErrorResponse(eCtx, err)
+	}
+	if err := h.store.SetExchangeRates(eCtx.Request().Context(), rates); err != nil {
+		return h.writeErrorResponse(eCtx, err)
+	}
+	h.exchange.SetRates(rates)
+	return eCtx.JSON(http.StatusOK, mapRatesToExchangeRatesResponse(rates))
+}
+
+// requestedCurrency normalizes code of the currency prices are requested in
+func (h *handler) requestedCurrency(code *string) (*string, error) {
+	if code == nil {
+		return nil, nil
+	}
+	normalized, err := h.currencies.Normalize(*code)
+	if err != nil {
+		return nil, fieldError{field: "currency", err: err}
+	}
+	return &normalized, nil
+}
+
+// listPrice returns price in currency from price list of the item, or nil when the list has no price in currency
+func listPrice(prices []store.ItemPrice, currency string) *int64 {
+	for i := range prices {
+		if prices[i].Currency == currency {
+			return &prices[i].Price
+		}
+	}
+	return nil
+}
+
+// resolvePrice returns price of the item in currency. Price scheduled in the currency takes precedence over the price
+// list, which takes precedence over base price of the item converted with rates. Nil is returned when the item cannot
+// be priced in currency.
+func resolvePrice(item store.Item, listPrice *int64, sales map[string]store.ScheduledPrice, rates money.Rates,
+	currency string) *api.CurrencyPrice {
+	regular, resp := regularPrice(item, listPrice, rates, currency)
+	if sale, ok := sales[currency]; ok {
+		amount := money.Amount{Minor: sale.Price, Currency: currency}
+		resp = &api.CurrencyPrice{Price: amount.String(), PriceCode: currency, Source: api.Scheduled}
+		setCompareAtPrice(resp, regular, amount)
+		return resp
+	}
+	if resp == nil || resp.Source != api.Conversion {
+		return resp
+	}
+	// price scheduled in currency of the item is converted the same way as its base price
+	sale, ok := sales[*item.PriceCode]
+	if !ok {
+		return resp
+	}
+	onSale := item
+	onSale.Price = &sale.Price
+	amount, resp := regularPrice(onSale, nil, rates, currency)
+	if resp != nil {
+		setCompareAtPrice(resp, regular, *amount)
+	}
+	return resp
+}
+
+// regularPrice returns price of the item in currency from its price list, base price or conversion of the base price
+// with rates. Nil is returned when the item has no price in currency and there is no rate to convert it.
+func regularPrice(item store.Item, listPrice *int64, rates money.Rates, currency string) (*money.Amount, *api.CurrencyPrice) {
+	if listPrice != nil {
+		amount := money.Amount{Minor: *listPrice, Currency: currency}
+		return &amount, &api.CurrencyPrice{Price: amount.String(), PriceCode: currency, Source: api.PriceList}
+	}
+	if item.Price == nil || item.PriceCode == nil {
+		return nil, nil
+	}
+	amount := money.Amount{Minor: *item.Price, Currency: *item.PriceCode}
+	if *item.PriceCode == currency {
+		return &amount, &api.CurrencyPrice{Price: amount.String(), PriceCode: currency, Source: api.Base}
+	}
+	converted, rate, err := rates.Convert(amount, currency)
+	if err != nil {
+		return nil, nil
+	}
+	exchangeRate := money.FormatRate(rate)
+	return &converted, &api.CurrencyPrice{
+		Price:          converted.String(),
+		PriceCode:      currency,
+		Source:         api.Conversion,
+		ExchangeRate:   &exchangeRate,
+		RateSource:     &rates.Source,
+		RatesUpdatedAt: &rates.UpdatedAt,
+	}
+}
+
+// setCompareAtPrice sets regular price as compareAtPrice when it is higher than the sale one
+func setCompareAtPrice(resp *api.CurrencyPrice, regular *money.Amount, sale money.Amount) {
+	if regular != nil && regular.Minor > sale.Minor {
+		compareAt := regular.String()
+		resp.CompareAtPrice = &compareAt
+	}
+}
+
+func mapItemPricesToPrices(prices []store.ItemPrice) []api.Price {
+	resp := make([]api.Price, 0, len(prices))
+	for _, price := range prices {
+		resp = append(resp, api.Price{
+			Price:     money.Amount{Minor: price.Price, Currency: price.Currency}.String(),
+			PriceCode: price.Currency,
+		})
+	}
+	return resp
+}
+
+func mapRatesToExchangeRatesResponse(rates money.Rates) api.ExchangeRatesResponse {
+	resp := api.ExchangeRatesResponse{
+		Base:      rates.Base,
+		Rates:     api.ExchangeRatesResponse_Rates{AdditionalProperties: make(map[string]string, len(rates.Rates))},
+		Source:    rates.Source,
+		UpdatedAt: rates.UpdatedAt,
+	}
+	for code, rate := range rates.Rates {
+		resp.Rates.AdditionalProperties[code] = money.FormatRate(rate)
+	}
+	return resp
+}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestItemPrices(t *testing.T) {
+	s := store.NewMemoryStore()
+	h := NewHandler(logrus.New(), s, allCurrencies, money.NewExchange(money.Rates{}), nil)
+	createItem := func(name, price, priceCode string) uint {
+		ctx, rec := newJSONContext(t, http.MethodPost, "/api/v1/items",
+			api.NewItemRequest{Name: name, Description: "desc", Price: price, PriceCode: priceCode})
+		require.NoError(t, h.CreateItem(ctx))
+		var item api.ItemResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+		return *item.Id
+	}
+	shirt := createItem("Shirt", "12.50", "EUR")
+	hat := createItem("Hat", "1500", "JPY")
+
+	ctx, rec := newJSONContext(t, http.MethodPut, "/api/v1/items/prices", api.ItemPricesRequest{
+		Prices: []api.Price{{Price: "13.99", PriceCode: "usd"}, {Price: "55", PriceCode: "PLN"}},
+	})
+	require.NoError(t, h.SetItemPrices(ctx, shirt))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var prices []api.Price
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&prices))
+	assert.Equal(t, []api.Price{{Price: "55.00", PriceCode: "PLN"}, {Price: "13.99", PriceCode: "USD"}}, prices)
+
+	t.Run("Invalid price lists are rejected", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			prices         []api.Price
+			expectedStatus int
+			expectedField  *string
+		}{
+			{
+				name:           "Unknown currency",
+				prices:         []api.Price{{Price: "1", PriceCode: "XYZ"}},
+				expectedStatus: http.StatusBadRequest,
+				expectedField:  v2p("priceCode"),
+			},
+			{
+				name:           "Too many fractional digits",
+				prices:         []api.Price{{Price: "1.5", PriceCode: "JPY"}},
+				expectedStatus: http.StatusBadRequest,
+				expectedField:  v2p("price"),
+			},
+			{
+				name:           "Repeated currency",
+				prices:         []api.Price{{Price: "1", PriceCode: "USD"}, {Price: "2", PriceCode: "usd"}},
+				expectedStatus: http.StatusBadRequest,
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				ctx, rec := newJSONContext(t, http.MethodPut, "/api/v1/items/prices", api.ItemPricesRequest{Prices: test.prices})
+				require.NoError(t, h.SetItemPrices(ctx, shirt))
+				assert.Equal(t, test.expectedStatus, rec.Code)
+				var resp api.ErrorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, test.expectedField, resp.Field)
+			})
+		}
+	})
+
+	t.Run("Price lists of unknown items are not found", func(t *testing.T) {
+		ctx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/prices", nil)
+		require.NoError(t, h.GetItemPrices(ctx, 404))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Exchange rates are not set until loaded", func(t *testing.T) {
+		ctx, rec := newJSONContext(t, http.MethodGet, "/api/v1/admin/exchange-rates", nil)
+		require.NoError(t, h.GetExchangeRates(ctx))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	updatedAt := time.Date(2022, 6, 1, 16, 0, 0, 0, time.UTC)
+	ctx, rec = newJSONContext(t, http.MethodPut, "/api/v1/admin/exchange-rates", api.ExchangeRatesRequest{
+		Base:      "eur",
+		Rates:     api.ExchangeRatesRequest_Rates{AdditionalProperties: map[string]string{"USD": "1.07", "JPY": "140"}},
+		UpdatedAt: &updatedAt,
+	})
+	require.NoError(t, h.SetExchangeRates(ctx))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var rates api.ExchangeRatesResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&rates))
+	assert.Equal(t, "EUR", rates.Base)
+	assert.Equal(t, map[string]string{"USD": "1.07", "JPY": "140"}, rates.Rates.AdditionalProperties)
+	assert.Equal(t, ratesSourceAdmin, rates.Source)
+	stored, err := s.GetExchangeRates(ctx.Request().Context())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"USD": "1.07", "JPY": "140"}, stored.Strings(), "rates are stored")
+
+	t.Run("Invalid exchange rates are rejected", func(t *testing.T) {
+		ctx, rec := newJSONContext(t, http.MethodPut, "/api/v1/admin/exchange-rates", api.ExchangeRatesRequest{
+			Base:  "EUR",
+			Rates: api.ExchangeRatesRequest_Rates{AdditionalProperties: map[string]string{"USD": "0"}},
+		})
+		require.NoError(t, h.SetExchangeRates(ctx))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Items are priced in requested currency", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			id       uint
+			currency string
+			expected *api.CurrencyPrice
+		}{
+			{
+				name:     "Base price",
+				id:       shirt,
+				currency: "eur",
+				expected: &api.CurrencyPrice{Price: "12.50", PriceCode: "EUR", Source: api.Base},
+			},
+			{
+				name:     "Price list",
+				id:       shirt,
+				currency: "USD",
+				expected: &api.CurrencyPrice{Price: "13.99", PriceCode: "USD", Source: api.PriceList},
+			},
+			{
+				name:     "Conversion",
+				id:       hat,
+				currency: "USD",
+				expected: &api.CurrencyPrice{
+					Price:          "11.46",
+					PriceCode:      "USD",
+					Source:         api.Conversion,
+					ExchangeRate:   v2p("0.0076428571"),
+					RateSource:     v2p(ratesSourceAdmin),
+					RatesUpdatedAt: &updatedAt,
+				},
+			},
+			{
+				name:     "No exchange rate",
+				id:       hat,
+				currency: "GBP",
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				ctx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+				require.NoError(t, h.FindItemByID(ctx, test.id, api.FindItemByIDParams{Currency: &test.currency}))
+				require.Equal(t, http.StatusOK, rec.Code)
+				var item api.ItemResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+				assert.Equal(t, test.expected, item.CurrencyPrice)
+			})
+		}
+	})
+
+	t.Run("Listed items are priced in requested currency", func(t *testing.T) {
+		ctx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+		require.NoError(t, h.GetItems(ctx, api.GetItemsParams{Currency: v2p("USD")}))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var items []api.ItemResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&items))
+		require.Len(t, items, 2)
+		assert.Equal(t, "13.99", items[0].CurrencyPrice.Price)
+		assert.Equal(t, "11.46", items[1].CurrencyPrice.Price)
+		assert.Nil(t, items[0].Prices)
+	})
+
+	t.Run("Unknown currency is rejected", func(t *testing.T) {
+		ctx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+		require.NoError(t, h.GetItems(ctx, api.GetItemsParams{Currency: v2p("XYZ")}))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	ctx, rec = newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+	require.NoError(t, h.FindItemByID(ctx, shirt, api.FindItemByIDParams{}))
+	var item api.ItemResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+	assert.Nil(t, item.CurrencyPrice)
+	assert.Equal(t, &prices, item.Prices)
+}
+
+// pricesCountingStore counts reads of price lists
+type pricesCountingStore struct {
+	*store.MemoryStore
+	priceReads int
+}
+
+func (s *pricesCountingStore) GetItemPrices(ctx context.Context, itemIDs []uint) ([]store.ItemPrice, error) {
+	s.priceReads++
+	return s.MemoryStore.GetItemPrices(ctx, itemIDs)
+}
+
+func TestFindItemByID_pricesAreReadOnce(t *testing.T) {
+	s := &pricesCountingStore{MemoryStore: store.NewMemoryStore()}
+	item, err := s.CreateItem(context.Background(), store.Item{Name: v2p("Shirt"), Description: v2p("desc"),
+		Price: v2p(int64(1250)), PriceCode: v2p("EUR")})
+	require.NoError(t, err)
+	_, err = s.SetItemPrices(context.Background(), item.ID, []store.ItemPrice{{Currency: "USD", Price: 1399}})
+	require.NoError(t, err)
+	h := NewHandler(logrus.New(), s, allCurrencies, money.NewExchange(money.Rates{}), nil)
+
+	ctx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+	require.NoError(t, h.FindItemByID(ctx, item.ID, api.FindItemByIDParams{Currency: v2p("USD")}))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp api.ItemResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "13.99", resp.CurrencyPrice.Price)
+	assert.Equal(t, &[]api.Price{{Price: "13.99", PriceCode: "USD"}}, resp.Prices)
+	assert.Equal(t, 1, s.priceReads)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/ptr"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// GetScheduledPrices returns scheduled prices of the item with ID
+func (h *handler) GetScheduledPrices(ctx echo.Context, id uint) error {
+	prices, err := h.store.GetScheduledPrices(ctx.Request().Context(), id)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, h.mapScheduledPricesToResponses(prices))
+}
+
+// CreateScheduledPrice schedules price of the item with ID
+func (h *handler) CreateScheduledPrice(ctx echo.Context, id uint) error {
+	var req api.ScheduledPriceRequest
+	if err := ctx.Bind(&req); err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+
+	price, priceCode, err := parsePrice(&req.Price, &req.PriceCode, h.currencies)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	scheduled, err := h.store.CreateScheduledPrice(ctx.Request().Context(), id, store.ScheduledPrice{
+		Currency: *priceCode,
+		Price:    *price,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+	})
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusCreated, h.mapScheduledPriceToResponse(scheduled))
+}
+
+// DeleteScheduledPriceByID removes scheduled price with ID of the item
+func (h *handler) DeleteScheduledPriceByID(ctx echo.Context, id uint, scheduledPriceID uint) error {
+	if err := h.store.DeleteScheduledPrice(ctx.Request().Context(), id, scheduledPriceID); err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// GetUpcomingPrices returns scheduled prices of all items which start or end in the future
+func (h *handler) GetUpcomingPrices(ctx echo.Context, params api.GetUpcomingPricesParams) error {
+	prices, err := h.store.GetUpcomingPrices(ctx.Request().Context(), h.now(), ptr.ValueOr(params.PageSize, 100),
+		ptr.ValueOr(params.Page, 1))
+	if err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while getting upcoming prices: %w", err))
+	}
+	return ctx.JSON(http.StatusOK, h.mapScheduledPricesToResponses(prices))
+}
+
+// activeSales returns scheduled prices in effect now keyed by item ID and currency. When windows overlap, the price
+// which started most recently is used.
+func (h *handler) activeSales(ctx context.Context, itemIDs []uint) (map[uint]map[string]store.ScheduledPrice, error) {
+	prices, err := h.store.GetActivePrices(ctx, itemIDs, h.now())
+	if err != nil {
+		return nil, err
+	}
+	sales := map[uint]map[string]store.ScheduledPrice{}
+	for _, price := range prices {
+		if sales[price.ItemID] == nil {
+			sales[price.ItemID] = map[string]store.ScheduledPrice{}
+		}
+		// prices are ordered by start, so later ones override earlier
+		sales[price.ItemID][price.Currency] = price
+	}
+	return sales, nil
+}
+
+// applySale replaces price of the item with the one scheduled in its currency. Regular price is kept as
+// compareAtPrice when it is higher.
+func applySale(resp *api.ItemResponse, item store.Item, sales map[string]store.ScheduledPrice) {
+	if item.Price == nil || item.PriceCode == nil {
+		return
+	}
+	sale, ok := sales[*item.PriceCode]
+	if !ok {
+		return
+	}
+	resp.Price = formatPrice(&sale.Price, item.PriceCode)
+	if *item.Price > sale.Price {
+		resp.CompareAtPrice = formatPrice(item.Price, item.PriceCode)
+	}
+}
+
+func (h *handler) mapScheduledPricesToResponses(prices []store.ScheduledPrice) []api.ScheduledPriceResponse {
+	resp := make([]api.ScheduledPriceResponse, 0, len(prices))
+	for _, price := range prices {
+		resp = append(resp, h.mapScheduledPriceToResponse(price))
+	}
+	return resp
+}
+
+func (h *handler) mapScheduledPriceToResponse(price store.ScheduledPrice) api.ScheduledPriceResponse {
+	resp := api.ScheduledPriceResponse{
+		Id:        price.ID,
+		ItemId:    price.ItemID,
+		Price:     *formatPrice(&price.Price, &price.Currency),
+		PriceCode: price.Currency,
+		StartsAt:  price.StartsAt.UTC(),
+		Active:    price.ActiveAt(h.now()),
+	}
+	if price.EndsAt != nil {
+		endsAt := price.EndsAt.UTC()
+		resp.EndsAt = &endsAt
+	}
+	return resp
+}
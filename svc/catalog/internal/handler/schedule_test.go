@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestScheduledPrices(t *testing.T) {
+	rates, err := money.ParseRates("EUR", map[string]string{"USD": "1.1"}, "test", time.Time{})
+	require.NoError(t, err)
+	h := NewHandler(logrus.New(), store.NewMemoryStore(), allCurrencies, money.NewExchange(rates), nil)
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	ctx, rec := newJSONContext(t, http.MethodPost, "/api/v1/items",
+		api.NewItemRequest{Name: "Shirt", Description: "desc", Price: "20", PriceCode: "EUR"})
+	require.NoError(t, h.CreateItem(ctx))
+	var item api.ItemResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+	itemID := *item.Id
+	schedule := func(req api.ScheduledPriceRequest) api.ScheduledPriceResponse {
+		ctx, rec := newJSONContext(t, http.MethodPost, "/api/v1/items/scheduled-prices", req)
+		require.NoError(t, h.CreateScheduledPrice(ctx, itemID))
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var resp api.ScheduledPriceResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		return resp
+	}
+	findItem := func(currency *string) api.ItemResponse {
+		ctx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+		require.NoError(t, h.FindItemByID(ctx, itemID, api.FindItemByIDParams{Currency: currency}))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp api.ItemResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		return resp
+	}
+
+	sale := schedule(api.ScheduledPriceRequest{
+		Price: "15", PriceCode: "eur", StartsAt: now.Add(-time.Hour), EndsAt: v2p(now.Add(time.Hour)),
+	})
+	assert.Equal(t, api.ScheduledPriceResponse{
+		Id: sale.Id, ItemId: itemID, Price: "15.00", PriceCode: "EUR",
+		StartsAt: now.Add(-time.Hour), EndsAt: v2p(now.Add(time.Hour)), Active: true,
+	}, sale)
+	upcoming := schedule(api.ScheduledPriceRequest{Price: "18", PriceCode: "EUR", StartsAt: now.Add(24 * time.Hour)})
+	assert.False(t, upcoming.Active)
+
+	t.Run("Invalid scheduled prices are rejected", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			req            api.ScheduledPriceRequest
+			expectedStatus int
+		}{
+			{
+				name:           "Window ends before it starts",
+				req:            api.ScheduledPriceRequest{Price: "1", PriceCode: "EUR", StartsAt: now, EndsAt: v2p(now.Add(-time.Hour))},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "Unknown currency",
+				req:            api.ScheduledPriceRequest{Price: "1", PriceCode: "XYZ", StartsAt: now},
+				expectedStatus: http.StatusBadRequest,
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				ctx, rec := newJSONContext(t, http.MethodPost, "/api/v1/items/scheduled-prices", test.req)
+				require.NoError(t, h.CreateScheduledPrice(ctx, itemID))
+				assert.Equal(t, test.expectedStatus, rec.Code)
+			})
+		}
+	})
+
+	t.Run("Active sale replaces price", func(t *testing.T) {
+		resp := findItem(nil)
+		assert.Equal(t, "15.00", *resp.Price)
+		assert.Equal(t, v2p("20.00"), resp.CompareAtPrice)
+	})
+
+	t.Run("Active sale is converted", func(t *testing.T) {
+		resp := findItem(v2p("USD"))
+		require.NotNil(t, resp.CurrencyPrice)
+		assert.Equal(t, api.Conversion, resp.CurrencyPrice.Source)
+		assert.Equal(t, "16.50", resp.CurrencyPrice.Price)
+		assert.Equal(t, v2p("22.00"), resp.CurrencyPrice.CompareAtPrice)
+	})
+
+	t.Run("Upcoming price changes are listed", func(t *testing.T) {
+		ctx, rec := newJSONContext(t, http.MethodGet, "/api/v1/prices/upcoming", nil)
+		require.NoError(t, h.GetUpcomingPrices(ctx, api.GetUpcomingPricesParams{}))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp []api.ScheduledPriceResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, []api.ScheduledPriceResponse{sale, upcoming}, resp)
+	})
+
+	usdSale := schedule(api.ScheduledPriceRequest{Price: "12", PriceCode: "USD", StartsAt: now.Add(-time.Minute)})
+	t.Run("Sale scheduled in requested currency takes precedence", func(t *testing.T) {
+		resp := findItem(v2p("USD"))
+		require.NotNil(t, resp.CurrencyPrice)
+		assert.Equal(t, api.CurrencyPrice{
+			Price:          "12.00",
+			PriceCode:      "USD",
+			Source:         api.Scheduled,
+			CompareAtPrice: v2p("22.00"),
+		}, *resp.CurrencyPrice)
+	})
+
+	ctx, rec = newJSONContext(t, http.MethodDelete, fmt.Sprintf("/api/v1/items/scheduled-prices/%d", usdSale.Id), nil)
+	require.NoError(t, h.DeleteScheduledPriceByID(ctx, itemID, usdSale.Id))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	ctx, rec = newJSONContext(t, http.MethodDelete, fmt.Sprintf("/api/v1/items/scheduled-prices/%d", usdSale.Id), nil)
+	require.NoError(t, h.DeleteScheduledPriceByID(ctx, itemID, usdSale.Id))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	t.Run("Regular price is restored once sale ends", func(t *testing.T) {
+		now = now.Add(2 * time.Hour)
+		resp := findItem(nil)
+		assert.Equal(t, "20.00", *resp.Price)
+		assert.Nil(t, resp.CompareAtPrice)
+
+		ctx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/scheduled-prices", nil)
+		require.NoError(t, h.GetScheduledPrices(ctx, itemID))
+		var prices []api.ScheduledPriceResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&prices))
+		require.Len(t, prices, 2)
+		assert.False(t, prices[0].Active)
+	})
+}
+
+func TestFindItemByID_etagFollowsSale(t *testing.T) {
+	rates, err := money.ParseRates("EUR", map[string]string{"USD": "1.1"}, "test", time.Time{})
+	require.NoError(t, err)
+	h := NewHandler(logrus.New(), store.NewMemoryStore(), allCurrencies, money.NewExchange(rates), nil)
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+	ctx, rec := newJSONContext(t, http.MethodPost, "/api/v1/items",
+		api.NewItemRequest{Name: "Shirt", Description: "desc", Price: "20", PriceCode: "EUR"})
+	require.NoError(t, h.CreateItem(ctx))
+	var item api.ItemResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+	ctx, rec = newJSONContext(t, http.MethodPost, "/api/v1/items/scheduled-prices",
+		api.ScheduledPriceRequest{Price: "15", PriceCode: "EUR", StartsAt: now.Add(time.Hour)})
+	require.NoError(t, h.CreateScheduledPrice(ctx, *item.Id))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	findItem := func(currency, ifNoneMatch *string) *httptest.ResponseRecorder {
+		ctx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+		require.NoError(t, h.FindItemByID(ctx, *item.Id, api.FindItemByIDParams{Currency: currency,
+			IfNoneMatch: ifNoneMatch}))
+		return rec
+	}
+	etag := findItem(nil, nil).Header().Get(headerETag)
+
+	assert.Equal(t, `"2"`, etag)
+	assert.Equal(t, http.StatusNotModified, findItem(nil, &etag).Code)
+	now = now.Add(2 * time.Hour)
+	rec = findItem(nil, &etag)
+	require.Equal(t, http.StatusOK, rec.Code, "item is rendered differently once sale starts")
+	saleETag := rec.Header().Get(headerETag)
+	assert.Equal(t, `"2-1"`, saleETag)
+	var resp api.ItemResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "15.00", *resp.Price)
+	assert.Equal(t, `"2"`, *resp.Etag, "version isn't bumped by start of sale")
+	assert.Equal(t, http.StatusNotModified, findItem(nil, &saleETag).Code)
+
+	ctx, rec = newJSONContext(t, http.MethodPut, "/api/v1/items", api.UpdateItemRequest{Name: v2p("Linen shirt")})
+	require.NoError(t, h.UpdateItemByID(ctx, *item.Id, api.UpdateItemByIDParams{IfMatch: &saleETag}))
+	assert.Equal(t, http.StatusOK, rec.Code, "ETag of fetched item is accepted in If-Match")
+}
+
+func TestFindItemByID_etagIsAcceptedInIfMatch(t *testing.T) {
+	h := NewHandler(logrus.New(), store.NewMemoryStore(), allCurrencies, noRates, nil)
+	ctx, rec := newJSONContext(t, http.MethodPost, "/api/v1/items",
+		api.NewItemRequest{Name: "Shirt", Description: "desc", Price: "20", PriceCode: "EUR"})
+	require.NoError(t, h.CreateItem(ctx))
+	var item api.ItemResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+
+	ctx, rec = newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+	require.NoError(t, h.FindItemByID(ctx, *item.Id, api.FindItemByIDParams{}))
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get(headerETag)
+	ctx, rec = newJSONContext(t, http.MethodPut, "/api/v1/items", api.UpdateItemRequest{Name: v2p("Linen shirt")})
+	require.NoError(t, h.UpdateItemByID(ctx, *item.Id, api.UpdateItemByIDParams{IfMatch: &etag}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	ctx, rec = newJSONContext(t, http.MethodPut, "/api/v1/items", api.UpdateItemRequest{Name: v2p("Wool shirt")})
+	require.NoError(t, h.UpdateItemByID(ctx, *item.Id, api.UpdateItemByIDParams{IfMatch: &etag}))
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code, "ETag of modified item is stale")
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"net/http"
+)
+
+// FindItemByExternalID returns live item with the external ID the same way as FindItemByID does
+func (h *handler) FindItemByExternalID(eCtx echo.Context, externalID string, params api.FindItemByExternalIDParams) error {
+	ctx, span := otel.Tracer("").Start(eCtx.Request().Context(), "GetItemByExternalID")
+	span.SetAttributes(attribute.Key("externalID").String(externalID))
+	defer span.End()
+	currency, err := h.requestedCurrency(params.Currency)
+	if err != nil {
+		return h.writeErrorResponse(eCtx, err)
+	}
+	item, err := h.store.GetItemByExternalID(ctx, externalID)
+	if err != nil {
+		return h.writeErrorResponse(eCtx, err)
+	}
+	return h.writeItem(ctx, eCtx, item, currency, params.IfNoneMatch)
+}
+
+// UpsertItemByExternalID creates item with the external ID or updates the live item which already has it
+func (h *handler) UpsertItemByExternalID(ctx echo.Context, externalID string) error {
+	var newItem api.NewItemRequest
+	if err := ctx.Bind(&newItem); err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+	if newItem.ExternalId != nil && *newItem.ExternalId != externalID {
+		return h.writeErrorResponse(ctx, fieldError{field: "externalId", err: fmt.Errorf(
+			"%w: externalId %q of the body doesn't match the path", store.ErrInvalidItem, *newItem.ExternalId)})
+	}
+	newItem.ExternalId = &externalID
+
+	model, err := mapItemToItemModel(newItem, h.currencies)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	item, outcome, err := h.store.UpsertItemByExternalID(ctx.Request().Context(), model)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+
+	ctx.Response().Header().Set(headerETag, formatETag(item.Version))
+	if outcome == store.UpsertCreated {
+		ctx.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/api/v1/items/%d", item.ID))
+		return ctx.JSON(http.StatusCreated, mapItemModelToItemResponse(item))
+	}
+	return ctx.JSON(http.StatusOK, mapItemModelToItemResponse(item))
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestUpsertItemByExternalID(t *testing.T) {
+	s := store.NewMemoryStore()
+	h := NewHandler(logrus.New(), s, allCurrencies, noRates, nil)
+	shirt := api.NewItemRequest{Name: "Shirt", Description: "Cotton", Price: "12.50", PriceCode: "EUR"}
+	tests := []struct {
+		name             string
+		externalID       string
+		body             api.NewItemRequest
+		expectedStatus   int
+		expectedETag     string
+		expectedLocation string
+	}{
+		{
+			name:             "Item is created",
+			externalID:       "SUP-1",
+			body:             shirt,
+			expectedStatus:   http.StatusCreated,
+			expectedETag:     `"1"`,
+			expectedLocation: "/api/v1/items/1",
+		},
+		{
+			name:           "Unchanged item keeps its version",
+			externalID:     "SUP-1",
+			body:           api.NewItemRequest{Name: "Shirt", Description: "Cotton", Price: "12.5", PriceCode: "eur"},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"1"`,
+		},
+		{
+			name:       "Item is updated",
+			externalID: "SUP-1",
+			body: api.NewItemRequest{ExternalId: v2p("SUP-1"), Name: "Shirt", Description: "Linen", Price: "15.00",
+				PriceCode: "EUR"},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"2"`,
+		},
+		{
+			name:       "External ID of the body doesn't match the path",
+			externalID: "SUP-2",
+			body: api.NewItemRequest{ExternalId: v2p("SUP-1"), Name: "Shirt", Description: "Linen", Price: "15.00",
+				PriceCode: "EUR"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid price",
+			externalID:     "SUP-2",
+			body:           api.NewItemRequest{Name: "Shirt", Description: "Cotton", Price: "12.505", PriceCode: "EUR"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eCtx, rec := newJSONContext(t, http.MethodPut, "/api/v1/items/by-external-id/"+test.externalID, test.body)
+
+			require.NoError(t, h.UpsertItemByExternalID(eCtx, test.externalID))
+
+			require.Equal(t, test.expectedStatus, rec.Code)
+			if rec.Code >= http.StatusBadRequest {
+				return
+			}
+			assert.Equal(t, test.expectedETag, rec.Header().Get("ETag"))
+			assert.Equal(t, test.expectedLocation, rec.Header().Get(echo.HeaderLocation))
+			var resp api.ItemResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, test.externalID, *resp.ExternalId)
+		})
+	}
+
+	items, err := s.GetItems(context.Background(), store.ItemQuery{PageSize: 10, Page: 1})
+	require.NoError(t, err)
+	assert.Len(t, items, 1, "upserts don't create duplicates")
+}
+
+func TestFindItemByExternalID(t *testing.T) {
+	s := store.NewMemoryStore()
+	h := NewHandler(logrus.New(), s, allCurrencies, noRates, nil)
+	item, err := s.CreateItem(context.Background(), store.Item{ExternalID: v2p("SUP-1"), Name: v2p("Shirt"),
+		Description: v2p("Cotton"), Price: v2p(int64(1250)), PriceCode: v2p("EUR")})
+	require.NoError(t, err)
+	tests := []struct {
+		name           string
+		externalID     string
+		ifNoneMatch    *string
+		expectedStatus int
+	}{
+		{
+			name:           "Item is found",
+			externalID:     "SUP-1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Item is not modified",
+			externalID:     "SUP-1",
+			ifNoneMatch:    v2p(formatETag(item.Version)),
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "Item is missing",
+			externalID:     "SUP-2",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/items/by-external-id/"+test.externalID, nil)
+
+			require.NoError(t, h.FindItemByExternalID(eCtx, test.externalID,
+				api.FindItemByExternalIDParams{IfNoneMatch: test.ifNoneMatch}))
+
+			require.Equal(t, test.expectedStatus, rec.Code)
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var resp api.ItemResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, item.ID, *resp.Id)
+			assert.Equal(t, "12.50", *resp.Price)
+		})
+	}
+}
+
+func TestCreateItem_externalIDConflict(t *testing.T) {
+	s := store.NewMemoryStore()
+	h := NewHandler(logrus.New(), s, allCurrencies, noRates, nil)
+	_, err := s.CreateItem(context.Background(), store.Item{ExternalID: v2p("SUP-1"), Name: v2p("Shirt"),
+		Description: v2p("Cotton"), Price: v2p(int64(1250)), PriceCode: v2p("EUR")})
+	require.NoError(t, err)
+	eCtx, rec := newJSONContext(t, http.MethodPost, "/api/v1/items", api.NewItemRequest{ExternalId: v2p("SUP-1"),
+		Name: "Hat", Description: "Wool", Price: "5.00", PriceCode: "EUR"})
+
+	require.NoError(t, h.CreateItem(eCtx))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestUpsertItemByExternalID_storeError(t *testing.T) {
+	h := NewHandler(logrus.New(), &mockCatalogStore{err: errors.New("connection refused")}, allCurrencies, noRates, nil)
+	eCtx, rec := newJSONContext(t, http.MethodPut, "/api/v1/items/by-external-id/SUP-1",
+		api.NewItemRequest{Name: "Shirt", Description: "Cotton", Price: "12.50", PriceCode: "EUR"})
+
+	require.NoError(t, h.UpsertItemByExternalID(eCtx, "SUP-1"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/ptr"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// GetVariants returns variants of the item with ID from the underlying store
+func (h *handler) GetVariants(ctx echo.Context, id uint) error {
+	variants, err := h.store.GetVariants(ctx.Request().Context(), id)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapVariantsToVariantResponses(variants))
+}
+
+// CreateVariant handles creation of a new variant of the item with ID in the underlying store
+func (h *handler) CreateVariant(ctx echo.Context, id uint) error {
+	var req api.VariantRequest
+	if err := ctx.Bind(&req); err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+
+	model, err := mapVariantRequestToVariant(req, h.currencies)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	variant, err := h.store.CreateVariant(ctx.Request().Context(), id, model)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusCreated, mapVariantToVariantResponse(variant))
+}
+
+// FindVariantByID returns variant with ID of the item from the underlying store
+func (h *handler) FindVariantByID(ctx echo.Context, id uint, variantID uint) error {
+	variant, err := h.store.GetVariant(ctx.Request().Context(), id, variantID)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapVariantToVariantResponse(variant))
+}
+
+// UpdateVariantByID replaces variant with ID of the item in the underlying store
+func (h *handler) UpdateVariantByID(ctx echo.Context, id uint, variantID uint) error {
+	var req api.VariantRequest
+	if err := ctx.Bind(&req); err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+
+	model, err := mapVariantRequestToVariant(req, h.currencies)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	variant, err := h.store.UpdateVariant(ctx.Request().Context(), id, variantID, model)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapVariantToVariantResponse(variant))
+}
+
+// DeleteVariantByID deletes variant with ID of the item from the underlying store
+func (h *handler) DeleteVariantByID(ctx echo.Context, id uint, variantID uint) error {
+	if err := h.store.DeleteVariant(ctx.Request().Context(), id, variantID); err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func mapVariantRequestToVariant(req api.VariantRequest, currencies *money.CurrencySet) (store.Variant, error) {
+	price, priceCode, err := parsePrice(req.Price, req.PriceCode, currencies)
+	if err != nil {
+		return store.Variant{}, err
+	}
+	return store.Variant{
+		SKU:       req.Sku,
+		Options:   ptr.ValueOr(req.Options, nil),
+		Price:     price,
+		PriceCode: priceCode,
+		Barcode:   req.Barcode,
+	}, nil
+}
+
+func mapVariantToVariantResponse(variant store.Variant) api.VariantResponse {
+	options := variant.Options
+	if options == nil {
+		options = store.VariantOptions{}
+	}
+	return api.VariantResponse{
+		Id:        variant.ID,
+		Sku:       variant.SKU,
+		Options:   options,
+		Price:     formatPrice(variant.Price, variant.PriceCode),
+		PriceCode: variant.PriceCode,
+		Barcode:   variant.Barcode,
+	}
+}
+
+func mapVariantsToVariantResponses(variants []store.Variant) []api.VariantResponse {
+	resp := make([]api.VariantResponse, 0, len(variants))
+	for _, variant := range variants {
+		resp = append(resp, mapVariantToVariantResponse(variant))
+	}
+	return resp
+}
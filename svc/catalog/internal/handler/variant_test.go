@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestVariants(t *testing.T) {
+	h := NewHandler(logrus.New(), store.NewMemoryStore(), allCurrencies, noRates, nil)
+	ctx, rec := newJSONContext(t, http.MethodPost, "/api/v1/items",
+		api.NewItemRequest{Name: "Shirt", Description: "desc", Price: "10", PriceCode: "EUR"})
+	require.NoError(t, h.CreateItem(ctx))
+	var item api.ItemResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+	itemID := *item.Id
+
+	ctx, rec = newJSONContext(t, http.MethodPost, "/api/v1/items/variants", api.VariantRequest{
+		Sku:       "SHIRT-M",
+		Options:   &map[string]string{"size": "M"},
+		Price:     v2p("12.5"),
+		PriceCode: v2p("EUR"),
+		Barcode:   v2p("4006381333931"),
+	})
+	require.NoError(t, h.CreateVariant(ctx, itemID))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var variant api.VariantResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&variant))
+	assert.Equal(t, api.VariantResponse{
+		Id:        variant.Id,
+		Sku:       "SHIRT-M",
+		Options:   map[string]string{"size": "M"},
+		Price:     v2p("12.50"),
+		PriceCode: v2p("EUR"),
+		Barcode:   v2p("4006381333931"),
+	}, variant)
+
+	tests := []struct {
+		name           string
+		itemID         uint
+		req            api.VariantRequest
+		expectedStatus int
+	}{
+		{
+			name:           "Conflict - options already used",
+			itemID:         itemID,
+			req:            api.VariantRequest{Sku: "SHIRT-M2", Options: &map[string]string{"size": "M"}},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Bad Request - invalid barcode",
+			itemID:         itemID,
+			req:            api.VariantRequest{Sku: "SHIRT-L", Barcode: v2p("4006381333932")},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Not Found - no item",
+			itemID:         100,
+			req:            api.VariantRequest{Sku: "SHIRT-L"},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, rec := newJSONContext(t, http.MethodPost, "/api/v1/items/variants", test.req)
+			require.NoError(t, h.CreateVariant(ctx, test.itemID))
+			assert.Equal(t, test.expectedStatus, rec.Code)
+		})
+	}
+
+	ctx, rec = newJSONContext(t, http.MethodGet, "/api/v1/items", nil)
+	require.NoError(t, h.FindItemByID(ctx, itemID, api.FindItemByIDParams{}))
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&item))
+	variant.Available = v2p(int64(0))
+	assert.Equal(t, &[]api.VariantResponse{variant}, item.Variants)
+	assert.Equal(t, formatETag(2), *item.Etag)
+
+	ctx, rec = newJSONContext(t, http.MethodPut, "/api/v1/items/variants", api.VariantRequest{Sku: "SHIRT-M"})
+	require.NoError(t, h.UpdateVariantByID(ctx, itemID, variant.Id))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var updated api.VariantResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&updated))
+	assert.Equal(t, api.VariantResponse{Id: variant.Id, Sku: "SHIRT-M", Options: map[string]string{}}, updated)
+
+	ctx, rec = newJSONContext(t, http.MethodDelete, "/api/v1/items/variants", nil)
+	require.NoError(t, h.DeleteVariantByID(ctx, itemID, variant.Id))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	ctx, rec = newJSONContext(t, http.MethodGet, "/api/v1/items/variants", nil)
+	require.NoError(t, h.FindVariantByID(ctx, itemID, variant.Id))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/ptr"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// CreateWebhook subscribes webhook to events in the underlying store
+func (h *handler) CreateWebhook(ctx echo.Context) error {
+	var req api.WebhookRequest
+	if err := ctx.Bind(&req); err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while decoding request body: %w", err))
+	}
+
+	eventTypes := make(store.EventTypes, 0, len(req.EventTypes))
+	for _, eventType := range req.EventTypes {
+		eventTypes = append(eventTypes, store.EventType(eventType))
+	}
+	webhook, err := h.store.CreateWebhook(ctx.Request().Context(), store.Webhook{
+		URL:        req.Url,
+		EventTypes: eventTypes,
+		Secret:     req.Secret,
+	})
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusCreated, mapWebhookToWebhookResponse(webhook))
+}
+
+// GetWebhooks returns all webhooks from the underlying store
+func (h *handler) GetWebhooks(ctx echo.Context) error {
+	webhooks, err := h.store.GetWebhooks(ctx.Request().Context())
+	if err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while getting webhooks: %w", err))
+	}
+
+	resp := make([]api.WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		resp = append(resp, mapWebhookToWebhookResponse(webhook))
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// FindWebhookByID returns webhook with ID from the underlying store
+func (h *handler) FindWebhookByID(ctx echo.Context, webhookID uint) error {
+	webhook, err := h.store.GetWebhook(ctx.Request().Context(), webhookID)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapWebhookToWebhookResponse(webhook))
+}
+
+// DeleteWebhook unsubscribes webhook with ID together with its deliveries
+func (h *handler) DeleteWebhook(ctx echo.Context, webhookID uint) error {
+	if err := h.store.DeleteWebhook(ctx.Request().Context(), webhookID); err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries returns deliveries of events to webhook with ID, newest first
+func (h *handler) GetWebhookDeliveries(ctx echo.Context, webhookID uint, params api.GetWebhookDeliveriesParams) error {
+	query := store.DeliveryQuery{WebhookID: &webhookID, PageSize: ptr.ValueOr(params.PageSize, 100),
+		Page: ptr.ValueOr(params.Page, 1)}
+	if params.Status != nil {
+		status := store.DeliveryStatus(*params.Status)
+		query.Status = &status
+	}
+	return h.writeDeliveries(ctx, query)
+}
+
+// GetDeadLetters returns deliveries to all webhooks which exhausted all attempts, newest first
+func (h *handler) GetDeadLetters(ctx echo.Context, params api.GetDeadLettersParams) error {
+	status := store.DeliveryDead
+	return h.writeDeliveries(ctx, store.DeliveryQuery{
+		Status:   &status,
+		PageSize: ptr.ValueOr(params.PageSize, 100),
+		Page:     ptr.ValueOr(params.Page, 1),
+	})
+}
+
+// RedeliverDelivery schedules delivery with ID to be attempted again right away
+func (h *handler) RedeliverDelivery(ctx echo.Context, webhookID uint, deliveryID uint) error {
+	delivery, err := h.store.RedeliverDelivery(ctx.Request().Context(), webhookID, deliveryID)
+	if err != nil {
+		return h.writeErrorResponse(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, mapDeliveryToDeliveryResponse(delivery))
+}
+
+// writeDeliveries responds with deliveries matching the query
+func (h *handler) writeDeliveries(ctx echo.Context, query store.DeliveryQuery) error {
+	deliveries, err := h.store.GetDeliveries(ctx.Request().Context(), query)
+	if err != nil {
+		return h.writeErrorResponse(ctx, fmt.Errorf("error while getting deliveries: %w", err))
+	}
+
+	resp := make([]api.DeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		resp = append(resp, mapDeliveryToDeliveryResponse(delivery))
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func mapWebhookToWebhookResponse(webhook store.Webhook) api.WebhookResponse {
+	eventTypes := make([]api.EventType, 0, len(webhook.EventTypes))
+	for _, eventType := range webhook.EventTypes {
+		eventTypes = append(eventTypes, api.EventType(eventType))
+	}
+	return api.WebhookResponse{
+		Id:         webhook.ID,
+		Url:        webhook.URL,
+		EventTypes: eventTypes,
+		CreatedAt:  webhook.CreatedAt,
+	}
+}
+
+func mapDeliveryToDeliveryResponse(delivery store.WebhookDelivery) api.DeliveryResponse {
+	resp := api.DeliveryResponse{
+		Id:             delivery.ID,
+		WebhookId:      delivery.WebhookID,
+		EventId:        delivery.EventID,
+		EventType:      api.EventType(delivery.EventType),
+		Status:         api.DeliveryStatus(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+	if delivery.Status == store.DeliveryPending {
+		resp.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return resp
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/konrad945/eCommerce/svc/catalog/api"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWebhooks(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemoryStore()
+	h := NewHandler(logrus.New(), s, allCurrencies, noRates, nil)
+
+	t.Run("Invalid webhook is rejected", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodPost, "/api/v1/webhooks", api.WebhookRequest{
+			Url: "ftp://example.com", EventTypes: []api.EventType{api.ItemCreated}, Secret: "0123456789abcdef",
+		})
+
+		require.NoError(t, h.CreateWebhook(eCtx))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	eCtx, rec := newJSONContext(t, http.MethodPost, "/api/v1/webhooks", api.WebhookRequest{
+		Url: "https://example.com/hook", EventTypes: []api.EventType{api.ItemCreated}, Secret: "0123456789abcdef",
+	})
+	require.NoError(t, h.CreateWebhook(eCtx))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "0123456789abcdef", "secret is not returned")
+	var webhook api.WebhookResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&webhook))
+	assert.Equal(t, []api.EventType{api.ItemCreated}, webhook.EventTypes)
+
+	// dead delivery, as left by the worker after all attempts failed
+	_, err := s.CreateDeliveries(ctx, store.Event{ID: 1, Type: store.ItemCreated}, `{"id":1}`)
+	require.NoError(t, err)
+	claimed, err := s.ClaimDueDeliveries(ctx, time.Now(), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	_, err = s.RecordDeliveryAttempt(ctx, claimed[0].ID, store.DeliveryAttempt{At: time.Now(), Error: v2p("timeout")})
+	require.NoError(t, err)
+
+	t.Run("Deliveries are filtered by status", func(t *testing.T) {
+		dead, delivered := api.DeliveryStatusDead, api.DeliveryStatusDelivered
+		for status, expected := range map[*api.DeliveryStatus]int{nil: 1, &dead: 1, &delivered: 0} {
+			eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/webhooks/1/deliveries", nil)
+
+			require.NoError(t, h.GetWebhookDeliveries(eCtx, webhook.Id, api.GetWebhookDeliveriesParams{Status: status}))
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			var resp []api.DeliveryResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Len(t, resp, expected)
+		}
+	})
+
+	t.Run("Dead letter is redelivered", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodGet, "/api/v1/webhooks/dead-letters", nil)
+		require.NoError(t, h.GetDeadLetters(eCtx, api.GetDeadLettersParams{}))
+		var deadLetters []api.DeliveryResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&deadLetters))
+		require.Len(t, deadLetters, 1)
+		assert.Equal(t, "timeout", *deadLetters[0].LastError)
+		assert.Nil(t, deadLetters[0].NextAttemptAt)
+
+		eCtx, rec = newJSONContext(t, http.MethodPost, "/api/v1/webhooks/1/deliveries/1/redeliver", nil)
+		require.NoError(t, h.RedeliverDelivery(eCtx, webhook.Id, deadLetters[0].Id))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var redelivered api.DeliveryResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&redelivered))
+		assert.Equal(t, api.DeliveryStatusPending, redelivered.Status)
+		assert.Equal(t, 0, redelivered.Attempts)
+		assert.NotNil(t, redelivered.NextAttemptAt)
+
+		eCtx, rec = newJSONContext(t, http.MethodPost, "/api/v1/webhooks/1/deliveries/1/redeliver", nil)
+		require.NoError(t, h.RedeliverDelivery(eCtx, webhook.Id, deadLetters[0].Id))
+		assert.Equal(t, http.StatusConflict, rec.Code, "pending delivery cannot be redelivered")
+	})
+
+	t.Run("Deleted webhook is not found", func(t *testing.T) {
+		eCtx, rec := newJSONContext(t, http.MethodDelete, "/api/v1/webhooks/1", nil)
+		require.NoError(t, h.DeleteWebhook(eCtx, webhook.Id))
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		eCtx, rec = newJSONContext(t, http.MethodGet, "/api/v1/webhooks/1", nil)
+		require.NoError(t, h.FindWebhookByID(eCtx, webhook.Id))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		eCtx, rec = newJSONContext(t, http.MethodGet, "/api/v1/webhooks/1/deliveries", nil)
+		require.NoError(t, h.GetWebhookDeliveries(eCtx, webhook.Id, api.GetWebhookDeliveriesParams{}))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,227 @@
+// Package importer imports catalog items from CSV and NDJSON files uploaded by suppliers
+package importer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/ptr"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"io"
+	"strings"
+	"time"
+)
+
+// Store is a set of store methods the worker imports items with
+type Store interface {
+	ClaimImportJob(ctx context.Context, now time.Time, lease time.Duration) (store.ImportJob, []byte, error)
+	RecordImportProgress(ctx context.Context, id uint, claim int, progress store.ImportProgress) error
+	UpsertItemByExternalID(ctx context.Context, item store.Item) (store.Item, store.UpsertOutcome, error)
+}
+
+// Config controls how import jobs are processed
+type Config struct {
+	// Lease is how long the claimed job is processed before another worker can take it over, it is extended every
+	// time progress is recorded
+	Lease time.Duration
+	// BatchSize is number of rows after which progress is recorded, so the job can be resumed from there
+	BatchSize int
+	// MaxAttempts is number of times the job is claimed before it is failed, e.g. as store keeps failing
+	MaxAttempts int
+	// RetryDelay is how long the job waits to be claimed again when importing a row fails
+	RetryDelay time.Duration
+}
+
+// Worker imports items from rows of queued jobs. Every row is validated and upserted by its external ID on its own,
+// invalid rows are rejected without stopping the import.
+type Worker struct {
+	store      Store
+	currencies *money.CurrencySet
+	conf       Config
+	log        *logrus.Logger
+	now        func() time.Time
+}
+
+// NewWorker creates Worker importing items to s, prices are accepted only in currencies from the set
+func NewWorker(s Store, currencies *money.CurrencySet, conf Config, log *logrus.Logger) *Worker {
+	return &Worker{store: s, currencies: currencies, conf: conf, log: log, now: time.Now}
+}
+
+// Import processes claimable jobs one by one until there are no more
+func (w *Worker) Import(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, data, err := w.store.ClaimImportJob(ctx, w.now(), w.conf.Lease)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return
+		}
+		if err != nil {
+			w.log.Errorf("error while claiming import job: %s", err)
+			return
+		}
+		w.process(ctx, job, data)
+	}
+}
+
+// process imports rows of the claimed job which were not processed yet
+func (w *Worker) process(ctx context.Context, job store.ImportJob, data []byte) {
+	progress := store.ImportProgress{ImportCounts: job.ImportCounts, Status: store.ImportRunning, Error: job.Error}
+	if job.Claims > w.conf.MaxAttempts {
+		w.fail(ctx, job, progress, fmt.Errorf("import was attempted %d times: %s", w.conf.MaxAttempts,
+			ptr.ValueOr(job.Error, "interrupted")))
+		return
+	}
+	rows, err := newReader(job.Format, bytes.NewReader(data))
+	if err != nil {
+		w.fail(ctx, job, progress, err)
+		return
+	}
+	pending := 0
+	for {
+		rec, err := rows.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			w.fail(ctx, job, progress, err)
+			return
+		}
+		if rec.row <= job.ProcessedRows {
+			// row was processed before the job was interrupted
+			continue
+		}
+		if err := w.importRecord(ctx, rec, &progress); err != nil {
+			if ctx.Err() == nil {
+				w.retry(ctx, job, progress, err)
+			}
+			// rows after the last recorded progress are imported again once the job is claimed again
+			return
+		}
+		if pending++; pending >= w.conf.BatchSize {
+			progress.LeaseUntil = w.now().Add(w.conf.Lease)
+			if !w.record(ctx, job, progress) {
+				return
+			}
+			progress.Rejections = nil
+			pending = 0
+		}
+	}
+	progress.Status = store.ImportCompleted
+	progress.Error = nil
+	if w.record(ctx, job, progress) {
+		w.log.Infof("import job %d completed: %d created, %d updated, %d unchanged and %d rejected items",
+			job.ID, progress.CreatedItems, progress.UpdatedItems, progress.UnchangedItems, progress.RejectedRows)
+	}
+}
+
+// importRecord upserts item from the record or rejects it when it is invalid. Error is returned only when the item
+// couldn't be upserted for reasons unrelated to the record, so it should be imported again later.
+func (w *Worker) importRecord(ctx context.Context, rec record, progress *store.ImportProgress) error {
+	item, rejection := w.item(rec)
+	if rejection == nil {
+		_, outcome, err := w.store.UpsertItemByExternalID(ctx, item)
+		switch {
+		case errors.Is(err, store.ErrInvalidItem):
+			rejection = newRejection(rec, "", err.Error())
+		case err != nil:
+			return fmt.Errorf("error while importing row %d: %w", rec.row, err)
+		case outcome == store.UpsertCreated:
+			progress.CreatedItems++
+		case outcome == store.UpsertUpdated:
+			progress.UpdatedItems++
+		default:
+			progress.UnchangedItems++
+		}
+	}
+	if rejection != nil {
+		progress.Rejections = append(progress.Rejections, *rejection)
+		progress.RejectedRows++
+	}
+	progress.ProcessedRows = rec.row
+	return nil
+}
+
+// item maps fields of the record to the upserted item, the record is rejected when any of them is invalid
+func (w *Worker) item(rec record) (store.Item, *store.ImportRejection) {
+	if rec.err != nil {
+		return store.Item{}, newRejection(rec, "", rec.err.Error())
+	}
+	required := []struct {
+		field string
+		value *string
+	}{
+		{field: fieldExternalID, value: rec.externalID},
+		{field: fieldName, value: rec.name},
+		{field: fieldPrice, value: rec.price},
+		{field: fieldPriceCode, value: rec.priceCode},
+	}
+	for _, r := range required {
+		if r.value == nil || strings.TrimSpace(*r.value) == "" {
+			return store.Item{}, newRejection(rec, r.field, r.field+" is required")
+		}
+	}
+	priceCode, err := w.currencies.Normalize(strings.TrimSpace(*rec.priceCode))
+	if err != nil {
+		return store.Item{}, newRejection(rec, fieldPriceCode, err.Error())
+	}
+	price, err := money.Parse(strings.TrimSpace(*rec.price), priceCode)
+	if err != nil {
+		return store.Item{}, newRejection(rec, fieldPrice, err.Error())
+	}
+	if price.Minor < 0 {
+		return store.Item{}, newRejection(rec, fieldPrice, "price should not be negative")
+	}
+	externalID := strings.TrimSpace(*rec.externalID)
+	name := strings.TrimSpace(*rec.name)
+	item := store.Item{ExternalID: &externalID, Name: &name, Price: &price.Minor, PriceCode: &priceCode}
+	// missing or blank description keeps description of the existing item, so files without it don't erase it
+	if description := strings.TrimSpace(ptr.ValueOr(rec.description, "")); description != "" {
+		item.Description = &description
+	}
+	return item, nil
+}
+
+// record records progress of the job and reports if the job can be processed further
+func (w *Worker) record(ctx context.Context, job store.ImportJob, progress store.ImportProgress) bool {
+	if err := w.store.RecordImportProgress(ctx, job.ID, job.Claims, progress); err != nil {
+		if ctx.Err() == nil {
+			w.log.Errorf("error while recording progress of import job %d: %s", job.ID, err)
+		}
+		return false
+	}
+	return true
+}
+
+// retry records progress of the job interrupted by err, so it is resumed after a delay
+func (w *Worker) retry(ctx context.Context, job store.ImportJob, progress store.ImportProgress, err error) {
+	w.log.Warnf("import job %d interrupted: %s", job.ID, err)
+	msg := err.Error()
+	progress.Error = &msg
+	progress.LeaseUntil = w.now().Add(w.conf.RetryDelay)
+	w.record(ctx, job, progress)
+}
+
+// fail records that the job failed as a whole with err
+func (w *Worker) fail(ctx context.Context, job store.ImportJob, progress store.ImportProgress, err error) {
+	w.log.Warnf("import job %d failed: %s", job.ID, err)
+	msg := err.Error()
+	progress.Status = store.ImportFailed
+	progress.Error = &msg
+	w.record(ctx, job, progress)
+}
+
+// newRejection returns rejection of the record, field is empty when the record as a whole is invalid
+func newRejection(rec record, field, msg string) *store.ImportRejection {
+	rejection := &store.ImportRejection{Row: rec.row, Message: msg}
+	if rec.externalID != nil && strings.TrimSpace(*rec.externalID) != "" {
+		externalID := strings.TrimSpace(*rec.externalID)
+		rejection.ExternalID = &externalID
+	}
+	if field != "" {
+		rejection.Field = &field
+	}
+	return rejection
+}
@@ -0,0 +1,206 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingStore fails upserts while failures are left
+type failingStore struct {
+	*store.MemoryStore
+	failures int
+}
+
+func (s *failingStore) UpsertItemByExternalID(ctx context.Context, item store.Item) (store.Item, store.UpsertOutcome, error) {
+	if s.failures > 0 {
+		s.failures--
+		return store.Item{}, "", errors.New("connection refused")
+	}
+	return s.MemoryStore.UpsertItemByExternalID(ctx, item)
+}
+
+func newTestWorker(t *testing.T, s Store) *Worker {
+	currencies, err := money.NewCurrencySet("EUR", "JPY")
+	require.NoError(t, err)
+	w := NewWorker(s, currencies, Config{
+		Lease: time.Minute, BatchSize: 2, MaxAttempts: 3, RetryDelay: time.Hour,
+	}, logrus.New())
+	// jobs are queued by the store at current time, so they are due for the worker
+	now := time.Now().Add(time.Second)
+	w.now = func() time.Time { return now }
+	return w
+}
+
+func TestWorker(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemoryStore()
+	w := newTestWorker(t, s)
+	rejections := func(jobID uint) []store.ImportRejection {
+		rejections, err := s.GetImportRejections(ctx, jobID, 10, 1)
+		require.NoError(t, err)
+		return rejections
+	}
+
+	t.Run("Valid rows are upserted and invalid ones rejected", func(t *testing.T) {
+		job, err := s.CreateImportJob(ctx, store.ImportCSV, []byte("externalId,name,description,price,priceCode\n"+
+			"SUP-1, Shirt ,Cotton,12.50,eur\n"+
+			"SUP-2,,Wool,10,EUR\n"+
+			"SUP-3,Hat,,10,USD\n"+
+			"SUP-4,Scarf,,12.505,EUR\n"+
+			"SUP-5,Gloves,,-1,EUR\n"+
+			"SUP-6,Socks,,1500,JPY\n"+
+			",Belt,,5,EUR\n"))
+		require.NoError(t, err)
+
+		w.Import(ctx)
+
+		imported, err := s.GetImportJob(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, store.ImportCompleted, imported.Status)
+		assert.Equal(t, store.ImportCounts{ProcessedRows: 7, CreatedItems: 2, RejectedRows: 5}, imported.ImportCounts)
+		rejected := rejections(job.ID)
+		require.Len(t, rejected, 5)
+		expected := []struct {
+			row   int
+			field string
+		}{{2, fieldName}, {3, fieldPriceCode}, {4, fieldPrice}, {5, fieldPrice}, {7, fieldExternalID}}
+		for i, e := range expected {
+			assert.Equal(t, e.row, rejected[i].Row)
+			assert.Equal(t, e.field, *rejected[i].Field)
+		}
+		assert.Equal(t, "SUP-2", *rejected[0].ExternalID)
+		assert.Nil(t, rejected[4].ExternalID)
+
+		items, err := s.GetItems(ctx, store.ItemQuery{PageSize: 10, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, "SUP-1", *items[0].ExternalID)
+		assert.Equal(t, "Shirt", *items[0].Name)
+		assert.Equal(t, int64(1250), *items[0].Price)
+		assert.Equal(t, "EUR", *items[0].PriceCode)
+		assert.Equal(t, int64(1500), *items[1].Price)
+	})
+
+	t.Run("Items are updated by external ID", func(t *testing.T) {
+		job, err := s.CreateImportJob(ctx, store.ImportNDJSON, []byte(
+			`{"externalId":"SUP-1","name":"Shirt","description":"Cotton","price":12.5,"priceCode":"EUR"}`+"\n"+
+				`{"externalId":"SUP-6","name":"Socks","price":"2000","priceCode":"JPY"}`+"\n"+
+				`{"externalId":"SUP-7","name":"Scarf","price":"200","priceCode":"EUR","description":"`+
+				strings.Repeat("a", 300)+`"}`+"\n"))
+		require.NoError(t, err)
+
+		w.Import(ctx)
+
+		imported, err := s.GetImportJob(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, store.ImportCounts{ProcessedRows: 3, UpdatedItems: 1, UnchangedItems: 1, RejectedRows: 1},
+			imported.ImportCounts)
+		rejected := rejections(job.ID)
+		require.Len(t, rejected, 1)
+		assert.Nil(t, rejected[0].Field)
+		assert.Contains(t, rejected[0].Message, "description should be at most")
+	})
+
+	t.Run("Descriptions are kept when missing or blank", func(t *testing.T) {
+		for i, data := range []string{
+			"externalId,name,price,priceCode\nSUP-1,Shirt,13,EUR\n",
+			"externalId,name,description,price,priceCode\nSUP-1,Shirt, ,14,EUR\n",
+			`{"externalId":"SUP-1","name":"Shirt","price":"15","priceCode":"EUR"}` + "\n",
+		} {
+			job, err := s.CreateImportJob(ctx, store.ImportCSV, []byte(data))
+			if i == 2 {
+				job, err = s.CreateImportJob(ctx, store.ImportNDJSON, []byte(data))
+			}
+			require.NoError(t, err)
+
+			w.Import(ctx)
+
+			imported, err := s.GetImportJob(ctx, job.ID)
+			require.NoError(t, err)
+			assert.Equal(t, store.ImportCounts{ProcessedRows: 1, UpdatedItems: 1}, imported.ImportCounts)
+			item, err := s.GetItemByExternalID(ctx, "SUP-1")
+			require.NoError(t, err)
+			assert.Equal(t, int64(1300+100*i), *item.Price)
+			assert.Equal(t, "Cotton", *item.Description)
+		}
+	})
+
+	t.Run("Unreadable file fails the job", func(t *testing.T) {
+		job, err := s.CreateImportJob(ctx, store.ImportCSV, []byte("id,title\n1,Shirt\n"))
+		require.NoError(t, err)
+
+		w.Import(ctx)
+
+		failed, err := s.GetImportJob(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, store.ImportFailed, failed.Status)
+		assert.Equal(t, "header row has no externalId column", *failed.Error)
+	})
+}
+
+func TestWorker_resume(t *testing.T) {
+	ctx := context.Background()
+	s := &failingStore{MemoryStore: store.NewMemoryStore(), failures: 1}
+	w := newTestWorker(t, s)
+	job, err := s.CreateImportJob(ctx, store.ImportCSV, []byte("externalId,name,price,priceCode\n"+
+		"SUP-1,Shirt,10,EUR\nSUP-2,Hat,x,EUR\nSUP-3,Scarf,5,EUR\nSUP-4,Gloves,5,EUR\n"))
+	require.NoError(t, err)
+	// the first two rows are imported before the job is interrupted
+	claimed, _, err := s.ClaimImportJob(ctx, w.now(), time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, s.RecordImportProgress(ctx, job.ID, claimed.Claims, store.ImportProgress{
+		ImportCounts: store.ImportCounts{ProcessedRows: 2, CreatedItems: 1, RejectedRows: 1},
+		Rejections:   []store.ImportRejection{{Row: 2, Message: "invalid price"}},
+		Status:       store.ImportRunning,
+		LeaseUntil:   w.now(),
+	}))
+
+	w.Import(ctx)
+
+	retried, err := s.GetImportJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, store.ImportRunning, retried.Status)
+	assert.Equal(t, "error while importing row 3: connection refused", *retried.Error)
+	assert.Equal(t, w.now().Add(time.Hour), retried.LeaseUntil, "job is retried after delay")
+
+	now := w.now().Add(time.Hour)
+	w.now = func() time.Time { return now }
+	w.Import(ctx)
+
+	imported, err := s.GetImportJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, store.ImportCompleted, imported.Status)
+	assert.Nil(t, imported.Error)
+	assert.Equal(t, store.ImportCounts{ProcessedRows: 4, CreatedItems: 3, RejectedRows: 1}, imported.ImportCounts)
+	count, err := s.CountItems(ctx, store.ItemFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count, "rows processed before the interruption are not imported again")
+}
+
+func TestWorker_maxAttempts(t *testing.T) {
+	ctx := context.Background()
+	s := &failingStore{MemoryStore: store.NewMemoryStore(), failures: 10}
+	w := newTestWorker(t, s)
+	job, err := s.CreateImportJob(ctx, store.ImportCSV, []byte("externalId,name,price,priceCode\nSUP-1,Shirt,10,EUR\n"))
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		w.Import(ctx)
+		now := w.now().Add(time.Hour)
+		w.now = func() time.Time { return now }
+	}
+
+	failed, err := s.GetImportJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, store.ImportFailed, failed.Status)
+	assert.Equal(t, 4, failed.Claims)
+	assert.Equal(t, "import was attempted 3 times: error while importing row 1: connection refused", *failed.Error)
+}
@@ -0,0 +1,201 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"io"
+	"strings"
+)
+
+// maxLineSize limits single line of NDJSON file
+const maxLineSize = 1 << 20
+
+// Fields of the item records are mapped to
+const (
+	fieldExternalID  = "externalId"
+	fieldName        = "name"
+	fieldDescription = "description"
+	fieldPrice       = "price"
+	fieldPriceCode   = "priceCode"
+)
+
+// csvColumns maps normalized CSV headers to fields, so spreadsheets can name columns as they like
+var csvColumns = map[string]string{
+	"externalid":  fieldExternalID,
+	"sku":         fieldExternalID,
+	"name":        fieldName,
+	"description": fieldDescription,
+	"price":       fieldPrice,
+	"pricecode":   fieldPriceCode,
+	"currency":    fieldPriceCode,
+}
+
+// requiredColumns have to be present in CSV header, rows without other columns are rejected
+var requiredColumns = []string{fieldExternalID, fieldName, fieldPrice, fieldPriceCode}
+
+// record is a row of the imported file with fields as they were provided, unset fields were missing. Rows are
+// numbered from 1 in order of records in the file, not counting the CSV header and blank lines.
+type record struct {
+	row         int
+	externalID  *string
+	name        *string
+	description *string
+	price       *string
+	priceCode   *string
+	// err is set when the row couldn't be read, e.g. it is malformed JSON
+	err error
+}
+
+// reader reads records of the imported file
+type reader interface {
+	// next returns the next record of the file or io.EOF when there are no more. Other errors mean that the rest of
+	// the file can't be read.
+	next() (record, error)
+}
+
+// newReader returns reader of file in provided format
+func newReader(format store.ImportFormat, r io.Reader) (reader, error) {
+	switch format {
+	case store.ImportCSV:
+		return newCSVReader(r)
+	case store.ImportNDJSON:
+		return newNDJSONReader(r), nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// csvReader reads records from CSV file with header row
+type csvReader struct {
+	r *csv.Reader
+	// fields holds field of every column, columns not mapped to any field are ignored
+	fields []string
+	row    int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("file has no header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error while reading header row: %w", err)
+	}
+	fields := make([]string, len(header))
+	mapped := map[string]bool{}
+	for i, column := range header {
+		field := csvColumns[normalizeColumn(column, i)]
+		if field == "" {
+			continue
+		}
+		if mapped[field] {
+			return nil, fmt.Errorf("column %q duplicates %s column", column, field)
+		}
+		fields[i] = field
+		mapped[field] = true
+	}
+	for _, field := range requiredColumns {
+		if !mapped[field] {
+			return nil, fmt.Errorf("header row has no %s column", field)
+		}
+	}
+	return &csvReader{r: cr, fields: fields}, nil
+}
+
+// normalizeColumn makes CSV header at index case insensitive and ignores separators, so "Price Code" and "price_code"
+// are the same column
+func normalizeColumn(column string, index int) string {
+	if index == 0 {
+		// spreadsheet applications often prepend byte order mark to CSV files
+		column = strings.TrimPrefix(column, "\ufeff")
+	}
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(column)))
+}
+
+func (r *csvReader) next() (record, error) {
+	values, err := r.r.Read()
+	if errors.Is(err, io.EOF) {
+		return record{}, io.EOF
+	}
+	r.row++
+	rec := record{row: r.row}
+	if errors.Is(err, csv.ErrFieldCount) {
+		rec.err = fmt.Errorf("row has %d columns, while header has %d", len(values), len(r.fields))
+		return rec, nil
+	}
+	if err != nil {
+		return record{}, fmt.Errorf("error while reading row %d: %w", r.row, err)
+	}
+	for i, value := range values {
+		value := value
+		switch r.fields[i] {
+		case fieldExternalID:
+			rec.externalID = &value
+		case fieldName:
+			rec.name = &value
+		case fieldDescription:
+			rec.description = &value
+		case fieldPrice:
+			rec.price = &value
+		case fieldPriceCode:
+			rec.priceCode = &value
+		}
+	}
+	return rec, nil
+}
+
+// jsonRecord is a line of NDJSON file, price can be either a JSON string or a number
+type jsonRecord struct {
+	ExternalID  *string      `json:"externalId"`
+	Name        *string      `json:"name"`
+	Description *string      `json:"description"`
+	Price       *json.Number `json:"price"`
+	PriceCode   *string      `json:"priceCode"`
+}
+
+// ndjsonReader reads records from file with JSON object on every line
+type ndjsonReader struct {
+	s   *bufio.Scanner
+	row int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, maxLineSize)
+	return &ndjsonReader{s: s}
+}
+
+func (r *ndjsonReader) next() (record, error) {
+	for r.s.Scan() {
+		line := r.s.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		r.row++
+		var decoded jsonRecord
+		if err := json.Unmarshal(line, &decoded); err != nil {
+			return record{row: r.row, err: fmt.Errorf("invalid JSON: %w", err)}, nil
+		}
+		rec := record{
+			row:         r.row,
+			externalID:  decoded.ExternalID,
+			name:        decoded.Name,
+			description: decoded.Description,
+			priceCode:   decoded.PriceCode,
+		}
+		if decoded.Price != nil {
+			price := decoded.Price.String()
+			rec.price = &price
+		}
+		return rec, nil
+	}
+	if err := r.s.Err(); err != nil {
+		return record{}, fmt.Errorf("error while reading row %d: %w", r.row+1, err)
+	}
+	return record{}, io.EOF
+}
@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"errors"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+)
+
+// readAll returns all records of the file, or error which stopped reading it
+func readAll(t *testing.T, format store.ImportFormat, content string) ([]record, error) {
+	rows, err := newReader(format, strings.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	var records []record
+	for {
+		rec, err := rows.next()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, rec)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectedRes []record
+		expectedErr string
+	}{
+		{
+			name:    "Columns are mapped by header",
+			content: "\ufeffSKU,Price Code,price,Name,Color\nSUP-1,eur,12.50,Shirt,red\n",
+			expectedRes: []record{
+				{row: 1, externalID: v2p("SUP-1"), name: v2p("Shirt"), price: v2p("12.50"), priceCode: v2p("eur")},
+			},
+		},
+		{
+			name: "Quoted values and blank lines",
+			content: "external_id,name,description,price,currency\n\n" +
+				"SUP-1,\"Shirt, blue\",\"Soft\nand warm\",10,EUR\n\nSUP-2,Hat,,5,EUR\n",
+			expectedRes: []record{
+				{row: 1, externalID: v2p("SUP-1"), name: v2p("Shirt, blue"), description: v2p("Soft\nand warm"),
+					price: v2p("10"), priceCode: v2p("EUR")},
+				{row: 2, externalID: v2p("SUP-2"), name: v2p("Hat"), description: v2p(""), price: v2p("5"),
+					priceCode: v2p("EUR")},
+			},
+		},
+		{
+			name:    "Row with different number of columns is rejected",
+			content: "externalId,name,price,priceCode\nSUP-1,Shirt,10\nSUP-2,Hat,5,EUR\n",
+			expectedRes: []record{
+				{row: 1, err: errors.New("row has 3 columns, while header has 4")},
+				{row: 2, externalID: v2p("SUP-2"), name: v2p("Hat"), price: v2p("5"), priceCode: v2p("EUR")},
+			},
+		},
+		{
+			name:        "Missing required column",
+			content:     "externalId,name,price\nSUP-1,Shirt,10\n",
+			expectedErr: "header row has no priceCode column",
+		},
+		{
+			name:        "Duplicated column",
+			content:     "externalId,sku,name,price,priceCode\n",
+			expectedErr: `column "sku" duplicates externalId column`,
+		},
+		{
+			name:        "Empty file",
+			content:     "",
+			expectedErr: "file has no header row",
+		},
+		{
+			name:        "Malformed quotes",
+			content:     "externalId,name,price,priceCode\nSUP-1,\"Shirt,10,EUR\n",
+			expectedErr: "error while reading row 1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := readAll(t, store.ImportCSV, test.content)
+
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedRes, records)
+		})
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	content := `{"externalId":"SUP-1","name":"Shirt","price":12.5,"priceCode":"EUR","color":"red"}` + "\n\n" +
+		`{"externalId":"SUP-2","name":"Hat","description":"Warm","price":"5.00","priceCode":"EUR"}` + "\n" +
+		`{"externalId":"SUP-3",` + "\n" +
+		`{"externalId":"SUP-4","price":"cheap"}`
+
+	records, err := readAll(t, store.ImportNDJSON, content)
+
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, record{row: 1, externalID: v2p("SUP-1"), name: v2p("Shirt"), price: v2p("12.5"),
+		priceCode: v2p("EUR")}, records[0])
+	assert.Equal(t, record{row: 2, externalID: v2p("SUP-2"), name: v2p("Hat"), description: v2p("Warm"),
+		price: v2p("5.00"), priceCode: v2p("EUR")}, records[1])
+	assert.Equal(t, 3, records[2].row, "blank lines are not counted")
+	assert.ErrorContains(t, records[2].err, "invalid JSON")
+	assert.ErrorContains(t, records[3].err, "invalid JSON")
+}
+
+func TestNDJSONReader_tooLongLine(t *testing.T) {
+	content := `{"externalId":"SUP-1"}` + "\n" + `{"name":"` + strings.Repeat("a", maxLineSize) + `"}`
+
+	records, err := readAll(t, store.ImportNDJSON, content)
+
+	assert.Len(t, records, 1)
+	assert.ErrorContains(t, err, "error while reading row 2")
+}
+
+func v2p[V any](val V) *V {
+	return &val
+}
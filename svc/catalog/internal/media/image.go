@@ -0,0 +1,241 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // registers GIF decoder, thumbnails of GIF images are encoded as PNG
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+)
+
+// Size is a name of stored rendition of the image
+type Size string
+
+const (
+	SizeOriginal Size = "original"
+	SizeSmall    Size = "small"
+	SizeMedium   Size = "medium"
+	SizeLarge    Size = "large"
+)
+
+const (
+	// maxPixels limits dimensions of uploaded images, as they are decoded into memory to make thumbnails
+	maxPixels   = 25_000_000
+	jpegQuality = 85
+)
+
+var ErrUnsupportedImage = errors.New("unsupported image")
+
+// thumbnailEdges are lengths of the longest edge of every thumbnail size in pixels
+var thumbnailEdges = map[Size]int{
+	SizeSmall:  160,
+	SizeMedium: 480,
+	SizeLarge:  1024,
+}
+
+// supportedTypes are content types of images which can be uploaded
+var supportedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Image is an uploaded image stored together with its thumbnails
+type Image struct {
+	// Key is a prefix of keys of all sizes of the image in the storage
+	Key         string
+	ContentType string
+	Width       int
+	Height      int
+	// Size is a number of bytes of the original image
+	Size int64
+}
+
+// ContentType returns content type of the image stored in provided size. Thumbnails of JPEG images are JPEG images,
+// thumbnails of other images are PNG images.
+func ContentType(original string, size Size) string {
+	if size == SizeOriginal || original == "image/jpeg" {
+		return original
+	}
+	return "image/png"
+}
+
+// Library stores uploaded images and thumbnails made of them
+type Library struct {
+	storage Storage
+	// decodes holds a slot for every image being decoded, as decoded image can take hundreds of megabytes
+	decodes chan struct{}
+}
+
+// NewLibrary creates Library keeping images in storage, which decodes at most maxDecodes images at once
+func NewLibrary(storage Storage, maxDecodes int) *Library {
+	if maxDecodes < 1 {
+		maxDecodes = 1
+	}
+	return &Library{storage: storage, decodes: make(chan struct{}, maxDecodes)}
+}
+
+// Save stores image in data together with its thumbnails. Content type of the image is detected from its content,
+// ErrUnsupportedImage is returned when it isn't JPEG, PNG or GIF image. Save waits until fewer than maxDecodes other
+// images are being decoded.
+func (l *Library) Save(ctx context.Context, data []byte) (Image, error) {
+	contentType := http.DetectContentType(data)
+	if !supportedTypes[contentType] {
+		return Image{}, fmt.Errorf("%w: expected JPEG, PNG or GIF image, got %s", ErrUnsupportedImage, contentType)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, fmt.Errorf("%w: %s", ErrUnsupportedImage, err)
+	}
+	if config.Width*config.Height > maxPixels {
+		return Image{}, fmt.Errorf("%w: image can have at most %d pixels", ErrUnsupportedImage, maxPixels)
+	}
+	select {
+	case l.decodes <- struct{}{}:
+		defer func() { <-l.decodes }()
+	case <-ctx.Done():
+		return Image{}, fmt.Errorf("error while waiting to decode image: %w", ctx.Err())
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, fmt.Errorf("%w: %s", ErrUnsupportedImage, err)
+	}
+
+	key, err := newKey()
+	if err != nil {
+		return Image{}, err
+	}
+	img := Image{Key: key, ContentType: contentType, Width: config.Width, Height: config.Height,
+		Size: int64(len(data))}
+	if err := l.save(ctx, img, data, decoded); err != nil {
+		if rmErr := l.Remove(context.Background(), key); rmErr != nil {
+			err = fmt.Errorf("%w, %s", err, rmErr)
+		}
+		return Image{}, err
+	}
+	return img, nil
+}
+
+// save stores original image and its thumbnails
+func (l *Library) save(ctx context.Context, img Image, data []byte, decoded image.Image) error {
+	if err := l.storage.Put(ctx, blobKey(img.Key, SizeOriginal), bytes.NewReader(data)); err != nil {
+		return err
+	}
+	// thumbnails read pixels directly, so other images are copied into RGBA one
+	rgba, ok := decoded.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(image.Rect(0, 0, img.Width, img.Height))
+		draw.Draw(rgba, rgba.Bounds(), decoded, decoded.Bounds().Min, draw.Src)
+	}
+	for size, edge := range thumbnailEdges {
+		var buf bytes.Buffer
+		var err error
+		if ContentType(img.ContentType, size) == "image/jpeg" {
+			err = jpeg.Encode(&buf, thumbnail(rgba, edge), &jpeg.Options{Quality: jpegQuality})
+		} else {
+			err = png.Encode(&buf, thumbnail(rgba, edge))
+		}
+		if err != nil {
+			return fmt.Errorf("error while encoding %s thumbnail: %w", size, err)
+		}
+		if err := l.storage.Put(ctx, blobKey(img.Key, size), &buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Open returns reader of the image with key stored in provided size
+func (l *Library) Open(ctx context.Context, key string, size Size) (io.ReadCloser, error) {
+	if _, ok := thumbnailEdges[size]; !ok && size != SizeOriginal {
+		return nil, fmt.Errorf("error while opening image %s: unknown size %q", key, size)
+	}
+	return l.storage.Open(ctx, blobKey(key, size))
+}
+
+// Remove deletes all sizes of the image with key
+func (l *Library) Remove(ctx context.Context, key string) error {
+	var errs []error
+	if err := l.storage.Delete(ctx, blobKey(key, SizeOriginal)); err != nil {
+		errs = append(errs, err)
+	}
+	for size := range thumbnailEdges {
+		if err := l.storage.Delete(ctx, blobKey(key, size)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("error while removing image %s: %w", key, errs[0])
+	}
+	return nil
+}
+
+// thumbnail scales src down to fit in a square with provided edge keeping its aspect ratio. Every pixel of the
+// thumbnail is an average of the pixels of src it covers. Images which already fit are returned as they are.
+func thumbnail(src *image.RGBA, edge int) *image.RGBA {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= edge && h <= edge {
+		return src
+	}
+	dw, dh := edge, edge
+	if w > h {
+		dh = h * edge / w
+	} else {
+		dw = w * edge / h
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*h/dh, (y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*w/dw, (x+1)*w/dw
+			var sum [4]uint64
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(bounds.Min.X+x0, bounds.Min.Y+sy)
+				for sx := x0; sx < x1; sx++ {
+					for c := range sum {
+						sum[c] += uint64(src.Pix[i+c])
+					}
+					i += 4
+				}
+			}
+			n := uint64((y1 - y0) * (x1 - x0))
+			j := dst.PixOffset(x, y)
+			for c := range sum {
+				dst.Pix[j+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return dst
+}
+
+// newKey returns random key of a new image
+func newKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error while generating image key: %w", err)
+	}
+	id := hex.EncodeToString(b)
+	// images are spread over subdirectories, so none of them grows too large
+	return "images/" + id[:2] + "/" + id, nil
+}
+
+// blobKey returns key of the image with key stored in provided size
+func blobKey(key string, size Size) string {
+	return key + "/" + string(size)
+}
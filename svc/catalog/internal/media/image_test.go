@@ -0,0 +1,175 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+	"time"
+)
+
+// encodeImage returns w x h image filled with red, encoded with enc
+func encodeImage(t *testing.T, w, h int, enc func(io.Writer, image.Image) error) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		copy(img.Pix[i:], []byte{255, 0, 0, 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, enc(&buf, img))
+	return buf.Bytes()
+}
+
+func encodeJPEG(w io.Writer, img image.Image) error { return jpeg.Encode(w, img, nil) }
+
+func encodeGIF(w io.Writer, img image.Image) error { return gif.Encode(w, img, nil) }
+
+func TestLibrary_Save(t *testing.T) {
+	tests := []struct {
+		name                string
+		data                []byte
+		expectedContentType string
+		expectedThumbType   string
+		expectedWidth       int
+		expectedHeight      int
+		expectedThumbs      map[Size]image.Point
+	}{
+		{
+			name:                "JPEG image",
+			data:                encodeImage(t, 2000, 1000, encodeJPEG),
+			expectedContentType: "image/jpeg",
+			expectedThumbType:   "jpeg",
+			expectedWidth:       2000,
+			expectedHeight:      1000,
+			expectedThumbs: map[Size]image.Point{
+				SizeSmall: {X: 160, Y: 80}, SizeMedium: {X: 480, Y: 240}, SizeLarge: {X: 1024, Y: 512},
+			},
+		},
+		{
+			name:                "PNG image smaller than thumbnails",
+			data:                encodeImage(t, 100, 300, png.Encode),
+			expectedContentType: "image/png",
+			expectedThumbType:   "png",
+			expectedWidth:       100,
+			expectedHeight:      300,
+			expectedThumbs: map[Size]image.Point{
+				SizeSmall: {X: 53, Y: 160}, SizeMedium: {X: 100, Y: 300}, SizeLarge: {X: 100, Y: 300},
+			},
+		},
+		{
+			name:                "GIF image",
+			data:                encodeImage(t, 320, 320, encodeGIF),
+			expectedContentType: "image/gif",
+			expectedThumbType:   "png",
+			expectedWidth:       320,
+			expectedHeight:      320,
+			expectedThumbs: map[Size]image.Point{
+				SizeSmall: {X: 160, Y: 160}, SizeMedium: {X: 320, Y: 320}, SizeLarge: {X: 320, Y: 320},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			storage, err := NewFileStorage(t.TempDir())
+			require.NoError(t, err)
+			l := NewLibrary(storage, 1)
+
+			img, err := l.Save(ctx, test.data)
+
+			require.NoError(t, err)
+			assert.Regexp(t, `^images/[0-9a-f]{2}/[0-9a-f]{32}$`, img.Key)
+			assert.Equal(t, test.expectedContentType, img.ContentType)
+			assert.Equal(t, test.expectedWidth, img.Width)
+			assert.Equal(t, test.expectedHeight, img.Height)
+			assert.Equal(t, int64(len(test.data)), img.Size)
+
+			r, err := l.Open(ctx, img.Key, SizeOriginal)
+			require.NoError(t, err)
+			original, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			assert.Equal(t, test.data, original, "original is stored as uploaded")
+			for size, dimensions := range test.expectedThumbs {
+				r, err := l.Open(ctx, img.Key, size)
+				require.NoError(t, err)
+				thumb, format, err := image.Decode(r)
+				require.NoError(t, err)
+				require.NoError(t, r.Close())
+				assert.Equal(t, test.expectedThumbType, format, size)
+				assert.Equal(t, dimensions, thumb.Bounds().Size(), size)
+			}
+
+			require.NoError(t, l.Remove(ctx, img.Key))
+			for _, size := range []Size{SizeOriginal, SizeSmall, SizeMedium, SizeLarge} {
+				_, err := l.Open(ctx, img.Key, size)
+				assert.True(t, errors.Is(err, ErrBlobNotFound), size)
+			}
+		})
+	}
+}
+
+func TestLibrary_Save_unsupported(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir())
+	require.NoError(t, err)
+	l := NewLibrary(storage, 1)
+
+	for name, data := range map[string][]byte{
+		"text":            []byte("not an image"),
+		"SVG":             []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`),
+		"truncated image": encodeImage(t, 50, 50, png.Encode)[:100],
+	} {
+		_, err := l.Save(context.Background(), data)
+
+		assert.True(t, errors.Is(err, ErrUnsupportedImage), name)
+	}
+}
+
+func TestLibrary_Save_limitsDecodes(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir())
+	require.NoError(t, err)
+	l := NewLibrary(storage, 1)
+	data := encodeImage(t, 50, 50, png.Encode)
+	l.decodes <- struct{}{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = l.Save(ctx, data)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "image is not decoded while other one is")
+
+	<-l.decodes
+	_, err = l.Save(context.Background(), data)
+	assert.NoError(t, err)
+	assert.Empty(t, l.decodes, "slot is released after save")
+}
+
+func TestThumbnail(t *testing.T) {
+	// black and white stripes, one pixel wide, average to grey
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x += 2 {
+		for y := 0; y < 2; y++ {
+			src.Set(x, y, color.White)
+			src.Set(x+1, y, color.Black)
+		}
+	}
+
+	thumb := thumbnail(src, 2)
+
+	assert.Equal(t, image.Pt(2, 1), thumb.Bounds().Size())
+	assert.Equal(t, color.RGBA{R: 127, G: 127, B: 127, A: 255}, thumb.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{R: 127, G: 127, B: 127, A: 255}, thumb.RGBAAt(1, 0))
+	assert.Same(t, src, thumbnail(src, 4), "image which fits isn't scaled")
+}
+
+func TestContentType(t *testing.T) {
+	assert.Equal(t, "image/gif", ContentType("image/gif", SizeOriginal))
+	assert.Equal(t, "image/png", ContentType("image/gif", SizeSmall))
+	assert.Equal(t, "image/jpeg", ContentType("image/jpeg", SizeLarge))
+}
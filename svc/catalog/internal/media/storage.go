@@ -0,0 +1,116 @@
+// Package media stores images of items and their thumbnails in blob storage
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// ErrBlobNotFound is returned when there is no blob with requested key
+var ErrBlobNotFound = errors.New("blob not found")
+
+// keyPattern matches keys of blobs, which are slash separated paths without empty, "." or ".." segments
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*(/[A-Za-z0-9_-][A-Za-z0-9._-]*)*$`)
+
+// Storage keeps blobs under keys, e.g. in local filesystem or object storage
+type Storage interface {
+	// Put stores content of r under key, replacing the blob stored there before
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open returns reader of blob with key, ErrBlobNotFound is returned when there is no such blob
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes blob with key, it is not an error when there is no such blob
+	Delete(ctx context.Context, key string) error
+}
+
+var _ Storage = (*FileStorage)(nil)
+
+// FileStorage keeps blobs as files in a local directory, keys are paths relative to it
+type FileStorage struct {
+	dir string
+}
+
+// NewFileStorage creates FileStorage keeping blobs in dir, which is created when it doesn't exist
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error while creating media directory: %w", err)
+	}
+	return &FileStorage{dir: dir}, nil
+}
+
+// Put writes content of r to a temporary file first, so readers never see partially written blob
+func (s *FileStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error while storing blob %s: %w", key, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error while storing blob %s: %w", key, err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("error while storing blob %s: %w", key, err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("error while storing blob %s: %w", key, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error while storing blob %s: %w", key, err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("error while storing blob %s: %w", key, err)
+	}
+	return nil
+}
+
+// Open returns the file of blob with key
+func (s *FileStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while opening blob %s: %w", key, err)
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("error while opening blob %s: %w", key, ErrBlobNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error while opening blob %s: %w", key, err)
+	}
+	return f, nil
+}
+
+// Delete removes the file of blob with key
+func (s *FileStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error while deleting blob %s: %w", key, err)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error while deleting blob %s: %w", key, err)
+	}
+	return nil
+}
+
+// path returns path of the file of blob with key, key can't point outside of the storage directory
+func (s *FileStorage) path(key string) (string, error) {
+	if !keyPattern.MatchString(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,51 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileStorage(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "media")
+	s, err := NewFileStorage(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(ctx, "images/ab/abc/original", strings.NewReader("first")))
+	require.NoError(t, s.Put(ctx, "images/ab/abc/original", strings.NewReader("second")), "blob is replaced")
+
+	r, err := s.Open(ctx, "images/ab/abc/original")
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "second", string(content))
+	entries, err := os.ReadDir(filepath.Join(dir, "images", "ab", "abc"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files are removed")
+
+	require.NoError(t, s.Delete(ctx, "images/ab/abc/original"))
+	require.NoError(t, s.Delete(ctx, "images/ab/abc/original"), "missing blob is not an error")
+	_, err = s.Open(ctx, "images/ab/abc/original")
+	assert.True(t, errors.Is(err, ErrBlobNotFound))
+}
+
+func TestFileStorage_invalidKey(t *testing.T) {
+	s, err := NewFileStorage(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../secret", "images/../../secret", "/etc/passwd", "images//original",
+		"images/.hidden"} {
+		assert.Error(t, s.Put(context.Background(), key, strings.NewReader("x")), key)
+		_, err := s.Open(context.Background(), key)
+		assert.Error(t, err, key)
+		assert.Error(t, s.Delete(context.Background(), key), key)
+	}
+}
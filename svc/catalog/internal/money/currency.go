@@ -0,0 +1,107 @@
+package money
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidCurrency = errors.New("invalid currency")
+
+// iso4217 is a table of active ISO 4217 currencies with code, numeric code, exponent and name columns
+//
+//go:embed iso4217.csv
+var iso4217 string
+
+// currencies indexes ISO 4217 table by currency code
+var currencies = mustLoadCurrencies(iso4217)
+
+// Currency is an ISO 4217 currency
+type Currency struct {
+	Code    string
+	Numeric string
+	// Exponent is number of digits after the decimal separator, e.g. 0 for JPY and 2 for EUR
+	Exponent int
+	Name     string
+}
+
+// LookupCurrency returns ISO 4217 currency with provided code. Code is case insensitive.
+func LookupCurrency(code string) (Currency, bool) {
+	currency, ok := currencies[strings.ToUpper(code)]
+	return currency, ok
+}
+
+// CurrencySet is a set of currencies the shop sells in
+type CurrencySet struct {
+	currencies map[string]Currency
+}
+
+// NewCurrencySet creates set of currencies with provided codes, or of all ISO 4217 currencies when none are provided
+func NewCurrencySet(codes ...string) (*CurrencySet, error) {
+	if len(codes) == 0 {
+		return &CurrencySet{currencies: currencies}, nil
+	}
+	set := &CurrencySet{currencies: make(map[string]Currency, len(codes))}
+	for _, code := range codes {
+		currency, ok := LookupCurrency(strings.TrimSpace(code))
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not an ISO 4217 currency code", ErrInvalidCurrency, code)
+		}
+		set.currencies[currency.Code] = currency
+	}
+	return set, nil
+}
+
+// Normalize returns upper-case code of the currency. ErrInvalidCurrency is returned for codes which are not ISO 4217
+// currencies or which are not in the set.
+func (s *CurrencySet) Normalize(code string) (string, error) {
+	currency, ok := LookupCurrency(code)
+	if !ok {
+		return "", fmt.Errorf("%w: %q is not an ISO 4217 currency code", ErrInvalidCurrency, code)
+	}
+	if _, ok := s.currencies[currency.Code]; !ok {
+		return "", fmt.Errorf("%w: %s is not accepted, use one of %s", ErrInvalidCurrency, currency.Code,
+			strings.Join(s.codes(), ", "))
+	}
+	return currency.Code, nil
+}
+
+// List returns currencies in the set ordered by code
+func (s *CurrencySet) List() []Currency {
+	list := make([]Currency, 0, len(s.currencies))
+	for _, code := range s.codes() {
+		list = append(list, s.currencies[code])
+	}
+	return list
+}
+
+// codes returns sorted codes of currencies in the set
+func (s *CurrencySet) codes() []string {
+	codes := make([]string, 0, len(s.currencies))
+	for code := range s.currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// mustLoadCurrencies parses ISO 4217 table, it panics when the table is malformed
+func mustLoadCurrencies(table string) map[string]Currency {
+	records, err := csv.NewReader(strings.NewReader(table)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("error while reading ISO 4217 table: %s", err))
+	}
+	loaded := make(map[string]Currency, len(records))
+	for _, record := range records[1:] {
+		exp, err := strconv.Atoi(record[2])
+		if err != nil {
+			panic(fmt.Sprintf("error while reading exponent of %s: %s", record[0], err))
+		}
+		loaded[record[0]] = Currency{Code: record[0], Numeric: record[1], Exponent: exp, Name: record[3]}
+	}
+	return loaded
+}
@@ -0,0 +1,55 @@
+package money
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLookupCurrency(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected Currency
+		found    bool
+	}{
+		{code: "EUR", expected: Currency{Code: "EUR", Numeric: "978", Exponent: 2, Name: "Euro"}, found: true},
+		{code: "jpy", expected: Currency{Code: "JPY", Numeric: "392", Exponent: 0, Name: "Yen"}, found: true},
+		{code: "BHD", expected: Currency{Code: "BHD", Numeric: "048", Exponent: 3, Name: "Bahraini Dinar"}, found: true},
+		{code: "banana"},
+		{code: ""},
+	}
+	for _, test := range tests {
+		currency, ok := LookupCurrency(test.code)
+		assert.Equal(t, test.found, ok, test.code)
+		assert.Equal(t, test.expected, currency, test.code)
+	}
+}
+
+func TestCurrencySet(t *testing.T) {
+	all, err := NewCurrencySet()
+	require.NoError(t, err)
+	assert.Len(t, all.List(), len(currencies))
+
+	_, err = NewCurrencySet("EUR", "XYZ")
+	assert.ErrorIs(t, err, ErrInvalidCurrency)
+
+	set, err := NewCurrencySet("usd", " EUR")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"EUR", "USD"}, set.codes())
+
+	tests := []struct {
+		code        string
+		expected    string
+		expectedErr error
+	}{
+		{code: "eur", expected: "EUR"},
+		{code: "USD", expected: "USD"},
+		{code: "GBP", expectedErr: ErrInvalidCurrency},
+		{code: "banana", expectedErr: ErrInvalidCurrency},
+	}
+	for _, test := range tests {
+		code, err := set.Normalize(test.code)
+		assert.ErrorIs(t, err, test.expectedErr, test.code)
+		assert.Equal(t, test.expected, code, test.code)
+	}
+}
@@ -0,0 +1,90 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultExponent is number of minor unit digits assumed for codes which are not ISO 4217 currencies
+const defaultExponent = 2
+
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// decimalRe matches plain decimal numbers, exponent notation and redundant leading zeros are not accepted
+var decimalRe = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?$`)
+
+// Amount is an exact amount of money expressed in minor units of its currency, e.g. cents for EUR
+type Amount struct {
+	Minor    int64
+	Currency string
+}
+
+// Exponent returns number of digits after the decimal separator used by currency, e.g. 0 for JPY and 2 for EUR
+func Exponent(currency string) int {
+	if c, ok := LookupCurrency(currency); ok {
+		return c.Exponent
+	}
+	return defaultExponent
+}
+
+// Parse converts decimal string in major units of the currency, e.g. "12.50", into Amount. Values with more
+// fractional digits than the currency allows are rejected instead of being rounded.
+func Parse(value, currency string) (Amount, error) {
+	if !decimalRe.MatchString(value) {
+		return Amount{}, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidAmount, value)
+	}
+	exp := Exponent(currency)
+	whole, fraction, _ := strings.Cut(value, ".")
+	if len(fraction) > exp {
+		return Amount{}, fmt.Errorf("%w: %s allows at most %d fractional digits", ErrInvalidAmount, currency, exp)
+	}
+	minor, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", exp-len(fraction)), 10, 64)
+	if err != nil {
+		return Amount{}, fmt.Errorf("%w: %q is out of range", ErrInvalidAmount, value)
+	}
+	return Amount{Minor: minor, Currency: currency}, nil
+}
+
+// ParseDecimal converts decimal string into exact rational number
+func ParseDecimal(value string) (*big.Rat, error) {
+	if !decimalRe.MatchString(value) {
+		return nil, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidAmount, value)
+	}
+	r, _ := new(big.Rat).SetString(value)
+	return r, nil
+}
+
+// String formats amount as decimal string with exactly as many fractional digits as the currency uses
+func (a Amount) String() string {
+	return a.Rat().FloatString(Exponent(a.Currency))
+}
+
+// Rat returns amount in major units of the currency as exact rational number
+func (a Amount) Rat() *big.Rat {
+	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(Exponent(a.Currency))), nil)
+	return new(big.Rat).SetFrac(big.NewInt(a.Minor), denom)
+}
+
+// ExponentSQL returns SQL expression evaluating to exponent of currency kept in column
+func ExponentSQL(column string) string {
+	codes := make([]string, 0, len(currencies))
+	for code, currency := range currencies {
+		if currency.Exponent != defaultExponent {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CASE UPPER(%s)", column)
+	for _, code := range codes {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", code, currencies[code].Exponent)
+	}
+	fmt.Fprintf(&b, " ELSE %d END", defaultExponent)
+	return b.String()
+}
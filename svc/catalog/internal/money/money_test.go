@@ -0,0 +1,69 @@
+package money
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value       string
+		currency    string
+		expected    Amount
+		expectedErr error
+	}{
+		{value: "12.50", currency: "EUR", expected: Amount{Minor: 1250, Currency: "EUR"}},
+		{value: "12.5", currency: "EUR", expected: Amount{Minor: 1250, Currency: "EUR"}},
+		{value: "0.1", currency: "usd", expected: Amount{Minor: 10, Currency: "usd"}},
+		{value: "1500", currency: "JPY", expected: Amount{Minor: 1500, Currency: "JPY"}},
+		{value: "1.234", currency: "BHD", expected: Amount{Minor: 1234, Currency: "BHD"}},
+		{value: "-3", currency: "EUR", expected: Amount{Minor: -300, Currency: "EUR"}},
+		{value: "1500.5", currency: "JPY", expectedErr: ErrInvalidAmount},
+		{value: "12.505", currency: "EUR", expectedErr: ErrInvalidAmount},
+		{value: "1e3", currency: "EUR", expectedErr: ErrInvalidAmount},
+		{value: "012", currency: "EUR", expectedErr: ErrInvalidAmount},
+		{value: ".5", currency: "EUR", expectedErr: ErrInvalidAmount},
+		{value: "", currency: "EUR", expectedErr: ErrInvalidAmount},
+		{value: "99999999999999999999", currency: "EUR", expectedErr: ErrInvalidAmount},
+	}
+	for _, test := range tests {
+		t.Run(test.value+test.currency, func(t *testing.T) {
+			amount, err := Parse(test.value, test.currency)
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Equal(t, test.expected, amount)
+		})
+	}
+}
+
+func TestAmount_String(t *testing.T) {
+	tests := []struct {
+		amount   Amount
+		expected string
+	}{
+		{amount: Amount{Minor: 1250, Currency: "EUR"}, expected: "12.50"},
+		{amount: Amount{Minor: 5, Currency: "EUR"}, expected: "0.05"},
+		{amount: Amount{Minor: -5, Currency: "EUR"}, expected: "-0.05"},
+		{amount: Amount{Minor: 1500, Currency: "JPY"}, expected: "1500"},
+		{amount: Amount{Minor: 1234, Currency: "BHD"}, expected: "1.234"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.amount.String())
+	}
+}
+
+func TestAmount_Rat(t *testing.T) {
+	a, _ := Parse("0.1", "EUR")
+	b, _ := Parse("0.2", "EUR")
+	c, _ := ParseDecimal("0.3")
+
+	assert.Zero(t, new(big.Rat).Add(a.Rat(), b.Rat()).Cmp(c))
+}
+
+func TestExponentSQL(t *testing.T) {
+	sql := ExponentSQL("price_code")
+
+	assert.True(t, strings.HasPrefix(sql, "CASE UPPER(price_code) WHEN 'BHD' THEN 3 WHEN 'BIF' THEN 0"))
+	assert.True(t, strings.HasSuffix(sql, "WHEN 'XPF' THEN 0 ELSE 2 END"))
+}
@@ -0,0 +1,184 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateDigits is number of fractional digits exchange rates are formatted with
+const rateDigits = 10
+
+var (
+	ErrInvalidRates = errors.New("invalid exchange rates")
+	ErrNoRate       = errors.New("no exchange rate")
+)
+
+// Rates is a snapshot of exchange rates. One unit of Base currency is worth Rates[code] units of currency with code.
+type Rates struct {
+	Base  string
+	Rates map[string]*big.Rat
+	// Source tells where rates come from, e.g. file they were loaded from
+	Source    string
+	UpdatedAt time.Time
+}
+
+// ParseRates creates Rates from decimal strings keyed by currency code. Codes are normalized to upper case.
+func ParseRates(base string, rates map[string]string, source string, updatedAt time.Time) (Rates, error) {
+	baseCurrency, ok := LookupCurrency(base)
+	if !ok {
+		return Rates{}, fmt.Errorf("%w: base %q is not an ISO 4217 currency code", ErrInvalidRates, base)
+	}
+	parsed := Rates{Base: baseCurrency.Code, Rates: make(map[string]*big.Rat, len(rates)), Source: source,
+		UpdatedAt: updatedAt}
+	for code, value := range rates {
+		currency, ok := LookupCurrency(code)
+		if !ok {
+			return Rates{}, fmt.Errorf("%w: %q is not an ISO 4217 currency code", ErrInvalidRates, code)
+		}
+		if _, ok := parsed.Rates[currency.Code]; ok {
+			return Rates{}, fmt.Errorf("%w: rate of %s is repeated", ErrInvalidRates, currency.Code)
+		}
+		rate, err := ParseDecimal(value)
+		if err != nil || rate.Sign() <= 0 {
+			return Rates{}, fmt.Errorf("%w: rate of %s should be positive decimal number", ErrInvalidRates, currency.Code)
+		}
+		parsed.Rates[currency.Code] = rate
+	}
+	return parsed, nil
+}
+
+// LoadRates reads rates from JSON file, e.g. {"base": "EUR", "updatedAt": "2022-06-01T16:00:00Z", "rates": {"USD": "1.07"}}.
+// Modification time of the file is used when updatedAt is not set.
+func LoadRates(path string) (Rates, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Rates{}, fmt.Errorf("error while opening exchange rates file: %w", err)
+	}
+	defer f.Close()
+
+	var file struct {
+		Base      string            `json:"base"`
+		UpdatedAt time.Time         `json:"updatedAt"`
+		Rates     map[string]string `json:"rates"`
+	}
+	if err := json.NewDecoder(f).Decode(&file); err != nil {
+		return Rates{}, fmt.Errorf("error while decoding exchange rates file: %w", err)
+	}
+	if file.UpdatedAt.IsZero() {
+		info, err := f.Stat()
+		if err != nil {
+			return Rates{}, fmt.Errorf("error while reading exchange rates file info: %w", err)
+		}
+		file.UpdatedAt = info.ModTime()
+	}
+	return ParseRates(file.Base, file.Rates, "file:"+path, file.UpdatedAt.UTC())
+}
+
+// Rate returns how many units of currency to are worth one unit of currency from
+func (r Rates) Rate(from, to string) (*big.Rat, error) {
+	fromRate, ok := r.rate(from)
+	if !ok {
+		return nil, fmt.Errorf("%w: from %s", ErrNoRate, from)
+	}
+	toRate, ok := r.rate(to)
+	if !ok {
+		return nil, fmt.Errorf("%w: to %s", ErrNoRate, to)
+	}
+	return new(big.Rat).Quo(toRate, fromRate), nil
+}
+
+// Convert converts amount into currency to, rounding half away from zero to its minor units. Applied rate is returned
+// together with converted amount.
+func (r Rates) Convert(amount Amount, to string) (Amount, *big.Rat, error) {
+	to = strings.ToUpper(to)
+	rate, err := r.Rate(amount.Currency, to)
+	if err != nil {
+		return Amount{}, nil, err
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(Exponent(to))), nil)
+	converted := new(big.Rat).Mul(amount.Rat(), rate)
+	minor := roundHalfAwayFromZero(converted.Mul(converted, new(big.Rat).SetInt(scale)))
+	if !minor.IsInt64() {
+		return Amount{}, nil, fmt.Errorf("%w: converted amount is out of range", ErrInvalidAmount)
+	}
+	return Amount{Minor: minor.Int64(), Currency: to}, rate, nil
+}
+
+// rate returns rate of currency with code against the base currency
+func (r Rates) rate(code string) (*big.Rat, bool) {
+	code = strings.ToUpper(code)
+	if r.Base != "" && code == r.Base {
+		return big.NewRat(1, 1), true
+	}
+	rate, ok := r.Rates[code]
+	return rate, ok
+}
+
+// Strings formats rates as exact decimal strings keyed by currency code, the way ParseRates accepts them
+func (r Rates) Strings() map[string]string {
+	formatted := make(map[string]string, len(r.Rates))
+	for code, rate := range r.Rates {
+		formatted[code] = formatExact(rate)
+	}
+	return formatted
+}
+
+// formatExact formats rate as decimal string with as many fractional digits as it takes to represent it exactly.
+// Rates which have no finite decimal representation are rounded to as many digits as their denominator has bits.
+func formatExact(rate *big.Rat) string {
+	digits := 0
+	ten := big.NewRat(10, 1)
+	for scaled := new(big.Rat).Set(rate); !scaled.IsInt() && digits < rate.Denom().BitLen(); digits++ {
+		scaled.Mul(scaled, ten)
+	}
+	return rate.FloatString(digits)
+}
+
+// FormatRate formats exchange rate as decimal string without trailing zeros
+func FormatRate(rate *big.Rat) string {
+	formatted := rate.FloatString(rateDigits)
+	return strings.TrimSuffix(strings.TrimRight(formatted, "0"), ".")
+}
+
+// roundHalfAwayFromZero rounds r to the nearest integer, halves are rounded away from zero
+func roundHalfAwayFromZero(r *big.Rat) *big.Int {
+	quo, rem := new(big.Int).QuoRem(new(big.Int).Abs(r.Num()), r.Denom(), new(big.Int))
+	if rem.Lsh(rem, 1).Cmp(r.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return quo
+}
+
+// Exchange holds current exchange rates, it is safe for concurrent use
+type Exchange struct {
+	mu    sync.RWMutex
+	rates Rates
+}
+
+// NewExchange creates Exchange with provided rates
+func NewExchange(rates Rates) *Exchange {
+	return &Exchange{rates: rates}
+}
+
+// Rates returns current exchange rates
+func (e *Exchange) Rates() Rates {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.rates
+}
+
+// SetRates replaces current exchange rates
+func (e *Exchange) SetRates(rates Rates) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rates = rates
+}
@@ -0,0 +1,124 @@
+package money
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseRates(t *testing.T) {
+	tests := []struct {
+		name        string
+		base        string
+		rates       map[string]string
+		expectedErr error
+	}{
+		{name: "Valid rates", base: "eur", rates: map[string]string{"usd": "1.07", "JPY": "140"}},
+		{name: "Unknown base", base: "XYZ", rates: map[string]string{"USD": "1.07"}, expectedErr: ErrInvalidRates},
+		{name: "Unknown currency", base: "EUR", rates: map[string]string{"XYZ": "1"}, expectedErr: ErrInvalidRates},
+		{name: "Zero rate", base: "EUR", rates: map[string]string{"USD": "0"}, expectedErr: ErrInvalidRates},
+		{name: "Negative rate", base: "EUR", rates: map[string]string{"USD": "-1.07"}, expectedErr: ErrInvalidRates},
+		{name: "Not a number", base: "EUR", rates: map[string]string{"USD": "1,07"}, expectedErr: ErrInvalidRates},
+		{name: "Repeated currency", base: "EUR", rates: map[string]string{"USD": "1", "usd": "1"}, expectedErr: ErrInvalidRates},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rates, err := ParseRates(test.base, test.rates, "test", time.Time{})
+			assert.ErrorIs(t, err, test.expectedErr)
+			if test.expectedErr == nil {
+				assert.Equal(t, "EUR", rates.Base)
+				assert.Equal(t, "1.07", FormatRate(rates.Rates["USD"]))
+				assert.Equal(t, "140", FormatRate(rates.Rates["JPY"]))
+			}
+		})
+	}
+}
+
+func TestRates_Convert(t *testing.T) {
+	rates, err := ParseRates("EUR", map[string]string{"USD": "1.07", "JPY": "140.5", "PLN": "4.6"}, "test", time.Time{})
+	require.NoError(t, err)
+	tests := []struct {
+		amount       Amount
+		to           string
+		expected     Amount
+		expectedRate string
+		expectedErr  error
+	}{
+		{amount: Amount{Minor: 1000, Currency: "EUR"}, to: "usd", expected: Amount{Minor: 1070, Currency: "USD"}, expectedRate: "1.07"},
+		{amount: Amount{Minor: 1250, Currency: "EUR"}, to: "JPY", expected: Amount{Minor: 1756, Currency: "JPY"}, expectedRate: "140.5"},
+		{amount: Amount{Minor: 1070, Currency: "USD"}, to: "EUR", expected: Amount{Minor: 1000, Currency: "EUR"}, expectedRate: "0.9345794393"},
+		{amount: Amount{Minor: 1000, Currency: "USD"}, to: "PLN", expected: Amount{Minor: 4299, Currency: "PLN"}, expectedRate: "4.2990654206"},
+		{amount: Amount{Minor: 1, Currency: "EUR"}, to: "JPY", expected: Amount{Minor: 1, Currency: "JPY"}, expectedRate: "140.5"},
+		{amount: Amount{Minor: -1000, Currency: "EUR"}, to: "USD", expected: Amount{Minor: -1070, Currency: "USD"}, expectedRate: "1.07"},
+		{amount: Amount{Minor: 1000, Currency: "EUR"}, to: "GBP", expectedErr: ErrNoRate},
+		{amount: Amount{Minor: 1000, Currency: "GBP"}, to: "EUR", expectedErr: ErrNoRate},
+	}
+	for _, test := range tests {
+		t.Run(test.amount.String()+test.amount.Currency+test.to, func(t *testing.T) {
+			converted, rate, err := rates.Convert(test.amount, test.to)
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Equal(t, test.expected, converted)
+			if test.expectedErr == nil {
+				assert.Equal(t, test.expectedRate, FormatRate(rate))
+			}
+		})
+	}
+}
+
+func TestRoundHalfAwayFromZero(t *testing.T) {
+	rates, err := ParseRates("EUR", map[string]string{"USD": "1.005"}, "test", time.Time{})
+	require.NoError(t, err)
+
+	up, _, err := rates.Convert(Amount{Minor: 100, Currency: "EUR"}, "USD")
+	require.NoError(t, err)
+	down, _, err := rates.Convert(Amount{Minor: -100, Currency: "EUR"}, "USD")
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(101), up.Minor)
+	assert.Equal(t, int64(-101), down.Minor)
+}
+
+func TestLoadRates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	require.NoError(t, os.WriteFile(path,
+		[]byte(`{"base": "EUR", "updatedAt": "2022-06-01T16:00:00Z", "rates": {"USD": "1.07"}}`), 0o600))
+
+	rates, err := LoadRates(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", rates.Base)
+	assert.Equal(t, "file:"+path, rates.Source)
+	assert.Equal(t, time.Date(2022, 6, 1, 16, 0, 0, 0, time.UTC), rates.UpdatedAt)
+	assert.Equal(t, "1.07", FormatRate(rates.Rates["USD"]))
+}
+
+func TestLoadRates_missingFile(t *testing.T) {
+	_, err := LoadRates(filepath.Join(t.TempDir(), "missing.json"))
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestExchange(t *testing.T) {
+	exchange := NewExchange(Rates{})
+	rates, err := ParseRates("EUR", map[string]string{"USD": "1.07"}, "admin", time.Time{})
+	require.NoError(t, err)
+
+	exchange.SetRates(rates)
+
+	assert.Equal(t, rates, exchange.Rates())
+}
+
+func TestRates_Strings(t *testing.T) {
+	values := map[string]string{"USD": "1.07", "JPY": "140", "PLN": "4.600000000000001", "CHF": "0.000000000001"}
+	rates, err := ParseRates("EUR", values, "test", time.Time{})
+	require.NoError(t, err)
+
+	assert.Equal(t, values, rates.Strings())
+
+	rates.Rates["USD"] = big.NewRat(1, 3)
+	assert.Equal(t, "0.33", rates.Strings()["USD"])
+}
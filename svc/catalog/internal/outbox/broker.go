@@ -0,0 +1,87 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"sync"
+)
+
+var ErrBrokerClosed = errors.New("event broker is closed")
+
+// Broker is a publisher fanning events out to subscribers in the same process, e.g. open event streams. Publishing
+// never blocks on a subscriber: one which doesn't keep up with its buffer is dropped, so it can resubscribe and catch
+// up from the store.
+type Broker struct {
+	mu            sync.Mutex
+	subscriptions map[*Subscription]struct{}
+	closed        bool
+}
+
+// Subscription receives events published after it was created, until it is closed or dropped by the broker
+type Subscription struct {
+	broker *Broker
+	events chan store.Event
+}
+
+// NewBroker creates Broker without any subscriptions
+func NewBroker() *Broker {
+	return &Broker{subscriptions: map[*Subscription]struct{}{}}
+}
+
+// Subscribe creates subscription buffering up to buffer events not yet received
+func (b *Broker) Subscribe(buffer int) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrBrokerClosed
+	}
+	sub := &Subscription{broker: b, events: make(chan store.Event, buffer)}
+	b.subscriptions[sub] = struct{}{}
+	return sub, nil
+}
+
+// Publish passes event to every subscription, dropping the ones with full buffer
+func (b *Broker) Publish(_ context.Context, event store.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscriptions {
+		select {
+		case sub.events <- event:
+		default:
+			b.drop(sub)
+		}
+	}
+	return nil
+}
+
+// Close drops all subscriptions and rejects new ones, so open event streams end e.g. on shutdown
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subscriptions {
+		b.drop(sub)
+	}
+}
+
+// drop removes subscription and closes its channel. Caller has to hold the lock.
+func (b *Broker) drop(sub *Subscription) {
+	if _, ok := b.subscriptions[sub]; !ok {
+		return
+	}
+	delete(b.subscriptions, sub)
+	close(sub.events)
+}
+
+// Events returns channel of subscribed events, which is closed once subscription is closed or dropped
+func (s *Subscription) Events() <-chan store.Event {
+	return s.events
+}
+
+// Close stops subscription, it is safe to close subscription which was already dropped
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.drop(s)
+}
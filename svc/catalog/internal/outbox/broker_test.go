@@ -0,0 +1,59 @@
+package outbox
+
+import (
+	"context"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// received drains events buffered by subscription and reports if it is still open
+func received(sub *Subscription) (ids []uint64, open bool) {
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return ids, false
+			}
+			ids = append(ids, event.ID)
+		default:
+			return ids, true
+		}
+	}
+}
+
+func TestBroker(t *testing.T) {
+	ctx := context.Background()
+	broker := NewBroker()
+	fast, err := broker.Subscribe(2)
+	require.NoError(t, err)
+	slow, err := broker.Subscribe(1)
+	require.NoError(t, err)
+
+	require.NoError(t, broker.Publish(ctx, testEvent(1)))
+	require.NoError(t, broker.Publish(ctx, testEvent(2)))
+
+	ids, open := received(fast)
+	assert.Equal(t, []uint64{1, 2}, ids)
+	assert.True(t, open)
+	ids, open = received(slow)
+	assert.Equal(t, []uint64{1}, ids)
+	assert.False(t, open, "subscription with full buffer is dropped")
+
+	closed, err := broker.Subscribe(1)
+	require.NoError(t, err)
+	closed.Close()
+	closed.Close()
+	require.NoError(t, broker.Publish(ctx, testEvent(3)))
+	_, open = received(closed)
+	assert.False(t, open)
+
+	broker.Close()
+	ids, open = received(fast)
+	assert.Equal(t, []uint64{3}, ids)
+	assert.False(t, open, "subscriptions are dropped on close")
+	_, err = broker.Subscribe(1)
+	assert.ErrorIs(t, err, ErrBrokerClosed)
+	assert.NoError(t, broker.Publish(ctx, store.Event{ID: 4}))
+}
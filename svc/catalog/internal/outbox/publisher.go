@@ -0,0 +1,113 @@
+// Package outbox publishes domain events relayed from the catalog store outbox to other services
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"io"
+	"os"
+	"sync"
+)
+
+// Publisher delivers events to other services. Events are delivered at least once, so consumers should ignore
+// events with IDs they have already seen.
+type Publisher interface {
+	Publish(ctx context.Context, event store.Event) error
+}
+
+// WriterPublisher publishes events as JSON lines written to underlying writer, e.g. a file or stdout
+type WriterPublisher struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewWriterPublisher creates WriterPublisher writing events to w
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{enc: json.NewEncoder(w)}
+}
+
+// NewFilePublisher creates WriterPublisher appending events to file at path. Returned file has to be closed once
+// publisher is no longer used.
+func NewFilePublisher(path string) (*WriterPublisher, *os.File, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error while opening events file: %w", err)
+	}
+	return NewWriterPublisher(f), f, nil
+}
+
+// Publish writes event as a single JSON line
+func (p *WriterPublisher) Publish(_ context.Context, event store.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.enc.Encode(event); err != nil {
+		return fmt.Errorf("error while writing event with id %d: %w", event.ID, err)
+	}
+	return nil
+}
+
+// MultiPublisher publishes every event with all publishers in order. When one of them fails, event is published
+// again with all of them, so each publisher has to tolerate duplicates.
+type MultiPublisher struct {
+	publishers []Publisher
+}
+
+// NewMultiPublisher creates MultiPublisher publishing with provided publishers
+func NewMultiPublisher(publishers ...Publisher) *MultiPublisher {
+	return &MultiPublisher{publishers: publishers}
+}
+
+// Publish publishes event with every publisher, stopping at the first one which fails
+func (p *MultiPublisher) Publish(ctx context.Context, event store.Event) error {
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MemoryPublisher keeps published events in memory and passes them to subscribers, meant for tests and consumers
+// running in the same process
+type MemoryPublisher struct {
+	mu          sync.RWMutex
+	events      []store.Event
+	subscribers []func(event store.Event)
+}
+
+// NewMemoryPublisher creates MemoryPublisher without any events
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publish stores event and passes it to every subscriber
+func (p *MemoryPublisher) Publish(ctx context.Context, event store.Event) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error while publishing event with id %d: %w", event.ID, err)
+	}
+	p.mu.Lock()
+	p.events = append(p.events, event)
+	subscribers := p.subscribers
+	p.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber(event)
+	}
+	return nil
+}
+
+// Subscribe registers fn to be called with every event published from now on
+func (p *MemoryPublisher) Subscribe(fn func(event store.Event)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subscribers = append(p.subscribers, fn)
+}
+
+// Events returns all published events in order of publishing
+func (p *MemoryPublisher) Events() []store.Event {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]store.Event(nil), p.events...)
+}
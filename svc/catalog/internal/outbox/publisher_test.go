@@ -0,0 +1,86 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEvent(id uint64) store.Event {
+	name := "Shirt"
+	return store.Event{
+		ID:         id,
+		Type:       store.ItemCreated,
+		ItemID:     1,
+		Version:    1,
+		Actor:      "alice",
+		Item:       store.ItemSnapshot{Name: &name},
+		OccurredAt: time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWriterPublisher(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := NewWriterPublisher(&buf)
+
+	require.NoError(t, publisher.Publish(context.Background(), testEvent(1)))
+	require.NoError(t, publisher.Publish(context.Background(), testEvent(2)))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"id":1,"type":"ItemCreated","itemId":1,"version":1,"actor":"alice",`+
+		`"item":{"name":"Shirt"},"occurredAt":"2022-06-01T12:00:00Z"}`, lines[0])
+	var event store.Event
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(t, testEvent(2), event)
+}
+
+func TestFilePublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	for id := uint64(1); id <= 2; id++ {
+		publisher, f, err := NewFilePublisher(path)
+		require.NoError(t, err)
+		require.NoError(t, publisher.Publish(context.Background(), testEvent(id)))
+		require.NoError(t, f.Close())
+	}
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(content), "\n"), "events are appended to the file")
+}
+
+func TestMultiPublisher(t *testing.T) {
+	first, second := NewMemoryPublisher(), NewMemoryPublisher()
+	publisher := NewMultiPublisher(first, second)
+
+	require.NoError(t, publisher.Publish(context.Background(), testEvent(1)))
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, publisher.Publish(canceled, testEvent(2)), context.Canceled)
+
+	assert.Equal(t, []store.Event{testEvent(1)}, first.Events())
+	assert.Equal(t, []store.Event{testEvent(1)}, second.Events())
+}
+
+func TestMemoryPublisher(t *testing.T) {
+	publisher := NewMemoryPublisher()
+	var received []uint64
+	publisher.Subscribe(func(event store.Event) { received = append(received, event.ID) })
+
+	require.NoError(t, publisher.Publish(context.Background(), testEvent(1)))
+	require.NoError(t, publisher.Publish(context.Background(), testEvent(2)))
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, publisher.Publish(canceled, testEvent(3)), context.Canceled)
+
+	assert.Equal(t, []store.Event{testEvent(1), testEvent(2)}, publisher.Events())
+	assert.Equal(t, []uint64{1, 2}, received)
+}
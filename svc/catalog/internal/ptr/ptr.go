@@ -0,0 +1,10 @@
+// Package ptr helps with optional values, e.g. fields of requests which are not set
+package ptr
+
+// ValueOr returns value p points to, or fallback when p is nil
+func ValueOr[V any](p *V, fallback V) V {
+	if p == nil {
+		return fallback
+	}
+	return *p
+}
@@ -0,0 +1,12 @@
+package ptr
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValueOr(t *testing.T) {
+	page := 3
+	assert.Equal(t, 3, ValueOr(&page, 1))
+	assert.Equal(t, 1, ValueOr(nil, 1))
+}
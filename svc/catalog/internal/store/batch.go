@@ -0,0 +1,187 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidOperation = errors.New("invalid item operation")
+
+// OperationType is a kind of change made by the item operation
+type OperationType string
+
+const (
+	OperationCreate OperationType = "create"
+	OperationUpdate OperationType = "update"
+	OperationDelete OperationType = "delete"
+)
+
+// ItemOperation is a single change of the batch. ID is required by updates and deletes, Item by creates and updates.
+// When IfVersion is not zero, item is changed only if its current version matches.
+type ItemOperation struct {
+	Type      OperationType
+	ID        uint
+	Item      Item
+	IfVersion uint
+}
+
+// validate checks if operation has known type, ID of the changed item and valid item
+func (op ItemOperation) validate() error {
+	switch {
+	case op.Type != OperationCreate && op.Type != OperationUpdate && op.Type != OperationDelete:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidOperation, op.Type)
+	case op.Type != OperationCreate && op.ID == 0:
+		return fmt.Errorf("%w: id is required by %s", ErrInvalidOperation, op.Type)
+	case op.Type == OperationCreate:
+		return op.Item.validateNew()
+	}
+	return op.Item.validate()
+}
+
+// OperationError is returned when operation at Index failed, so none of the batch operations were applied
+type OperationError struct {
+	Index int
+	Err   error
+}
+
+func (e OperationError) Error() string {
+	return fmt.Sprintf("error in operation %d: %s", e.Index, e.Err)
+}
+
+func (e OperationError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyItemOperations applies operations in order within a single transaction and returns state of every item right
+// after its operation. When any operation fails, none are applied and OperationError is returned.
+func (s *CatalogStore) ApplyItemOperations(ctx context.Context, ops []ItemOperation) ([]Item, error) {
+	for i, op := range ops {
+		if err := op.validate(); err != nil {
+			return nil, OperationError{Index: i, Err: err}
+		}
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	items := make([]Item, 0, len(ops))
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for i, op := range ops {
+			item, err := s.applyItemOperation(tx, op)
+			if err != nil {
+				return OperationError{Index: i, Err: err}
+			}
+			items = append(items, item)
+		}
+		return nil
+	})
+	var opErr OperationError
+	if errors.As(err, &opErr) {
+		return nil, opErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error while applying item operations: %w", err)
+	}
+	return items, nil
+}
+
+// applyItemOperation applies validated operation within transaction tx
+func (s *CatalogStore) applyItemOperation(tx *gorm.DB, op ItemOperation) (Item, error) {
+	switch op.Type {
+	case OperationCreate:
+		return insertItem(tx, op.Item)
+	case OperationUpdate:
+		return s.updateItem(tx, op.ID, op.Item, op.IfVersion)
+	default:
+		return s.deleteItem(tx, op.ID, op.IfVersion)
+	}
+}
+
+// ApplyItemOperations applies operations in order atomically and returns state of every item right after its
+// operation. When any operation fails, changes of the previous ones are rolled back and OperationError is returned.
+func (s *MemoryStore) ApplyItemOperations(ctx context.Context, ops []ItemOperation) ([]Item, error) {
+	for i, op := range ops {
+		if err := op.validate(); err != nil {
+			return nil, OperationError{Index: i, Err: err}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while applying item operations: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sp := s.itemSavepoint()
+	items := make([]Item, 0, len(ops))
+	for i, op := range ops {
+		sp.save(s, op.ID)
+		item, err := s.applyItemOperation(ctx, op)
+		if err != nil {
+			s.rollbackItems(sp)
+			return nil, OperationError{Index: i, Err: err}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// applyItemOperation applies validated operation. Caller has to hold the lock.
+func (s *MemoryStore) applyItemOperation(ctx context.Context, op ItemOperation) (Item, error) {
+	switch op.Type {
+	case OperationCreate:
+		return s.createItem(ctx, op.Item)
+	case OperationUpdate:
+		return s.updateItem(ctx, op.ID, op.Item, op.IfVersion)
+	default:
+		return s.deleteItem(ctx, op.ID, op.IfVersion)
+	}
+}
+
+// itemSavepoint is state of items which changes of the batch are rolled back to
+type itemSavepoint struct {
+	lastID        uint
+	lastHistoryID uint
+	lastEventID   uint64
+	events        int
+	// items holds state of existing items before the batch changed them, together with length of their history
+	items   map[uint]Item
+	history map[uint]int
+}
+
+// itemSavepoint returns savepoint of the current state of items. Caller has to hold the lock.
+func (s *MemoryStore) itemSavepoint() *itemSavepoint {
+	return &itemSavepoint{
+		lastID:        s.lastID,
+		lastHistoryID: s.lastHistoryID,
+		lastEventID:   s.lastEventID,
+		events:        len(s.events),
+		items:         map[uint]Item{},
+		history:       map[uint]int{},
+	}
+}
+
+// save keeps state of existing item with ID unless it was already saved
+func (sp *itemSavepoint) save(s *MemoryStore, id uint) {
+	item, ok := s.items[id]
+	if _, saved := sp.items[id]; !ok || saved || id > sp.lastID {
+		return
+	}
+	sp.items[id] = item
+	sp.history[id] = len(s.history[id])
+}
+
+// rollbackItems brings items back to the savepoint, removing the ones created since. Caller has to hold the lock.
+func (s *MemoryStore) rollbackItems(sp *itemSavepoint) {
+	for id := sp.lastID + 1; id <= s.lastID; id++ {
+		delete(s.items, id)
+		delete(s.history, id)
+	}
+	for id, item := range sp.items {
+		s.items[id] = item
+		s.history[id] = s.history[id][:sp.history[id]]
+	}
+	s.lastID = sp.lastID
+	s.lastHistoryID = sp.lastHistoryID
+	s.lastEventID = sp.lastEventID
+	s.events = s.events[:sp.events]
+}
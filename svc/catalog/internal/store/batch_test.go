@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestApplyItemOperations(t *testing.T) {
+	ops := []ItemOperation{
+		{Type: OperationCreate, Item: Item{Name: &itemName, Description: &itemDesc, Price: &itemPrice,
+			PriceCode: &itemPriceCode}},
+		{Type: OperationDelete, ID: itemID},
+	}
+	tests := []struct {
+		name      string
+		deleteErr error
+	}{
+		{
+			name: "All operations applied",
+		},
+		{
+			name:      "Failed operation rolls back the batch",
+			deleteErr: errors.New("some err"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(createItem).WithArgs(nil, itemName, itemDesc, itemPrice, itemPriceCode, itemVersion, nil).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(itemID))
+			expectRecordChange(mock, HistoryCreated, itemVersion, "system",
+				`{"name":"some name","description":"some desc","price":5000,"priceCode":"EUR"}`)
+			if test.deleteErr != nil {
+				mock.ExpectExec(deleteItem).WithArgs(sqlmock.AnyArg(), itemID).WillReturnError(test.deleteErr)
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectExec(deleteItem).WithArgs(sqlmock.AnyArg(), itemID).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`SELECT \* FROM "items" WHERE "items"\."id" = \$1 ORDER BY "items"\."id" LIMIT 1`).
+					WithArgs(itemID).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "version", "deleted_at"}).
+					AddRow(itemID, itemName, itemVersion, time.Now()))
+				expectRecordChange(mock, HistoryDeleted, itemVersion, "system", `{"name":"some name"}`)
+				mock.ExpectCommit()
+			}
+
+			items, err := store.ApplyItemOperations(context.Background(), ops)
+
+			if test.deleteErr != nil {
+				var opErr OperationError
+				require.ErrorAs(t, err, &opErr)
+				assert.Equal(t, 1, opErr.Index)
+				assert.ErrorIs(t, err, test.deleteErr)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, items, 2)
+				assert.Equal(t, itemID, items[0].ID)
+				assert.True(t, items[1].DeletedAt.Valid)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestApplyItemOperations_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		op   ItemOperation
+	}{
+		{
+			name: "Unknown type",
+			op:   ItemOperation{Type: "upsert", ID: itemID},
+		},
+		{
+			name: "Update without ID",
+			op:   ItemOperation{Type: OperationUpdate, Item: Item{Name: &itemName}},
+		},
+		{
+			name: "Create without required fields",
+			op:   ItemOperation{Type: OperationCreate, Item: Item{Name: &itemName}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+
+			_, err := store.ApplyItemOperations(context.Background(),
+				[]ItemOperation{{Type: OperationDelete, ID: itemID}, test.op})
+
+			var opErr OperationError
+			require.ErrorAs(t, err, &opErr)
+			assert.Equal(t, 1, opErr.Index)
+			assert.False(t, errors.Is(err, gorm.ErrRecordNotFound))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
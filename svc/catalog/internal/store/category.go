@@ -0,0 +1,537 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	maxCategoryNameLength = 250
+	// maxCategoryDepth bounds walks up the category tree, so they end even if parents form a cycle
+	maxCategoryDepth = 100
+	// categoryMoveLockID is a key of postgres advisory lock serializing moves of categories
+	categoryMoveLockID = 7403123
+)
+
+var (
+	ErrInvalidCategory     = errors.New("invalid category")
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+)
+
+// descendantCategories selects ID of the category and IDs of all categories below it in the tree. UNION drops
+// categories which were already visited, so the query ends even if parents form a cycle.
+const descendantCategories = `WITH RECURSIVE tree AS (
+	SELECT id FROM categories WHERE id = ?
+	UNION
+	SELECT categories.id FROM categories JOIN tree ON categories.parent_id = tree.id
+) SELECT id FROM tree`
+
+// categoryPath selects the category and at most maxCategoryDepth of its ancestors, root first
+const categoryPath = `WITH RECURSIVE path AS (
+	SELECT id, name, parent_id, 0 AS depth FROM categories WHERE id = ?
+	UNION ALL
+	SELECT categories.id, categories.name, categories.parent_id, path.depth + 1
+	FROM categories JOIN path ON categories.id = path.parent_id
+	WHERE path.depth < ?
+) SELECT id, name, parent_id FROM path ORDER BY depth DESC`
+
+// itemBreadcrumbs selects paths from the root to every category items are assigned to, at most maxCategoryDepth
+// categories long
+const itemBreadcrumbs = `WITH RECURSIVE path AS (
+	SELECT item_categories.item_id, item_categories.category_id AS leaf_id,
+		categories.id, categories.name, categories.parent_id, 0 AS depth
+	FROM item_categories JOIN categories ON categories.id = item_categories.category_id
+	WHERE item_categories.item_id IN ?
+	UNION ALL
+	SELECT path.item_id, path.leaf_id, categories.id, categories.name, categories.parent_id, path.depth + 1
+	FROM categories JOIN path ON categories.id = path.parent_id
+	WHERE path.depth < ?
+) SELECT item_id, leaf_id, id, name, parent_id FROM path ORDER BY item_id, leaf_id, depth DESC`
+
+// Category represents node of the category tree in underlying db
+type Category struct {
+	ID   uint
+	Name *string
+	// ParentID is not set for root categories. When updating category, zero value moves it to the root.
+	ParentID *uint
+}
+
+// ItemCategory assigns item to the category
+type ItemCategory struct {
+	ItemID     uint `gorm:"primaryKey"`
+	CategoryID uint `gorm:"primaryKey"`
+}
+
+// breadcrumbRow is a row returned by itemBreadcrumbs query
+type breadcrumbRow struct {
+	ItemID   uint
+	LeafID   uint
+	Category `gorm:"embedded"`
+}
+
+// validate checks if set fields fit into underlying db columns
+func (c Category) validate() error {
+	if c.Name != nil && (*c.Name == "" || utf8.RuneCountInString(*c.Name) > maxCategoryNameLength) {
+		return fmt.Errorf("%w: name should be between 1 and %d characters long", ErrInvalidCategory, maxCategoryNameLength)
+	}
+	return nil
+}
+
+// parent returns ID of parent category, nil when category should be a root one
+func (c Category) parent() *uint {
+	if c.ParentID == nil || *c.ParentID == 0 {
+		return nil
+	}
+	return c.ParentID
+}
+
+// CreateCategory persists category in db and returns it with assigned ID
+func (s *CatalogStore) CreateCategory(ctx context.Context, category Category) (Category, error) {
+	if category.Name == nil {
+		return Category{}, fmt.Errorf("%w: name is required", ErrInvalidCategory)
+	}
+	if err := category.validate(); err != nil {
+		return Category{}, err
+	}
+	category.ParentID = category.parent()
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if category.ParentID != nil {
+			if err := categoriesExist(tx, *category.ParentID); err != nil {
+				return err
+			}
+		}
+		return tx.Create(&category).Error
+	})
+	if err != nil {
+		return Category{}, fmt.Errorf("error while adding category to db: %w", err)
+	}
+	return category, nil
+}
+
+// GetCategory returns category with provided ID from db
+func (s *CatalogStore) GetCategory(ctx context.Context, id uint) (category Category, err error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err = db.First(&category, id).Error
+	return
+}
+
+// GetCategories returns all categories from db ordered by name
+func (s *CatalogStore) GetCategories(ctx context.Context) (categories []Category, err error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err = db.Order("name").Order("id").Find(&categories).Error
+	return
+}
+
+// GetCategoryPath returns category with provided ID preceded by all its ancestors, root first
+func (s *CatalogStore) GetCategoryPath(ctx context.Context, id uint) ([]Category, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var path []Category
+	if err := db.Raw(categoryPath, id, maxCategoryDepth).Scan(&path).Error; err != nil {
+		return nil, fmt.Errorf("error while getting path of category with id %d: %w", id, err)
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("error while getting path of category with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	return path, nil
+}
+
+// UpdateCategory renames category with ID or moves it to another parent and returns its new state. Category cannot
+// be moved under itself or any of its descendants. Moves are serialized, so concurrent ones cannot form a cycle.
+func (s *CatalogStore) UpdateCategory(ctx context.Context, id uint, category Category) (Category, error) {
+	if err := category.validate(); err != nil {
+		return Category{}, err
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var updated Category
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// descendants are checked after the lock is taken, so they include moves committed in the meantime
+		if category.parent() != nil {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", categoryMoveLockID).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.First(&updated, id).Error; err != nil {
+			return err
+		}
+		updates := map[string]interface{}{}
+		if category.Name != nil {
+			updates["name"] = *category.Name
+			updated.Name = category.Name
+		}
+		if category.ParentID != nil {
+			parent := category.parent()
+			if parent != nil {
+				if err := categoriesExist(tx, *parent); err != nil {
+					return err
+				}
+				var descendants []uint
+				if err := tx.Raw(descendantCategories, id).Scan(&descendants).Error; err != nil {
+					return err
+				}
+				if containsID(descendants, *parent) {
+					return fmt.Errorf("%w: category cannot be moved under itself", ErrInvalidCategory)
+				}
+			}
+			updates["parent_id"] = parent
+			updated.ParentID = parent
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&Category{}).Where("id = ?", id).Updates(updates).Error
+	})
+	if err != nil {
+		return Category{}, fmt.Errorf("error while updating category with id %d: %w", id, err)
+	}
+	return updated, nil
+}
+
+// DeleteCategory removes category with ID from db together with its item assignments. Categories which have
+// subcategories cannot be deleted.
+func (s *CatalogStore) DeleteCategory(ctx context.Context, id uint) error {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var children int64
+		if err := tx.Model(&Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return ErrCategoryHasChildren
+		}
+		resp := tx.Delete(&Category{}, id)
+		if resp.Error == nil && resp.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return resp.Error
+	})
+	if err != nil {
+		return fmt.Errorf("error while deleting category with id %d: %w", id, err)
+	}
+	return nil
+}
+
+// SetItemCategories replaces categories item with ID is assigned to. Item version is incremented and the revision
+// is recorded in its history.
+func (s *CatalogStore) SetItemCategories(ctx context.Context, itemID uint, categoryIDs []uint) error {
+	categoryIDs = uniqueIDs(categoryIDs)
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := reviseItem(tx, itemID, PartCategories); err != nil {
+			return err
+		}
+		if err := categoriesExist(tx, categoryIDs...); err != nil {
+			return err
+		}
+		if err := tx.Where("item_id = ?", itemID).Delete(&ItemCategory{}).Error; err != nil {
+			return err
+		}
+		if len(categoryIDs) == 0 {
+			return nil
+		}
+		assignments := make([]ItemCategory, 0, len(categoryIDs))
+		for _, categoryID := range categoryIDs {
+			assignments = append(assignments, ItemCategory{ItemID: itemID, CategoryID: categoryID})
+		}
+		return tx.Create(&assignments).Error
+	})
+	if err != nil {
+		return fmt.Errorf("error while assigning categories to item with id %d: %w", itemID, err)
+	}
+	return nil
+}
+
+// GetItemBreadcrumbs returns paths from the root to every category provided items are assigned to, keyed by item ID
+func (s *CatalogStore) GetItemBreadcrumbs(ctx context.Context, itemIDs []uint) (map[uint][][]Category, error) {
+	breadcrumbs := map[uint][][]Category{}
+	if len(itemIDs) == 0 {
+		return breadcrumbs, nil
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var rows []breadcrumbRow
+	if err := db.Raw(itemBreadcrumbs, itemIDs, maxCategoryDepth).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error while getting item breadcrumbs: %w", err)
+	}
+	for i, row := range rows {
+		if i == 0 || rows[i-1].ItemID != row.ItemID || rows[i-1].LeafID != row.LeafID {
+			breadcrumbs[row.ItemID] = append(breadcrumbs[row.ItemID], nil)
+		}
+		paths := breadcrumbs[row.ItemID]
+		paths[len(paths)-1] = append(paths[len(paths)-1], row.Category)
+	}
+	return breadcrumbs, nil
+}
+
+// categoriesExist returns ErrInvalidCategory unless all categories with provided unique IDs exist
+func categoriesExist(tx *gorm.DB, ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&Category{}).Where("id IN ?", ids).Count(&count).Error; err != nil {
+		return err
+	}
+	if count != int64(len(ids)) {
+		return fmt.Errorf("%w: category does not exist", ErrInvalidCategory)
+	}
+	return nil
+}
+
+// CreateCategory persists category in memory and returns it with assigned ID
+func (s *MemoryStore) CreateCategory(ctx context.Context, category Category) (Category, error) {
+	if category.Name == nil {
+		return Category{}, fmt.Errorf("%w: name is required", ErrInvalidCategory)
+	}
+	if err := category.validate(); err != nil {
+		return Category{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return Category{}, fmt.Errorf("error while adding category to db: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	category.ParentID = category.parent()
+	if category.ParentID != nil {
+		if _, ok := s.categories[*category.ParentID]; !ok {
+			return Category{}, fmt.Errorf("error while adding category to db: %w: category does not exist", ErrInvalidCategory)
+		}
+	}
+	s.lastCategoryID++
+	category.ID = s.lastCategoryID
+	s.categories[category.ID] = cloneCategory(category)
+	return cloneCategory(category), nil
+}
+
+// GetCategory returns category with provided ID from memory
+func (s *MemoryStore) GetCategory(ctx context.Context, id uint) (Category, error) {
+	if err := ctx.Err(); err != nil {
+		return Category{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	category, ok := s.categories[id]
+	if !ok {
+		return Category{}, gorm.ErrRecordNotFound
+	}
+	return cloneCategory(category), nil
+}
+
+// GetCategories returns all categories from memory ordered by name
+func (s *MemoryStore) GetCategories(ctx context.Context) ([]Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	categories := make([]Category, 0, len(s.categories))
+	for _, category := range s.categories {
+		categories = append(categories, cloneCategory(category))
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if *categories[i].Name != *categories[j].Name {
+			return *categories[i].Name < *categories[j].Name
+		}
+		return categories[i].ID < categories[j].ID
+	})
+	return categories, nil
+}
+
+// GetCategoryPath returns category with provided ID preceded by all its ancestors, root first
+func (s *MemoryStore) GetCategoryPath(ctx context.Context, id uint) ([]Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while getting path of category with id %d: %w", id, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.categories[id]; !ok {
+		return nil, fmt.Errorf("error while getting path of category with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	return s.path(id), nil
+}
+
+// UpdateCategory renames category with ID or moves it to another parent and returns its new state. Category cannot
+// be moved under itself or any of its descendants.
+func (s *MemoryStore) UpdateCategory(ctx context.Context, id uint, category Category) (Category, error) {
+	if err := category.validate(); err != nil {
+		return Category{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return Category{}, fmt.Errorf("error while updating category with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	updated, ok := s.categories[id]
+	if !ok {
+		return Category{}, fmt.Errorf("error while updating category with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	if category.ParentID != nil {
+		parent := category.parent()
+		if parent != nil {
+			if _, ok := s.categories[*parent]; !ok {
+				return Category{}, fmt.Errorf("error while updating category with id %d: %w: category does not exist",
+					id, ErrInvalidCategory)
+			}
+			if containsID(s.descendants(id), *parent) {
+				return Category{}, fmt.Errorf("error while updating category with id %d: %w: category cannot be moved under itself",
+					id, ErrInvalidCategory)
+			}
+		}
+		updated.ParentID = parent
+	}
+	if category.Name != nil {
+		updated.Name = category.Name
+	}
+	s.categories[id] = cloneCategory(updated)
+	return cloneCategory(updated), nil
+}
+
+// DeleteCategory removes category with ID from memory together with its item assignments. Categories which have
+// subcategories cannot be deleted.
+func (s *MemoryStore) DeleteCategory(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error while deleting category with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.categories[id]; !ok {
+		return fmt.Errorf("error while deleting category with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	if len(s.descendants(id)) > 1 {
+		return fmt.Errorf("error while deleting category with id %d: %w", id, ErrCategoryHasChildren)
+	}
+	delete(s.categories, id)
+	for _, categories := range s.itemCategories {
+		delete(categories, id)
+	}
+	return nil
+}
+
+// SetItemCategories replaces categories item with ID is assigned to. Item version is incremented and the revision
+// is recorded in its history.
+func (s *MemoryStore) SetItemCategories(ctx context.Context, itemID uint, categoryIDs []uint) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error while assigning categories to item with id %d: %w", itemID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.live(itemID); !ok {
+		return fmt.Errorf("error while assigning categories to item with id %d: %w", itemID, gorm.ErrRecordNotFound)
+	}
+	assigned := map[uint]bool{}
+	for _, categoryID := range categoryIDs {
+		if _, ok := s.categories[categoryID]; !ok {
+			return fmt.Errorf("error while assigning categories to item with id %d: %w: category does not exist",
+				itemID, ErrInvalidCategory)
+		}
+		assigned[categoryID] = true
+	}
+	s.itemCategories[itemID] = assigned
+	s.reviseItem(ctx, itemID, PartCategories)
+	return nil
+}
+
+// GetItemBreadcrumbs returns paths from the root to every category provided items are assigned to, keyed by item ID
+func (s *MemoryStore) GetItemBreadcrumbs(ctx context.Context, itemIDs []uint) (map[uint][][]Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while getting item breadcrumbs: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	breadcrumbs := map[uint][][]Category{}
+	for _, itemID := range itemIDs {
+		var leaves []uint
+		for categoryID := range s.itemCategories[itemID] {
+			leaves = append(leaves, categoryID)
+		}
+		sort.Slice(leaves, func(i, j int) bool { return leaves[i] < leaves[j] })
+		for _, leaf := range leaves {
+			breadcrumbs[itemID] = append(breadcrumbs[itemID], s.path(leaf))
+		}
+	}
+	return breadcrumbs, nil
+}
+
+// inCategory reports if item with ID is assigned to the category filter points at, or to any of its descendants
+// when filter includes them. Caller has to hold the lock.
+func (s *MemoryStore) inCategory(filter ItemFilter, itemID uint) bool {
+	if filter.CategoryID == nil {
+		return true
+	}
+	categories := []uint{*filter.CategoryID}
+	if filter.IncludeDescendants {
+		categories = s.descendants(*filter.CategoryID)
+	}
+	for _, categoryID := range categories {
+		if s.itemCategories[itemID][categoryID] {
+			return true
+		}
+	}
+	return false
+}
+
+// path returns category with ID preceded by all its ancestors, root first. Caller has to hold the lock.
+func (s *MemoryStore) path(id uint) []Category {
+	var path []Category
+	for category, ok := s.categories[id]; ok; category, ok = s.categories[derefOrZero(category.ParentID)] {
+		path = append([]Category{cloneCategory(category)}, path...)
+	}
+	return path
+}
+
+// descendants returns ID of the category and IDs of all categories below it. Caller has to hold the lock.
+func (s *MemoryStore) descendants(id uint) []uint {
+	ids := []uint{id}
+	for i := 0; i < len(ids); i++ {
+		for _, category := range s.categories {
+			if category.ParentID != nil && *category.ParentID == ids[i] {
+				ids = append(ids, category.ID)
+			}
+		}
+	}
+	return ids
+}
+
+func cloneCategory(category Category) Category {
+	return Category{ID: category.ID, Name: clonePtr(category.Name), ParentID: clonePtr(category.ParentID)}
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := map[uint]bool{}
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func newMockStore(t *testing.T) (*CatalogStore, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}))
+	require.NoError(t, err)
+	return &CatalogStore{db: db}, mock
+}
+
+func TestDeleteCategory(t *testing.T) {
+	tests := []struct {
+		name         string
+		children     int
+		rowsAffected int64
+		expectedErr  error
+	}{
+		{
+			name:         "Successful - leaf category deleted",
+			rowsAffected: 1,
+		},
+		{
+			name:        "Conflict - category has children",
+			children:    2,
+			expectedErr: ErrCategoryHasChildren,
+		},
+		{
+			name:        "Not Found - no category in db",
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT count\(\*\) FROM "categories" WHERE parent_id = \$1`).WithArgs(3).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(test.children))
+			if test.children == 0 {
+				mock.ExpectExec(`DELETE FROM "categories" WHERE "categories"\."id" = \$1`).WithArgs(3).
+					WillReturnResult(sqlmock.NewResult(0, test.rowsAffected))
+			}
+			if test.expectedErr == nil {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			err := store.DeleteCategory(context.Background(), 3)
+
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateCategory_move(t *testing.T) {
+	tests := []struct {
+		name        string
+		descendants []uint
+		expectedErr error
+	}{
+		{
+			name:        "Successful - category moved",
+			descendants: []uint{3, 4},
+		},
+		{
+			name:        "Bad Request - category moved under its descendant",
+			descendants: []uint{3, 4, 5},
+			expectedErr: ErrInvalidCategory,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectBegin()
+			mock.ExpectExec(`SELECT pg_advisory_xact_lock\(\$1\)`).WithArgs(categoryMoveLockID).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(`SELECT \* FROM "categories" WHERE "categories"\."id" = \$1`).WithArgs(3).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "parent_id"}).AddRow(3, "Shirts", nil))
+			mock.ExpectQuery(`SELECT count\(\*\) FROM "categories" WHERE id IN \(\$1\)`).WithArgs(5).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			rows := sqlmock.NewRows([]string{"id"})
+			for _, id := range test.descendants {
+				rows.AddRow(id)
+			}
+			mock.ExpectQuery(`WITH RECURSIVE tree AS .* UNION SELECT`).WithArgs(3).WillReturnRows(rows)
+			if test.expectedErr == nil {
+				mock.ExpectExec(`UPDATE "categories" SET "parent_id"=\$1 WHERE id = \$2`).WithArgs(5, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			category, err := store.UpdateCategory(context.Background(), 3, Category{ParentID: v2p(uint(5))})
+
+			assert.ErrorIs(t, err, test.expectedErr)
+			if test.expectedErr == nil {
+				assert.Equal(t, v2p(uint(5)), category.ParentID)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSetItemCategories(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectBegin()
+	expectReviseItem(mock, PartCategories)
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "categories" WHERE id IN \(\$1,\$2\)`).WithArgs(4, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectExec(`DELETE FROM "item_categories" WHERE item_id = \$1`).WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "item_categories" \("item_id","category_id"\) VALUES \(\$1,\$2\),\(\$3,\$4\)`).
+		WithArgs(1, 4, 1, 5).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := store.SetItemCategories(context.Background(), 1, []uint{4, 5, 4})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetItemBreadcrumbs(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectQuery(`WITH RECURSIVE path AS .* WHERE item_categories\.item_id IN \(\$1,\$2\).* WHERE path\.depth < \$3`).
+		WithArgs(1, 2, maxCategoryDepth).
+		WillReturnRows(sqlmock.NewRows([]string{"item_id", "leaf_id", "id", "name", "parent_id"}).
+			AddRow(1, 2, 1, "Clothes", nil).
+			AddRow(1, 2, 2, "Shirts", 1).
+			AddRow(1, 3, 3, "Sale", nil).
+			AddRow(2, 3, 3, "Sale", nil))
+
+	breadcrumbs, err := store.GetItemBreadcrumbs(context.Background(), []uint{1, 2})
+
+	require.NoError(t, err)
+	clothes := Category{ID: 1, Name: v2p("Clothes")}
+	shirts := Category{ID: 2, Name: v2p("Shirts"), ParentID: v2p(uint(1))}
+	sale := Category{ID: 3, Name: v2p("Sale")}
+	assert.Equal(t, map[uint][][]Category{
+		1: {{clothes, shirts}, {sale}},
+		2: {{sale}},
+	}, breadcrumbs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetItems_categoryFilter(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectQuery(`SELECT \* FROM "items" WHERE id IN \(SELECT item_id FROM item_categories WHERE category_id IN ` +
+		`\(WITH RECURSIVE tree AS .* SELECT id FROM tree\)\) AND "items"\."deleted_at" IS NULL ORDER BY "id" LIMIT 10`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	items, err := store.GetItems(context.Background(), ItemQuery{
+		ItemFilter: ItemFilter{CategoryID: v2p(uint(7)), IncludeDescendants: true},
+		PageSize:   10,
+		Page:       1,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,1519 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testDBDSNEnv names env variable with connection string to postgres db used by conformance tests of CatalogStore
+const testDBDSNEnv = "CATALOG_TEST_DB_DSN"
+
+// conformanceStore is a set of store methods which every catalog store backend has to provide
+type conformanceStore interface {
+	CreateItem(ctx context.Context, item Item) (Item, error)
+	DeleteItem(ctx context.Context, id uint, ifVersion uint) error
+	GetItem(ctx context.Context, id uint) (Item, error)
+	GetItems(ctx context.Context, query ItemQuery) ([]Item, error)
+	CountItems(ctx context.Context, filter ItemFilter) (int64, error)
+	SearchItems(ctx context.Context, query SearchQuery) ([]SearchResult, int64, error)
+	UpdateItem(ctx context.Context, id uint, item Item, ifVersion uint) (Item, error)
+	GetDeletedItems(ctx context.Context, pageSize, page int) ([]Item, error)
+	RestoreItem(ctx context.Context, id uint) (Item, error)
+	PurgeDeletedItems(ctx context.Context, deletedBefore time.Time) (int64, error)
+	CreateCategory(ctx context.Context, category Category) (Category, error)
+	GetCategory(ctx context.Context, id uint) (Category, error)
+	GetCategories(ctx context.Context) ([]Category, error)
+	GetCategoryPath(ctx context.Context, id uint) ([]Category, error)
+	UpdateCategory(ctx context.Context, id uint, category Category) (Category, error)
+	DeleteCategory(ctx context.Context, id uint) error
+	SetItemCategories(ctx context.Context, itemID uint, categoryIDs []uint) error
+	GetItemBreadcrumbs(ctx context.Context, itemIDs []uint) (map[uint][][]Category, error)
+	CreateVariant(ctx context.Context, itemID uint, variant Variant) (Variant, error)
+	GetVariants(ctx context.Context, itemID uint) ([]Variant, error)
+	GetVariant(ctx context.Context, itemID, id uint) (Variant, error)
+	UpdateVariant(ctx context.Context, itemID, id uint, variant Variant) (Variant, error)
+	DeleteVariant(ctx context.Context, itemID, id uint) error
+	GetStock(ctx context.Context, key StockKey) (Stock, error)
+	GetStockLevels(ctx context.Context, itemIDs []uint) ([]Stock, error)
+	AdjustStock(ctx context.Context, key StockKey, delta int64) (Stock, error)
+	Reserve(ctx context.Context, key StockKey, quantity int64, ttl time.Duration) (Reservation, error)
+	GetReservation(ctx context.Context, id uint) (Reservation, error)
+	CommitReservation(ctx context.Context, id uint) (Reservation, error)
+	ReleaseReservation(ctx context.Context, id uint) (Reservation, error)
+	ReleaseExpiredReservations(ctx context.Context, now time.Time) (int64, error)
+	SetItemPrices(ctx context.Context, itemID uint, prices []ItemPrice) ([]ItemPrice, error)
+	GetItemPrices(ctx context.Context, itemIDs []uint) ([]ItemPrice, error)
+	SetExchangeRates(ctx context.Context, rates money.Rates) error
+	GetExchangeRates(ctx context.Context) (money.Rates, error)
+	CreateScheduledPrice(ctx context.Context, itemID uint, price ScheduledPrice) (ScheduledPrice, error)
+	GetScheduledPrices(ctx context.Context, itemID uint) ([]ScheduledPrice, error)
+	DeleteScheduledPrice(ctx context.Context, itemID, id uint) error
+	GetActivePrices(ctx context.Context, itemIDs []uint, at time.Time) ([]ScheduledPrice, error)
+	GetUpcomingPrices(ctx context.Context, after time.Time, pageSize, page int) ([]ScheduledPrice, error)
+	GetItemHistory(ctx context.Context, itemID uint) ([]ItemChange, error)
+	GetItemAsOf(ctx context.Context, itemID uint, at time.Time) (Item, error)
+	RelayEvents(ctx context.Context, limit int, publish func(ctx context.Context, event Event) error) (int, error)
+	GetEvents(ctx context.Context, afterPosition uint64, limit int) ([]Event, error)
+	PruneEvents(ctx context.Context, publishedBefore time.Time) (int64, error)
+	ApplyItemOperations(ctx context.Context, ops []ItemOperation) ([]Item, error)
+	CreateWebhook(ctx context.Context, webhook Webhook) (Webhook, error)
+	GetWebhooks(ctx context.Context) ([]Webhook, error)
+	GetWebhook(ctx context.Context, id uint) (Webhook, error)
+	DeleteWebhook(ctx context.Context, id uint) error
+	CreateDeliveries(ctx context.Context, event Event, payload string) (int64, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error)
+	RecordDeliveryAttempt(ctx context.Context, id uint, attempt DeliveryAttempt) (WebhookDelivery, error)
+	GetDeliveries(ctx context.Context, query DeliveryQuery) ([]WebhookDelivery, error)
+	RedeliverDelivery(ctx context.Context, webhookID, id uint) (WebhookDelivery, error)
+	UpsertItemByExternalID(ctx context.Context, item Item) (Item, UpsertOutcome, error)
+	GetItemByExternalID(ctx context.Context, externalID string) (Item, error)
+	CreateImportJob(ctx context.Context, format ImportFormat, data []byte) (ImportJob, error)
+	GetImportJob(ctx context.Context, id uint) (ImportJob, error)
+	ClaimImportJob(ctx context.Context, now time.Time, lease time.Duration) (ImportJob, []byte, error)
+	RecordImportProgress(ctx context.Context, id uint, claim int, progress ImportProgress) error
+	GetImportRejections(ctx context.Context, jobID uint, pageSize, page int) ([]ImportRejection, error)
+	CreateItemImage(ctx context.Context, itemID uint, image ItemImage) (ItemImage, error)
+	GetItemImages(ctx context.Context, itemIDs []uint) (map[uint][]ItemImage, error)
+	GetItemImage(ctx context.Context, itemID, id uint) (ItemImage, error)
+	UpdateItemImage(ctx context.Context, itemID, id uint, altText *string, position int) (ItemImage, error)
+	DeleteItemImage(ctx context.Context, itemID, id uint) error
+	GetMediaDeletions(ctx context.Context, limit int) ([]MediaDeletion, error)
+	CompleteMediaDeletion(ctx context.Context, blobKey string) error
+	ExportItems(ctx context.Context, query ExportQuery, export func(items []Item) error) error
+}
+
+func TestMemoryStore_conformance(t *testing.T) {
+	runConformance(t, func(t *testing.T) conformanceStore {
+		return NewMemoryStore()
+	})
+}
+
+func TestCatalogStore_conformance(t *testing.T) {
+	dsn := os.Getenv(testDBDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set, skipping postgres conformance tests", testDBDSNEnv)
+	}
+	s, err := openCatalogStore(dsn, 0)
+	require.NoError(t, err)
+	migrator, err := s.Migrator()
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	runConformance(t, func(t *testing.T) conformanceStore {
+		require.NoError(t, s.db.Exec("TRUNCATE items, categories, import_jobs RESTART IDENTITY CASCADE").Error)
+		return s
+	})
+}
+
+// runConformance runs shared test suite which asserts that store behaves the same as every other backend
+func runConformance(t *testing.T, newStore func(t *testing.T) conformanceStore) {
+	ctx := context.Background()
+	newItem := func(name string) Item {
+		return Item{Name: v2p(name), Description: v2p("desc"), Price: v2p(int64(1050)), PriceCode: v2p("EUR")}
+	}
+
+	t.Run("CreateItem assigns ID and GetItem returns it", func(t *testing.T) {
+		s := newStore(t)
+
+		created, err := s.CreateItem(ctx, newItem("first"))
+		require.NoError(t, err)
+		assert.NotZero(t, created.ID)
+
+		got, err := s.GetItem(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, created, got)
+	})
+
+	t.Run("CreateItem rejects invalid items", func(t *testing.T) {
+		s := newStore(t)
+
+		_, err := s.CreateItem(ctx, Item{Name: v2p("no price")})
+		assert.ErrorIs(t, err, ErrInvalidItem)
+		tooLong := newItem(strings.Repeat("x", maxNameLength+1))
+		_, err = s.CreateItem(ctx, tooLong)
+		assert.ErrorIs(t, err, ErrInvalidItem)
+	})
+
+	t.Run("GetItem returns not found for unknown ID", func(t *testing.T) {
+		s := newStore(t)
+
+		_, err := s.GetItem(ctx, 404)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("GetItems paginates", func(t *testing.T) {
+		s := newStore(t)
+		for _, name := range []string{"a", "b", "c"} {
+			_, err := s.CreateItem(ctx, newItem(name))
+			require.NoError(t, err)
+		}
+
+		first, err := s.GetItems(ctx, ItemQuery{PageSize: 2, Page: 1})
+		require.NoError(t, err)
+		second, err := s.GetItems(ctx, ItemQuery{PageSize: 2, Page: 2})
+		require.NoError(t, err)
+		beyond, err := s.GetItems(ctx, ItemQuery{PageSize: 2, Page: 3})
+		require.NoError(t, err)
+
+		assert.Len(t, first, 2)
+		assert.Len(t, second, 1)
+		assert.Empty(t, beyond)
+		var names []string
+		for _, item := range append(first, second...) {
+			names = append(names, *item.Name)
+		}
+		assert.ElementsMatch(t, []string{"a", "b", "c"}, names)
+	})
+
+	t.Run("GetItems validates page params", func(t *testing.T) {
+		s := newStore(t)
+
+		_, err := s.GetItems(ctx, ItemQuery{PageSize: 0, Page: 1})
+		assert.ErrorIs(t, err, ErrInvalidPageParams)
+		_, err = s.GetItems(ctx, ItemQuery{PageSize: 1, Page: 0})
+		assert.ErrorIs(t, err, ErrInvalidPageParams)
+	})
+
+	t.Run("GetItems filters items", func(t *testing.T) {
+		s := newStore(t)
+		for _, item := range []Item{
+			{Name: v2p("Red Shirt"), Description: v2p("desc"), Price: v2p(int64(1000)), PriceCode: v2p("EUR")},
+			{Name: v2p("Blue shirt"), Description: v2p("desc"), Price: v2p(int64(2000)), PriceCode: v2p("USD")},
+			{Name: v2p("Blue 100% wool"), Description: v2p("desc"), Price: v2p(int64(3000)), PriceCode: v2p("EUR")},
+		} {
+			_, err := s.CreateItem(ctx, item)
+			require.NoError(t, err)
+		}
+
+		tests := []struct {
+			filter        ItemFilter
+			expectedNames []string
+		}{
+			{filter: ItemFilter{MinPrice: v2p("20")}, expectedNames: []string{"Blue shirt", "Blue 100% wool"}},
+			{filter: ItemFilter{MaxPrice: v2p("20")}, expectedNames: []string{"Red Shirt", "Blue shirt"}},
+			{filter: ItemFilter{MinPrice: v2p("15"), MaxPrice: v2p("25")}, expectedNames: []string{"Blue shirt"}},
+			{filter: ItemFilter{PriceCode: v2p("eur")}, expectedNames: []string{"Red Shirt", "Blue 100% wool"}},
+			{filter: ItemFilter{Name: v2p("SHIRT")}, expectedNames: []string{"Red Shirt", "Blue shirt"}},
+			{filter: ItemFilter{Name: v2p("100%")}, expectedNames: []string{"Blue 100% wool"}},
+			{filter: ItemFilter{Name: v2p("_")}},
+			{filter: ItemFilter{Name: v2p("blue"), PriceCode: v2p("USD")}, expectedNames: []string{"Blue shirt"}},
+		}
+		for _, test := range tests {
+			items, err := s.GetItems(ctx, ItemQuery{ItemFilter: test.filter, PageSize: 10, Page: 1})
+			require.NoError(t, err)
+			names := []string{}
+			for _, item := range items {
+				names = append(names, *item.Name)
+			}
+			assert.ElementsMatch(t, test.expectedNames, names, "filter %+v", test.filter)
+
+			count, err := s.CountItems(ctx, test.filter)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(test.expectedNames)), count, "filter %+v", test.filter)
+		}
+	})
+
+	t.Run("GetItems sorts items", func(t *testing.T) {
+		s := newStore(t)
+		for _, item := range []Item{
+			{Name: v2p("b"), Description: v2p("desc"), Price: v2p(int64(1000)), PriceCode: v2p("EUR")},
+			{Name: v2p("a"), Description: v2p("desc"), Price: v2p(int64(3000)), PriceCode: v2p("EUR")},
+			{Name: v2p("c"), Description: v2p("desc"), Price: v2p(int64(1000)), PriceCode: v2p("EUR")},
+		} {
+			_, err := s.CreateItem(ctx, item)
+			require.NoError(t, err)
+		}
+
+		tests := []struct {
+			sort          string
+			expectedNames []string
+		}{
+			{sort: "", expectedNames: []string{"b", "a", "c"}},
+			{sort: "-id", expectedNames: []string{"c", "a", "b"}},
+			{sort: "name", expectedNames: []string{"a", "b", "c"}},
+			{sort: "-name", expectedNames: []string{"c", "b", "a"}},
+			{sort: "price", expectedNames: []string{"b", "c", "a"}},
+			{sort: "-price", expectedNames: []string{"a", "c", "b"}},
+		}
+		for _, test := range tests {
+			items, err := s.GetItems(ctx, ItemQuery{Sort: test.sort, PageSize: 10, Page: 1})
+			require.NoError(t, err)
+			var names []string
+			for _, item := range items {
+				names = append(names, *item.Name)
+			}
+			assert.Equal(t, test.expectedNames, names, "sort %q", test.sort)
+		}
+
+		_, err := s.GetItems(ctx, ItemQuery{Sort: "description", PageSize: 10, Page: 1})
+		assert.ErrorIs(t, err, ErrInvalidSort)
+	})
+
+	t.Run("GetItems sorts prices in different currencies by amount", func(t *testing.T) {
+		s := newStore(t)
+		for _, item := range []Item{
+			{Name: v2p("yen"), Description: v2p("desc"), Price: v2p(int64(1000)), PriceCode: v2p("JPY")},
+			{Name: v2p("dollar"), Description: v2p("desc"), Price: v2p(int64(999)), PriceCode: v2p("USD")},
+			{Name: v2p("dinar"), Description: v2p("desc"), Price: v2p(int64(20000)), PriceCode: v2p("BHD")},
+		} {
+			_, err := s.CreateItem(ctx, item)
+			require.NoError(t, err)
+		}
+
+		for sort, expectedNames := range map[string][]string{
+			"price":  {"dollar", "dinar", "yen"},
+			"-price": {"yen", "dinar", "dollar"},
+		} {
+			var names []string
+			query := ItemQuery{Sort: sort, PageSize: 1, Page: 1}
+			for {
+				items, err := s.GetItems(ctx, query)
+				require.NoError(t, err)
+				for _, item := range items {
+					names = append(names, *item.Name)
+				}
+				if query.Cursor = NextCursor(query, items); query.Cursor == "" {
+					break
+				}
+			}
+			assert.Equal(t, expectedNames, names, "sort %q", sort)
+		}
+	})
+
+	t.Run("GetItems continues listing from cursor", func(t *testing.T) {
+		s := newStore(t)
+		for i, price := range []int64{3000, 1000, 2000, 1000, 3000, 2000, 1000} {
+			_, err := s.CreateItem(ctx, Item{Name: v2p(fmt.Sprintf("item %d", i)), Description: v2p("desc"),
+				Price: v2p(price), PriceCode: v2p("EUR")})
+			require.NoError(t, err)
+		}
+
+		for _, sort := range []string{"", "-id", "price", "-price", "name"} {
+			all, err := s.GetItems(ctx, ItemQuery{Sort: sort, PageSize: 100, Page: 1})
+			require.NoError(t, err)
+
+			var listed []Item
+			query := ItemQuery{Sort: sort, PageSize: 3, Page: 1}
+			for {
+				items, err := s.GetItems(ctx, query)
+				require.NoError(t, err)
+				listed = append(listed, items...)
+				if query.Cursor = NextCursor(query, items); query.Cursor == "" {
+					break
+				}
+			}
+			assert.Equal(t, all, listed, "sort %q", sort)
+		}
+	})
+
+	t.Run("GetItems cursor is not affected by inserts", func(t *testing.T) {
+		s := newStore(t)
+		for _, name := range []string{"b", "d"} {
+			_, err := s.CreateItem(ctx, Item{Name: v2p(name), Description: v2p("desc"), Price: v2p(int64(100)), PriceCode: v2p("EUR")})
+			require.NoError(t, err)
+		}
+
+		query := ItemQuery{Sort: "name", PageSize: 1, Page: 1}
+		items, err := s.GetItems(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		_, err = s.CreateItem(ctx, Item{Name: v2p("a"), Description: v2p("desc"), Price: v2p(int64(100)), PriceCode: v2p("EUR")})
+		require.NoError(t, err)
+
+		query.Cursor = NextCursor(query, items)
+		items, err = s.GetItems(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "d", *items[0].Name)
+	})
+
+	t.Run("GetItems validates cursor", func(t *testing.T) {
+		s := newStore(t)
+		query := ItemQuery{Sort: "price", PageSize: 1, Page: 1}
+		cursor := NextCursor(query, []Item{{ID: 1, Price: v2p(int64(100))}})
+
+		for _, invalid := range []ItemQuery{
+			{Sort: "price", Cursor: "not a cursor", PageSize: 1, Page: 1},
+			{Sort: "-price", Cursor: cursor, PageSize: 1, Page: 1},
+			{Sort: "price", Cursor: cursor, PageSize: 1, Page: 2},
+		} {
+			_, err := s.GetItems(ctx, invalid)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		}
+	})
+
+	t.Run("SearchItems ranks matching items", func(t *testing.T) {
+		s := newStore(t)
+		for _, item := range []Item{
+			{Name: v2p("Scarf"), Description: v2p("Goes well with any sweater"), Price: v2p(int64(1000)), PriceCode: v2p("EUR")},
+			{Name: v2p("Wool sweater"), Description: v2p("Warm and soft"), Price: v2p(int64(2000)), PriceCode: v2p("EUR")},
+			{Name: v2p("Cotton shirt"), Description: v2p("Light"), Price: v2p(int64(3000)), PriceCode: v2p("EUR")},
+			{Name: v2p("Deleted sweater"), Description: v2p("desc"), Price: v2p(int64(3000)), PriceCode: v2p("EUR")},
+		} {
+			created, err := s.CreateItem(ctx, item)
+			require.NoError(t, err)
+			if strings.HasPrefix(*item.Name, "Deleted") {
+				require.NoError(t, s.DeleteItem(ctx, created.ID, 0))
+			}
+		}
+
+		results, total, err := s.SearchItems(ctx, SearchQuery{Text: "Sweater", PageSize: 10, Page: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, results, 2)
+		assert.Equal(t, "Wool sweater", *results[0].Name)
+		assert.Equal(t, "Wool <mark>sweater</mark>", results[0].NameHighlight)
+		assert.Equal(t, "Scarf", *results[1].Name)
+		assert.Contains(t, results[1].DescriptionHighlight, "<mark>sweater</mark>")
+		assert.Greater(t, results[0].Rank, results[1].Rank)
+
+		results, total, err = s.SearchItems(ctx, SearchQuery{Text: "wool sweater", PageSize: 10, Page: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, results, 1)
+		assert.Equal(t, "Wool sweater", *results[0].Name)
+
+		results, total, err = s.SearchItems(ctx, SearchQuery{Text: "sweater", PageSize: 1, Page: 2})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, results, 1)
+		assert.Equal(t, "Scarf", *results[0].Name)
+
+		results, total, err = s.SearchItems(ctx, SearchQuery{Text: "sweater", PageSize: 1, Page: 5})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total, "total is counted past the last page")
+		assert.Empty(t, results)
+
+		_, _, err = s.SearchItems(ctx, SearchQuery{Text: " - ", PageSize: 10, Page: 1})
+		assert.ErrorIs(t, err, ErrInvalidSearchQuery)
+	})
+
+	t.Run("SearchItems escapes highlighted text", func(t *testing.T) {
+		s := newStore(t)
+		_, err := s.CreateItem(ctx, Item{Name: v2p(`<script>alert("sweater")</script>`), Description: v2p("Tom & Jerry"),
+			Price: v2p(int64(1000)), PriceCode: v2p("EUR")})
+		require.NoError(t, err)
+
+		results, _, err := s.SearchItems(ctx, SearchQuery{Text: "sweater", PageSize: 10, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "&lt;script&gt;alert(&#34;<mark>sweater</mark>&#34;)&lt;/script&gt;", results[0].NameHighlight)
+		assert.Equal(t, "Tom &amp; Jerry", results[0].DescriptionHighlight)
+	})
+
+	t.Run("Categories form a tree", func(t *testing.T) {
+		s := newStore(t)
+		clothes, err := s.CreateCategory(ctx, Category{Name: v2p("Clothes")})
+		require.NoError(t, err)
+		shirts, err := s.CreateCategory(ctx, Category{Name: v2p("Shirts"), ParentID: &clothes.ID})
+		require.NoError(t, err)
+		linen, err := s.CreateCategory(ctx, Category{Name: v2p("Linen"), ParentID: &shirts.ID})
+		require.NoError(t, err)
+
+		got, err := s.GetCategory(ctx, shirts.ID)
+		require.NoError(t, err)
+		assert.Equal(t, shirts, got)
+
+		path, err := s.GetCategoryPath(ctx, linen.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []Category{clothes, shirts, linen}, path)
+
+		categories, err := s.GetCategories(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []Category{clothes, linen, shirts}, categories)
+
+		_, err = s.CreateCategory(ctx, Category{Name: v2p("Orphan"), ParentID: v2p(uint(100))})
+		assert.ErrorIs(t, err, ErrInvalidCategory)
+		_, err = s.CreateCategory(ctx, Category{})
+		assert.ErrorIs(t, err, ErrInvalidCategory)
+		_, err = s.GetCategoryPath(ctx, 100)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("UpdateCategory moves category", func(t *testing.T) {
+		s := newStore(t)
+		clothes, err := s.CreateCategory(ctx, Category{Name: v2p("Clothes")})
+		require.NoError(t, err)
+		shirts, err := s.CreateCategory(ctx, Category{Name: v2p("Shirts"), ParentID: &clothes.ID})
+		require.NoError(t, err)
+
+		_, err = s.UpdateCategory(ctx, clothes.ID, Category{ParentID: &shirts.ID})
+		assert.ErrorIs(t, err, ErrInvalidCategory)
+		_, err = s.UpdateCategory(ctx, clothes.ID, Category{ParentID: &clothes.ID})
+		assert.ErrorIs(t, err, ErrInvalidCategory)
+		_, err = s.UpdateCategory(ctx, 100, Category{Name: v2p("Missing")})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		updated, err := s.UpdateCategory(ctx, shirts.ID, Category{Name: v2p("Tops"), ParentID: v2p(uint(0))})
+		require.NoError(t, err)
+		assert.Equal(t, Category{ID: shirts.ID, Name: v2p("Tops")}, updated)
+
+		updated, err = s.UpdateCategory(ctx, clothes.ID, Category{ParentID: &shirts.ID})
+		require.NoError(t, err)
+		assert.Equal(t, Category{ID: clothes.ID, Name: v2p("Clothes"), ParentID: &shirts.ID}, updated)
+	})
+
+	t.Run("DeleteCategory removes only leaf categories", func(t *testing.T) {
+		s := newStore(t)
+		clothes, err := s.CreateCategory(ctx, Category{Name: v2p("Clothes")})
+		require.NoError(t, err)
+		shirts, err := s.CreateCategory(ctx, Category{Name: v2p("Shirts"), ParentID: &clothes.ID})
+		require.NoError(t, err)
+		item, err := s.CreateItem(ctx, Item{Name: v2p("n"), Description: v2p("d"), Price: v2p(int64(100)), PriceCode: v2p("EUR")})
+		require.NoError(t, err)
+		require.NoError(t, s.SetItemCategories(ctx, item.ID, []uint{shirts.ID}))
+
+		assert.ErrorIs(t, s.DeleteCategory(ctx, clothes.ID), ErrCategoryHasChildren)
+		require.NoError(t, s.DeleteCategory(ctx, shirts.ID))
+		require.NoError(t, s.DeleteCategory(ctx, clothes.ID))
+		assert.ErrorIs(t, s.DeleteCategory(ctx, clothes.ID), gorm.ErrRecordNotFound)
+
+		breadcrumbs, err := s.GetItemBreadcrumbs(ctx, []uint{item.ID})
+		require.NoError(t, err)
+		assert.Empty(t, breadcrumbs[item.ID])
+	})
+
+	t.Run("Items are listed by category", func(t *testing.T) {
+		s := newStore(t)
+		clothes, err := s.CreateCategory(ctx, Category{Name: v2p("Clothes")})
+		require.NoError(t, err)
+		shirts, err := s.CreateCategory(ctx, Category{Name: v2p("Shirts"), ParentID: &clothes.ID})
+		require.NoError(t, err)
+		sale, err := s.CreateCategory(ctx, Category{Name: v2p("Sale")})
+		require.NoError(t, err)
+		var items []Item
+		for _, name := range []string{"Jacket", "Shirt", "Socks"} {
+			item, err := s.CreateItem(ctx, Item{Name: v2p(name), Description: v2p("d"), Price: v2p(int64(100)), PriceCode: v2p("EUR")})
+			require.NoError(t, err)
+			items = append(items, item)
+		}
+		require.NoError(t, s.SetItemCategories(ctx, items[0].ID, []uint{clothes.ID}))
+		require.NoError(t, s.SetItemCategories(ctx, items[1].ID, []uint{shirts.ID, sale.ID, shirts.ID}))
+		assert.ErrorIs(t, s.SetItemCategories(ctx, items[2].ID, []uint{100}), ErrInvalidCategory)
+		assert.ErrorIs(t, s.SetItemCategories(ctx, 100, []uint{sale.ID}), gorm.ErrRecordNotFound)
+
+		shirt, err := s.GetItem(ctx, items[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), shirt.Version)
+
+		tests := []struct {
+			filter        ItemFilter
+			expectedNames []string
+		}{
+			{filter: ItemFilter{CategoryID: &clothes.ID}, expectedNames: []string{"Jacket"}},
+			{filter: ItemFilter{CategoryID: &clothes.ID, IncludeDescendants: true}, expectedNames: []string{"Jacket", "Shirt"}},
+			{filter: ItemFilter{CategoryID: &sale.ID, IncludeDescendants: true}, expectedNames: []string{"Shirt"}},
+			{filter: ItemFilter{CategoryID: v2p(uint(100))}},
+		}
+		for _, test := range tests {
+			listed, err := s.GetItems(ctx, ItemQuery{ItemFilter: test.filter, PageSize: 10, Page: 1})
+			require.NoError(t, err)
+			names := []string{}
+			for _, item := range listed {
+				names = append(names, *item.Name)
+			}
+			assert.ElementsMatch(t, test.expectedNames, names, "filter %+v", test.filter)
+
+			count, err := s.CountItems(ctx, test.filter)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(test.expectedNames)), count)
+		}
+
+		breadcrumbs, err := s.GetItemBreadcrumbs(ctx, []uint{items[0].ID, items[1].ID, items[2].ID})
+		require.NoError(t, err)
+		assert.Equal(t, map[uint][][]Category{
+			items[0].ID: {{clothes}},
+			items[1].ID: {{clothes, shirts}, {sale}},
+		}, breadcrumbs)
+
+		require.NoError(t, s.SetItemCategories(ctx, items[1].ID, nil))
+		breadcrumbs, err = s.GetItemBreadcrumbs(ctx, []uint{items[1].ID})
+		require.NoError(t, err)
+		assert.Empty(t, breadcrumbs)
+	})
+
+	t.Run("Price filters respect exponent of item currency", func(t *testing.T) {
+		s := newStore(t)
+		for _, item := range []Item{
+			{Name: v2p("euro"), Description: v2p("d"), Price: v2p(int64(1000)), PriceCode: v2p("EUR")},
+			{Name: v2p("yen"), Description: v2p("d"), Price: v2p(int64(1500)), PriceCode: v2p("JPY")},
+			{Name: v2p("dinar"), Description: v2p("d"), Price: v2p(int64(1234)), PriceCode: v2p("BHD")},
+		} {
+			_, err := s.CreateItem(ctx, item)
+			require.NoError(t, err)
+		}
+
+		items, err := s.GetItems(ctx, ItemQuery{ItemFilter: ItemFilter{MinPrice: v2p("12")}, PageSize: 10, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "yen", *items[0].Name)
+		count, err := s.CountItems(ctx, ItemFilter{MaxPrice: v2p("1.234")})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		_, err = s.GetItems(ctx, ItemQuery{ItemFilter: ItemFilter{MinPrice: v2p("1e3")}, PageSize: 10, Page: 1})
+		assert.ErrorIs(t, err, money.ErrInvalidAmount)
+	})
+
+	t.Run("Price filters are exact at bounds without float representation", func(t *testing.T) {
+		s := newStore(t)
+		for _, price := range []int64{29, 115} {
+			_, err := s.CreateItem(ctx, Item{Name: v2p(fmt.Sprint(price)), Description: v2p("d"), Price: v2p(price),
+				PriceCode: v2p("EUR")})
+			require.NoError(t, err)
+		}
+
+		count, err := s.CountItems(ctx, ItemFilter{MaxPrice: v2p("0.29")})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		count, err = s.CountItems(ctx, ItemFilter{MinPrice: v2p("0.29"), MaxPrice: v2p("1.15")})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+		count, err = s.CountItems(ctx, ItemFilter{MinPrice: v2p("1.15")})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Variants are managed per item", func(t *testing.T) {
+		s := newStore(t)
+		shirt, err := s.CreateItem(ctx, Item{Name: v2p("Shirt"), Description: v2p("d"), Price: v2p(int64(1000)), PriceCode: v2p("EUR")})
+		require.NoError(t, err)
+		socks, err := s.CreateItem(ctx, Item{Name: v2p("Socks"), Description: v2p("d"), Price: v2p(int64(500)), PriceCode: v2p("EUR")})
+		require.NoError(t, err)
+
+		redM, err := s.CreateVariant(ctx, shirt.ID, Variant{SKU: "SHIRT-RED-M", Options: VariantOptions{"size": "M", "colour": "red"},
+			Price: v2p(int64(1200)), PriceCode: v2p("EUR"), Barcode: v2p("4006381333931")})
+		require.NoError(t, err)
+		assert.Equal(t, shirt.ID, redM.ItemID)
+		redL, err := s.CreateVariant(ctx, shirt.ID, Variant{SKU: "SHIRT-RED-L", Options: VariantOptions{"size": "L", "colour": "red"}})
+		require.NoError(t, err)
+		_, err = s.CreateVariant(ctx, socks.ID, Variant{SKU: "SOCKS-M", Options: VariantOptions{"size": "M", "colour": "red"}})
+		require.NoError(t, err)
+
+		_, err = s.CreateVariant(ctx, shirt.ID, Variant{SKU: "SHIRT-RED-M2", Options: VariantOptions{"colour": "red", "size": "M"}})
+		assert.ErrorIs(t, err, ErrVariantConflict)
+		_, err = s.CreateVariant(ctx, socks.ID, Variant{SKU: "SHIRT-RED-L", Options: VariantOptions{"size": "L"}})
+		assert.ErrorIs(t, err, ErrVariantConflict)
+		_, err = s.CreateVariant(ctx, shirt.ID, Variant{SKU: "SHIRT-XL", Barcode: v2p("4006381333932")})
+		assert.ErrorIs(t, err, ErrInvalidVariant)
+		_, err = s.CreateVariant(ctx, 100, Variant{SKU: "MISSING"})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		variants, err := s.GetVariants(ctx, shirt.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []Variant{redM, redL}, variants)
+
+		updated, err := s.UpdateVariant(ctx, shirt.ID, redL.ID, Variant{SKU: "SHIRT-BLUE-L", Options: VariantOptions{"size": "L", "colour": "blue"}})
+		require.NoError(t, err)
+		got, err := s.GetVariant(ctx, shirt.ID, redL.ID)
+		require.NoError(t, err)
+		assert.Equal(t, updated, got)
+		_, err = s.UpdateVariant(ctx, shirt.ID, redL.ID, Variant{SKU: "SHIRT-BLUE-L", Options: VariantOptions{"size": "M", "colour": "red"}})
+		assert.ErrorIs(t, err, ErrVariantConflict)
+		_, err = s.UpdateVariant(ctx, socks.ID, redL.ID, Variant{SKU: "SHIRT-BLUE-L"})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = s.GetVariant(ctx, socks.ID, redL.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		require.NoError(t, s.DeleteVariant(ctx, shirt.ID, redM.ID))
+		assert.ErrorIs(t, s.DeleteVariant(ctx, shirt.ID, redM.ID), gorm.ErrRecordNotFound)
+		variants, err = s.GetVariants(ctx, shirt.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []Variant{updated}, variants)
+
+		shirt, err = s.GetItem(ctx, shirt.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(5), shirt.Version)
+
+		require.NoError(t, s.DeleteItem(ctx, shirt.ID, 0))
+		_, err = s.GetVariants(ctx, shirt.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("UpdateItem updates only set fields", func(t *testing.T) {
+		s := newStore(t)
+		created, err := s.CreateItem(ctx, newItem("before"))
+		require.NoError(t, err)
+
+		updated, err := s.UpdateItem(ctx, created.ID, Item{Name: v2p("after")}, 0)
+		require.NoError(t, err)
+
+		got, err := s.GetItem(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, updated, got)
+		assert.Equal(t, "after", *got.Name)
+		assert.Equal(t, *created.Description, *got.Description)
+		assert.Equal(t, *created.Price, *got.Price)
+	})
+
+	t.Run("UpdateItem returns not found for unknown ID", func(t *testing.T) {
+		s := newStore(t)
+
+		_, err := s.UpdateItem(ctx, 404, Item{Name: v2p("after")}, 0)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = s.UpdateItem(ctx, 404, Item{Name: v2p("after")}, 1)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("UpdateItem rejects invalid fields", func(t *testing.T) {
+		s := newStore(t)
+		created, err := s.CreateItem(ctx, newItem("before"))
+		require.NoError(t, err)
+
+		_, err = s.UpdateItem(ctx, created.ID, Item{PriceCode: v2p("EURO")}, 0)
+		assert.ErrorIs(t, err, ErrInvalidItem)
+		_, err = s.UpdateItem(ctx, created.ID, Item{Price: v2p(int64(100))}, 0)
+		assert.ErrorIs(t, err, ErrInvalidItem)
+	})
+
+	t.Run("DeleteItem removes item", func(t *testing.T) {
+		s := newStore(t)
+		created, err := s.CreateItem(ctx, newItem("to delete"))
+		require.NoError(t, err)
+
+		require.NoError(t, s.DeleteItem(ctx, created.ID, 0))
+
+		_, err = s.GetItem(ctx, created.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, s.DeleteItem(ctx, created.ID, 0), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Version starts at 1 and is incremented on update", func(t *testing.T) {
+		s := newStore(t)
+		created, err := s.CreateItem(ctx, newItem("versioned"))
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), created.Version)
+
+		updated, err := s.UpdateItem(ctx, created.ID, Item{Name: v2p("v2")}, created.Version)
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), updated.Version)
+	})
+
+	t.Run("Conditional update and delete reject stale version", func(t *testing.T) {
+		s := newStore(t)
+		created, err := s.CreateItem(ctx, newItem("versioned"))
+		require.NoError(t, err)
+		_, err = s.UpdateItem(ctx, created.ID, Item{Name: v2p("v2")}, created.Version)
+		require.NoError(t, err)
+
+		_, err = s.UpdateItem(ctx, created.ID, Item{Name: v2p("stale")}, created.Version)
+		assert.ErrorIs(t, err, ErrVersionMismatch)
+		assert.ErrorIs(t, s.DeleteItem(ctx, created.ID, created.Version), ErrVersionMismatch)
+
+		got, err := s.GetItem(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "v2", *got.Name)
+		assert.NoError(t, s.DeleteItem(ctx, created.ID, got.Version))
+	})
+
+	t.Run("Deleted item is moved to trash and can be restored", func(t *testing.T) {
+		s := newStore(t)
+		kept, err := s.CreateItem(ctx, newItem("kept"))
+		require.NoError(t, err)
+		deleted, err := s.CreateItem(ctx, newItem("deleted"))
+		require.NoError(t, err)
+		require.NoError(t, s.DeleteItem(ctx, deleted.ID, 0))
+
+		live, err := s.GetItems(ctx, ItemQuery{PageSize: 10, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, live, 1)
+		assert.Equal(t, kept.ID, live[0].ID)
+		_, err = s.UpdateItem(ctx, deleted.ID, Item{Name: v2p("after")}, 0)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		trash, err := s.GetDeletedItems(ctx, 10, 1)
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		assert.Equal(t, deleted.ID, trash[0].ID)
+		assert.True(t, trash[0].DeletedAt.Valid)
+
+		restored, err := s.RestoreItem(ctx, deleted.ID)
+		require.NoError(t, err)
+		assert.False(t, restored.DeletedAt.Valid)
+		assert.Greater(t, restored.Version, deleted.Version)
+		got, err := s.GetItem(ctx, deleted.ID)
+		require.NoError(t, err)
+		assert.Equal(t, restored, got)
+		trash, err = s.GetDeletedItems(ctx, 10, 1)
+		require.NoError(t, err)
+		assert.Empty(t, trash)
+	})
+
+	t.Run("RestoreItem returns not found for items not in trash", func(t *testing.T) {
+		s := newStore(t)
+		created, err := s.CreateItem(ctx, newItem("live"))
+		require.NoError(t, err)
+
+		_, err = s.RestoreItem(ctx, created.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = s.RestoreItem(ctx, 404)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("PurgeDeletedItems removes only items deleted before given time", func(t *testing.T) {
+		s := newStore(t)
+		live, err := s.CreateItem(ctx, newItem("live"))
+		require.NoError(t, err)
+		deleted, err := s.CreateItem(ctx, newItem("deleted"))
+		require.NoError(t, err)
+		require.NoError(t, s.DeleteItem(ctx, deleted.ID, 0))
+
+		purged, err := s.PurgeDeletedItems(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, purged)
+		purged, err = s.PurgeDeletedItems(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		_, err = s.RestoreItem(ctx, deleted.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = s.GetItem(ctx, live.ID)
+		assert.NoError(t, err)
+		changes, err := s.GetItemHistory(ctx, deleted.ID)
+		require.NoError(t, err, "history is kept after purge")
+		require.Len(t, changes, 2)
+		assert.Equal(t, HistoryDeleted, changes[1].Operation)
+		asOf, err := s.GetItemAsOf(ctx, deleted.ID, changes[0].ChangedAt)
+		require.NoError(t, err)
+		assert.Equal(t, "deleted", *asOf.Name)
+	})
+
+	t.Run("Stock adjustments never drop below reserved quantity", func(t *testing.T) {
+		s := newStore(t)
+		item, err := s.CreateItem(ctx, newItem("stocked"))
+		require.NoError(t, err)
+		variant, err := s.CreateVariant(ctx, item.ID, Variant{SKU: "STOCK-M", Options: VariantOptions{"size": "M"}})
+		require.NoError(t, err)
+		key := StockKey{ItemID: item.ID, VariantID: variant.ID}
+
+		stock, err := s.GetStock(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, Stock{ItemID: item.ID, VariantID: variant.ID}, stock)
+		stock, err = s.AdjustStock(ctx, key, 5)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), stock.OnHand)
+		_, err = s.AdjustStock(ctx, StockKey{ItemID: item.ID}, 2)
+		require.NoError(t, err)
+		_, err = s.Reserve(ctx, key, 3, time.Hour)
+		require.NoError(t, err)
+
+		_, err = s.AdjustStock(ctx, key, -3)
+		assert.ErrorIs(t, err, ErrInsufficientStock)
+		_, err = s.AdjustStock(ctx, key, 0)
+		assert.ErrorIs(t, err, ErrInvalidStock)
+		_, err = s.AdjustStock(ctx, StockKey{ItemID: item.ID, VariantID: 404}, 1)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = s.GetStock(ctx, StockKey{ItemID: 404})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		stock, err = s.AdjustStock(ctx, key, -2)
+		require.NoError(t, err)
+		assert.Equal(t, Stock{ItemID: item.ID, VariantID: variant.ID, OnHand: 3, Reserved: 3}, stock)
+		assert.Zero(t, stock.Available())
+		levels, err := s.GetStockLevels(ctx, []uint{item.ID})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []Stock{{ItemID: item.ID, OnHand: 2}, stock}, levels)
+	})
+
+	t.Run("Reservations hold stock until committed, released or expired", func(t *testing.T) {
+		s := newStore(t)
+		item, err := s.CreateItem(ctx, newItem("reserved"))
+		require.NoError(t, err)
+		key := StockKey{ItemID: item.ID}
+		_, err = s.Reserve(ctx, key, 1, time.Hour)
+		assert.ErrorIs(t, err, ErrInsufficientStock)
+		_, err = s.AdjustStock(ctx, key, 10)
+		require.NoError(t, err)
+
+		committed, err := s.Reserve(ctx, key, 4, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, ReservationPending, committed.Status)
+		released, err := s.Reserve(ctx, key, 3, time.Hour)
+		require.NoError(t, err)
+		expired, err := s.Reserve(ctx, key, 2, time.Hour)
+		require.NoError(t, err)
+		_, err = s.Reserve(ctx, key, 2, time.Hour)
+		assert.ErrorIs(t, err, ErrInsufficientStock)
+
+		committed, err = s.CommitReservation(ctx, committed.ID)
+		require.NoError(t, err)
+		assert.Equal(t, ReservationCommitted, committed.Status)
+		_, err = s.ReleaseReservation(ctx, released.ID)
+		require.NoError(t, err)
+		_, err = s.CommitReservation(ctx, released.ID)
+		assert.ErrorIs(t, err, ErrReservationNotPending)
+		count, err := s.ReleaseExpiredReservations(ctx, time.Now().Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		_, err = s.ReleaseReservation(ctx, expired.ID)
+		assert.ErrorIs(t, err, ErrReservationNotPending)
+		expired, err = s.GetReservation(ctx, expired.ID)
+		require.NoError(t, err)
+		assert.Equal(t, ReservationExpired, expired.Status)
+		_, err = s.GetReservation(ctx, 404)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		stock, err := s.GetStock(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, Stock{ItemID: item.ID, OnHand: 6}, stock)
+	})
+
+	t.Run("Expired reservation cannot be committed", func(t *testing.T) {
+		s := newStore(t)
+		item, err := s.CreateItem(ctx, newItem("short"))
+		require.NoError(t, err)
+		key := StockKey{ItemID: item.ID}
+		_, err = s.AdjustStock(ctx, key, 1)
+		require.NoError(t, err)
+		reservation, err := s.Reserve(ctx, key, 1, time.Millisecond)
+		require.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+
+		_, err = s.CommitReservation(ctx, reservation.ID)
+		assert.ErrorIs(t, err, ErrReservationExpired)
+		stock, err := s.GetStock(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stock.Available())
+		reservation, err = s.Reserve(ctx, key, 1, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, ReservationPending, reservation.Status)
+	})
+
+	t.Run("Price lists are replaced as a whole", func(t *testing.T) {
+		s := newStore(t)
+		item, err := s.CreateItem(ctx, newItem("priced"))
+		require.NoError(t, err)
+		other, err := s.CreateItem(ctx, newItem("other"))
+		require.NoError(t, err)
+
+		prices, err := s.SetItemPrices(ctx, item.ID, []ItemPrice{{Currency: "USD", Price: 1299}, {Currency: "PLN", Price: 4999}})
+		require.NoError(t, err)
+		assert.Equal(t, []ItemPrice{{ItemID: item.ID, Currency: "PLN", Price: 4999}, {ItemID: item.ID, Currency: "USD", Price: 1299}}, prices)
+		_, err = s.SetItemPrices(ctx, other.ID, []ItemPrice{{Currency: "GBP", Price: 999}})
+		require.NoError(t, err)
+		_, err = s.SetItemPrices(ctx, item.ID, []ItemPrice{{Currency: "USD", Price: 1399}})
+		require.NoError(t, err)
+		_, err = s.SetItemPrices(ctx, 404, nil)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		prices, err = s.GetItemPrices(ctx, []uint{other.ID, item.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []ItemPrice{{ItemID: item.ID, Currency: "USD", Price: 1399}, {ItemID: other.ID, Currency: "GBP", Price: 999}}, prices)
+		stored, err := s.GetItem(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), stored.Version)
+	})
+
+	t.Run("Exchange rates are replaced as a whole", func(t *testing.T) {
+		s := newStore(t)
+		rates, err := s.GetExchangeRates(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, rates.Base, "no rates were set")
+
+		updatedAt := time.Date(2022, 6, 1, 16, 0, 0, 0, time.UTC)
+		first, err := money.ParseRates("EUR", map[string]string{"USD": "1.07", "JPY": "140"}, "file:rates.json", updatedAt)
+		require.NoError(t, err)
+		require.NoError(t, s.SetExchangeRates(ctx, first))
+		second, err := money.ParseRates("USD", map[string]string{"PLN": "4.299065420560747"}, "admin", updatedAt.Add(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, s.SetExchangeRates(ctx, second))
+
+		rates, err = s.GetExchangeRates(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "USD", rates.Base)
+		assert.Equal(t, map[string]string{"PLN": "4.299065420560747"}, rates.Strings(), "rates are exact")
+		assert.Equal(t, "admin", rates.Source)
+		assert.True(t, updatedAt.Add(time.Hour).Equal(rates.UpdatedAt))
+	})
+
+	t.Run("Scheduled prices are active within their window", func(t *testing.T) {
+		s := newStore(t)
+		item, err := s.CreateItem(ctx, newItem("on sale"))
+		require.NoError(t, err)
+		deleted, err := s.CreateItem(ctx, newItem("deleted"))
+		require.NoError(t, err)
+		now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+		schedule := func(itemID uint, price int64, startsAt time.Time, endsAt *time.Time) uint {
+			scheduled, err := s.CreateScheduledPrice(ctx, itemID,
+				ScheduledPrice{Currency: "EUR", Price: price, StartsAt: startsAt, EndsAt: endsAt})
+			require.NoError(t, err)
+			return scheduled.ID
+		}
+		// ids returns IDs of scheduled prices, timestamps read back from postgres are not in UTC
+		ids := func(prices []ScheduledPrice, err error) []uint {
+			require.NoError(t, err)
+			scheduled := make([]uint, 0, len(prices))
+			for _, price := range prices {
+				scheduled = append(scheduled, price.ID)
+			}
+			return scheduled
+		}
+		past := schedule(item.ID, 900, now.Add(-48*time.Hour), v2p(now.Add(-24*time.Hour)))
+		active := schedule(item.ID, 800, now.Add(-time.Hour), v2p(now.Add(time.Hour)))
+		open := schedule(item.ID, 700, now.Add(-2*time.Hour), nil)
+		upcoming := schedule(item.ID, 600, now.Add(24*time.Hour), nil)
+		schedule(deleted.ID, 500, now.Add(time.Hour), nil)
+		require.NoError(t, s.DeleteItem(ctx, deleted.ID, 0))
+
+		assert.Equal(t, []uint{past, open, active, upcoming}, ids(s.GetScheduledPrices(ctx, item.ID)))
+		assert.Equal(t, []uint{open, active}, ids(s.GetActivePrices(ctx, []uint{item.ID}, now)))
+		assert.Equal(t, []uint{open}, ids(s.GetActivePrices(ctx, []uint{item.ID}, now.Add(2*time.Hour))),
+			"open-ended price is in effect again once later price ends")
+		assert.Equal(t, []uint{active, upcoming}, ids(s.GetUpcomingPrices(ctx, now, 10, 1)))
+		_, err = s.GetUpcomingPrices(ctx, now, 0, 1)
+		assert.ErrorIs(t, err, ErrInvalidPageParams)
+
+		require.NoError(t, s.DeleteScheduledPrice(ctx, item.ID, active))
+		assert.ErrorIs(t, s.DeleteScheduledPrice(ctx, item.ID, active), gorm.ErrRecordNotFound)
+		_, err = s.GetScheduledPrices(ctx, deleted.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		stored, err := s.GetItem(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(6), stored.Version)
+	})
+
+	t.Run("Item operations are applied atomically", func(t *testing.T) {
+		s := newStore(t)
+		kept, err := s.CreateItem(ctx, newItem("kept"))
+		require.NoError(t, err)
+		removed, err := s.CreateItem(ctx, newItem("removed"))
+		require.NoError(t, err)
+
+		_, err = s.ApplyItemOperations(ctx, []ItemOperation{
+			{Type: OperationCreate, Item: newItem("rolled back")},
+			{Type: OperationUpdate, ID: kept.ID, Item: Item{Name: v2p("renamed")}},
+			{Type: OperationDelete, ID: removed.ID, IfVersion: removed.Version + 1},
+		})
+		var opErr OperationError
+		require.ErrorAs(t, err, &opErr)
+		assert.Equal(t, 2, opErr.Index)
+		assert.ErrorIs(t, err, ErrVersionMismatch)
+		items, err := s.GetItems(ctx, ItemQuery{PageSize: 10, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, items, 2, "created item is rolled back")
+		assert.Equal(t, "kept", *items[0].Name, "update is rolled back")
+		changes, err := s.GetItemHistory(ctx, kept.ID)
+		require.NoError(t, err)
+		assert.Len(t, changes, 1)
+		n, err := s.RelayEvents(ctx, 10, func(context.Context, Event) error { return nil })
+		require.NoError(t, err)
+		assert.Equal(t, 2, n, "events of rolled back changes are not emitted")
+
+		applied, err := s.ApplyItemOperations(ctx, []ItemOperation{
+			{Type: OperationCreate, Item: newItem("created")},
+			{Type: OperationUpdate, ID: kept.ID, Item: Item{Name: v2p("renamed")}, IfVersion: kept.Version},
+			{Type: OperationDelete, ID: removed.ID},
+		})
+		require.NoError(t, err)
+		require.Len(t, applied, 3)
+		assert.Greater(t, applied[0].ID, removed.ID)
+		assert.Equal(t, "renamed", *applied[1].Name)
+		assert.Equal(t, uint(2), applied[1].Version)
+		assert.True(t, applied[2].DeletedAt.Valid)
+		_, err = s.GetItem(ctx, removed.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Changes of items are recorded in history", func(t *testing.T) {
+		s := newStore(t)
+		beforeCreate := time.Now()
+		item, err := s.CreateItem(WithActor(ctx, "alice"), newItem("original"))
+		require.NoError(t, err)
+		afterCreate := time.Now()
+		_, err = s.UpdateItem(WithActor(ctx, "bob"), item.ID, Item{Name: v2p("renamed")}, 0)
+		require.NoError(t, err)
+		require.NoError(t, s.DeleteItem(ctx, item.ID, 0))
+		_, err = s.RestoreItem(ctx, item.ID)
+		require.NoError(t, err)
+
+		changes, err := s.GetItemHistory(ctx, item.ID)
+		require.NoError(t, err)
+		require.Len(t, changes, 4)
+		type entry struct {
+			version   uint
+			operation HistoryOperation
+			actor     string
+			name      string
+		}
+		var entries []entry
+		for _, change := range changes {
+			entries = append(entries, entry{change.Version, change.Operation, change.Actor, *change.Snapshot.Name})
+		}
+		assert.Equal(t, []entry{
+			{1, HistoryCreated, "alice", "original"},
+			{2, HistoryUpdated, "bob", "renamed"},
+			{2, HistoryDeleted, "system", "renamed"},
+			{3, HistoryRestored, "system", "renamed"},
+		}, entries)
+
+		asOf, err := s.GetItemAsOf(ctx, item.ID, afterCreate)
+		require.NoError(t, err)
+		assert.Equal(t, "original", *asOf.Name)
+		assert.Equal(t, uint(1), asOf.Version)
+		_, err = s.GetItemAsOf(ctx, item.ID, beforeCreate.Add(-time.Second))
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		require.NoError(t, s.DeleteItem(ctx, item.ID, 0))
+		_, err = s.GetItemAsOf(ctx, item.ID, time.Now())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "deleted item did not exist")
+		_, err = s.GetItemHistory(ctx, item.ID+100)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Revisions of item parts are recorded in history", func(t *testing.T) {
+		s := newStore(t)
+		item, err := s.CreateItem(ctx, newItem("shirt"))
+		require.NoError(t, err)
+		category, err := s.CreateCategory(ctx, Category{Name: v2p("Shirts")})
+		require.NoError(t, err)
+		_, err = s.SetItemPrices(WithActor(ctx, "alice"), item.ID, []ItemPrice{{Currency: "USD", Price: 1299}})
+		require.NoError(t, err)
+		_, err = s.CreateScheduledPrice(ctx, item.ID, ScheduledPrice{Currency: "EUR", Price: 999, StartsAt: time.Now()})
+		require.NoError(t, err)
+		_, err = s.CreateVariant(ctx, item.ID, Variant{SKU: "SHIRT-M", Options: VariantOptions{"size": "M"}})
+		require.NoError(t, err)
+		require.NoError(t, s.SetItemCategories(ctx, item.ID, []uint{category.ID}))
+		assert.ErrorIs(t, s.SetItemCategories(ctx, item.ID, []uint{category.ID + 100}), ErrInvalidCategory)
+		_, err = s.CreateItemImage(ctx, item.ID, ItemImage{BlobKey: "images/aa/a", ContentType: "image/png", Width: 10,
+			Height: 10, Size: 100})
+		require.NoError(t, err)
+
+		changes, err := s.GetItemHistory(ctx, item.ID)
+		require.NoError(t, err)
+		type entry struct {
+			version   uint
+			operation HistoryOperation
+			actor     string
+			part      ItemPart
+		}
+		var entries []entry
+		for _, change := range changes {
+			entries = append(entries, entry{change.Version, change.Operation, change.Actor, change.Snapshot.Part})
+			assert.Equal(t, "shirt", *change.Snapshot.Name)
+		}
+		assert.Equal(t, []entry{
+			{1, HistoryCreated, "system", ""},
+			{2, HistoryRevised, "alice", PartPrices},
+			{3, HistoryRevised, "system", PartScheduledPrices},
+			{4, HistoryRevised, "system", PartVariants},
+			{5, HistoryRevised, "system", PartCategories},
+			{6, HistoryRevised, "system", PartImages},
+		}, entries, "versions have no gaps")
+		stored, err := s.GetItem(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(6), stored.Version)
+
+		var published []Event
+		_, err = s.RelayEvents(ctx, 10, func(_ context.Context, event Event) error {
+			published = append(published, event)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, published, 6)
+		assert.Equal(t, ItemRevised, published[1].Type)
+		assert.Equal(t, uint(2), published[1].Version)
+		assert.Equal(t, PartPrices, published[1].Item.Part)
+	})
+
+	t.Run("Events of item changes are relayed in order", func(t *testing.T) {
+		s := newStore(t)
+		item, err := s.CreateItem(ctx, newItem("original"))
+		require.NoError(t, err)
+		_, err = s.UpdateItem(ctx, item.ID, Item{Name: v2p("renamed")}, 0)
+		require.NoError(t, err)
+		require.NoError(t, s.DeleteItem(ctx, item.ID, 0))
+		type relayed struct {
+			eventType EventType
+			version   uint
+			name      string
+		}
+		var published []relayed
+		var positions []uint64
+		failOn := ItemDeleted
+		publish := func(_ context.Context, event Event) error {
+			if event.Type == failOn {
+				return errors.New("publisher unavailable")
+			}
+			published = append(published, relayed{event.Type, event.Version, *event.Item.Name})
+			positions = append(positions, event.Position)
+			return nil
+		}
+
+		replayed, err := s.GetEvents(ctx, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, replayed, "events are replayed once published")
+		n, err := s.RelayEvents(ctx, 10, publish)
+		assert.Error(t, err)
+		assert.Equal(t, 2, n)
+		failOn = ""
+		n, err = s.RelayEvents(ctx, 10, publish)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		n, err = s.RelayEvents(ctx, 10, publish)
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+
+		assert.Equal(t, []relayed{
+			{ItemCreated, 1, "original"},
+			{ItemUpdated, 2, "renamed"},
+			{ItemDeleted, 2, "renamed"},
+		}, published)
+		assert.IsIncreasing(t, positions)
+
+		replayed, err = s.GetEvents(ctx, positions[0], 1)
+		require.NoError(t, err)
+		require.Len(t, replayed, 1)
+		assert.Equal(t, positions[1], replayed[0].Position)
+		assert.Equal(t, ItemUpdated, replayed[0].Type)
+		replayed, err = s.GetEvents(ctx, positions[2], 10)
+		require.NoError(t, err)
+		assert.Empty(t, replayed)
+	})
+
+	t.Run("Published events are pruned", func(t *testing.T) {
+		s := newStore(t)
+		item, err := s.CreateItem(ctx, newItem("original"))
+		require.NoError(t, err)
+		_, err = s.UpdateItem(ctx, item.ID, Item{Name: v2p("renamed")}, 0)
+		require.NoError(t, err)
+		var published []Event
+		publish := func(_ context.Context, event Event) error {
+			published = append(published, event)
+			return nil
+		}
+		_, err = s.RelayEvents(ctx, 10, publish)
+		require.NoError(t, err)
+		require.NoError(t, s.DeleteItem(ctx, item.ID, 0))
+
+		pruned, err := s.PruneEvents(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, pruned, "recently published events are kept")
+		pruned, err = s.PruneEvents(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), pruned, "pending event is kept")
+		replayed, err := s.GetEvents(ctx, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, replayed)
+
+		n, err := s.RelayEvents(ctx, 10, publish)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		require.Len(t, published, 3)
+		assert.Equal(t, ItemDeleted, published[2].Type)
+		assert.Greater(t, published[2].ID, published[1].ID)
+		assert.Greater(t, published[2].Position, published[1].Position)
+		item, err = s.CreateItem(ctx, newItem("another"))
+		require.NoError(t, err)
+		_, err = s.RelayEvents(ctx, 10, publish)
+		require.NoError(t, err)
+		require.Len(t, published, 4)
+		assert.Greater(t, published[3].ID, published[2].ID, "IDs are not reused after pruning")
+	})
+
+	t.Run("Webhook deliveries are retried until delivered or dead", func(t *testing.T) {
+		s := newStore(t)
+		secret := "0123456789abcdef"
+		all, err := s.CreateWebhook(ctx, Webhook{URL: "https://example.com/all", Secret: secret,
+			EventTypes: EventTypes{ItemCreated, ItemUpdated}})
+		require.NoError(t, err)
+		deletes, err := s.CreateWebhook(ctx, Webhook{URL: "https://example.com/deletes", Secret: secret,
+			EventTypes: EventTypes{ItemDeleted}})
+		require.NoError(t, err)
+		_, err = s.CreateWebhook(ctx, Webhook{URL: "ftp://example.com", Secret: secret, EventTypes: EventTypes{ItemCreated}})
+		assert.ErrorIs(t, err, ErrInvalidWebhook)
+		_, err = s.CreateWebhook(ctx, Webhook{URL: "https://example.com", Secret: secret, EventTypes: EventTypes{"Unknown"}})
+		assert.ErrorIs(t, err, ErrInvalidWebhook)
+		webhooks, err := s.GetWebhooks(ctx)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 2)
+		assert.Equal(t, EventTypes{ItemCreated, ItemUpdated}, webhooks[0].EventTypes)
+
+		created, err := s.CreateDeliveries(ctx, Event{ID: 1, Type: ItemCreated}, `{"id":1}`)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), created)
+		created, err = s.CreateDeliveries(ctx, Event{ID: 1, Type: ItemCreated}, `{"id":1}`)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), created, "event is delivered to a webhook only once")
+
+		now := time.Now()
+		claimed, err := s.ClaimDueDeliveries(ctx, now, time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, `{"id":1}`, claimed[0].Payload)
+		assert.Equal(t, all.ID, claimed[0].WebhookID)
+		claimedAgain, err := s.ClaimDueDeliveries(ctx, now, time.Minute, 10)
+		require.NoError(t, err)
+		assert.Empty(t, claimedAgain, "claimed delivery is leased")
+
+		retryAt := now.Add(time.Second)
+		delivery, err := s.RecordDeliveryAttempt(ctx, claimed[0].ID,
+			DeliveryAttempt{At: now, StatusCode: v2p(500), Error: v2p("server error"), RetryAt: &retryAt})
+		require.NoError(t, err)
+		assert.Equal(t, DeliveryPending, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, 500, *delivery.LastStatusCode)
+		delivery, err = s.RecordDeliveryAttempt(ctx, claimed[0].ID, DeliveryAttempt{At: now, Error: v2p("timeout")})
+		require.NoError(t, err)
+		assert.Equal(t, DeliveryDead, delivery.Status)
+		assert.Equal(t, 2, delivery.Attempts)
+		_, err = s.RecordDeliveryAttempt(ctx, claimed[0].ID, DeliveryAttempt{At: now, Delivered: true})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "only pending deliveries are attempted")
+
+		dead := DeliveryDead
+		letters, err := s.GetDeliveries(ctx, DeliveryQuery{Status: &dead, PageSize: 10, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, letters, 1)
+		_, err = s.RedeliverDelivery(ctx, deletes.ID, letters[0].ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		delivery, err = s.RedeliverDelivery(ctx, all.ID, letters[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, DeliveryPending, delivery.Status)
+		assert.Equal(t, 0, delivery.Attempts)
+		_, err = s.RedeliverDelivery(ctx, all.ID, letters[0].ID)
+		assert.ErrorIs(t, err, ErrDeliveryPending)
+		delivery, err = s.RecordDeliveryAttempt(ctx, letters[0].ID, DeliveryAttempt{At: now, StatusCode: v2p(204), Delivered: true})
+		require.NoError(t, err)
+		assert.Equal(t, DeliveryDelivered, delivery.Status)
+		assert.NotNil(t, delivery.DeliveredAt)
+
+		deliveries, err := s.GetDeliveries(ctx, DeliveryQuery{WebhookID: &deletes.ID, PageSize: 10, Page: 1})
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+		require.NoError(t, s.DeleteWebhook(ctx, all.ID))
+		_, err = s.GetDeliveries(ctx, DeliveryQuery{WebhookID: &all.ID, PageSize: 10, Page: 1})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, s.DeleteWebhook(ctx, all.ID), gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Items are upserted by external ID", func(t *testing.T) {
+		s := newStore(t)
+		item := newItem("first")
+		item.ExternalID = v2p("SUP-1")
+
+		created, outcome, err := s.UpsertItemByExternalID(ctx, item)
+		require.NoError(t, err)
+		assert.Equal(t, UpsertCreated, outcome)
+		assert.Equal(t, uint(1), created.Version)
+		unchanged, outcome, err := s.UpsertItemByExternalID(ctx, item)
+		require.NoError(t, err)
+		assert.Equal(t, UpsertUnchanged, outcome)
+		assert.Equal(t, created, unchanged)
+		item.Name = v2p("renamed")
+		updated, outcome, err := s.UpsertItemByExternalID(ctx, item)
+		require.NoError(t, err)
+		assert.Equal(t, UpsertUpdated, outcome)
+		assert.Equal(t, created.ID, updated.ID)
+		assert.Equal(t, "renamed", *updated.Name)
+		assert.Equal(t, uint(2), updated.Version)
+		history, err := s.GetItemHistory(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Len(t, history, 2, "unchanged item is not recorded")
+		assert.Equal(t, "SUP-1", *history[1].Snapshot.ExternalID)
+		kept, outcome, err := s.UpsertItemByExternalID(ctx, Item{ExternalID: item.ExternalID, Name: item.Name,
+			Price: item.Price, PriceCode: item.PriceCode})
+		require.NoError(t, err)
+		assert.Equal(t, UpsertUnchanged, outcome, "description is kept when not provided")
+		assert.Equal(t, *item.Description, *kept.Description)
+		described, _, err := s.UpsertItemByExternalID(ctx, Item{ExternalID: v2p("SUP-3"), Name: item.Name,
+			Price: item.Price, PriceCode: item.PriceCode})
+		require.NoError(t, err)
+		assert.Equal(t, "", *described.Description)
+
+		_, _, err = s.UpsertItemByExternalID(ctx, newItem("no key"))
+		assert.ErrorIs(t, err, ErrInvalidItem)
+		_, err = s.CreateItem(ctx, item)
+		assert.ErrorIs(t, err, ErrExternalIDConflict)
+
+		require.NoError(t, s.DeleteItem(ctx, created.ID, 0))
+		recreated, outcome, err := s.UpsertItemByExternalID(ctx, item)
+		require.NoError(t, err)
+		assert.Equal(t, UpsertCreated, outcome, "deleted items don't hold their external ID")
+		assert.NotEqual(t, created.ID, recreated.ID)
+		_, err = s.RestoreItem(ctx, created.ID)
+		assert.ErrorIs(t, err, ErrExternalIDConflict)
+
+		found, err := s.GetItemByExternalID(ctx, "SUP-1")
+		require.NoError(t, err)
+		assert.Equal(t, recreated, found, "live item is found")
+		_, err = s.GetItemByExternalID(ctx, "SUP-2")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Import jobs are claimed until finished", func(t *testing.T) {
+		s := newStore(t)
+		now := time.Now().Add(time.Second)
+
+		_, err := s.CreateImportJob(ctx, "xlsx", []byte("data"))
+		assert.ErrorIs(t, err, ErrInvalidImport)
+		_, err = s.CreateImportJob(ctx, ImportCSV, nil)
+		assert.ErrorIs(t, err, ErrInvalidImport)
+		job, err := s.CreateImportJob(ctx, ImportCSV, []byte("externalId,name"))
+		require.NoError(t, err)
+		assert.Equal(t, ImportQueued, job.Status)
+
+		claimed, data, err := s.ClaimImportJob(ctx, now, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, job.ID, claimed.ID)
+		assert.Equal(t, ImportRunning, claimed.Status)
+		assert.Equal(t, 1, claimed.Claims)
+		assert.Equal(t, "externalId,name", string(data))
+		_, _, err = s.ClaimImportJob(ctx, now, time.Minute)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "claimed job is leased")
+
+		err = s.RecordImportProgress(ctx, job.ID, claimed.Claims, ImportProgress{
+			ImportCounts: ImportCounts{ProcessedRows: 2, CreatedItems: 1, RejectedRows: 1},
+			Rejections:   []ImportRejection{{Row: 2, Field: v2p("price"), Message: "invalid price"}},
+			Status:       ImportRunning,
+			LeaseUntil:   now.Add(time.Minute),
+		})
+		require.NoError(t, err)
+		// lease of the job ends, e.g. as its worker stopped, so another one takes it over
+		reclaimed, _, err := s.ClaimImportJob(ctx, now.Add(time.Minute), time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 2, reclaimed.Claims)
+		assert.Equal(t, 2, reclaimed.ProcessedRows, "job is resumed")
+		err = s.RecordImportProgress(ctx, job.ID, claimed.Claims, ImportProgress{Status: ImportCompleted})
+		assert.ErrorIs(t, err, ErrImportJobLost)
+		err = s.RecordImportProgress(ctx, job.ID, reclaimed.Claims, ImportProgress{
+			ImportCounts: ImportCounts{ProcessedRows: 3, CreatedItems: 1, RejectedRows: 2},
+			Rejections:   []ImportRejection{{Row: 3, ExternalID: v2p("SUP-3"), Message: "name is required"}},
+			Status:       ImportCompleted,
+		})
+		require.NoError(t, err)
+
+		finished, err := s.GetImportJob(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, ImportCompleted, finished.Status)
+		assert.Equal(t, ImportCounts{ProcessedRows: 3, CreatedItems: 1, RejectedRows: 2}, finished.ImportCounts)
+		assert.NotNil(t, finished.StartedAt)
+		assert.NotNil(t, finished.FinishedAt)
+		_, _, err = s.ClaimImportJob(ctx, now.Add(time.Hour), time.Minute)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "finished job is not claimed")
+
+		rejections, err := s.GetImportRejections(ctx, job.ID, 10, 1)
+		require.NoError(t, err)
+		require.Len(t, rejections, 2)
+		assert.Equal(t, 2, rejections[0].Row)
+		assert.Equal(t, "price", *rejections[0].Field)
+		assert.Equal(t, "SUP-3", *rejections[1].ExternalID)
+		rejections, err = s.GetImportRejections(ctx, job.ID, 1, 2)
+		require.NoError(t, err)
+		assert.Len(t, rejections, 1)
+		_, err = s.GetImportRejections(ctx, job.ID+1, 10, 1)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = s.GetImportJob(ctx, job.ID+1)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Items are exported in batches from the cursor", func(t *testing.T) {
+		s := newStore(t)
+		var ids []uint
+		for _, name := range []string{"shirt", "hat", "red shirt", "blue shirt", "green shirt"} {
+			created, err := s.CreateItem(ctx, newItem(name))
+			require.NoError(t, err)
+			ids = append(ids, created.ID)
+		}
+		require.NoError(t, s.DeleteItem(ctx, ids[3], 0))
+		export := func(query ExportQuery) ([][]uint, error) {
+			var batches [][]uint
+			err := s.ExportItems(ctx, query, func(items []Item) error {
+				var batch []uint
+				for _, item := range items {
+					batch = append(batch, item.ID)
+				}
+				batches = append(batches, batch)
+				return nil
+			})
+			return batches, err
+		}
+
+		batches, err := export(ExportQuery{ItemFilter: ItemFilter{Name: v2p("shirt")}, BatchSize: 2})
+		require.NoError(t, err)
+		assert.Equal(t, [][]uint{{ids[0], ids[2]}, {ids[4]}}, batches, "deleted items are not exported")
+
+		batches, err = export(ExportQuery{AfterID: ids[2], BatchSize: 2})
+		require.NoError(t, err)
+		assert.Equal(t, [][]uint{{ids[4]}}, batches)
+
+		_, err = export(ExportQuery{ItemFilter: ItemFilter{MinPrice: v2p("cheap")}, BatchSize: 2})
+		assert.Error(t, err)
+	})
+
+	t.Run("Images are ordered and their blobs queued for deletion", func(t *testing.T) {
+		s := newStore(t)
+		item, err := s.CreateItem(ctx, newItem("shirt"))
+		require.NoError(t, err)
+		other, err := s.CreateItem(ctx, newItem("hat"))
+		require.NoError(t, err)
+		var ids []uint
+		for _, key := range []string{"images/aa/a", "images/bb/b", "images/cc/c"} {
+			image, err := s.CreateItemImage(ctx, item.ID, ItemImage{BlobKey: key, ContentType: "image/png", Width: 10,
+				Height: 10, Size: 100})
+			require.NoError(t, err)
+			ids = append(ids, image.ID)
+		}
+		_, err = s.CreateItemImage(ctx, other.ID, ItemImage{BlobKey: "images/dd/d", ContentType: "image/jpeg",
+			Width: 10, Height: 10, Size: 100})
+		require.NoError(t, err)
+		positions := func() map[uint]int {
+			images, err := s.GetItemImages(ctx, []uint{item.ID})
+			require.NoError(t, err)
+			positions := map[uint]int{}
+			for i, image := range images[item.ID] {
+				assert.Equal(t, i, image.Position, "images are ordered by position")
+				positions[image.ID] = image.Position
+			}
+			return positions
+		}
+		assert.Equal(t, map[uint]int{ids[0]: 0, ids[1]: 1, ids[2]: 2}, positions())
+
+		moved, err := s.UpdateItemImage(ctx, item.ID, ids[2], v2p("Back"), 0)
+		require.NoError(t, err)
+		assert.Equal(t, "Back", *moved.AltText)
+		assert.Equal(t, map[uint]int{ids[2]: 0, ids[0]: 1, ids[1]: 2}, positions())
+		_, err = s.UpdateItemImage(ctx, other.ID, ids[0], nil, 0)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "image of another item")
+
+		require.NoError(t, s.DeleteItemImage(ctx, item.ID, ids[0]))
+		assert.Equal(t, map[uint]int{ids[2]: 0, ids[1]: 1}, positions())
+		_, err = s.GetItemImage(ctx, item.ID, ids[0])
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		image, err := s.GetItemImage(ctx, item.ID, ids[1])
+		require.NoError(t, err)
+		assert.Equal(t, "images/bb/b", image.BlobKey)
+		reloaded, err := s.GetItem(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, item.Version+5, reloaded.Version, "every change of images changes the item")
+
+		require.NoError(t, s.DeleteItem(ctx, other.ID, 0))
+		_, err = s.PurgeDeletedItems(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		deletions, err := s.GetMediaDeletions(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []MediaDeletion{{BlobKey: "images/aa/a"}, {BlobKey: "images/dd/d"}}, deletions)
+		require.NoError(t, s.CompleteMediaDeletion(ctx, "images/aa/a"))
+		deletions, err = s.GetMediaDeletions(ctx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []MediaDeletion{{BlobKey: "images/dd/d"}}, deletions)
+	})
+
+	t.Run("Canceled context is respected", func(t *testing.T) {
+		s := newStore(t)
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := s.GetItems(canceled, ItemQuery{PageSize: 10, Page: 1})
+		assert.Error(t, err)
+	})
+}
+
+func v2p[V any](val V) *V {
+	return &val
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursor points at the last item of already listed page. It holds the value of sort key, so listing can be continued
+// with keyset condition instead of offset, which is not affected by items inserted or deleted during the scan.
+type cursor struct {
+	Sort  string          `json:"s"`
+	Value json.RawMessage `json:"v,omitempty"`
+	ID    uint            `json:"id"`
+}
+
+// priceKey is the value of price sort key, as price is compared along with the exponent of its currency
+type priceKey struct {
+	Minor    int64  `json:"m"`
+	Currency string `json:"c"`
+}
+
+// NextCursor returns opaque cursor pointing at the page following provided items, which were listed with query.
+// Empty string is returned when items don't fill the whole page, meaning there is nothing more to list.
+func NextCursor(query ItemQuery, items []Item) string {
+	if len(items) == 0 || len(items) < query.PageSize {
+		return ""
+	}
+	order, err := ParseSort(query.Sort)
+	if err != nil {
+		return ""
+	}
+	last := items[len(items)-1]
+	c := cursor{Sort: query.Sort, ID: last.ID}
+	switch order.Field {
+	case "name":
+		c.Value, _ = json.Marshal(derefOrZero(last.Name))
+	case "price":
+		c.Value, _ = json.Marshal(priceKey{Minor: derefOrZero(last.Price), Currency: derefOrZero(last.PriceCode)})
+	case "priceCode":
+		c.Value, _ = json.Marshal(derefOrZero(last.PriceCode))
+	}
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor parses cursor and checks it was issued for the same sort order. Returned item has ID and sort field set.
+func decodeCursor(encoded string, order SortOrder, sort string) (Item, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Item{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return Item{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if c.Sort != sort {
+		return Item{}, fmt.Errorf("%w: cursor was issued for sort %q", ErrInvalidCursor, c.Sort)
+	}
+
+	item := Item{ID: c.ID}
+	switch order.Field {
+	case "name":
+		err = json.Unmarshal(c.Value, &item.Name)
+	case "price":
+		var key priceKey
+		err = json.Unmarshal(c.Value, &key)
+		item.Price, item.PriceCode = &key.Minor, &key.Currency
+	case "priceCode":
+		err = json.Unmarshal(c.Value, &item.PriceCode)
+	}
+	if err != nil {
+		return Item{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return item, nil
+}
+
+// after narrows down db query to items listed after the one cursor points at
+func (o SortOrder) after(last Item) func(db *gorm.DB) *gorm.DB {
+	op := ">"
+	if o.Desc {
+		op = "<"
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		var value interface{}
+		placeholder := "?"
+		switch o.Field {
+		case "name":
+			value = derefOrZero(last.Name)
+		case "price":
+			value = money.Amount{Minor: derefOrZero(last.Price), Currency: derefOrZero(last.PriceCode)}.String()
+			placeholder = "CAST(? AS numeric)"
+		case "priceCode":
+			value = derefOrZero(last.PriceCode)
+		default:
+			return db.Where("id "+op+" ?", last.ID)
+		}
+		return db.Where("("+sortableFields[o.Field].Name+", id) "+op+" ("+placeholder+", ?)", value, last.ID)
+	}
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gorm.io/gorm"
+	"sort"
+)
+
+// ExportQuery selects items streamed by export. Items are exported in order of their IDs.
+type ExportQuery struct {
+	ItemFilter
+	// AfterID resumes interrupted export after the item with ID
+	AfterID uint
+	// BatchSize is number of items fetched at once
+	BatchSize int
+}
+
+// validate checks batch size and filter of the query
+func (q ExportQuery) validate() error {
+	if q.BatchSize < 1 {
+		return ErrInvalidPageParams
+	}
+	return q.ItemFilter.validate()
+}
+
+// ExportItems passes all items matching the query to export in batches. Items are read from a server-side cursor
+// within a read-only transaction, so the export is a consistent snapshot of the catalog and only a single batch is
+// held in memory. Query timeout applies to fetching every batch, not to the whole export.
+func (s *CatalogStore) ExportItems(ctx context.Context, query ExportQuery, export func(items []Item) error) error {
+	if err := query.validate(); err != nil {
+		return err
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		items := tx.Model(&Item{}).Scopes(query.ItemFilter.scope).Where("id > ?", query.AfterID).Order("id")
+		if err := tx.Exec("DECLARE items_export NO SCROLL CURSOR FOR ?", items).Error; err != nil {
+			return err
+		}
+		for {
+			batch, err := s.fetchExported(ctx, tx, query.BatchSize)
+			if err != nil || len(batch) == 0 {
+				return err
+			}
+			if err := export(batch); err != nil {
+				return err
+			}
+			if len(batch) < query.BatchSize {
+				return nil
+			}
+		}
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("error while exporting items: %w", err)
+	}
+	return nil
+}
+
+// fetchExported fetches the next batch of exported items from the cursor declared within transaction tx
+func (s *CatalogStore) fetchExported(ctx context.Context, tx *gorm.DB, size int) ([]Item, error) {
+	if s.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.queryTimeout)
+		defer cancel()
+	}
+	var batch []Item
+	// FETCH doesn't accept bind parameters, so the count is formatted into the statement
+	err := tx.WithContext(ctx).Raw(fmt.Sprintf("FETCH FORWARD %d FROM items_export", size)).Scan(&batch).Error
+	return batch, err
+}
+
+// ExportItems passes all items matching the query to export in batches. Lock is held only while the next batch is
+// collected, so items changed during the export are exported in their state at that time.
+func (s *MemoryStore) ExportItems(ctx context.Context, query ExportQuery, export func(items []Item) error) error {
+	if err := query.validate(); err != nil {
+		return err
+	}
+	afterID := query.AfterID
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("error while exporting items: %w", err)
+		}
+		batch := s.exportBatch(query, afterID)
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := export(batch); err != nil {
+			return fmt.Errorf("error while exporting items: %w", err)
+		}
+		if len(batch) < query.BatchSize {
+			return nil
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+}
+
+// exportBatch returns the next batch of items matching the query with IDs greater than afterID
+func (s *MemoryStore) exportBatch(query ExportQuery, afterID uint) []Item {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var items []Item
+	for _, item := range s.items {
+		if item.ID > afterID && !item.DeletedAt.Valid && query.ItemFilter.matches(item) &&
+			s.inCategory(query.ItemFilter, item.ID) {
+			items = append(items, cloneItem(item))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	if len(items) > query.BatchSize {
+		items = items[:query.BatchSize]
+	}
+	return items
+}
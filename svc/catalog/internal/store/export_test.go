@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const (
+	declareExport = `DECLARE items_export NO SCROLL CURSOR FOR SELECT \* FROM "items" WHERE ` +
+		`id > \$1 AND UPPER\(price_code\) = \$2 AND "items"\."deleted_at" IS NULL ORDER BY id`
+	fetchExport = `FETCH FORWARD 2 FROM items_export`
+)
+
+func TestExportItems(t *testing.T) {
+	priceCode := "eur"
+	columns := []string{"id", "name", "price_minor", "price_code"}
+	tests := []struct {
+		name        string
+		batches     [][]uint
+		exportErr   error
+		expectedIDs []uint
+		expectedErr string
+	}{
+		{
+			name:        "Successful - batches are fetched until the cursor is exhausted",
+			batches:     [][]uint{{11, 12}, {13}},
+			expectedIDs: []uint{11, 12, 13},
+		},
+		{
+			name:        "Successful - full batch is followed by an empty one",
+			batches:     [][]uint{{11, 12}, {}},
+			expectedIDs: []uint{11, 12},
+		},
+		{
+			name:        "Failure - export function fails",
+			batches:     [][]uint{{11, 12}},
+			exportErr:   errors.New("broken pipe"),
+			expectedIDs: []uint{11, 12},
+			expectedErr: "error while exporting items: broken pipe",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectBegin()
+			mock.ExpectExec(declareExport).WithArgs(10, "EUR").WillReturnResult(sqlmock.NewResult(0, 0))
+			for _, batch := range test.batches {
+				rows := sqlmock.NewRows(columns)
+				for _, id := range batch {
+					rows.AddRow(id, itemName, itemPrice, itemPriceCode)
+				}
+				mock.ExpectQuery(fetchExport).WithArgs().WillReturnRows(rows)
+			}
+			if test.exportErr != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			var exported []uint
+			err := store.ExportItems(context.Background(), ExportQuery{
+				ItemFilter: ItemFilter{PriceCode: &priceCode}, AfterID: 10, BatchSize: 2,
+			}, func(items []Item) error {
+				for _, item := range items {
+					exported = append(exported, item.ID)
+				}
+				return test.exportErr
+			})
+
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedIDs, exported)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestExportItems_invalidQuery(t *testing.T) {
+	minPrice := "cheap"
+	for _, query := range []ExportQuery{
+		{BatchSize: 0},
+		{ItemFilter: ItemFilter{MinPrice: &minPrice}, BatchSize: 10},
+	} {
+		store, mock := newMockStore(t)
+
+		err := store.ExportItems(context.Background(), query, func([]Item) error { return nil })
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}
+}
@@ -0,0 +1,276 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// systemActor is recorded in history when change is not attributed to anyone, e.g. made by background job
+	systemActor    = "system"
+	maxActorLength = 100
+)
+
+// HistoryOperation is a kind of change recorded in item history
+type HistoryOperation string
+
+const (
+	HistoryCreated  HistoryOperation = "created"
+	HistoryUpdated  HistoryOperation = "updated"
+	HistoryDeleted  HistoryOperation = "deleted"
+	HistoryRestored HistoryOperation = "restored"
+	// HistoryRevised is recorded when part of the item kept apart from its fields changes, e.g. its price list
+	HistoryRevised HistoryOperation = "revised"
+)
+
+// ItemPart is a part of the item kept apart from its fields, which is changed by revision
+type ItemPart string
+
+const (
+	PartPrices          ItemPart = "prices"
+	PartScheduledPrices ItemPart = "scheduledPrices"
+	PartVariants        ItemPart = "variants"
+	PartCategories      ItemPart = "categories"
+	PartImages          ItemPart = "images"
+)
+
+type actorKey struct{}
+
+// WithActor returns context which attributes changes made with it to actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// actorFrom returns actor changes made with ctx are attributed to
+func actorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	if actor == "" {
+		return systemActor
+	}
+	if utf8.RuneCountInString(actor) > maxActorLength {
+		actor = string([]rune(actor)[:maxActorLength])
+	}
+	return actor
+}
+
+// ItemSnapshot is state of item fields recorded in history
+type ItemSnapshot struct {
+	ExternalID  *string `json:"externalId,omitempty"`
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Price       *int64  `json:"price,omitempty"`
+	PriceCode   *string `json:"priceCode,omitempty"`
+	// Part is the part of the item changed by revision, empty for other operations
+	Part ItemPart `json:"part,omitempty"`
+}
+
+// Value stores snapshot as jsonb
+func (s ItemSnapshot) Value() (driver.Value, error) {
+	b, err := json.Marshal(s)
+	return string(b), err
+}
+
+// Scan reads snapshot stored as jsonb
+func (s *ItemSnapshot) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	}
+	return fmt.Errorf("unsupported type of item snapshot: %T", src)
+}
+
+// ItemChange is an entry of append-only item history, it holds state of the item right after the change
+type ItemChange struct {
+	ID        uint
+	ItemID    uint
+	Version   uint
+	Operation HistoryOperation
+	Actor     string
+	ChangedAt time.Time
+	Snapshot  ItemSnapshot
+}
+
+func (ItemChange) TableName() string {
+	return "item_history"
+}
+
+// Item returns state of the item recorded by the change
+func (c ItemChange) Item() Item {
+	return Item{
+		ID:          c.ItemID,
+		ExternalID:  clonePtr(c.Snapshot.ExternalID),
+		Name:        clonePtr(c.Snapshot.Name),
+		Description: clonePtr(c.Snapshot.Description),
+		Price:       clonePtr(c.Snapshot.Price),
+		PriceCode:   clonePtr(c.Snapshot.PriceCode),
+		Version:     c.Version,
+	}
+}
+
+// newItemChange records state of the item after operation attributed to actor from ctx
+func newItemChange(ctx context.Context, item Item, operation HistoryOperation, changedAt time.Time) ItemChange {
+	return ItemChange{
+		ItemID:    item.ID,
+		Version:   item.Version,
+		Operation: operation,
+		Actor:     actorFrom(ctx),
+		ChangedAt: changedAt,
+		Snapshot: ItemSnapshot{
+			ExternalID:  clonePtr(item.ExternalID),
+			Name:        clonePtr(item.Name),
+			Description: clonePtr(item.Description),
+			Price:       clonePtr(item.Price),
+			PriceCode:   clonePtr(item.PriceCode),
+		},
+	}
+}
+
+// recordChange appends state of the item after operation to its history and emits event about it to the outbox
+func recordChange(tx *gorm.DB, item Item, operation HistoryOperation) error {
+	return appendChange(tx, newItemChange(tx.Statement.Context, item, operation, time.Now()))
+}
+
+// reviseItem increments version of the item with ID, as its part changes, and records the revision in its history.
+// ErrRecordNotFound is returned when there is no such item or it was deleted.
+func reviseItem(tx *gorm.DB, itemID uint, part ItemPart) error {
+	var item Item
+	resp := tx.Model(&item).Clauses(clause.Returning{}).Where("id = ?", itemID).
+		Update("version", gorm.Expr("version + 1"))
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if resp.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	change := newItemChange(tx.Statement.Context, item, HistoryRevised, time.Now())
+	change.Snapshot.Part = part
+	return appendChange(tx, change)
+}
+
+// appendChange appends change to item history and emits event about it to the outbox
+func appendChange(tx *gorm.DB, change ItemChange) error {
+	if err := tx.Create(&change).Error; err != nil {
+		return err
+	}
+	event := newEvent(change)
+	return tx.Create(&event).Error
+}
+
+// GetItemHistory returns all recorded changes of the item with ID, including deleted or purged one, oldest first
+func (s *CatalogStore) GetItemHistory(ctx context.Context, itemID uint) ([]ItemChange, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	changes := []ItemChange{}
+	if err := db.Where("item_id = ?", itemID).Order("changed_at").Order("id").Find(&changes).Error; err != nil {
+		return nil, fmt.Errorf("error while getting history of item with id %d: %w", itemID, err)
+	}
+	// every item has its creation recorded, so there is no item without history
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("error while getting history of item with id %d: %w", itemID, gorm.ErrRecordNotFound)
+	}
+	return changes, nil
+}
+
+// GetItemAsOf returns state of the item with ID at provided time. ErrRecordNotFound is returned when the item did
+// not exist at that time or was deleted.
+func (s *CatalogStore) GetItemAsOf(ctx context.Context, itemID uint, at time.Time) (Item, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var change ItemChange
+	err := db.Where("item_id = ? AND changed_at <= ?", itemID, at).
+		Order("changed_at DESC").Order("id DESC").Take(&change).Error
+	if err == nil && change.Operation == HistoryDeleted {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		return Item{}, fmt.Errorf("error while getting item with id %d as of %s: %w", itemID, at, err)
+	}
+	return change.Item(), nil
+}
+
+// GetItemHistory returns all recorded changes of the item with ID, including deleted or purged one, oldest first
+func (s *MemoryStore) GetItemHistory(ctx context.Context, itemID uint) ([]ItemChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while getting history of item with id %d: %w", itemID, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.history[itemID]) == 0 {
+		return nil, fmt.Errorf("error while getting history of item with id %d: %w", itemID, gorm.ErrRecordNotFound)
+	}
+	changes := make([]ItemChange, 0, len(s.history[itemID]))
+	for _, change := range s.history[itemID] {
+		changes = append(changes, cloneItemChange(change))
+	}
+	return changes, nil
+}
+
+// GetItemAsOf returns state of the item with ID at provided time. ErrRecordNotFound is returned when the item did
+// not exist at that time or was deleted.
+func (s *MemoryStore) GetItemAsOf(ctx context.Context, itemID uint, at time.Time) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, fmt.Errorf("error while getting item with id %d as of %s: %w", itemID, at, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	changes := s.history[itemID]
+	// history is ordered by time of change, so the last change made until at is the first one before later ones
+	n := sort.Search(len(changes), func(i int) bool { return changes[i].ChangedAt.After(at) })
+	if n == 0 || changes[n-1].Operation == HistoryDeleted {
+		return Item{}, fmt.Errorf("error while getting item with id %d as of %s: %w", itemID, at, gorm.ErrRecordNotFound)
+	}
+	return changes[n-1].Item(), nil
+}
+
+// recordChange appends state of the item after operation to its history and emits event about it to the outbox.
+// Caller has to hold the lock.
+func (s *MemoryStore) recordChange(ctx context.Context, item Item, operation HistoryOperation) {
+	s.appendChange(newItemChange(ctx, item, operation, s.now()))
+}
+
+// reviseItem increments version of the item with ID, as its part changes, and records the revision in its history.
+// Caller has to hold the lock.
+func (s *MemoryStore) reviseItem(ctx context.Context, itemID uint, part ItemPart) {
+	item := s.items[itemID]
+	item.Version++
+	s.items[itemID] = item
+	change := newItemChange(ctx, item, HistoryRevised, s.now())
+	change.Snapshot.Part = part
+	s.appendChange(change)
+}
+
+// appendChange appends change to item history and emits event about it to the outbox. Caller has to hold the lock.
+func (s *MemoryStore) appendChange(change ItemChange) {
+	s.lastHistoryID++
+	change.ID = s.lastHistoryID
+	s.history[change.ItemID] = append(s.history[change.ItemID], change)
+	s.recordEvent(cloneItemChange(change))
+}
+
+func cloneItemChange(change ItemChange) ItemChange {
+	clone := change
+	clone.Snapshot = cloneSnapshot(change.Snapshot)
+	return clone
+}
+
+func cloneSnapshot(snapshot ItemSnapshot) ItemSnapshot {
+	return ItemSnapshot{
+		ExternalID:  clonePtr(snapshot.ExternalID),
+		Name:        clonePtr(snapshot.Name),
+		Description: clonePtr(snapshot.Description),
+		Price:       clonePtr(snapshot.Price),
+		PriceCode:   clonePtr(snapshot.PriceCode),
+		Part:        snapshot.Part,
+	}
+}
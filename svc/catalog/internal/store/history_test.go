@@ -0,0 +1,147 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetItemHistory(t *testing.T) {
+	changedAt := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := `{"name":"some name","price":5000,"priceCode":"EUR"}`
+	tests := []struct {
+		name        string
+		rows        *sqlmock.Rows
+		expected    []ItemChange
+		expectedErr error
+	}{
+		{
+			name: "History of the item",
+			rows: sqlmock.NewRows([]string{"id", "item_id", "version", "operation", "actor", "changed_at", "snapshot"}).
+				AddRow(1, 1, 1, "created", "alice", changedAt, []byte(snapshot)).
+				AddRow(2, 1, 1, "deleted", "system", changedAt, []byte(snapshot)),
+			expected: []ItemChange{
+				{
+					ID: 1, ItemID: 1, Version: 1, Operation: HistoryCreated, Actor: "alice", ChangedAt: changedAt,
+					Snapshot: ItemSnapshot{Name: v2p("some name"), Price: v2p[int64](5000), PriceCode: v2p("EUR")},
+				},
+				{
+					ID: 2, ItemID: 1, Version: 1, Operation: HistoryDeleted, Actor: "system", ChangedAt: changedAt,
+					Snapshot: ItemSnapshot{Name: v2p("some name"), Price: v2p[int64](5000), PriceCode: v2p("EUR")},
+				},
+			},
+		},
+		{
+			name:        "Missing item",
+			rows:        sqlmock.NewRows([]string{"id", "item_id", "version", "operation", "actor", "changed_at", "snapshot"}),
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectQuery(`SELECT \* FROM "item_history" WHERE item_id = \$1 ORDER BY changed_at,id`).
+				WithArgs(1).WillReturnRows(test.rows)
+
+			changes, err := store.GetItemHistory(context.Background(), 1)
+
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, changes)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetItemAsOf(t *testing.T) {
+	at := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	changedAt := at.Add(-time.Hour)
+	tests := []struct {
+		name        string
+		rows        *sqlmock.Rows
+		expected    Item
+		expectedErr error
+	}{
+		{
+			name: "Item updated before",
+			rows: sqlmock.NewRows([]string{"id", "item_id", "version", "operation", "actor", "changed_at", "snapshot"}).
+				AddRow(2, 1, 2, "updated", "alice", changedAt, []byte(`{"name":"some name","description":"some desc"}`)),
+			expected: Item{ID: 1, Name: v2p("some name"), Description: v2p("some desc"), Version: 2},
+		},
+		{
+			name: "Item deleted before",
+			rows: sqlmock.NewRows([]string{"id", "item_id", "version", "operation", "actor", "changed_at", "snapshot"}).
+				AddRow(3, 1, 2, "deleted", "alice", changedAt, []byte(`{"name":"some name"}`)),
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:        "Item created later",
+			rows:        sqlmock.NewRows([]string{"id"}),
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:        "Db error",
+			expectedErr: errors.New("some error"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			query := mock.ExpectQuery(`SELECT \* FROM "item_history" WHERE item_id = \$1 AND changed_at <= \$2 `+
+				`ORDER BY changed_at DESC,id DESC LIMIT 1`).WithArgs(1, at)
+			if test.rows != nil {
+				query.WillReturnRows(test.rows)
+			} else {
+				query.WillReturnError(test.expectedErr)
+			}
+
+			item, err := store.GetItemAsOf(context.Background(), 1, at)
+
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, item)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWithActor(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{
+			name:     "Actor is set",
+			ctx:      WithActor(context.Background(), "alice"),
+			expected: "alice",
+		},
+		{
+			name:     "Actor is not set",
+			ctx:      context.Background(),
+			expected: systemActor,
+		},
+		{
+			name:     "Actor is too long",
+			ctx:      WithActor(context.Background(), strings.Repeat("ą", maxActorLength+1)),
+			expected: strings.Repeat("ą", maxActorLength),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, actorFrom(test.ctx))
+		})
+	}
+}
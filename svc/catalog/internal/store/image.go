@@ -0,0 +1,395 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxAltTextLength = 250
+	maxItemImages    = 20
+)
+
+var ErrInvalidImage = errors.New("invalid image")
+
+// ItemImage is an image of the item, its content and thumbnails are kept in media storage under BlobKey
+type ItemImage struct {
+	ID     uint
+	ItemID uint
+	// Position orders images of the item starting from 0, the first image is the main one
+	Position    int
+	AltText     *string
+	BlobKey     string
+	ContentType string
+	Width       int
+	Height      int
+	// Size is a number of bytes of the original image
+	Size      int64 `gorm:"column:size_bytes"`
+	CreatedAt time.Time
+}
+
+// MediaDeletion is a blob of removed image waiting to be deleted from media storage
+type MediaDeletion struct {
+	BlobKey string `gorm:"primaryKey"`
+}
+
+// validateImage checks if alternative text of the image isn't too long and its position isn't negative
+func validateImage(altText *string, position int) error {
+	if position < 0 {
+		return fmt.Errorf("%w: position cannot be negative", ErrInvalidImage)
+	}
+	if altText != nil && utf8.RuneCountInString(*altText) > maxAltTextLength {
+		return fmt.Errorf("%w: altText should be at most %d characters long", ErrInvalidImage, maxAltTextLength)
+	}
+	return nil
+}
+
+// moveImage moves image with ID to position in images ordered by position, positions past the last image move it
+// to the end. Images with changed position are returned with their new position.
+func moveImage(images []ItemImage, id uint, position int) (moved []ItemImage, found bool) {
+	from := -1
+	for i := range images {
+		if images[i].ID == id {
+			from = i
+		}
+	}
+	if from < 0 {
+		return nil, false
+	}
+	if position > len(images)-1 {
+		position = len(images) - 1
+	}
+	image := images[from]
+	ordered := append(append([]ItemImage{}, images[:from]...), images[from+1:]...)
+	ordered = append(ordered[:position], append([]ItemImage{image}, ordered[position:]...)...)
+	for i := range ordered {
+		if ordered[i].Position != i {
+			ordered[i].Position = i
+			moved = append(moved, ordered[i])
+		}
+	}
+	return moved, true
+}
+
+// CreateItemImage adds image at the end of images of the item with ID and returns it with assigned ID and position
+func (s *CatalogStore) CreateItemImage(ctx context.Context, itemID uint, image ItemImage) (ItemImage, error) {
+	if err := validateImage(image.AltText, 0); err != nil {
+		return ItemImage{}, err
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	image.ID = 0
+	image.ItemID = itemID
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := reviseItem(tx, itemID, PartImages); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&ItemImage{}).Where("item_id = ?", itemID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxItemImages {
+			return fmt.Errorf("%w: item can have at most %d images", ErrInvalidImage, maxItemImages)
+		}
+		image.Position = int(count)
+		return tx.Create(&image).Error
+	})
+	if err != nil {
+		return ItemImage{}, fmt.Errorf("error while adding image to item with id %d: %w", itemID, err)
+	}
+	return image, nil
+}
+
+// GetItemImages returns images of items with provided IDs ordered by position
+func (s *CatalogStore) GetItemImages(ctx context.Context, itemIDs []uint) (map[uint][]ItemImage, error) {
+	images := map[uint][]ItemImage{}
+	if len(itemIDs) == 0 {
+		return images, nil
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var found []ItemImage
+	if err := db.Where("item_id IN ?", itemIDs).Order("item_id").Order("position").Find(&found).Error; err != nil {
+		return nil, fmt.Errorf("error while getting item images: %w", err)
+	}
+	for _, image := range found {
+		images[image.ItemID] = append(images[image.ItemID], image)
+	}
+	return images, nil
+}
+
+// GetItemImage returns image with ID of the live item with provided ID
+func (s *CatalogStore) GetItemImage(ctx context.Context, itemID, id uint) (image ItemImage, err error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err = db.Select("item_images.*").Joins("JOIN items ON items.id = item_images.item_id AND items.deleted_at IS NULL").
+		Where("item_images.item_id = ?", itemID).First(&image, id).Error
+	return
+}
+
+// UpdateItemImage sets alternative text of image with ID of the item with provided ID and moves it to position,
+// shifting other images of the item
+func (s *CatalogStore) UpdateItemImage(ctx context.Context, itemID, id uint, altText *string,
+	position int) (ItemImage, error) {
+	if err := validateImage(altText, position); err != nil {
+		return ItemImage{}, err
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var image ItemImage
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := reviseItem(tx, itemID, PartImages); err != nil {
+			return err
+		}
+		var images []ItemImage
+		if err := tx.Where("item_id = ?", itemID).Order("position").Find(&images).Error; err != nil {
+			return err
+		}
+		moved, found := moveImage(images, id, position)
+		if !found {
+			return gorm.ErrRecordNotFound
+		}
+		// uniqueness of positions is checked on commit, so images can be shifted one by one
+		for _, m := range moved {
+			if err := tx.Model(&ItemImage{}).Where("id = ?", m.ID).Update("position", m.Position).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&image).Clauses(clause.Returning{}).Where("id = ?", id).Update("alt_text", altText).Error
+	})
+	if err != nil {
+		return ItemImage{}, fmt.Errorf("error while updating image with id %d: %w", id, err)
+	}
+	return image, nil
+}
+
+// DeleteItemImage removes image with ID of the item with provided ID, its blobs are queued for deletion from media
+// storage
+func (s *CatalogStore) DeleteItemImage(ctx context.Context, itemID, id uint) error {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := reviseItem(tx, itemID, PartImages); err != nil {
+			return err
+		}
+		var image ItemImage
+		if err := tx.Clauses(clause.Returning{}).Where("item_id = ?", itemID).Delete(&image, id).Error; err != nil {
+			return err
+		}
+		if image.ID == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Model(&ItemImage{}).Where("item_id = ? AND position > ?", itemID, image.Position).
+			Update("position", gorm.Expr("position - 1")).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&MediaDeletion{BlobKey: image.BlobKey}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("error while deleting image with id %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetMediaDeletions returns at most limit blobs waiting to be deleted from media storage
+func (s *CatalogStore) GetMediaDeletions(ctx context.Context, limit int) ([]MediaDeletion, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var deletions []MediaDeletion
+	if err := db.Order("blob_key").Limit(limit).Find(&deletions).Error; err != nil {
+		return nil, fmt.Errorf("error while getting media deletions: %w", err)
+	}
+	return deletions, nil
+}
+
+// CompleteMediaDeletion records that blob with key was deleted from media storage
+func (s *CatalogStore) CompleteMediaDeletion(ctx context.Context, blobKey string) error {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	if err := db.Delete(&MediaDeletion{BlobKey: blobKey}).Error; err != nil {
+		return fmt.Errorf("error while completing media deletion of %s: %w", blobKey, err)
+	}
+	return nil
+}
+
+// CreateItemImage adds image at the end of images of the item with ID and returns it with assigned ID and position
+func (s *MemoryStore) CreateItemImage(ctx context.Context, itemID uint, image ItemImage) (ItemImage, error) {
+	if err := validateImage(image.AltText, 0); err != nil {
+		return ItemImage{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return ItemImage{}, fmt.Errorf("error while adding image to item with id %d: %w", itemID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.live(itemID); !ok {
+		return ItemImage{}, fmt.Errorf("error while adding image to item with id %d: %w", itemID, gorm.ErrRecordNotFound)
+	}
+	images := s.imagesOf(itemID)
+	if len(images) >= maxItemImages {
+		return ItemImage{}, fmt.Errorf("error while adding image to item with id %d: %w: item can have at most %d images",
+			itemID, ErrInvalidImage, maxItemImages)
+	}
+	s.lastImageID++
+	image.ID = s.lastImageID
+	image.ItemID = itemID
+	image.Position = len(images)
+	image.CreatedAt = s.now()
+	s.images[image.ID] = cloneItemImage(image)
+	s.reviseItem(ctx, itemID, PartImages)
+	return cloneItemImage(image), nil
+}
+
+// GetItemImages returns images of items with provided IDs ordered by position
+func (s *MemoryStore) GetItemImages(ctx context.Context, itemIDs []uint) (map[uint][]ItemImage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while getting item images: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	images := map[uint][]ItemImage{}
+	for _, itemID := range itemIDs {
+		if itemImages := s.imagesOf(itemID); len(itemImages) > 0 {
+			images[itemID] = itemImages
+		}
+	}
+	return images, nil
+}
+
+// GetItemImage returns image with ID of the live item with provided ID
+func (s *MemoryStore) GetItemImage(ctx context.Context, itemID, id uint) (ItemImage, error) {
+	if err := ctx.Err(); err != nil {
+		return ItemImage{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	image, ok := s.images[id]
+	if _, live := s.live(itemID); !ok || !live || image.ItemID != itemID {
+		return ItemImage{}, gorm.ErrRecordNotFound
+	}
+	return cloneItemImage(image), nil
+}
+
+// UpdateItemImage sets alternative text of image with ID of the item with provided ID and moves it to position,
+// shifting other images of the item
+func (s *MemoryStore) UpdateItemImage(ctx context.Context, itemID, id uint, altText *string,
+	position int) (ItemImage, error) {
+	if err := validateImage(altText, position); err != nil {
+		return ItemImage{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return ItemImage{}, fmt.Errorf("error while updating image with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.live(itemID); !ok {
+		return ItemImage{}, fmt.Errorf("error while updating image with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	moved, found := moveImage(s.imagesOf(itemID), id, position)
+	if !found {
+		return ItemImage{}, fmt.Errorf("error while updating image with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	for _, m := range moved {
+		image := s.images[m.ID]
+		image.Position = m.Position
+		s.images[m.ID] = image
+	}
+	image := s.images[id]
+	image.AltText = clonePtr(altText)
+	s.images[id] = image
+	s.reviseItem(ctx, itemID, PartImages)
+	return cloneItemImage(image), nil
+}
+
+// DeleteItemImage removes image with ID of the item with provided ID, its blobs are queued for deletion from media
+// storage
+func (s *MemoryStore) DeleteItemImage(ctx context.Context, itemID, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error while deleting image with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	image, ok := s.images[id]
+	if _, live := s.live(itemID); !ok || !live || image.ItemID != itemID {
+		return fmt.Errorf("error while deleting image with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	s.deleteImage(image)
+	for _, other := range s.imagesOf(itemID) {
+		if other.Position > image.Position {
+			other.Position--
+			s.images[other.ID] = other
+		}
+	}
+	s.reviseItem(ctx, itemID, PartImages)
+	return nil
+}
+
+// GetMediaDeletions returns at most limit blobs waiting to be deleted from media storage
+func (s *MemoryStore) GetMediaDeletions(ctx context.Context, limit int) ([]MediaDeletion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while getting media deletions: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.mediaDeletions))
+	for key := range s.mediaDeletions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	deletions := make([]MediaDeletion, 0, len(keys))
+	for _, key := range keys {
+		deletions = append(deletions, MediaDeletion{BlobKey: key})
+	}
+	return deletions, nil
+}
+
+// CompleteMediaDeletion records that blob with key was deleted from media storage
+func (s *MemoryStore) CompleteMediaDeletion(ctx context.Context, blobKey string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error while completing media deletion of %s: %w", blobKey, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.mediaDeletions, blobKey)
+	return nil
+}
+
+// imagesOf returns images of the item with ID ordered by position. Caller has to hold the lock.
+func (s *MemoryStore) imagesOf(itemID uint) []ItemImage {
+	var images []ItemImage
+	for _, image := range s.images {
+		if image.ItemID == itemID {
+			images = append(images, cloneItemImage(image))
+		}
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].Position < images[j].Position })
+	return images
+}
+
+// deleteImage removes image and queues its blobs for deletion. Caller has to hold the lock.
+func (s *MemoryStore) deleteImage(image ItemImage) {
+	delete(s.images, image.ID)
+	s.mediaDeletions[image.BlobKey] = true
+}
+
+func cloneItemImage(image ItemImage) ItemImage {
+	clone := image
+	clone.AltText = clonePtr(image.AltText)
+	return clone
+}
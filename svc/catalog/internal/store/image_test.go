@@ -0,0 +1,188 @@
+package store
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestMoveImage(t *testing.T) {
+	images := []ItemImage{{ID: 1, Position: 0}, {ID: 2, Position: 1}, {ID: 3, Position: 2}}
+	tests := []struct {
+		name          string
+		id            uint
+		position      int
+		expectedMoved []ItemImage
+		expectedFound bool
+	}{
+		{
+			name:          "Last image becomes the main one",
+			id:            3,
+			position:      0,
+			expectedMoved: []ItemImage{{ID: 3, Position: 0}, {ID: 1, Position: 1}, {ID: 2, Position: 2}},
+			expectedFound: true,
+		},
+		{
+			name:          "Main image is moved by one",
+			id:            1,
+			position:      1,
+			expectedMoved: []ItemImage{{ID: 2, Position: 0}, {ID: 1, Position: 1}},
+			expectedFound: true,
+		},
+		{
+			name:          "Position past the last image moves it to the end",
+			id:            2,
+			position:      10,
+			expectedMoved: []ItemImage{{ID: 3, Position: 1}, {ID: 2, Position: 2}},
+			expectedFound: true,
+		},
+		{
+			name:          "Image stays where it is",
+			id:            2,
+			position:      1,
+			expectedFound: true,
+		},
+		{
+			name:     "Missing image",
+			id:       4,
+			position: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			moved, found := moveImage(images, test.id, test.position)
+
+			assert.Equal(t, test.expectedFound, found)
+			assert.Equal(t, test.expectedMoved, moved)
+			assert.Equal(t, []ItemImage{{ID: 1, Position: 0}, {ID: 2, Position: 1}, {ID: 3, Position: 2}}, images,
+				"images are not modified")
+		})
+	}
+}
+
+func TestCreateItemImage(t *testing.T) {
+	tests := []struct {
+		name        string
+		count       int
+		expectedErr error
+	}{
+		{
+			name:  "Successful - image is added at the end",
+			count: 2,
+		},
+		{
+			name:        "Failure - item has too many images",
+			count:       maxItemImages,
+			expectedErr: ErrInvalidImage,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			createdAt := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+			mock.ExpectBegin()
+			expectReviseItem(mock, PartImages)
+			mock.ExpectQuery(`SELECT count\(\*\) FROM "item_images" WHERE item_id = \$1`).WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(test.count))
+			if test.expectedErr != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectQuery(`INSERT INTO "item_images" \("item_id","position","alt_text","blob_key","content_type",`+
+					`"width","height","size_bytes","created_at"\) VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7,\$8,\$9\) RETURNING "id"`).
+					WithArgs(1, 2, "Blue shirt", "images/ab/abc", "image/png", 640, 480, 1024, createdAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectCommit()
+			}
+
+			image, err := store.CreateItemImage(context.Background(), 1, ItemImage{AltText: v2p("Blue shirt"),
+				BlobKey: "images/ab/abc", ContentType: "image/png", Width: 640, Height: 480, Size: 1024,
+				CreatedAt: createdAt})
+
+			assert.ErrorIs(t, err, test.expectedErr)
+			if test.expectedErr == nil {
+				assert.Equal(t, uint(5), image.ID)
+				assert.Equal(t, 2, image.Position)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateItemImage(t *testing.T) {
+	store, mock := newMockStore(t)
+	columns := []string{"id", "item_id", "position", "alt_text", "blob_key"}
+	mock.ExpectBegin()
+	expectReviseItem(mock, PartImages)
+	mock.ExpectQuery(`SELECT \* FROM "item_images" WHERE item_id = \$1 ORDER BY position`).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(4, 1, 0, nil, "images/ab/abc").AddRow(5, 1, 1, nil, "images/cd/cde"))
+	mock.ExpectExec(`UPDATE "item_images" SET "position"=\$1 WHERE id = \$2`).WithArgs(0, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "item_images" SET "position"=\$1 WHERE id = \$2`).WithArgs(1, 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`UPDATE "item_images" SET "alt_text"=\$1 WHERE id = \$2 RETURNING \*`).WithArgs("Back", 5).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(5, 1, 0, "Back", "images/cd/cde"))
+	mock.ExpectCommit()
+
+	image, err := store.UpdateItemImage(context.Background(), 1, 5, v2p("Back"), 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, ItemImage{ID: 5, ItemID: 1, Position: 0, AltText: v2p("Back"), BlobKey: "images/cd/cde"}, image)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateItemImage_invalid(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	_, err := store.UpdateItemImage(context.Background(), 1, 5, nil, -1)
+
+	assert.ErrorIs(t, err, ErrInvalidImage)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteItemImage(t *testing.T) {
+	tests := []struct {
+		name        string
+		deleted     bool
+		expectedErr error
+	}{
+		{
+			name:    "Successful - following images are shifted and blobs queued for deletion",
+			deleted: true,
+		},
+		{
+			name:        "Failure - image not found",
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectBegin()
+			expectReviseItem(mock, PartImages)
+			rows := sqlmock.NewRows([]string{"id", "item_id", "position", "blob_key"})
+			if test.deleted {
+				rows.AddRow(5, 1, 1, "images/cd/cde")
+			}
+			mock.ExpectQuery(`DELETE FROM "item_images" WHERE item_id = \$1 AND "item_images"\."id" = \$2 RETURNING \*`).
+				WithArgs(1, 5).WillReturnRows(rows)
+			if test.deleted {
+				mock.ExpectExec(`UPDATE "item_images" SET "position"=position - 1 WHERE item_id = \$1 AND position > \$2`).
+					WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(`INSERT INTO "media_deletions" \("blob_key"\) VALUES \(\$1\) ON CONFLICT DO NOTHING`).
+					WithArgs("images/cd/cde").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			err := store.DeleteItemImage(context.Background(), 1, 5)
+
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
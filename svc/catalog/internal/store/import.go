@@ -0,0 +1,408 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidImport = errors.New("invalid import")
+	// ErrImportJobLost is returned when progress is recorded by a worker whose claim of the job has ended
+	ErrImportJobLost = errors.New("import job is no longer claimed by the worker")
+)
+
+// ImportFormat is a format of the uploaded file
+type ImportFormat string
+
+const (
+	ImportCSV    ImportFormat = "csv"
+	ImportNDJSON ImportFormat = "ndjson"
+)
+
+// ImportStatus describes lifecycle of the import job. Queued and running jobs are processed by workers, until they
+// are completed or fail as a whole.
+type ImportStatus string
+
+const (
+	ImportQueued    ImportStatus = "queued"
+	ImportRunning   ImportStatus = "running"
+	ImportCompleted ImportStatus = "completed"
+	ImportFailed    ImportStatus = "failed"
+)
+
+// finished reports if job with the status is no longer processed
+func (s ImportStatus) finished() bool {
+	return s == ImportCompleted || s == ImportFailed
+}
+
+// ImportCounts sums up outcomes of processed rows
+type ImportCounts struct {
+	ProcessedRows  int
+	CreatedItems   int
+	UpdatedItems   int
+	UnchangedItems int
+	RejectedRows   int
+}
+
+// ImportJob is an asynchronous import of items from the uploaded file
+type ImportJob struct {
+	ID     uint
+	Format ImportFormat
+	Status ImportStatus
+	// Claims is number of times the job was claimed by workers, it identifies the current claim
+	Claims int
+	// LeaseUntil is when the job can be claimed again, e.g. after the worker processing it stopped
+	LeaseUntil   time.Time
+	ImportCounts `gorm:"embedded"`
+	// Error explains why the job failed
+	Error      *string
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// importUpload is the file uploaded for the import job, it is removed once the job finishes
+type importUpload struct {
+	JobID uint `gorm:"primaryKey;autoIncrement:false"`
+	Data  []byte
+}
+
+// ImportRejection explains why row of the imported file was rejected. Rows are numbered from 1, not counting
+// the CSV header.
+type ImportRejection struct {
+	ID         uint
+	JobID      uint
+	Row        int `gorm:"column:row_number"`
+	ExternalID *string
+	// Field is not set when the row as a whole is invalid
+	Field   *string
+	Message string
+}
+
+// ImportProgress is state of the claimed job after the next rows were processed
+type ImportProgress struct {
+	ImportCounts
+	// Rejections of rows processed since the previously recorded progress
+	Rejections []ImportRejection
+	// Status is running until the job is completed or failed
+	Status ImportStatus
+	Error  *string
+	// LeaseUntil extends the claim of running job
+	LeaseUntil time.Time
+}
+
+// updates returns column updates recording the progress at provided time
+func (p ImportProgress) updates(now time.Time) map[string]interface{} {
+	updates := map[string]interface{}{
+		"processed_rows":  p.ProcessedRows,
+		"created_items":   p.CreatedItems,
+		"updated_items":   p.UpdatedItems,
+		"unchanged_items": p.UnchangedItems,
+		"rejected_rows":   p.RejectedRows,
+		"status":          p.Status,
+		"error":           p.Error,
+		"lease_until":     p.LeaseUntil,
+	}
+	if p.Status.finished() {
+		updates["lease_until"] = now
+		updates["finished_at"] = now
+	}
+	return updates
+}
+
+// validate checks if progress moves the job to a known status
+func (p ImportProgress) validate() error {
+	if p.Status != ImportRunning && !p.Status.finished() {
+		return fmt.Errorf("%w: unexpected status %q of the progress", ErrInvalidImport, p.Status)
+	}
+	return nil
+}
+
+// validateImport checks if uploaded file has known format and any content
+func validateImport(format ImportFormat, data []byte) error {
+	switch {
+	case format != ImportCSV && format != ImportNDJSON:
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalidImport, format)
+	case len(data) == 0:
+		return fmt.Errorf("%w: file is empty", ErrInvalidImport)
+	}
+	return nil
+}
+
+// CreateImportJob queues import of the uploaded file in provided format and returns the job with assigned ID
+func (s *CatalogStore) CreateImportJob(ctx context.Context, format ImportFormat, data []byte) (ImportJob, error) {
+	if err := validateImport(format, data); err != nil {
+		return ImportJob{}, err
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	now := time.Now()
+	job := ImportJob{Format: format, Status: ImportQueued, LeaseUntil: now, CreatedAt: now}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&job).Error; err != nil {
+			return err
+		}
+		return tx.Create(&importUpload{JobID: job.ID, Data: data}).Error
+	})
+	if err != nil {
+		return ImportJob{}, fmt.Errorf("error while creating import job: %w", err)
+	}
+	return job, nil
+}
+
+// GetImportJob returns import job with provided ID
+func (s *CatalogStore) GetImportJob(ctx context.Context, id uint) (ImportJob, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var job ImportJob
+	if err := db.First(&job, id).Error; err != nil {
+		return ImportJob{}, fmt.Errorf("error while getting import job with id %d: %w", id, err)
+	}
+	return job, nil
+}
+
+// ClaimImportJob claims the oldest unfinished job whose lease ended at provided time until the lease ends again and
+// returns it together with its uploaded file. ErrRecordNotFound is returned when there is no such job.
+func (s *CatalogStore) ClaimImportJob(ctx context.Context, now time.Time, lease time.Duration) (ImportJob, []byte, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var job ImportJob
+	var upload importUpload
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND lease_until <= ?", []ImportStatus{ImportQueued, ImportRunning}, now).
+			Order("id").Take(&job).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&job).Clauses(clause.Returning{}).Updates(map[string]interface{}{
+			"status":      ImportRunning,
+			"claims":      gorm.Expr("claims + 1"),
+			"lease_until": now.Add(lease),
+			"started_at":  gorm.Expr("COALESCE(started_at, ?)", now),
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Take(&upload, job.ID).Error
+	})
+	if err != nil {
+		return ImportJob{}, nil, fmt.Errorf("error while claiming import job: %w", err)
+	}
+	return job, upload.Data, nil
+}
+
+// RecordImportProgress records progress of the job with ID made within provided claim. Uploaded file is removed once
+// the job is finished. ErrImportJobLost is returned when the job was claimed again in the meantime.
+func (s *CatalogStore) RecordImportProgress(ctx context.Context, id uint, claim int, progress ImportProgress) error {
+	if err := progress.validate(); err != nil {
+		return err
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		resp := tx.Model(&ImportJob{}).Where("id = ? AND claims = ? AND status = ?", id, claim, ImportRunning).
+			Updates(progress.updates(time.Now()))
+		if resp.Error == nil && resp.RowsAffected == 0 {
+			resp.Error = ErrImportJobLost
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if len(progress.Rejections) > 0 {
+			rejections := newRejections(id, progress.Rejections)
+			if err := tx.Create(&rejections).Error; err != nil {
+				return err
+			}
+		}
+		if progress.Status.finished() {
+			return tx.Delete(&importUpload{}, id).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error while recording progress of import job with id %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetImportRejections returns requested page of rejections of the job with ID, ordered by row
+func (s *CatalogStore) GetImportRejections(ctx context.Context, jobID uint, pageSize, page int) ([]ImportRejection, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, ErrInvalidPageParams
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	rejections := []ImportRejection{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&ImportJob{}, jobID).Error; err != nil {
+			return err
+		}
+		return tx.Where("job_id = ?", jobID).Order("row_number").Order("id").
+			Offset((page - 1) * pageSize).Limit(pageSize).Find(&rejections).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while getting rejections of import job with id %d: %w", jobID, err)
+	}
+	return rejections, nil
+}
+
+// newRejections returns copies of rejections assigned to the job with ID. External IDs are truncated to fit into
+// the column, as rows are rejected also because of too long ones.
+func newRejections(jobID uint, rejections []ImportRejection) []ImportRejection {
+	assigned := make([]ImportRejection, 0, len(rejections))
+	for _, rejection := range rejections {
+		rejection = cloneRejection(rejection)
+		rejection.ID = 0
+		rejection.JobID = jobID
+		if rejection.ExternalID != nil && utf8.RuneCountInString(*rejection.ExternalID) > maxExternalIDLength {
+			truncated := string([]rune(*rejection.ExternalID)[:maxExternalIDLength])
+			rejection.ExternalID = &truncated
+		}
+		assigned = append(assigned, rejection)
+	}
+	return assigned
+}
+
+// CreateImportJob queues import of the uploaded file in provided format and returns the job with assigned ID
+func (s *MemoryStore) CreateImportJob(ctx context.Context, format ImportFormat, data []byte) (ImportJob, error) {
+	if err := validateImport(format, data); err != nil {
+		return ImportJob{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return ImportJob{}, fmt.Errorf("error while creating import job: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.lastImportJobID++
+	job := ImportJob{ID: s.lastImportJobID, Format: format, Status: ImportQueued, LeaseUntil: now, CreatedAt: now}
+	s.importJobs[job.ID] = job
+	s.importUploads[job.ID] = append([]byte(nil), data...)
+	return cloneImportJob(job), nil
+}
+
+// GetImportJob returns import job with provided ID
+func (s *MemoryStore) GetImportJob(ctx context.Context, id uint) (ImportJob, error) {
+	if err := ctx.Err(); err != nil {
+		return ImportJob{}, fmt.Errorf("error while getting import job with id %d: %w", id, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.importJobs[id]
+	if !ok {
+		return ImportJob{}, fmt.Errorf("error while getting import job with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	return cloneImportJob(job), nil
+}
+
+// ClaimImportJob claims the oldest unfinished job whose lease ended at provided time until the lease ends again and
+// returns it together with its uploaded file. ErrRecordNotFound is returned when there is no such job.
+func (s *MemoryStore) ClaimImportJob(ctx context.Context, now time.Time, lease time.Duration) (ImportJob, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return ImportJob{}, nil, fmt.Errorf("error while claiming import job: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var claimable []ImportJob
+	for _, job := range s.importJobs {
+		if !job.Status.finished() && !job.LeaseUntil.After(now) {
+			claimable = append(claimable, job)
+		}
+	}
+	if len(claimable) == 0 {
+		return ImportJob{}, nil, fmt.Errorf("error while claiming import job: %w", gorm.ErrRecordNotFound)
+	}
+	sort.Slice(claimable, func(i, j int) bool { return claimable[i].ID < claimable[j].ID })
+	job := claimable[0]
+	job.Status = ImportRunning
+	job.Claims++
+	job.LeaseUntil = now.Add(lease)
+	if job.StartedAt == nil {
+		job.StartedAt = &now
+	}
+	s.importJobs[job.ID] = job
+	return cloneImportJob(job), append([]byte(nil), s.importUploads[job.ID]...), nil
+}
+
+// RecordImportProgress records progress of the job with ID made within provided claim. Uploaded file is removed once
+// the job is finished. ErrImportJobLost is returned when the job was claimed again in the meantime.
+func (s *MemoryStore) RecordImportProgress(ctx context.Context, id uint, claim int, progress ImportProgress) error {
+	if err := progress.validate(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error while recording progress of import job with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.importJobs[id]
+	if !ok || job.Claims != claim || job.Status != ImportRunning {
+		return fmt.Errorf("error while recording progress of import job with id %d: %w", id, ErrImportJobLost)
+	}
+	job.ImportCounts = progress.ImportCounts
+	job.Status = progress.Status
+	job.Error = clonePtr(progress.Error)
+	job.LeaseUntil = progress.LeaseUntil
+	if job.Status.finished() {
+		now := s.now()
+		job.LeaseUntil = now
+		job.FinishedAt = &now
+		delete(s.importUploads, id)
+	}
+	s.importJobs[id] = job
+	for _, rejection := range newRejections(id, progress.Rejections) {
+		s.lastImportRejectionID++
+		rejection.ID = s.lastImportRejectionID
+		s.importRejections[id] = append(s.importRejections[id], rejection)
+	}
+	return nil
+}
+
+// GetImportRejections returns requested page of rejections of the job with ID, ordered by row
+func (s *MemoryStore) GetImportRejections(ctx context.Context, jobID uint, pageSize, page int) ([]ImportRejection, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, ErrInvalidPageParams
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while getting rejections of import job with id %d: %w", jobID, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.importJobs[jobID]; !ok {
+		return nil, fmt.Errorf("error while getting rejections of import job with id %d: %w", jobID,
+			gorm.ErrRecordNotFound)
+	}
+	rejections := make([]ImportRejection, 0, len(s.importRejections[jobID]))
+	for _, rejection := range s.importRejections[jobID] {
+		rejections = append(rejections, cloneRejection(rejection))
+	}
+	sort.SliceStable(rejections, func(i, j int) bool { return rejections[i].Row < rejections[j].Row })
+	return paginate(rejections, pageSize, page), nil
+}
+
+func cloneImportJob(job ImportJob) ImportJob {
+	clone := job
+	clone.Error = clonePtr(job.Error)
+	clone.StartedAt = clonePtr(job.StartedAt)
+	clone.FinishedAt = clonePtr(job.FinishedAt)
+	return clone
+}
+
+func cloneRejection(rejection ImportRejection) ImportRejection {
+	clone := rejection
+	clone.ExternalID = clonePtr(rejection.ExternalID)
+	clone.Field = clonePtr(rejection.Field)
+	return clone
+}
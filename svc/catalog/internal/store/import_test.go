@@ -0,0 +1,152 @@
+package store
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestCreateImportJob(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "import_jobs" \("format","status","claims","lease_until","processed_rows",`+
+		`"created_items","updated_items","unchanged_items","rejected_rows","error","created_at","started_at",`+
+		`"finished_at"\) VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7,\$8,\$9,\$10,\$11,\$12,\$13\) RETURNING "id"`).
+		WithArgs(ImportCSV, ImportQueued, 0, sqlmock.AnyArg(), 0, 0, 0, 0, 0, nil, sqlmock.AnyArg(), nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectExec(`INSERT INTO "import_uploads" \("job_id","data"\) VALUES \(\$1,\$2\)`).
+		WithArgs(4, []byte("externalId,name")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	job, err := store.CreateImportJob(context.Background(), ImportCSV, []byte("externalId,name"))
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(4), job.ID)
+	assert.Equal(t, ImportQueued, job.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimImportJob(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		rows        *sqlmock.Rows
+		expectedErr error
+	}{
+		{
+			name: "Claimed",
+			rows: sqlmock.NewRows([]string{"id", "status", "claims"}).AddRow(4, "queued", 0),
+		},
+		{
+			name:        "No claimable job",
+			rows:        sqlmock.NewRows([]string{"id", "status", "claims"}),
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT \* FROM "import_jobs" WHERE status IN \(\$1,\$2\) AND lease_until <= \$3 `+
+				`ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED`).
+				WithArgs(ImportQueued, ImportRunning, now).WillReturnRows(test.rows)
+			if test.expectedErr != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectQuery(`UPDATE "import_jobs" SET "claims"=claims \+ 1,"lease_until"=\$1,`+
+					`"started_at"=COALESCE\(started_at, \$2\),"status"=\$3 WHERE "id" = \$4 RETURNING \*`).
+					WithArgs(now.Add(time.Minute), now, ImportRunning, 4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status", "claims", "lease_until"}).
+						AddRow(4, "running", 1, now.Add(time.Minute)))
+				mock.ExpectQuery(`SELECT \* FROM "import_uploads" WHERE "import_uploads"\."job_id" = \$1 LIMIT 1`).
+					WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"job_id", "data"}).AddRow(4, []byte("data")))
+				mock.ExpectCommit()
+			}
+
+			job, data, err := store.ClaimImportJob(context.Background(), now, time.Minute)
+
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, ImportJob{ID: 4, Status: ImportRunning, Claims: 1, LeaseUntil: now.Add(time.Minute)}, job)
+				assert.Equal(t, "data", string(data))
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRecordImportProgress(t *testing.T) {
+	leaseUntil := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	updateJob := `UPDATE "import_jobs" SET "created_items"=\$1,"error"=\$2,(?:"finished_at"=\$\d+,)?"lease_until"=\$\d+,` +
+		`"processed_rows"=\$\d+,"rejected_rows"=\$\d+,"status"=\$\d+,"unchanged_items"=\$\d+,"updated_items"=\$\d+ ` +
+		`WHERE id = \$\d+ AND claims = \$\d+ AND status = \$\d+`
+	insertRejection := `INSERT INTO "import_rejections" \("job_id","row_number","external_id","field","message"\) ` +
+		`VALUES \(\$1,\$2,\$3,\$4,\$5\) RETURNING "id"`
+
+	t.Run("Running", func(t *testing.T) {
+		store, mock := newMockStore(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(updateJob).
+			WithArgs(1, nil, leaseUntil, 2, 1, ImportRunning, 0, 0, 4, 1, ImportRunning).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertRejection).WithArgs(4, 2, nil, "price", "invalid price").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		err := store.RecordImportProgress(context.Background(), 4, 1, ImportProgress{
+			ImportCounts: ImportCounts{ProcessedRows: 2, CreatedItems: 1, RejectedRows: 1},
+			Rejections:   []ImportRejection{{Row: 2, Field: v2p("price"), Message: "invalid price"}},
+			Status:       ImportRunning,
+			LeaseUntil:   leaseUntil,
+		})
+
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Completed", func(t *testing.T) {
+		store, mock := newMockStore(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(updateJob).
+			WithArgs(1, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), 2, 0, ImportCompleted, 1, 0, 4, 1, ImportRunning).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM "import_uploads" WHERE "import_uploads"\."job_id" = \$1`).WithArgs(4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := store.RecordImportProgress(context.Background(), 4, 1, ImportProgress{
+			ImportCounts: ImportCounts{ProcessedRows: 2, CreatedItems: 1, UnchangedItems: 1},
+			Status:       ImportCompleted,
+		})
+
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Claimed by another worker", func(t *testing.T) {
+		store, mock := newMockStore(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(updateJob).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := store.RecordImportProgress(context.Background(), 4, 1, ImportProgress{Status: ImportFailed})
+
+		assert.ErrorIs(t, err, ErrImportJobLost)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Invalid status", func(t *testing.T) {
+		store, mock := newMockStore(t)
+
+		err := store.RecordImportProgress(context.Background(), 4, 1, ImportProgress{Status: ImportQueued})
+
+		assert.ErrorIs(t, err, ErrInvalidImport)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
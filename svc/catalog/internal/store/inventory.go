@@ -0,0 +1,503 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"time"
+)
+
+// checkViolation is postgres error code reported when check constraint is violated
+const checkViolation = "23514"
+
+var (
+	ErrInvalidStock          = errors.New("invalid stock operation")
+	ErrInsufficientStock     = errors.New("insufficient stock")
+	ErrReservationNotPending = errors.New("reservation is no longer pending")
+	ErrReservationExpired    = errors.New("reservation expired")
+)
+
+// ReservationStatus describes lifecycle of the reservation. Only pending reservations hold stock.
+type ReservationStatus string
+
+const (
+	ReservationPending   ReservationStatus = "pending"
+	ReservationCommitted ReservationStatus = "committed"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationExpired   ReservationStatus = "expired"
+)
+
+// releaseExpiredReservations marks pending reservations which expired before provided time as expired and returns
+// their quantities to stock. Reservations can be narrowed down to a single stock level, zero item ID means all.
+// Number of expired reservations is returned.
+const releaseExpiredReservations = `WITH expired AS (
+	UPDATE reservations SET status = 'expired'
+	WHERE status = 'pending' AND expires_at <= ? AND (? = 0 OR (item_id = ? AND variant_id = ?))
+	RETURNING item_id, variant_id, quantity
+), released AS (
+	UPDATE stock_levels SET reserved = stock_levels.reserved - sums.quantity
+	FROM (SELECT item_id, variant_id, sum(quantity) AS quantity FROM expired GROUP BY item_id, variant_id) sums
+	WHERE stock_levels.item_id = sums.item_id AND stock_levels.variant_id = sums.variant_id
+) SELECT count(*) FROM expired`
+
+// StockKey addresses stock level of the item, or of its variant
+type StockKey struct {
+	ItemID uint
+	// VariantID is zero for stock kept for the item as a whole
+	VariantID uint
+}
+
+// Stock is a stock level of the item or its variant. Reserved units are held by pending reservations and cannot be
+// reserved again.
+type Stock struct {
+	ItemID    uint `gorm:"primaryKey;autoIncrement:false"`
+	VariantID uint `gorm:"primaryKey;autoIncrement:false"`
+	OnHand    int64
+	Reserved  int64
+}
+
+// TableName overrides name of the table stock levels are kept in
+func (Stock) TableName() string {
+	return "stock_levels"
+}
+
+// Key returns key addressing stock level
+func (s Stock) Key() StockKey {
+	return StockKey{ItemID: s.ItemID, VariantID: s.VariantID}
+}
+
+// Available returns number of units which can be reserved
+func (s Stock) Available() int64 {
+	return s.OnHand - s.Reserved
+}
+
+// Reservation holds quantity of stock for in-progress checkout until it is committed, released or it expires
+type Reservation struct {
+	ID        uint
+	ItemID    uint
+	VariantID uint
+	Quantity  int64
+	Status    ReservationStatus
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// Key returns key addressing stock level the reservation holds units of
+func (r Reservation) Key() StockKey {
+	return StockKey{ItemID: r.ItemID, VariantID: r.VariantID}
+}
+
+// GetStock returns stock level addressed by key. Zero stock is returned for items and variants which stock was never
+// adjusted.
+func (s *CatalogStore) GetStock(ctx context.Context, key StockKey) (Stock, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	stock := Stock{ItemID: key.ItemID, VariantID: key.VariantID}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := stockKeyExists(tx, key); err != nil {
+			return err
+		}
+		err := tx.Where("item_id = ? AND variant_id = ?", key.ItemID, key.VariantID).Take(&stock).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return Stock{}, fmt.Errorf("error while getting stock of item with id %d: %w", key.ItemID, err)
+	}
+	return stock, nil
+}
+
+// GetStockLevels returns all stock levels of items with provided IDs
+func (s *CatalogStore) GetStockLevels(ctx context.Context, itemIDs []uint) (levels []Stock, err error) {
+	if len(itemIDs) == 0 {
+		return nil, nil
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err = db.Where("item_id IN ?", itemIDs).Order("item_id").Order("variant_id").Find(&levels).Error
+	return
+}
+
+// AdjustStock changes number of units on hand by delta and returns new stock level. Stock cannot drop below number of
+// reserved units, otherwise ErrInsufficientStock is returned.
+func (s *CatalogStore) AdjustStock(ctx context.Context, key StockKey, delta int64) (Stock, error) {
+	if delta == 0 {
+		return Stock{}, fmt.Errorf("%w: delta cannot be zero", ErrInvalidStock)
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	stock := Stock{ItemID: key.ItemID, VariantID: key.VariantID, OnHand: delta}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := stockKeyExists(tx, key); err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "item_id"}, {Name: "variant_id"}},
+			DoUpdates: clause.Set{{Column: clause.Column{Name: "on_hand"}, Value: gorm.Expr("stock_levels.on_hand + ?", delta)}},
+		}, clause.Returning{}).Create(&stock).Error
+	})
+	if err != nil {
+		return Stock{}, fmt.Errorf("error while adjusting stock of item with id %d: %w", key.ItemID, stockErr(err))
+	}
+	return stock, nil
+}
+
+// Reserve holds quantity of available units until reservation expires. Concurrent reservations never hold more
+// units than there are on hand, ErrInsufficientStock is returned instead.
+func (s *CatalogStore) Reserve(ctx context.Context, key StockKey, quantity int64, ttl time.Duration) (Reservation, error) {
+	if quantity < 1 || ttl <= 0 {
+		return Reservation{}, fmt.Errorf("%w: quantity and ttl should be positive", ErrInvalidStock)
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	now := time.Now()
+	reservation := Reservation{
+		ItemID:    key.ItemID,
+		VariantID: key.VariantID,
+		Quantity:  quantity,
+		Status:    ReservationPending,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := stockKeyExists(tx, key); err != nil {
+			return err
+		}
+		if err := tx.Exec(releaseExpiredReservations, now, key.ItemID, key.ItemID, key.VariantID).Error; err != nil {
+			return err
+		}
+		resp := tx.Model(&Stock{}).
+			Where("item_id = ? AND variant_id = ? AND on_hand - reserved >= ?", key.ItemID, key.VariantID, quantity).
+			Update("reserved", gorm.Expr("reserved + ?", quantity))
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if resp.RowsAffected == 0 {
+			return ErrInsufficientStock
+		}
+		return tx.Create(&reservation).Error
+	})
+	if err != nil {
+		return Reservation{}, fmt.Errorf("error while reserving stock of item with id %d: %w", key.ItemID, err)
+	}
+	return reservation, nil
+}
+
+// GetReservation returns reservation with provided ID
+func (s *CatalogStore) GetReservation(ctx context.Context, id uint) (reservation Reservation, err error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err = db.First(&reservation, id).Error
+	return
+}
+
+// CommitReservation removes reserved units from stock, e.g. once order is placed
+func (s *CatalogStore) CommitReservation(ctx context.Context, id uint) (Reservation, error) {
+	return s.finishReservation(ctx, id, ReservationCommitted)
+}
+
+// ReleaseReservation returns reserved units to available stock, e.g. once checkout is abandoned
+func (s *CatalogStore) ReleaseReservation(ctx context.Context, id uint) (Reservation, error) {
+	return s.finishReservation(ctx, id, ReservationReleased)
+}
+
+// ReleaseExpiredReservations returns units held by reservations which expired before provided time to available stock
+// and returns number of expired reservations
+func (s *CatalogStore) ReleaseExpiredReservations(ctx context.Context, now time.Time) (int64, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var expired int64
+	if err := db.Raw(releaseExpiredReservations, now, 0, 0, 0).Scan(&expired).Error; err != nil {
+		return 0, fmt.Errorf("error while releasing expired reservations: %w", err)
+	}
+	return expired, nil
+}
+
+// finishReservation moves pending reservation to provided status. Committed reservations remove units from stock,
+// released and expired ones make them available again.
+func (s *CatalogStore) finishReservation(ctx context.Context, id uint, status ReservationStatus) (Reservation, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var reservation Reservation
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reservation, id).Error; err != nil {
+			return err
+		}
+		if reservation.Status != ReservationPending {
+			return fmt.Errorf("%w: reservation is %s", ErrReservationNotPending, reservation.Status)
+		}
+		if !reservation.ExpiresAt.After(time.Now()) {
+			status = ReservationExpired
+		}
+		updates := map[string]interface{}{"reserved": gorm.Expr("reserved - ?", reservation.Quantity)}
+		if status == ReservationCommitted {
+			updates["on_hand"] = gorm.Expr("on_hand - ?", reservation.Quantity)
+		}
+		err := tx.Model(&Stock{}).Where("item_id = ? AND variant_id = ?", reservation.ItemID, reservation.VariantID).
+			Updates(updates).Error
+		if err != nil {
+			return err
+		}
+		reservation.Status = status
+		return tx.Model(&reservation).Update("status", status).Error
+	})
+	if err == nil && reservation.Status == ReservationExpired {
+		err = ErrReservationExpired
+	}
+	if err != nil {
+		return Reservation{}, fmt.Errorf("error while finishing reservation with id %d: %w", id, err)
+	}
+	return reservation, nil
+}
+
+// stockKeyExists returns ErrRecordNotFound unless item exists and, when set, the variant belongs to it
+func stockKeyExists(tx *gorm.DB, key StockKey) error {
+	var count int64
+	if err := tx.Model(&Item{}).Where("id = ?", key.ItemID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 1 && key.VariantID != 0 {
+		err := tx.Model(&Variant{}).Where("id = ? AND item_id = ?", key.VariantID, key.ItemID).Count(&count).Error
+		if err != nil {
+			return err
+		}
+	}
+	if count != 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// stockErr translates violation of stock level constraints into ErrInsufficientStock
+func stockErr(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == checkViolation {
+		return fmt.Errorf("%w: stock cannot drop below reserved quantity", ErrInsufficientStock)
+	}
+	return err
+}
+
+// GetStock returns stock level addressed by key. Zero stock is returned for items and variants which stock was never
+// adjusted.
+func (s *MemoryStore) GetStock(ctx context.Context, key StockKey) (Stock, error) {
+	if err := ctx.Err(); err != nil {
+		return Stock{}, fmt.Errorf("error while getting stock of item with id %d: %w", key.ItemID, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.stockKeyExists(key) {
+		return Stock{}, fmt.Errorf("error while getting stock of item with id %d: %w", key.ItemID, gorm.ErrRecordNotFound)
+	}
+	return s.stock(key), nil
+}
+
+// GetStockLevels returns all stock levels of items with provided IDs
+func (s *MemoryStore) GetStockLevels(ctx context.Context, itemIDs []uint) ([]Stock, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var levels []Stock
+	for _, level := range s.stockLevels {
+		if containsID(itemIDs, level.ItemID) {
+			levels = append(levels, level)
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		if levels[i].ItemID != levels[j].ItemID {
+			return levels[i].ItemID < levels[j].ItemID
+		}
+		return levels[i].VariantID < levels[j].VariantID
+	})
+	return levels, nil
+}
+
+// AdjustStock changes number of units on hand by delta and returns new stock level. Stock cannot drop below number of
+// reserved units, otherwise ErrInsufficientStock is returned.
+func (s *MemoryStore) AdjustStock(ctx context.Context, key StockKey, delta int64) (Stock, error) {
+	if delta == 0 {
+		return Stock{}, fmt.Errorf("%w: delta cannot be zero", ErrInvalidStock)
+	}
+	if err := ctx.Err(); err != nil {
+		return Stock{}, fmt.Errorf("error while adjusting stock of item with id %d: %w", key.ItemID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stockKeyExists(key) {
+		return Stock{}, fmt.Errorf("error while adjusting stock of item with id %d: %w", key.ItemID, gorm.ErrRecordNotFound)
+	}
+	stock := s.stock(key)
+	stock.OnHand += delta
+	if stock.OnHand < stock.Reserved {
+		return Stock{}, fmt.Errorf("error while adjusting stock of item with id %d: %w: stock cannot drop below reserved quantity",
+			key.ItemID, ErrInsufficientStock)
+	}
+	s.stockLevels[key] = stock
+	return stock, nil
+}
+
+// Reserve holds quantity of available units until reservation expires. Reservations never hold more units than there
+// are on hand, ErrInsufficientStock is returned instead.
+func (s *MemoryStore) Reserve(ctx context.Context, key StockKey, quantity int64, ttl time.Duration) (Reservation, error) {
+	if quantity < 1 || ttl <= 0 {
+		return Reservation{}, fmt.Errorf("%w: quantity and ttl should be positive", ErrInvalidStock)
+	}
+	if err := ctx.Err(); err != nil {
+		return Reservation{}, fmt.Errorf("error while reserving stock of item with id %d: %w", key.ItemID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stockKeyExists(key) {
+		return Reservation{}, fmt.Errorf("error while reserving stock of item with id %d: %w", key.ItemID, gorm.ErrRecordNotFound)
+	}
+	now := s.now()
+	s.releaseExpired(now)
+	stock, ok := s.stockLevels[key]
+	if !ok || stock.Available() < quantity {
+		return Reservation{}, fmt.Errorf("error while reserving stock of item with id %d: %w", key.ItemID, ErrInsufficientStock)
+	}
+	stock.Reserved += quantity
+	s.stockLevels[key] = stock
+	s.lastReservationID++
+	reservation := Reservation{
+		ID:        s.lastReservationID,
+		ItemID:    key.ItemID,
+		VariantID: key.VariantID,
+		Quantity:  quantity,
+		Status:    ReservationPending,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	s.reservations[reservation.ID] = reservation
+	return reservation, nil
+}
+
+// GetReservation returns reservation with provided ID
+func (s *MemoryStore) GetReservation(ctx context.Context, id uint) (Reservation, error) {
+	if err := ctx.Err(); err != nil {
+		return Reservation{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	reservation, ok := s.reservations[id]
+	if !ok {
+		return Reservation{}, gorm.ErrRecordNotFound
+	}
+	return reservation, nil
+}
+
+// CommitReservation removes reserved units from stock, e.g. once order is placed
+func (s *MemoryStore) CommitReservation(ctx context.Context, id uint) (Reservation, error) {
+	return s.finishReservation(ctx, id, ReservationCommitted)
+}
+
+// ReleaseReservation returns reserved units to available stock, e.g. once checkout is abandoned
+func (s *MemoryStore) ReleaseReservation(ctx context.Context, id uint) (Reservation, error) {
+	return s.finishReservation(ctx, id, ReservationReleased)
+}
+
+// ReleaseExpiredReservations returns units held by reservations which expired before provided time to available stock
+// and returns number of expired reservations
+func (s *MemoryStore) ReleaseExpiredReservations(ctx context.Context, now time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("error while releasing expired reservations: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.releaseExpired(now), nil
+}
+
+// finishReservation moves pending reservation to provided status. Committed reservations remove units from stock,
+// released and expired ones make them available again.
+func (s *MemoryStore) finishReservation(ctx context.Context, id uint, status ReservationStatus) (Reservation, error) {
+	if err := ctx.Err(); err != nil {
+		return Reservation{}, fmt.Errorf("error while finishing reservation with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reservation, ok := s.reservations[id]
+	if !ok {
+		return Reservation{}, fmt.Errorf("error while finishing reservation with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	if reservation.Status != ReservationPending {
+		return Reservation{}, fmt.Errorf("error while finishing reservation with id %d: %w: reservation is %s",
+			id, ErrReservationNotPending, reservation.Status)
+	}
+	if !reservation.ExpiresAt.After(s.now()) {
+		s.releaseExpired(s.now())
+		return Reservation{}, fmt.Errorf("error while finishing reservation with id %d: %w", id, ErrReservationExpired)
+	}
+	stock := s.stockLevels[reservation.Key()]
+	stock.Reserved -= reservation.Quantity
+	if status == ReservationCommitted {
+		stock.OnHand -= reservation.Quantity
+	}
+	s.stockLevels[reservation.Key()] = stock
+	reservation.Status = status
+	s.reservations[id] = reservation
+	return reservation, nil
+}
+
+// releaseExpired marks pending reservations which expired before provided time as expired and returns their
+// quantities to stock. Caller has to hold the lock.
+func (s *MemoryStore) releaseExpired(now time.Time) int64 {
+	var expired int64
+	for id, reservation := range s.reservations {
+		if reservation.Status != ReservationPending || reservation.ExpiresAt.After(now) {
+			continue
+		}
+		stock := s.stockLevels[reservation.Key()]
+		stock.Reserved -= reservation.Quantity
+		s.stockLevels[reservation.Key()] = stock
+		reservation.Status = ReservationExpired
+		s.reservations[id] = reservation
+		expired++
+	}
+	return expired
+}
+
+// stockKeyExists reports if item exists and, when set, the variant belongs to it. Caller has to hold the lock.
+func (s *MemoryStore) stockKeyExists(key StockKey) bool {
+	if _, ok := s.live(key.ItemID); !ok {
+		return false
+	}
+	variant, ok := s.variants[key.VariantID]
+	return key.VariantID == 0 || (ok && variant.ItemID == key.ItemID)
+}
+
+// stock returns stock level addressed by key, zero one when it was never adjusted. Caller has to hold the lock.
+func (s *MemoryStore) stock(key StockKey) Stock {
+	stock, ok := s.stockLevels[key]
+	if !ok {
+		return Stock{ItemID: key.ItemID, VariantID: key.VariantID}
+	}
+	return stock
+}
+
+// deleteStock removes stock levels and reservations matching predicate. Caller has to hold the lock.
+func (s *MemoryStore) deleteStock(matches func(key StockKey) bool) {
+	for key := range s.stockLevels {
+		if matches(key) {
+			delete(s.stockLevels, key)
+		}
+	}
+	for id, reservation := range s.reservations {
+		if matches(reservation.Key()) {
+			delete(s.reservations, id)
+		}
+	}
+}
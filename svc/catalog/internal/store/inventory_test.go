@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAdjustStock(t *testing.T) {
+	tests := []struct {
+		name        string
+		upsertErr   error
+		expectedErr error
+	}{
+		{
+			name: "Successful - stock adjusted",
+		},
+		{
+			name:        "Conflict - stock drops below reserved quantity",
+			upsertErr:   &pgconn.PgError{Code: checkViolation},
+			expectedErr: ErrInsufficientStock,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT count\(\*\) FROM "items" WHERE id = \$1`).WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			mock.ExpectQuery(`SELECT count\(\*\) FROM "variants" WHERE id = \$1 AND item_id = \$2`).WithArgs(2, 1).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			upsert := mock.ExpectQuery(`INSERT INTO "stock_levels" \("item_id","variant_id","on_hand","reserved"\) `+
+				`VALUES \(\$1,\$2,\$3,\$4\) ON CONFLICT \("item_id","variant_id"\) `+
+				`DO UPDATE SET "on_hand"=stock_levels\.on_hand \+ \$5 RETURNING \*`).
+				WithArgs(1, 2, -2, 0, -2)
+			if test.upsertErr != nil {
+				upsert.WillReturnError(test.upsertErr)
+				mock.ExpectRollback()
+			} else {
+				upsert.WillReturnRows(sqlmock.NewRows([]string{"item_id", "variant_id", "on_hand", "reserved"}).AddRow(1, 2, 3, 1))
+				mock.ExpectCommit()
+			}
+
+			stock, err := store.AdjustStock(context.Background(), StockKey{ItemID: 1, VariantID: 2}, -2)
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, Stock{ItemID: 1, VariantID: 2, OnHand: 3, Reserved: 1}, stock)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReserve(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expectedErr  error
+	}{
+		{
+			name:         "Successful - stock reserved",
+			rowsAffected: 1,
+		},
+		{
+			name:        "Conflict - not enough available stock",
+			expectedErr: ErrInsufficientStock,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT count\(\*\) FROM "items" WHERE id = \$1`).WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			mock.ExpectExec(`WITH expired AS \( UPDATE reservations SET status = 'expired'`).
+				WithArgs(sqlmock.AnyArg(), 1, 1, 0).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(`UPDATE "stock_levels" SET "reserved"=reserved \+ \$1 `+
+				`WHERE item_id = \$2 AND variant_id = \$3 AND on_hand - reserved >= \$4`).WithArgs(2, 1, 0, 2).
+				WillReturnResult(sqlmock.NewResult(0, test.rowsAffected))
+			if test.expectedErr != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectQuery(`INSERT INTO "reservations"`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				mock.ExpectCommit()
+			}
+
+			reservation, err := store.Reserve(context.Background(), StockKey{ItemID: 1}, 2, time.Minute)
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, uint(7), reservation.ID)
+				assert.Equal(t, ReservationPending, reservation.Status)
+				assert.Equal(t, time.Minute, reservation.ExpiresAt.Sub(reservation.CreatedAt))
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReleaseExpiredReservations(t *testing.T) {
+	store, mock := newMockStore(t)
+	now := time.Now()
+	mock.ExpectQuery(`WITH expired AS \( UPDATE reservations SET status = 'expired' `+
+		`WHERE status = 'pending' AND expires_at <= \$1`).WithArgs(now, 0, 0, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	released, err := store.ReleaseExpiredReservations(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), released)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
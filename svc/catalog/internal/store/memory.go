@@ -0,0 +1,392 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"gorm.io/gorm"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is thread-safe, in-memory implementation of the catalog store meant for local development and tests.
+// It mirrors semantics of CatalogStore, including returned errors.
+type MemoryStore struct {
+	mu             sync.RWMutex
+	items          map[uint]Item
+	lastID         uint
+	categories     map[uint]Category
+	lastCategoryID uint
+	// itemCategories holds IDs of categories every item is assigned to
+	itemCategories map[uint]map[uint]bool
+	variants       map[uint]Variant
+	lastVariantID  uint
+	// itemPrices holds price lists of items ordered by currency
+	itemPrices      map[uint][]ItemPrice
+	scheduledPrices map[uint]ScheduledPrice
+	// lastScheduledPriceID is the ID assigned to the most recently scheduled price
+	lastScheduledPriceID uint
+	// history holds changes of every item, oldest first
+	history       map[uint][]ItemChange
+	lastHistoryID uint
+	// events holds the outbox ordered by ID, events before firstPendingEvent were already published
+	events            []Event
+	firstPendingEvent int
+	lastEventID       uint64
+	// lastEventPosition is the stream position assigned to the most recently published event
+	lastEventPosition uint64
+	// relayMu serializes relaying of events, which happens without holding mu
+	relayMu        sync.Mutex
+	webhooks       map[uint]Webhook
+	lastWebhookID  uint
+	deliveries     map[uint]WebhookDelivery
+	lastDeliveryID uint
+	importJobs     map[uint]ImportJob
+	// importUploads holds uploaded files of unfinished import jobs
+	importUploads         map[uint][]byte
+	lastImportJobID       uint
+	importRejections      map[uint][]ImportRejection
+	lastImportRejectionID uint
+	images                map[uint]ItemImage
+	lastImageID           uint
+	mediaDeletions        map[string]bool
+	stockLevels           map[StockKey]Stock
+	reservations          map[uint]Reservation
+	// lastReservationID is the ID assigned to the most recent reservation
+	lastReservationID uint
+	now               func() time.Time
+	// exchangeRates are rates prices are converted with, set with SetExchangeRates
+	exchangeRates money.Rates
+}
+
+// NewMemoryStore creates empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		items:            map[uint]Item{},
+		categories:       map[uint]Category{},
+		itemCategories:   map[uint]map[uint]bool{},
+		variants:         map[uint]Variant{},
+		itemPrices:       map[uint][]ItemPrice{},
+		scheduledPrices:  map[uint]ScheduledPrice{},
+		history:          map[uint][]ItemChange{},
+		webhooks:         map[uint]Webhook{},
+		deliveries:       map[uint]WebhookDelivery{},
+		importJobs:       map[uint]ImportJob{},
+		importUploads:    map[uint][]byte{},
+		importRejections: map[uint][]ImportRejection{},
+		images:           map[uint]ItemImage{},
+		mediaDeletions:   map[string]bool{},
+		stockLevels:      map[StockKey]Stock{},
+		reservations:     map[uint]Reservation{},
+		now:              time.Now,
+	}
+}
+
+// CreateItem persists Item in memory and returns it with assigned ID
+func (s *MemoryStore) CreateItem(ctx context.Context, item Item) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, fmt.Errorf("error while adding item to db: %w", err)
+	}
+	if err := item.validateNew(); err != nil {
+		return Item{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	created, err := s.createItem(ctx, item)
+	if err != nil {
+		return Item{}, fmt.Errorf("error while adding item to db: %w", err)
+	}
+	return created, nil
+}
+
+// createItem adds item with the next ID and records its creation. Caller has to hold the lock.
+func (s *MemoryStore) createItem(ctx context.Context, item Item) (Item, error) {
+	if item.ExternalID != nil {
+		if _, ok := s.liveByExternalID(*item.ExternalID); ok {
+			return Item{}, ErrExternalIDConflict
+		}
+	}
+	s.lastID++
+	item.ID = s.lastID
+	item.Version = 1
+	item.DeletedAt = gorm.DeletedAt{}
+	s.items[item.ID] = cloneItem(item)
+	s.recordChange(ctx, item, HistoryCreated)
+	return cloneItem(item), nil
+}
+
+// DeleteItem moves item with ID to trash. When ifVersion is not zero, item is deleted only if its current
+// version matches, otherwise ErrVersionMismatch is returned.
+func (s *MemoryStore) DeleteItem(ctx context.Context, id uint, ifVersion uint) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error while deleting item with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.deleteItem(ctx, id, ifVersion); err != nil {
+		return fmt.Errorf("error while deleting item with id %d: %w", id, err)
+	}
+	return nil
+}
+
+// deleteItem moves item with ID to trash, records its deletion and returns deleted item. Caller has to hold the lock.
+func (s *MemoryStore) deleteItem(ctx context.Context, id uint, ifVersion uint) (Item, error) {
+	stored, ok := s.live(id)
+	if !ok {
+		return Item{}, gorm.ErrRecordNotFound
+	}
+	if ifVersion != 0 && stored.Version != ifVersion {
+		return Item{}, ErrVersionMismatch
+	}
+	stored.DeletedAt = gorm.DeletedAt{Time: s.now(), Valid: true}
+	s.items[id] = stored
+	s.recordChange(ctx, stored, HistoryDeleted)
+	return cloneItem(stored), nil
+}
+
+// GetDeletedItems returns requested page of deleted items, most recently deleted first
+func (s *MemoryStore) GetDeletedItems(ctx context.Context, pageSize, page int) ([]Item, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, ErrInvalidPageParams
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var items []Item
+	for _, item := range s.items {
+		if item.DeletedAt.Valid {
+			items = append(items, cloneItem(item))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].DeletedAt.Time.Equal(items[j].DeletedAt.Time) {
+			return items[i].DeletedAt.Time.After(items[j].DeletedAt.Time)
+		}
+		return items[i].ID < items[j].ID
+	})
+	return paginate(items, pageSize, page), nil
+}
+
+// RestoreItem brings back deleted item with ID and returns its new state. ErrExternalIDConflict is returned when
+// external ID of the item was taken by another one in the meantime.
+func (s *MemoryStore) RestoreItem(ctx context.Context, id uint) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, fmt.Errorf("error while restoring item with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.items[id]
+	if !ok || !stored.DeletedAt.Valid {
+		return Item{}, fmt.Errorf("error while restoring item with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	if stored.ExternalID != nil {
+		if _, ok := s.liveByExternalID(*stored.ExternalID); ok {
+			return Item{}, fmt.Errorf("error while restoring item with id %d: %w", id, ErrExternalIDConflict)
+		}
+	}
+	stored.DeletedAt = gorm.DeletedAt{}
+	stored.Version++
+	s.items[id] = stored
+	s.recordChange(ctx, stored, HistoryRestored)
+	return cloneItem(stored), nil
+}
+
+// PurgeDeletedItems permanently removes items deleted before provided time and returns number of removed items.
+// Blobs of their images are queued for deletion from media storage.
+func (s *MemoryStore) PurgeDeletedItems(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("error while purging deleted items: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var purged int64
+	for id, item := range s.items {
+		if item.DeletedAt.Valid && item.DeletedAt.Time.Before(deletedBefore) {
+			delete(s.items, id)
+			delete(s.itemCategories, id)
+			delete(s.itemPrices, id)
+			for priceID, price := range s.scheduledPrices {
+				if price.ItemID == id {
+					delete(s.scheduledPrices, priceID)
+				}
+			}
+			for variantID, variant := range s.variants {
+				if variant.ItemID == id {
+					delete(s.variants, variantID)
+				}
+			}
+			for _, image := range s.imagesOf(id) {
+				s.deleteImage(image)
+			}
+			s.deleteStock(func(key StockKey) bool { return key.ItemID == id })
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// GetItem returns item with provided ID from memory
+func (s *MemoryStore) GetItem(ctx context.Context, id uint) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, fmt.Errorf("error while getting item with id %d: %w", id, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	item, ok := s.live(id)
+	if !ok {
+		return Item{}, fmt.Errorf("error while getting item with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	return cloneItem(item), nil
+}
+
+// GetItems returns requested page of filtered and sorted items. Page is selected by cursor when set, otherwise by
+// page number.
+func (s *MemoryStore) GetItems(ctx context.Context, query ItemQuery) ([]Item, error) {
+	order, last, err := query.validate()
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	items := make([]Item, 0, len(s.items))
+	for _, item := range s.items {
+		if item.DeletedAt.Valid || !query.ItemFilter.matches(item) || !s.inCategory(query.ItemFilter, item.ID) {
+			continue
+		}
+		if last != nil && !order.less(*last, item) {
+			continue
+		}
+		items = append(items, cloneItem(item))
+	}
+	sort.Slice(items, func(i, j int) bool { return order.less(items[i], items[j]) })
+	return paginate(items, query.PageSize, query.Page), nil
+}
+
+// CountItems returns number of items matching the filter
+func (s *MemoryStore) CountItems(ctx context.Context, filter ItemFilter) (int64, error) {
+	if err := filter.validate(); err != nil {
+		return 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var count int64
+	for _, item := range s.items {
+		if !item.DeletedAt.Valid && filter.matches(item) && s.inCategory(filter, item.ID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// UpdateItem updates set fields of item with ID in memory and returns its new state. When ifVersion is not zero,
+// item is updated only if its current version matches, otherwise ErrVersionMismatch is returned.
+func (s *MemoryStore) UpdateItem(ctx context.Context, id uint, item Item, ifVersion uint) (Item, error) {
+	if err := item.validate(); err != nil {
+		return Item{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return Item{}, fmt.Errorf("error while updating item with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.updateItem(ctx, id, item, ifVersion)
+}
+
+// updateItem updates item with ID, records the change and returns new state of the item. Caller has to hold the lock.
+func (s *MemoryStore) updateItem(ctx context.Context, id uint, item Item, ifVersion uint) (Item, error) {
+	stored, ok := s.live(id)
+	if !ok {
+		return Item{}, gorm.ErrRecordNotFound
+	}
+	if ifVersion != 0 && stored.Version != ifVersion {
+		return Item{}, ErrVersionMismatch
+	}
+	if item.Name != nil {
+		stored.Name = item.Name
+	}
+	if item.Description != nil {
+		stored.Description = item.Description
+	}
+	if item.Price != nil {
+		stored.Price = item.Price
+	}
+	if item.PriceCode != nil {
+		stored.PriceCode = item.PriceCode
+	}
+	stored.Version++
+	s.items[id] = cloneItem(stored)
+	s.recordChange(ctx, stored, HistoryUpdated)
+	return cloneItem(stored), nil
+}
+
+// live returns item with ID unless it doesn't exist or was deleted. Caller has to hold the lock.
+func (s *MemoryStore) live(id uint) (Item, bool) {
+	item, ok := s.items[id]
+	if !ok || item.DeletedAt.Valid {
+		return Item{}, false
+	}
+	return item, true
+}
+
+// liveByExternalID returns live item with external ID. Caller has to hold the lock.
+func (s *MemoryStore) liveByExternalID(externalID string) (Item, bool) {
+	for _, item := range s.items {
+		if !item.DeletedAt.Valid && item.ExternalID != nil && *item.ExternalID == externalID {
+			return item, true
+		}
+	}
+	return Item{}, false
+}
+
+// paginate returns requested page of sorted elements
+func paginate[V any](elems []V, pageSize, page int) []V {
+	from := (page - 1) * pageSize
+	if from >= len(elems) {
+		return []V{}
+	}
+	to := from + pageSize
+	if to > len(elems) {
+		to = len(elems)
+	}
+	return elems[from:to]
+}
+
+// cloneItem returns copy of item which doesn't share pointers with the original
+func cloneItem(item Item) Item {
+	return Item{
+		ID:          item.ID,
+		ExternalID:  clonePtr(item.ExternalID),
+		Name:        clonePtr(item.Name),
+		Description: clonePtr(item.Description),
+		Price:       clonePtr(item.Price),
+		PriceCode:   clonePtr(item.PriceCode),
+		Version:     item.Version,
+		DeletedAt:   item.DeletedAt,
+	}
+}
+
+func clonePtr[V any](v *V) *V {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
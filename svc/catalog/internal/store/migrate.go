@@ -0,0 +1,232 @@
+package store
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// migrationLockID is a key of postgres advisory lock taken for the time of running migrations
+const migrationLockID = 7403121
+
+//go:embed migrations/*.sql
+var embeddedMigrations embed.FS
+
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version integer PRIMARY KEY,
+	name varchar(250) NOT NULL,
+	applied_at timestamptz NOT NULL
+)`
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+var ErrNoMigrationToRollback = errors.New("there is no applied migration to roll back")
+
+// Migration represents single, versioned schema change
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus represents migration together with information when it was applied
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// schemaMigration represents schema_migrations tracking table in underlying db
+type schemaMigration struct {
+	Version   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator creates Migrator running migrations embedded in binary
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(embeddedMigrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Migrator returns Migrator operating on the store db
+func (s *CatalogStore) Migrator() (*Migrator, error) {
+	return NewMigrator(s.db)
+}
+
+// Up applies all pending migrations in version order and returns the ones which were applied
+func (m *Migrator) Up(ctx context.Context) (applied []Migration, err error) {
+	err = m.withLock(ctx, func(conn *gorm.DB) error {
+		done, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(mig.Up).Error; err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now().UTC()}).Error
+			}); err != nil {
+				return fmt.Errorf("error while applying migration %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return
+}
+
+// Down rolls back the most recently applied migration and returns it
+func (m *Migrator) Down(ctx context.Context) (rolledBack Migration, err error) {
+	err = m.withLock(ctx, func(conn *gorm.DB) error {
+		var last schemaMigration
+		if err := conn.Order("version DESC").Limit(1).Find(&last).Error; err != nil {
+			return fmt.Errorf("error while getting last applied migration: %w", err)
+		}
+		if last.Version == 0 {
+			return ErrNoMigrationToRollback
+		}
+		mig, ok := m.find(last.Version)
+		if !ok {
+			return fmt.Errorf("applied migration %04d_%s is unknown to this binary", last.Version, last.Name)
+		}
+		if err := conn.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(mig.Down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, mig.Version).Error
+		}); err != nil {
+			return fmt.Errorf("error while rolling back migration %04d_%s: %w", mig.Version, mig.Name, err)
+		}
+		rolledBack = mig
+		return nil
+	})
+	return
+}
+
+// Status returns all known migrations with information if and when they were applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn := m.db.WithContext(ctx)
+	if err := conn.Exec(createMigrationsTable).Error; err != nil {
+		return nil, fmt.Errorf("error while creating migrations table: %w", err)
+	}
+	done, err := appliedVersions(conn)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		status := MigrationStatus{Migration: mig}
+		if applied, ok := done[mig.Version]; ok {
+			status.AppliedAt = &applied.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// withLock runs fn on a single connection holding migration advisory lock, so concurrently starting
+// instances do not apply the same migration twice
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) (err error) {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockID).Error; err != nil {
+			return fmt.Errorf("error while acquiring migration lock: %w", err)
+		}
+		defer func() {
+			if unlockErr := conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockID).Error; unlockErr != nil && err == nil {
+				err = fmt.Errorf("error while releasing migration lock: %w", unlockErr)
+			}
+		}()
+		if err := conn.Exec(createMigrationsTable).Error; err != nil {
+			return fmt.Errorf("error while creating migrations table: %w", err)
+		}
+		return fn(conn)
+	})
+}
+
+func (m *Migrator) find(version uint) (Migration, bool) {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig, true
+		}
+	}
+	return Migration{}, false
+}
+
+func appliedVersions(db *gorm.DB) (map[uint]schemaMigration, error) {
+	var rows []schemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error while getting applied migrations: %w", err)
+	}
+	applied := make(map[uint]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// loadMigrations reads pairs of <version>_<name>.up.sql and <version>_<name>.down.sql files from dir
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading migrations dir: %w", err)
+	}
+	byVersion := map[uint]*Migration{}
+	for _, entry := range entries {
+		match := migrationFileRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			return nil, fmt.Errorf("unexpected file %s in migrations dir", entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing version of migration %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error while reading migration %s: %w", entry.Name(), err)
+		}
+		mig, ok := byVersion[uint(version)]
+		if !ok {
+			mig = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = mig
+		}
+		if mig.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, mig.Name, match[2])
+		}
+		if match[3] == "up" {
+			mig.Up = string(content)
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s should have both up and down file", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
@@ -0,0 +1,234 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+const (
+	lockMigrations       = `SELECT pg_advisory_lock\(\$1\)`
+	unlockMigrations     = `SELECT pg_advisory_unlock\(\$1\)`
+	createMigrationTable = `CREATE TABLE IF NOT EXISTS schema_migrations`
+	getAppliedMigrations = `SELECT \* FROM "schema_migrations" ORDER BY version`
+	getLastMigration     = `SELECT \* FROM "schema_migrations" ORDER BY version DESC LIMIT 1`
+	insertMigration      = `INSERT INTO "schema_migrations"`
+	deleteMigration      = `DELETE FROM "schema_migrations" WHERE "schema_migrations"\."version" = \$1`
+)
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "create_items", Up: "CREATE TABLE items", Down: "DROP TABLE items"},
+	{Version: 2, Name: "add_column", Up: "ALTER TABLE items ADD COLUMN x", Down: "ALTER TABLE items DROP COLUMN x"},
+}
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       fstest.MapFS
+		expectedRes []Migration
+		expectedErr string
+	}{
+		{
+			name: "Successful - sorted by version",
+			files: fstest.MapFS{
+				"m/0002_add_column.up.sql":     {Data: []byte(testMigrations[1].Up)},
+				"m/0002_add_column.down.sql":   {Data: []byte(testMigrations[1].Down)},
+				"m/0001_create_items.up.sql":   {Data: []byte(testMigrations[0].Up)},
+				"m/0001_create_items.down.sql": {Data: []byte(testMigrations[0].Down)},
+			},
+			expectedRes: testMigrations,
+		},
+		{
+			name: "Unsuccessful - missing down migration",
+			files: fstest.MapFS{
+				"m/0001_create_items.up.sql": {Data: []byte(testMigrations[0].Up)},
+			},
+			expectedErr: "should have both up and down file",
+		},
+		{
+			name: "Unsuccessful - unexpected file name",
+			files: fstest.MapFS{
+				"m/create_items.sql": {Data: []byte(testMigrations[0].Up)},
+			},
+			expectedErr: "unexpected file",
+		},
+		{
+			name: "Unsuccessful - duplicated version",
+			files: fstest.MapFS{
+				"m/0001_create_items.up.sql": {Data: []byte(testMigrations[0].Up)},
+				"m/0001_add_column.up.sql":   {Data: []byte(testMigrations[1].Up)},
+			},
+			expectedErr: "is used by both",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			migrations, err := loadMigrations(test.files, "m")
+
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedRes, migrations)
+			}
+		})
+	}
+}
+
+func TestNewMigrator_embeddedMigrations(t *testing.T) {
+	m, err := NewMigrator(nil)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, m.migrations)
+	for i, mig := range m.migrations {
+		assert.Equal(t, uint(i+1), mig.Version, "migration versions should be continuous")
+	}
+}
+
+func TestMigratorUp(t *testing.T) {
+	tests := []struct {
+		name            string
+		appliedVersions []uint
+		mockErr         error
+		expectedApplied []Migration
+	}{
+		{
+			name:            "Successful - all migrations pending",
+			expectedApplied: testMigrations,
+		},
+		{
+			name:            "Successful - only pending migrations applied",
+			appliedVersions: []uint{1},
+			expectedApplied: testMigrations[1:],
+		},
+		{
+			name:            "Successful - nothing to apply",
+			appliedVersions: []uint{1, 2},
+		},
+		{
+			name:            "Unsuccessful - error while applying migration",
+			appliedVersions: []uint{1},
+			mockErr:         errors.New("some err"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, mock := newTestMigrator(t)
+
+			mock.ExpectExec(lockMigrations).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(createMigrationTable).WillReturnResult(sqlmock.NewResult(0, 0))
+			rows := sqlmock.NewRows([]string{"version", "name", "applied_at"})
+			for _, v := range test.appliedVersions {
+				rows.AddRow(v, testMigrations[v-1].Name, time.Now())
+			}
+			mock.ExpectQuery(getAppliedMigrations).WillReturnRows(rows)
+			for _, mig := range testMigrations[len(test.appliedVersions):] {
+				mock.ExpectBegin()
+				if test.mockErr != nil {
+					mock.ExpectExec(mig.Up).WillReturnError(test.mockErr)
+					mock.ExpectRollback()
+					break
+				}
+				mock.ExpectExec(mig.Up).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(insertMigration).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			}
+			mock.ExpectExec(unlockMigrations).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			applied, err := m.Up(context.Background())
+
+			if test.mockErr != nil {
+				assert.ErrorContains(t, err, "some err")
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedApplied, applied)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigratorDown(t *testing.T) {
+	tests := []struct {
+		name           string
+		lastVersion    uint
+		expectedRes    Migration
+		expectedErr    error
+		expectRollback bool
+	}{
+		{
+			name:           "Successful - last migration rolled back",
+			lastVersion:    2,
+			expectedRes:    testMigrations[1],
+			expectRollback: true,
+		},
+		{
+			name:        "Unsuccessful - nothing applied",
+			expectedErr: ErrNoMigrationToRollback,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, mock := newTestMigrator(t)
+
+			mock.ExpectExec(lockMigrations).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(createMigrationTable).WillReturnResult(sqlmock.NewResult(0, 0))
+			rows := sqlmock.NewRows([]string{"version", "name", "applied_at"})
+			if test.lastVersion != 0 {
+				rows.AddRow(test.lastVersion, testMigrations[test.lastVersion-1].Name, time.Now())
+			}
+			mock.ExpectQuery(getLastMigration).WillReturnRows(rows)
+			if test.expectRollback {
+				mock.ExpectBegin()
+				mock.ExpectExec(test.expectedRes.Down).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(deleteMigration).WithArgs(test.lastVersion).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+			mock.ExpectExec(unlockMigrations).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			rolledBack, err := m.Down(context.Background())
+
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedRes, rolledBack)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigratorStatus(t *testing.T) {
+	m, mock := newTestMigrator(t)
+	appliedAt := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(createMigrationTable).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(getAppliedMigrations).WillReturnRows(sqlmock.
+		NewRows([]string{"version", "name", "applied_at"}).
+		AddRow(1, testMigrations[0].Name, appliedAt))
+
+	statuses, err := m.Status(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []MigrationStatus{
+		{Migration: testMigrations[0], AppliedAt: &appliedAt},
+		{Migration: testMigrations[1]},
+	}, statuses)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}))
+	require.NoError(t, err)
+	return &Migrator{db: db, migrations: testMigrations}, mock
+}
@@ -0,0 +1 @@
+ALTER TABLE items ADD COLUMN version integer NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+ALTER TABLE items ADD COLUMN deleted_at timestamptz;
+CREATE INDEX items_deleted_at_idx ON items (deleted_at);
@@ -0,0 +1,4 @@
+ALTER TABLE items ADD COLUMN search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', name), 'A') || setweight(to_tsvector('english', description), 'B')
+) STORED;
+CREATE INDEX items_search_idx ON items USING GIN (search);
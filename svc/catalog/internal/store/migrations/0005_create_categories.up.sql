@@ -0,0 +1,13 @@
+CREATE TABLE categories (
+    id SERIAL PRIMARY KEY,
+    name varchar(250) NOT NULL,
+    parent_id integer REFERENCES categories (id)
+);
+CREATE INDEX categories_parent_id_idx ON categories (parent_id);
+
+CREATE TABLE item_categories (
+    item_id integer NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    category_id integer NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (item_id, category_id)
+);
+CREATE INDEX item_categories_category_id_idx ON item_categories (category_id);
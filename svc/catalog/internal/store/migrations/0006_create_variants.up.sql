@@ -0,0 +1,10 @@
+CREATE TABLE variants (
+    id SERIAL PRIMARY KEY,
+    item_id integer NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    sku varchar(64) NOT NULL,
+    options jsonb NOT NULL DEFAULT '{}',
+    price numeric,
+    barcode varchar(14),
+    CONSTRAINT variants_sku_key UNIQUE (sku),
+    CONSTRAINT variants_item_id_options_key UNIQUE (item_id, options)
+);
@@ -0,0 +1,20 @@
+CREATE TABLE stock_levels (
+    item_id integer NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    variant_id integer NOT NULL DEFAULT 0,
+    on_hand bigint NOT NULL DEFAULT 0,
+    reserved bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (item_id, variant_id),
+    CONSTRAINT stock_levels_available_check CHECK (reserved >= 0 AND on_hand >= reserved)
+);
+
+CREATE TABLE reservations (
+    id SERIAL PRIMARY KEY,
+    item_id integer NOT NULL,
+    variant_id integer NOT NULL,
+    quantity bigint NOT NULL CHECK (quantity > 0),
+    status varchar(16) NOT NULL,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL,
+    FOREIGN KEY (item_id, variant_id) REFERENCES stock_levels (item_id, variant_id) ON DELETE CASCADE
+);
+CREATE INDEX reservations_pending_expires_at_idx ON reservations (expires_at) WHERE status = 'pending';
@@ -0,0 +1,18 @@
+CREATE FUNCTION pg_temp.currency_exponent(code text) RETURNS integer AS $$
+    SELECT CASE UPPER(code)
+        WHEN 'BHD' THEN 3 WHEN 'BIF' THEN 0 WHEN 'CLF' THEN 4 WHEN 'CLP' THEN 0 WHEN 'DJF' THEN 0 WHEN 'GNF' THEN 0
+        WHEN 'IQD' THEN 3 WHEN 'ISK' THEN 0 WHEN 'JOD' THEN 3 WHEN 'JPY' THEN 0 WHEN 'KMF' THEN 0 WHEN 'KRW' THEN 0
+        WHEN 'KWD' THEN 3 WHEN 'LYD' THEN 3 WHEN 'OMR' THEN 3 WHEN 'PYG' THEN 0 WHEN 'RWF' THEN 0 WHEN 'TND' THEN 3
+        WHEN 'UGX' THEN 0 WHEN 'UYI' THEN 0 WHEN 'UYW' THEN 4 WHEN 'VND' THEN 0 WHEN 'VUV' THEN 0 WHEN 'XAF' THEN 0
+        WHEN 'XOF' THEN 0 WHEN 'XPF' THEN 0
+        ELSE 2
+    END
+$$ LANGUAGE SQL IMMUTABLE;
+
+ALTER TABLE items ADD COLUMN price numeric;
+UPDATE items SET price = price_minor / 10::numeric ^ pg_temp.currency_exponent(price_code);
+ALTER TABLE items ALTER COLUMN price SET NOT NULL, DROP COLUMN price_minor;
+
+ALTER TABLE variants DROP CONSTRAINT variants_price_check, ADD COLUMN price numeric;
+UPDATE variants SET price = price_minor / 10::numeric ^ pg_temp.currency_exponent(price_code);
+ALTER TABLE variants DROP COLUMN price_minor, DROP COLUMN price_code;
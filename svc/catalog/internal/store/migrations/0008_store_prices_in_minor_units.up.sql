@@ -0,0 +1,41 @@
+CREATE FUNCTION pg_temp.currency_exponent(code text) RETURNS integer AS $$
+    SELECT CASE UPPER(code)
+        WHEN 'BHD' THEN 3 WHEN 'BIF' THEN 0 WHEN 'CLF' THEN 4 WHEN 'CLP' THEN 0 WHEN 'DJF' THEN 0 WHEN 'GNF' THEN 0
+        WHEN 'IQD' THEN 3 WHEN 'ISK' THEN 0 WHEN 'JOD' THEN 3 WHEN 'JPY' THEN 0 WHEN 'KMF' THEN 0 WHEN 'KRW' THEN 0
+        WHEN 'KWD' THEN 3 WHEN 'LYD' THEN 3 WHEN 'OMR' THEN 3 WHEN 'PYG' THEN 0 WHEN 'RWF' THEN 0 WHEN 'TND' THEN 3
+        WHEN 'UGX' THEN 0 WHEN 'UYI' THEN 0 WHEN 'UYW' THEN 4 WHEN 'VND' THEN 0 WHEN 'VUV' THEN 0 WHEN 'XAF' THEN 0
+        WHEN 'XOF' THEN 0 WHEN 'XPF' THEN 0
+        ELSE 2
+    END
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Prices which cannot be expressed in minor units of their currency would be silently rounded, so they have to be
+-- fixed by hand before the migration is retried.
+DO $$
+DECLARE
+    invalid integer;
+BEGIN
+    SELECT count(*) INTO invalid FROM items
+    WHERE price * 10::numeric ^ pg_temp.currency_exponent(price_code)
+        <> trunc(price * 10::numeric ^ pg_temp.currency_exponent(price_code));
+    IF invalid > 0 THEN
+        RAISE EXCEPTION '% items have price with more fractional digits than their currency allows', invalid;
+    END IF;
+    SELECT count(*) INTO invalid FROM variants JOIN items ON items.id = variants.item_id
+    WHERE variants.price * 10::numeric ^ pg_temp.currency_exponent(items.price_code)
+        <> trunc(variants.price * 10::numeric ^ pg_temp.currency_exponent(items.price_code));
+    IF invalid > 0 THEN
+        RAISE EXCEPTION '% variants have price with more fractional digits than currency of their item allows', invalid;
+    END IF;
+END $$;
+
+ALTER TABLE items ADD COLUMN price_minor bigint;
+UPDATE items SET price_minor = (price * 10::numeric ^ pg_temp.currency_exponent(price_code))::bigint;
+ALTER TABLE items ALTER COLUMN price_minor SET NOT NULL, DROP COLUMN price;
+
+ALTER TABLE variants ADD COLUMN price_minor bigint, ADD COLUMN price_code varchar(3);
+UPDATE variants SET price_minor = (variants.price * 10::numeric ^ pg_temp.currency_exponent(items.price_code))::bigint,
+    price_code = items.price_code
+FROM items WHERE items.id = variants.item_id AND variants.price IS NOT NULL;
+ALTER TABLE variants DROP COLUMN price,
+    ADD CONSTRAINT variants_price_check CHECK ((price_minor IS NULL) = (price_code IS NULL) AND price_minor >= 0);
@@ -0,0 +1,2 @@
+-- Original case of currency codes is not kept, upper-case codes remain valid after rollback.
+SELECT 1;
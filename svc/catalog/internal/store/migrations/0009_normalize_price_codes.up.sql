@@ -0,0 +1,2 @@
+UPDATE items SET price_code = UPPER(price_code) WHERE price_code <> UPPER(price_code);
+UPDATE variants SET price_code = UPPER(price_code) WHERE price_code <> UPPER(price_code);
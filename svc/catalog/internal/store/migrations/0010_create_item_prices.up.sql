@@ -0,0 +1,6 @@
+CREATE TABLE item_prices (
+    item_id integer NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    currency varchar(3) NOT NULL,
+    price_minor bigint NOT NULL CHECK (price_minor >= 0),
+    PRIMARY KEY (item_id, currency)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE scheduled_prices (
+    id SERIAL PRIMARY KEY,
+    item_id integer NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    currency varchar(3) NOT NULL,
+    price_minor bigint NOT NULL CHECK (price_minor >= 0),
+    starts_at timestamptz NOT NULL,
+    ends_at timestamptz,
+    CONSTRAINT scheduled_prices_window_check CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+CREATE INDEX scheduled_prices_item_id_starts_at_idx ON scheduled_prices (item_id, starts_at);
+CREATE INDEX scheduled_prices_starts_at_idx ON scheduled_prices (starts_at);
@@ -0,0 +1,2 @@
+DROP TABLE item_history;
+DROP FUNCTION item_history_append_only();
@@ -0,0 +1,30 @@
+CREATE TABLE item_history (
+    id BIGSERIAL PRIMARY KEY,
+    item_id integer NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    version integer NOT NULL,
+    operation varchar(16) NOT NULL,
+    actor varchar(100) NOT NULL,
+    changed_at timestamptz NOT NULL,
+    snapshot jsonb NOT NULL
+);
+CREATE INDEX item_history_item_id_changed_at_idx ON item_history (item_id, changed_at);
+
+CREATE FUNCTION item_history_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'item history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER item_history_append_only BEFORE UPDATE ON item_history
+    FOR EACH ROW EXECUTE FUNCTION item_history_append_only();
+
+-- existing items start their history with current state
+INSERT INTO item_history (item_id, version, operation, actor, changed_at, snapshot)
+SELECT id, version, 'created', 'migration', now(),
+       jsonb_strip_nulls(jsonb_build_object('name', name, 'description', description, 'price', price_minor,
+                                            'priceCode', price_code))
+FROM items;
+INSERT INTO item_history (item_id, version, operation, actor, changed_at, snapshot)
+SELECT item_id, version, 'deleted', actor, changed_at, snapshot
+FROM item_history
+WHERE item_id IN (SELECT id FROM items WHERE deleted_at IS NOT NULL);
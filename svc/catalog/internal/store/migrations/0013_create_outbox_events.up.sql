@@ -0,0 +1,12 @@
+CREATE TABLE outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    type varchar(32) NOT NULL,
+    item_id integer NOT NULL,
+    version integer NOT NULL,
+    actor varchar(100) NOT NULL,
+    item jsonb NOT NULL,
+    occurred_at timestamptz NOT NULL,
+    published_at timestamptz
+);
+-- relay reads only events which were not published yet
+CREATE INDEX outbox_events_pending_idx ON outbox_events (id) WHERE published_at IS NULL;
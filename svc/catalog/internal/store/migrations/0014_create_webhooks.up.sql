@@ -0,0 +1,27 @@
+CREATE TABLE webhooks (
+    id SERIAL PRIMARY KEY,
+    url varchar(2048) NOT NULL,
+    event_types jsonb NOT NULL,
+    secret varchar(255) NOT NULL,
+    created_at timestamptz NOT NULL
+);
+
+CREATE TABLE webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    webhook_id integer NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_id bigint NOT NULL,
+    event_type varchar(32) NOT NULL,
+    payload text NOT NULL,
+    status varchar(16) NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL,
+    last_attempt_at timestamptz,
+    last_status_code integer,
+    last_error text,
+    created_at timestamptz NOT NULL,
+    delivered_at timestamptz,
+    CONSTRAINT webhook_deliveries_event_unique UNIQUE (webhook_id, event_id)
+);
+-- worker claims only pending deliveries which are due
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_status_idx ON webhook_deliveries (status, id);
@@ -0,0 +1,2 @@
+DROP INDEX items_external_id_idx;
+ALTER TABLE items DROP COLUMN external_id;
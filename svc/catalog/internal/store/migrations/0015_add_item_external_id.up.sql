@@ -0,0 +1,3 @@
+ALTER TABLE items ADD COLUMN external_id varchar(100);
+-- external ID identifies live items only, so the same one can be imported again after the item is moved to trash
+CREATE UNIQUE INDEX items_external_id_idx ON items (external_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+DROP TABLE import_rejections;
+DROP TABLE import_uploads;
+DROP TABLE import_jobs;
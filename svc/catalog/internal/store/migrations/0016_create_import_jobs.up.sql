@@ -0,0 +1,34 @@
+CREATE TABLE import_jobs (
+    id SERIAL PRIMARY KEY,
+    format varchar(16) NOT NULL,
+    status varchar(16) NOT NULL,
+    claims integer NOT NULL DEFAULT 0,
+    lease_until timestamptz NOT NULL,
+    processed_rows integer NOT NULL DEFAULT 0,
+    created_items integer NOT NULL DEFAULT 0,
+    updated_items integer NOT NULL DEFAULT 0,
+    unchanged_items integer NOT NULL DEFAULT 0,
+    rejected_rows integer NOT NULL DEFAULT 0,
+    error text,
+    created_at timestamptz NOT NULL,
+    started_at timestamptz,
+    finished_at timestamptz
+);
+-- worker claims only unfinished jobs whose lease has ended
+CREATE INDEX import_jobs_claimable_idx ON import_jobs (lease_until) WHERE status IN ('queued', 'running');
+
+-- uploaded files are kept only until their import finishes
+CREATE TABLE import_uploads (
+    job_id integer PRIMARY KEY REFERENCES import_jobs (id) ON DELETE CASCADE,
+    data bytea NOT NULL
+);
+
+CREATE TABLE import_rejections (
+    id BIGSERIAL PRIMARY KEY,
+    job_id integer NOT NULL REFERENCES import_jobs (id) ON DELETE CASCADE,
+    row_number integer NOT NULL,
+    external_id varchar(100),
+    field varchar(32),
+    message text NOT NULL
+);
+CREATE INDEX import_rejections_job_id_idx ON import_rejections (job_id, row_number);
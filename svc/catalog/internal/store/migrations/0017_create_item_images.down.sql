@@ -0,0 +1,2 @@
+DROP TABLE media_deletions;
+DROP TABLE item_images;
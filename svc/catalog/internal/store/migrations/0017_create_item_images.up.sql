@@ -0,0 +1,19 @@
+CREATE TABLE item_images (
+    id SERIAL PRIMARY KEY,
+    item_id integer NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    position integer NOT NULL,
+    alt_text varchar(250),
+    blob_key varchar(100) NOT NULL,
+    content_type varchar(32) NOT NULL,
+    width integer NOT NULL,
+    height integer NOT NULL,
+    size_bytes bigint NOT NULL,
+    created_at timestamptz NOT NULL,
+    -- checked on commit, so images can be reordered one row at a time
+    CONSTRAINT item_images_item_id_position_key UNIQUE (item_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+-- blobs of removed images are deleted from media storage in the background
+CREATE TABLE media_deletions (
+    blob_key varchar(100) PRIMARY KEY
+);
@@ -0,0 +1,2 @@
+ALTER TABLE outbox_events DROP COLUMN position;
+DROP SEQUENCE outbox_events_position_seq;
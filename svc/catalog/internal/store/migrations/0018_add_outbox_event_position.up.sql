@@ -0,0 +1,6 @@
+-- positions are assigned when events are relayed, so they increase in the order events become visible to readers
+CREATE SEQUENCE outbox_events_position_seq;
+ALTER TABLE outbox_events ADD COLUMN position bigint UNIQUE;
+-- events published so far keep their IDs as positions, so streams resume where they were
+UPDATE outbox_events SET position = id WHERE published_at IS NOT NULL;
+SELECT setval('outbox_events_position_seq', COALESCE(max(position), 0) + 1, false) FROM outbox_events;
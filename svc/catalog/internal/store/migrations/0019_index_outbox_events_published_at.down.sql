@@ -0,0 +1 @@
+DROP INDEX outbox_events_published_at_idx;
@@ -0,0 +1,2 @@
+-- published events are pruned once they are older than retention
+CREATE INDEX outbox_events_published_at_idx ON outbox_events (published_at) WHERE published_at IS NOT NULL;
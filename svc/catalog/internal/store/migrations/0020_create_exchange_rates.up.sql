@@ -0,0 +1,8 @@
+-- table holds a single row, so every instance converts prices with the same rates
+CREATE TABLE exchange_rates (
+    id boolean PRIMARY KEY DEFAULT true CHECK (id),
+    base varchar(3) NOT NULL,
+    rates jsonb NOT NULL DEFAULT '{}',
+    source text NOT NULL,
+    updated_at timestamptz NOT NULL
+);
@@ -0,0 +1,7 @@
+DROP TRIGGER item_history_append_only ON item_history;
+CREATE TRIGGER item_history_append_only BEFORE UPDATE ON item_history
+    FOR EACH ROW EXECUTE FUNCTION item_history_append_only();
+
+DELETE FROM item_history WHERE item_id NOT IN (SELECT id FROM items);
+ALTER TABLE item_history ADD CONSTRAINT item_history_item_id_fkey
+    FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE;
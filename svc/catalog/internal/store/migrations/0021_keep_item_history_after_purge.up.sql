@@ -0,0 +1,6 @@
+-- history outlives items purged from trash, item IDs are never reused so it stays unambiguous
+ALTER TABLE item_history DROP CONSTRAINT item_history_item_id_fkey;
+
+DROP TRIGGER item_history_append_only ON item_history;
+CREATE TRIGGER item_history_append_only BEFORE UPDATE OR DELETE ON item_history
+    FOR EACH ROW EXECUTE FUNCTION item_history_append_only();
@@ -1,10 +1,87 @@
 package store
 
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"unicode/utf8"
+)
+
+const (
+	maxNameLength       = 250
+	maxDescLength       = 250
+	maxPriceCodeLength  = 3
+	maxExternalIDLength = 100
+)
+
+var (
+	ErrInvalidItem        = errors.New("invalid item")
+	ErrExternalIDConflict = errors.New("external id is already used by another item")
+)
+
 // Item represent Items entity in underlying db
 type Item struct {
-	ID          uint
+	ID uint
+	// ExternalID is a unique key of the item in supplier or ERP systems, it can't be changed once set
+	ExternalID  *string
 	Name        *string
 	Description *string
-	Price       *float64
-	PriceCode   *string
+	// Price is expressed in minor units of PriceCode currency, e.g. cents for EUR
+	Price     *int64 `gorm:"column:price_minor"`
+	PriceCode *string
+	// Version is incremented on every update of the item
+	Version uint
+	// DeletedAt is set when item is moved to trash, such items are hidden from regular queries
+	DeletedAt gorm.DeletedAt
+}
+
+// validateNew checks if item has all fields required to be persisted
+func (i Item) validateNew() error {
+	switch {
+	case i.Name == nil:
+		return fmt.Errorf("%w: name is required", ErrInvalidItem)
+	case i.Description == nil:
+		return fmt.Errorf("%w: description is required", ErrInvalidItem)
+	case i.Price == nil:
+		return fmt.Errorf("%w: price is required", ErrInvalidItem)
+	case i.PriceCode == nil:
+		return fmt.Errorf("%w: priceCode is required", ErrInvalidItem)
+	}
+	return i.validate()
+}
+
+// validate checks if set fields fit into underlying db columns
+func (i Item) validate() error {
+	switch {
+	case i.Name != nil && utf8.RuneCountInString(*i.Name) > maxNameLength:
+		return fmt.Errorf("%w: name should be at most %d characters long", ErrInvalidItem, maxNameLength)
+	case i.Description != nil && utf8.RuneCountInString(*i.Description) > maxDescLength:
+		return fmt.Errorf("%w: description should be at most %d characters long", ErrInvalidItem, maxDescLength)
+	case i.PriceCode != nil && utf8.RuneCountInString(*i.PriceCode) > maxPriceCodeLength:
+		return fmt.Errorf("%w: priceCode should be at most %d characters long", ErrInvalidItem, maxPriceCodeLength)
+	case i.ExternalID != nil && (*i.ExternalID == "" || utf8.RuneCountInString(*i.ExternalID) > maxExternalIDLength):
+		return fmt.Errorf("%w: externalId should be between 1 and %d characters long", ErrInvalidItem,
+			maxExternalIDLength)
+	case (i.Price == nil) != (i.PriceCode == nil):
+		return fmt.Errorf("%w: price and priceCode have to be set together", ErrInvalidItem)
+	}
+	return nil
+}
+
+// updates returns columns of set fields to be updated together with version increment
+func (i Item) updates() map[string]interface{} {
+	updates := map[string]interface{}{"version": gorm.Expr("version + 1")}
+	if i.Name != nil {
+		updates["name"] = *i.Name
+	}
+	if i.Description != nil {
+		updates["description"] = *i.Description
+	}
+	if i.Price != nil {
+		updates["price_minor"] = *i.Price
+	}
+	if i.PriceCode != nil {
+		updates["price_code"] = *i.PriceCode
+	}
+	return updates
 }
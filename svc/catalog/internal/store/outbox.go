@@ -0,0 +1,269 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+	"time"
+)
+
+// EventType is a kind of domain event emitted on change of an item
+type EventType string
+
+const (
+	ItemCreated  EventType = "ItemCreated"
+	ItemUpdated  EventType = "ItemUpdated"
+	ItemDeleted  EventType = "ItemDeleted"
+	ItemRestored EventType = "ItemRestored"
+	// ItemRevised is emitted when part of the item kept apart from its fields changes, e.g. its price list
+	ItemRevised EventType = "ItemRevised"
+)
+
+// relayLockID is a key of postgres advisory lock serializing relaying of events
+const relayLockID = 7403122
+
+// eventTypes maps operations recorded in item history to events they emit
+var eventTypes = map[HistoryOperation]EventType{
+	HistoryCreated:  ItemCreated,
+	HistoryUpdated:  ItemUpdated,
+	HistoryDeleted:  ItemDeleted,
+	HistoryRestored: ItemRestored,
+	HistoryRevised:  ItemRevised,
+}
+
+// valid reports if event type is emitted by the catalog
+func (t EventType) valid() bool {
+	for _, emitted := range eventTypes {
+		if emitted == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a domain event stored in the outbox in the same transaction as the change which emitted it. Events are
+// relayed in order of their IDs, so events of every item are published in the order changes were made.
+type Event struct {
+	ID      uint64    `json:"id"`
+	Type    EventType `json:"type"`
+	ItemID  uint      `json:"itemId"`
+	Version uint      `json:"version"`
+	Actor   string    `json:"actor"`
+	// Item is state of the item right after the change
+	Item       ItemSnapshot `json:"item"`
+	OccurredAt time.Time    `json:"occurredAt"`
+	// PublishedAt is set once the event was relayed to the publisher
+	PublishedAt *time.Time `json:"-"`
+	// Position is assigned when the event is relayed. Unlike IDs, which are assigned when changes are made and may be
+	// committed out of order, positions increase in the order events are published, so streams resume after them.
+	Position uint64 `json:"-" gorm:"<-:update"`
+}
+
+func (Event) TableName() string {
+	return "outbox_events"
+}
+
+// newEvent returns event emitted by the change of an item
+func newEvent(change ItemChange) Event {
+	return Event{
+		Type:       eventTypes[change.Operation],
+		ItemID:     change.ItemID,
+		Version:    change.Version,
+		Actor:      change.Actor,
+		Item:       change.Snapshot,
+		OccurredAt: change.ChangedAt,
+	}
+}
+
+// RelayEvents passes up to limit unpublished events to publish in order and marks the published ones with their stream
+// positions. Relaying stops at the first event publish fails for, so it is retried before any later event. Events are
+// delivered at least once, as an event is published again when marking it fails. Number of published events is
+// returned, counting only the ones marked as published.
+func (s *CatalogStore) RelayEvents(ctx context.Context, limit int, publish func(ctx context.Context, event Event) error) (int, error) {
+	if limit < 1 {
+		return 0, ErrInvalidPageParams
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var published []Event
+	var publishErr error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// relays are serialized, so positions become visible to readers in increasing order
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", relayLockID).Error; err != nil {
+			return err
+		}
+		var events []Event
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("published_at IS NULL").
+			Order("id").Limit(limit).Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+		// positions of events which fail to publish are skipped, readers only rely on their order
+		var positions []uint64
+		err = tx.Raw("SELECT nextval('outbox_events_position_seq') FROM generate_series(1, ?)", len(events)).
+			Scan(&positions).Error
+		if err != nil {
+			return err
+		}
+		for i, event := range events {
+			event.Position = positions[i]
+			if publishErr = publish(ctx, event); publishErr != nil {
+				break
+			}
+			published = append(published, event)
+		}
+		now := time.Now()
+		for _, event := range published {
+			err := tx.Model(&Event{}).Where("id = ?", event.ID).
+				Updates(map[string]interface{}{"published_at": now, "position": event.Position}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error while relaying events: %w", err)
+	}
+	if publishErr != nil {
+		return len(published), fmt.Errorf("error while publishing event: %w", publishErr)
+	}
+	return len(published), nil
+}
+
+// GetEvents returns up to limit published events with position greater than afterPosition in order of their positions
+func (s *CatalogStore) GetEvents(ctx context.Context, afterPosition uint64, limit int) (events []Event, err error) {
+	if limit < 1 {
+		return nil, ErrInvalidPageParams
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	if err := db.Where("position > ?", afterPosition).Order("position").Limit(limit).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("error while getting events after position %d: %w", afterPosition, err)
+	}
+	return events, nil
+}
+
+// PruneEvents removes events published before provided time from the outbox and returns their number
+func (s *CatalogStore) PruneEvents(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	res := db.Where("published_at < ?", publishedBefore).Delete(&Event{})
+	if res.Error != nil {
+		return 0, fmt.Errorf("error while pruning events: %w", res.Error)
+	}
+	return res.RowsAffected, nil
+}
+
+// RelayEvents passes up to limit unpublished events to publish in order and marks the published ones with their stream
+// positions. Relaying stops at the first event publish fails for, so it is retried before any later event. Number of
+// published events is returned.
+func (s *MemoryStore) RelayEvents(ctx context.Context, limit int, publish func(ctx context.Context, event Event) error) (int, error) {
+	if limit < 1 {
+		return 0, ErrInvalidPageParams
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("error while relaying events: %w", err)
+	}
+
+	// publish is called without holding the lock, so it can use the store
+	s.relayMu.Lock()
+	defer s.relayMu.Unlock()
+	s.mu.RLock()
+	from := s.firstPendingEvent
+	to := from + limit
+	if to > len(s.events) {
+		to = len(s.events)
+	}
+	pending := make([]Event, 0, to-from)
+	for i, event := range s.events[from:to] {
+		event = cloneEvent(event)
+		event.Position = s.lastEventPosition + uint64(i) + 1
+		pending = append(pending, event)
+	}
+	s.mu.RUnlock()
+
+	var published int
+	var publishErr error
+	for _, event := range pending {
+		if publishErr = publish(ctx, event); publishErr != nil {
+			break
+		}
+		published++
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for i := from; i < from+published; i++ {
+		s.events[i].PublishedAt = &now
+		s.events[i].Position = pending[i-from].Position
+	}
+	s.firstPendingEvent += published
+	s.lastEventPosition += uint64(published)
+	if publishErr != nil {
+		return published, fmt.Errorf("error while publishing event: %w", publishErr)
+	}
+	return published, nil
+}
+
+// GetEvents returns up to limit published events with position greater than afterPosition in order of their positions
+func (s *MemoryStore) GetEvents(ctx context.Context, afterPosition uint64, limit int) ([]Event, error) {
+	if limit < 1 {
+		return nil, ErrInvalidPageParams
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while getting events after position %d: %w", afterPosition, err)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// events are published in order, so their positions increase up to the first pending one
+	published := s.events[:s.firstPendingEvent]
+	from := sort.Search(len(published), func(i int) bool { return published[i].Position > afterPosition })
+	events := []Event{}
+	for _, event := range published[from:] {
+		if len(events) == limit {
+			break
+		}
+		events = append(events, cloneEvent(event))
+	}
+	return events, nil
+}
+
+// PruneEvents removes events published before provided time from the outbox and returns their number
+func (s *MemoryStore) PruneEvents(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("error while pruning events: %w", err)
+	}
+	// relaying refers to events by their index in the outbox
+	s.relayMu.Lock()
+	defer s.relayMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// events are published in order, so the ones published before the time come first
+	pruned := sort.Search(s.firstPendingEvent, func(i int) bool {
+		return !s.events[i].PublishedAt.Before(publishedBefore)
+	})
+	s.events = append([]Event{}, s.events[pruned:]...)
+	s.firstPendingEvent -= pruned
+	return int64(pruned), nil
+}
+
+// recordEvent appends event emitted by the change to the outbox. Caller has to hold the lock.
+func (s *MemoryStore) recordEvent(change ItemChange) {
+	event := newEvent(change)
+	s.lastEventID++
+	event.ID = s.lastEventID
+	s.events = append(s.events, event)
+}
+
+func cloneEvent(event Event) Event {
+	clone := event
+	clone.Item = cloneSnapshot(event.Item)
+	clone.PublishedAt = clonePtr(event.PublishedAt)
+	return clone
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRelayEvents(t *testing.T) {
+	occurredAt := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name              string
+		publishErr        error
+		expectedPublished []uint64
+	}{
+		{
+			name:              "All events published",
+			expectedPublished: []uint64{1, 2},
+		},
+		{
+			name:       "Relaying stops at failed event",
+			publishErr: errors.New("some error"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectBegin()
+			mock.ExpectExec(`SELECT pg_advisory_xact_lock\(\$1\)`).WithArgs(relayLockID).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(`SELECT \* FROM "outbox_events" WHERE published_at IS NULL ORDER BY id LIMIT 10 FOR UPDATE`).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "type", "item_id", "version", "actor", "item", "occurred_at", "published_at"}).
+					AddRow(1, "ItemCreated", 1, 1, "alice", []byte(`{"name":"some name"}`), occurredAt, nil).
+					AddRow(2, "ItemUpdated", 1, 2, "bob", []byte(`{"name":"other name"}`), occurredAt, nil))
+			mock.ExpectQuery(`SELECT nextval\('outbox_events_position_seq'\) FROM generate_series\(1, \$1\)`).WithArgs(2).
+				WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(7).AddRow(8))
+			for i, id := range test.expectedPublished {
+				mock.ExpectExec(`UPDATE "outbox_events" SET "position"=\$1,"published_at"=\$2 WHERE id = \$3`).
+					WithArgs(uint64(7+i), sqlmock.AnyArg(), id).WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mock.ExpectCommit()
+			var published []Event
+
+			n, err := store.RelayEvents(context.Background(), 10, func(_ context.Context, event Event) error {
+				if test.publishErr != nil {
+					return test.publishErr
+				}
+				published = append(published, event)
+				return nil
+			})
+
+			if test.publishErr != nil {
+				assert.ErrorIs(t, err, test.publishErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, len(test.expectedPublished), n)
+			ids := []uint64{}
+			for _, event := range published {
+				ids = append(ids, event.ID)
+			}
+			assert.Equal(t, append([]uint64{}, test.expectedPublished...), ids)
+			if len(published) > 0 {
+				assert.Equal(t, Event{ID: 1, Type: ItemCreated, ItemID: 1, Version: 1, Actor: "alice",
+					Item: ItemSnapshot{Name: v2p("some name")}, OccurredAt: occurredAt, Position: 7}, published[0])
+				assert.Equal(t, uint64(8), published[1].Position)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRelayEvents_invalidLimit(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	_, err := store.RelayEvents(context.Background(), 0, func(context.Context, Event) error { return nil })
+
+	assert.ErrorIs(t, err, ErrInvalidPageParams)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPruneEvents(t *testing.T) {
+	store, mock := newMockStore(t)
+	publishedBefore := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "outbox_events" WHERE published_at < \$1`).WithArgs(publishedBefore).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	pruned, err := store.PruneEvents(context.Background(), publishedBefore)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), pruned)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEvents(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectQuery(`SELECT \* FROM "outbox_events" WHERE position > \$1 ORDER BY position LIMIT 10`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "item_id", "item", "position"}).
+			AddRow(5, "ItemCreated", 1, []byte(`{}`), 4).
+			AddRow(4, "ItemDeleted", 1, []byte(`{}`), 5))
+
+	events, err := store.GetEvents(context.Background(), 3, 10)
+
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, uint64(4), events[0].Position)
+	assert.Equal(t, uint64(4), events[1].ID)
+	assert.Equal(t, ItemDeleted, events[1].Type)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,18 +1,29 @@
 package store
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/jackc/pgconn"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"os"
+	"time"
 )
 
-var ErrInvalidPageParams = errors.New("page and pageSize parameters should be greater than or equal to 1")
+var (
+	ErrInvalidPageParams = errors.New("page and pageSize parameters should be greater than or equal to 1")
+	ErrVersionMismatch   = errors.New("item version does not match expected one")
+)
+
+// uniqueViolation is postgres error code reported when unique constraint is violated
+const uniqueViolation = "23505"
 
 type CatalogStore struct {
-	db *gorm.DB
+	db           *gorm.DB
+	queryTimeout time.Duration
 }
 
 // dbCredentials represents vault credential file struct
@@ -21,66 +32,266 @@ type dbCredentials = struct {
 }
 
 // NewCatalogStore initialize connection to underlying db. Connection parameters should be passed by env variables.
-func NewCatalogStore(dbCredPath string) (*CatalogStore, error) {
+// Every query is bounded by queryTimeout, zero value disables the timeout.
+func NewCatalogStore(dbCredPath string, queryTimeout time.Duration) (*CatalogStore, error) {
 	file, err := os.Open(dbCredPath)
 	if err != nil {
 		return nil, fmt.Errorf("error while opening file at %s: %w", dbCredPath, err)
 	}
+	defer file.Close()
 	var credentials = &dbCredentials{}
 	if err := json.NewDecoder(file).Decode(credentials); err != nil {
 		return nil, fmt.Errorf("error while decoding json: %w", err)
 	}
 
-	db, err := gorm.Open(postgres.Open(credentials.DBConnection), &gorm.Config{})
+	return openCatalogStore(credentials.DBConnection, queryTimeout)
+}
+
+// openCatalogStore opens connection to db described by dsn
+func openCatalogStore(dsn string, queryTimeout time.Duration) (*CatalogStore, error) {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
 		return nil, fmt.Errorf("error shile opening connection to db: %w", err)
 	}
+	if err := db.Use(tracingPlugin{}); err != nil {
+		return nil, fmt.Errorf("error while registering tracing plugin: %w", err)
+	}
 
-	return &CatalogStore{db: db}, nil
+	return &CatalogStore{db: db, queryTimeout: queryTimeout}, nil
 }
 
 // CreateItem persists Item in db and returns its ID
-func (s *CatalogStore) CreateItem(item Item) (Item, error) {
-	if err := s.db.Create(&item).Error; err != nil {
+func (s *CatalogStore) CreateItem(ctx context.Context, item Item) (Item, error) {
+	if err := item.validateNew(); err != nil {
+		return Item{}, err
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var created Item
+	err := db.Transaction(func(tx *gorm.DB) (err error) {
+		created, err = insertItem(tx, item)
+		return err
+	})
+	if err != nil {
 		return Item{}, fmt.Errorf("error while adding item to db: %w", err)
 	}
-	return item, nil
+	return created, nil
 }
 
-// DeleteItem deletes item with ID from db
-func (s *CatalogStore) DeleteItem(id uint) error {
-	if err := s.db.Delete(&Item{}, id).Error; err != nil {
+// insertItem inserts item within transaction tx and records its creation
+func insertItem(tx *gorm.DB, item Item) (Item, error) {
+	item.Version = 1
+	if err := tx.Create(&item).Error; err != nil {
+		return Item{}, externalIDErr(err)
+	}
+	return item, recordChange(tx, item, HistoryCreated)
+}
+
+// externalIDErr translates violation of external ID uniqueness into ErrExternalIDConflict
+func externalIDErr(err error) error {
+	if isUniqueViolation(err) {
+		return ErrExternalIDConflict
+	}
+	return err
+}
+
+// DeleteItem moves item with ID to trash. When ifVersion is not zero, item is deleted only if its current version
+// matches, otherwise ErrVersionMismatch is returned.
+func (s *CatalogStore) DeleteItem(ctx context.Context, id uint, ifVersion uint) error {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		_, err := s.deleteItem(tx, id, ifVersion)
+		return err
+	})
+	if err != nil {
 		return fmt.Errorf("error while deleting item with id %d: %w", id, err)
 	}
 	return nil
 }
 
+// deleteItem moves item with ID to trash within transaction tx, records its deletion and returns deleted item
+func (s *CatalogStore) deleteItem(tx *gorm.DB, id uint, ifVersion uint) (Item, error) {
+	query := tx
+	if ifVersion != 0 {
+		query = query.Where("version = ?", ifVersion)
+	}
+	resp := query.Delete(&Item{}, id)
+	if err := resp.Error; err != nil {
+		return Item{}, err
+	}
+	if resp.RowsAffected != 1 {
+		return Item{}, s.missReason(tx, id, ifVersion)
+	}
+	var deleted Item
+	if err := tx.Unscoped().First(&deleted, id).Error; err != nil {
+		return Item{}, err
+	}
+	return deleted, recordChange(tx, deleted, HistoryDeleted)
+}
+
+// GetDeletedItems returns requested page of deleted items from db, most recently deleted first
+func (s *CatalogStore) GetDeletedItems(ctx context.Context, pageSize, page int) (items []Item, err error) {
+	if page < 1 || pageSize < 1 {
+		return nil, ErrInvalidPageParams
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err = db.Unscoped().Model(&Item{}).Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").Order("id").
+		Offset((page - 1) * pageSize).Limit(pageSize).Find(&items).Error
+	return
+}
+
+// RestoreItem brings back deleted item with ID and returns its new state. ErrExternalIDConflict is returned when
+// external ID of the item was taken by another one in the meantime.
+func (s *CatalogStore) RestoreItem(ctx context.Context, id uint) (Item, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var restored Item
+	err := db.Transaction(func(tx *gorm.DB) error {
+		resp := tx.Unscoped().Model(&restored).Clauses(clause.Returning{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+		if err := resp.Error; err != nil {
+			return externalIDErr(err)
+		}
+		if resp.RowsAffected != 1 {
+			return gorm.ErrRecordNotFound
+		}
+		return recordChange(tx, restored, HistoryRestored)
+	})
+	if err != nil {
+		return Item{}, fmt.Errorf("error while restoring item with id %d: %w", id, err)
+	}
+	return restored, nil
+}
+
+// PurgeDeletedItems permanently removes items deleted before provided time and returns number of removed items.
+// Blobs of their images are queued for deletion from media storage.
+func (s *CatalogStore) PurgeDeletedItems(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var purged int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT INTO media_deletions (blob_key) SELECT blob_key FROM item_images `+
+			`WHERE item_id IN (SELECT id FROM items WHERE deleted_at < ?) ON CONFLICT DO NOTHING`, deletedBefore).Error
+		if err != nil {
+			return err
+		}
+		resp := tx.Unscoped().Where("deleted_at < ?", deletedBefore).Delete(&Item{})
+		purged = resp.RowsAffected
+		return resp.Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error while purging deleted items: %w", err)
+	}
+	return purged, nil
+}
+
 // GetItem returns item with provided ID from db
-func (s *CatalogStore) GetItem(id uint) (Item, error) {
+func (s *CatalogStore) GetItem(ctx context.Context, id uint) (Item, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
 	var item Item
-	if err := s.db.First(&item, id).Error; err != nil {
+	if err := db.First(&item, id).Error; err != nil {
 		return Item{}, fmt.Errorf("error while getting item with id %d: %w", id, err)
 	}
 	return item, nil
 }
 
-func (s *CatalogStore) GetItems(pageSize, page int) (items []Item, err error) {
-	if page < 1 || pageSize < 1 {
-		return nil, ErrInvalidPageParams
+// GetItems returns requested page of filtered and sorted items from db. Page is selected with keyset condition when
+// query has a cursor, otherwise with offset.
+func (s *CatalogStore) GetItems(ctx context.Context, query ItemQuery) (items []Item, err error) {
+	order, last, err := query.validate()
+	if err != nil {
+		return nil, err
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	db = db.Model(&Item{}).Scopes(query.ItemFilter.scope, order.scope)
+	if last != nil {
+		db = db.Scopes(order.after(*last))
+	} else {
+		db = db.Offset((query.Page - 1) * query.PageSize)
+	}
+	err = db.Limit(query.PageSize).Find(&items).Error
+	return
+}
+
+// CountItems returns number of items matching the filter
+func (s *CatalogStore) CountItems(ctx context.Context, filter ItemFilter) (count int64, err error) {
+	if err := filter.validate(); err != nil {
+		return 0, err
 	}
-	err = s.db.Model(&Item{}).Offset((page - 1) * pageSize).Limit(pageSize).Find(&items).Error
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err = db.Model(&Item{}).Scopes(filter.scope).Count(&count).Error
 	return
 }
 
-// UpdateItem updates item with ID in db.
-func (s *CatalogStore) UpdateItem(id uint, item Item) error {
-	resp := s.db.Model(Item{}).Where("id = ?", id).Updates(&item)
+// UpdateItem updates item with ID in db and returns its new state. When ifVersion is not zero, item is updated
+// only if its current version matches, otherwise ErrVersionMismatch is returned.
+func (s *CatalogStore) UpdateItem(ctx context.Context, id uint, item Item, ifVersion uint) (Item, error) {
+	if err := item.validate(); err != nil {
+		return Item{}, err
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var updated Item
+	err := db.Transaction(func(tx *gorm.DB) (err error) {
+		updated, err = s.updateItem(tx, id, item, ifVersion)
+		return err
+	})
+	if err != nil {
+		return Item{}, fmt.Errorf("error while updating item with id %d: %w", id, err)
+	}
+	return updated, nil
+}
+
+// updateItem updates item with ID within transaction tx, records the change and returns new state of the item
+func (s *CatalogStore) updateItem(tx *gorm.DB, id uint, item Item, ifVersion uint) (Item, error) {
+	var updated Item
+	query := tx.Model(&updated).Clauses(clause.Returning{}).Where("id = ?", id)
+	if ifVersion != 0 {
+		query = query.Where("version = ?", ifVersion)
+	}
+	resp := query.Updates(item.updates())
 	if err := resp.Error; err != nil {
-		return fmt.Errorf("error while updating item with id %d: %w", id, err)
+		return Item{}, err
 	}
 	if resp.RowsAffected != 1 {
+		return Item{}, s.missReason(tx, id, ifVersion)
+	}
+	return updated, recordChange(tx, updated, HistoryUpdated)
+}
+
+// missReason explains why item with id was not affected by conditional statement
+func (s *CatalogStore) missReason(db *gorm.DB, id uint, ifVersion uint) error {
+	if ifVersion == 0 {
 		return gorm.ErrRecordNotFound
 	}
+	var count int64
+	if err := db.Model(&Item{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return ErrVersionMismatch
+}
 
-	return nil
+// conn returns db session bound to ctx and limited by the store query timeout
+func (s *CatalogStore) conn(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	cancel := context.CancelFunc(func() {})
+	if s.queryTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.queryTimeout)
+	}
+	return s.db.WithContext(ctx), cancel
+}
+
+// isUniqueViolation reports if err was caused by violation of unique constraint
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
 }
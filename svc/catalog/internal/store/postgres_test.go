@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
 	"fmt"
@@ -10,21 +11,51 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"testing"
+	"time"
 )
 
 const (
-	getItem    = `SELECT \* FROM "items" WHERE "items"\."id" = \$1 ORDER BY "items"\."id" LIMIT 1`
-	deleteItem = `DELETE FROM "items" WHERE "items"\."id" = \$1`
+	getItem    = `SELECT \* FROM "items" WHERE "items"\."id" = \$1 AND "items"\."deleted_at" IS NULL ORDER BY "items"\."id" LIMIT 1`
+	deleteItem = `UPDATE "items" SET "deleted_at"=\$1 WHERE "items"\."id" = \$2 AND "items"\."deleted_at" IS NULL`
 	createItem = `INSERT INTO "items"`
-	updateItem = `UPDATE "items"`
+	updateItem = `UPDATE "items" SET "description"=\$1,"name"=\$2,"price_code"=\$3,"price_minor"=\$4,"version"=version \+ 1 WHERE id = \$5`
+	countItem  = `SELECT count\(\*\) FROM "items" WHERE id = \$1`
+	// insertChange is insert of item state to its history
+	insertChange = `INSERT INTO "item_history" \("item_id","version","operation","actor","changed_at","snapshot"\) ` +
+		`VALUES \(\$1,\$2,\$3,\$4,\$5,\$6\) RETURNING "id"`
+	// insertEvent is insert of event to the outbox
+	insertEvent = `INSERT INTO "outbox_events" \("type","item_id","version","actor","item","occurred_at","published_at"\) ` +
+		`VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7\) RETURNING "id"`
+	// incrementVersion is increment of item version made when its part is revised
+	incrementVersion = `UPDATE "items" SET "version"=version \+ 1 WHERE id = \$1 AND "items"\."deleted_at" IS NULL RETURNING \*`
 )
 
+// expectRecordChange expects item state to be appended to its history and event about it to the outbox
+func expectRecordChange(mock sqlmock.Sqlmock, operation HistoryOperation, version uint, actor, snapshot string) {
+	mock.ExpectQuery(insertChange).
+		WithArgs(itemID, version, operation, actor, sqlmock.AnyArg(), snapshot).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(insertEvent).
+		WithArgs(eventTypes[operation], itemID, version, actor, snapshot, sqlmock.AnyArg(), nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+// expectReviseItem expects version of the item to be incremented and revision of its part recorded in history
+func expectReviseItem(mock sqlmock.Sqlmock, part ItemPart) {
+	mock.ExpectQuery(incrementVersion).WithArgs(itemID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price_minor", "price_code", "version"}).
+			AddRow(itemID, itemName, itemPrice, itemPriceCode, itemVersion+1))
+	expectRecordChange(mock, HistoryRevised, itemVersion+1, "system",
+		fmt.Sprintf(`{"name":"some name","price":5000,"priceCode":"EUR","part":"%s"}`, part))
+}
+
 var (
 	itemID        = uint(1)
 	itemName      = "some name"
 	itemDesc      = "some desc"
-	itemPrice     = float64(50)
+	itemPrice     = int64(5000)
 	itemPriceCode = "EUR"
+	itemVersion   = uint(1)
 )
 
 func TestGetItem(t *testing.T) {
@@ -43,7 +74,7 @@ func TestGetItem(t *testing.T) {
 		{
 			name: "Successful",
 			rows: sqlmock.
-				NewRows([]string{"id", "name", "description", "price", "price_code"}).
+				NewRows([]string{"id", "name", "description", "price_minor", "price_code"}).
 				AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode),
 			expectedRes: Item{
 				ID:          itemID,
@@ -56,7 +87,7 @@ func TestGetItem(t *testing.T) {
 		{
 			name: "Unsuccessful - error",
 			rows: sqlmock.
-				NewRows([]string{"id", "name", "description", "price", "price_code"}).
+				NewRows([]string{"id", "name", "description", "price_minor", "price_code"}).
 				AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode),
 			expectedErr: errors.New("some err"),
 		},
@@ -69,7 +100,7 @@ func TestGetItem(t *testing.T) {
 				mock.ExpectQuery(getItem).WithArgs(itemID).WillReturnRows(test.rows)
 			}
 
-			item, err := store.GetItem(1)
+			item, err := store.GetItem(context.Background(), 1)
 
 			if test.expectedErr != nil {
 				assert.ErrorContains(t, err, "some err")
@@ -100,7 +131,7 @@ func TestGetItems(t *testing.T) {
 		{
 			name: "Successful",
 			rows: sqlmock.
-				NewRows([]string{"id", "name", "description", "price", "price_code"}).
+				NewRows([]string{"id", "name", "description", "price_minor", "price_code"}).
 				AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode),
 			page:     1,
 			pageSize: 10,
@@ -108,7 +139,7 @@ func TestGetItems(t *testing.T) {
 		{
 			name: "Successful",
 			rows: sqlmock.
-				NewRows([]string{"id", "name", "description", "price", "price_code"}).
+				NewRows([]string{"id", "name", "description", "price_minor", "price_code"}).
 				AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode),
 			page:     2,
 			pageSize: 10,
@@ -116,7 +147,7 @@ func TestGetItems(t *testing.T) {
 		{
 			name: "Unsuccessful - error",
 			rows: sqlmock.
-				NewRows([]string{"id", "name", "description", "price", "price_code"}).
+				NewRows([]string{"id", "name", "description", "price_minor", "price_code"}).
 				AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode),
 			page:        1,
 			pageSize:    10,
@@ -125,7 +156,7 @@ func TestGetItems(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			query := fmt.Sprintf(`SELECT \* FROM "items" LIMIT %d`, test.pageSize)
+			query := fmt.Sprintf(`SELECT \* FROM "items" WHERE "items"\."deleted_at" IS NULL ORDER BY "id" LIMIT %d`, test.pageSize)
 			if test.page > 1 {
 				query = query + fmt.Sprintf(" OFFSET %d", (test.page-1)*test.pageSize)
 			}
@@ -135,7 +166,7 @@ func TestGetItems(t *testing.T) {
 				mock.ExpectQuery(query).WillReturnRows(test.rows)
 			}
 
-			items, err := store.GetItems(test.pageSize, test.page)
+			items, err := store.GetItems(context.Background(), ItemQuery{PageSize: test.pageSize, Page: test.page})
 
 			if test.expectedErr != nil {
 				assert.ErrorContains(t, err, "some err")
@@ -149,6 +180,76 @@ func TestGetItems(t *testing.T) {
 	}
 }
 
+func TestGetItems_filtersAndSort(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}))
+	require.NoError(t, err)
+	store := &CatalogStore{db: db}
+
+	mock.ExpectQuery(`SELECT \* FROM "items" WHERE price_minor >= CAST\(\$1 AS numeric\) \* 10::numeric \^ CASE UPPER\(price_code\) .* END `+
+		`AND price_minor <= CAST\(\$2 AS numeric\) \* 10::numeric \^ CASE UPPER\(price_code\) .* END AND UPPER\(price_code\) = \$3 AND name ILIKE \$4 `+
+		`AND "items"\."deleted_at" IS NULL ORDER BY price_minor / 10::numeric \^ CASE UPPER\(price_code\) .* END DESC,"id" DESC LIMIT 10`).
+		WithArgs("10", "20.5", "EUR", `%50\%%`).
+		WillReturnRows(sqlmock.
+			NewRows([]string{"id", "name", "description", "price_minor", "price_code"}).
+			AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode))
+
+	items, err := store.GetItems(context.Background(), ItemQuery{
+		ItemFilter: ItemFilter{MinPrice: v2p("10"), MaxPrice: v2p("20.5"), PriceCode: v2p("eur"), Name: v2p("50%")},
+		Sort:       "-price",
+		PageSize:   10,
+		Page:       1,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetItems_cursor(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}))
+	require.NoError(t, err)
+	store := &CatalogStore{db: db}
+	query := ItemQuery{Sort: "-price", PageSize: 1, Page: 1}
+	query.Cursor = NextCursor(query, []Item{{ID: 7, Price: v2p(int64(1500)), PriceCode: v2p("JPY")}})
+	query.PageSize = 10
+
+	mock.ExpectQuery(`SELECT \* FROM "items" WHERE \(price_minor / 10::numeric \^ CASE UPPER\(price_code\) .* END, id\) < `+
+		`\(CAST\(\$1 AS numeric\), \$2\) AND "items"\."deleted_at" IS NULL `+
+		`ORDER BY price_minor / 10::numeric \^ CASE UPPER\(price_code\) .* END DESC,"id" DESC LIMIT 10`).
+		WithArgs("1500", 7).
+		WillReturnRows(sqlmock.
+			NewRows([]string{"id", "name", "description", "price_minor", "price_code"}).
+			AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode))
+
+	items, err := store.GetItems(context.Background(), query)
+
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountItems(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}))
+	require.NoError(t, err)
+	store := &CatalogStore{db: db}
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "items" WHERE price_minor >= CAST\(\$1 AS numeric\) .* AND "items"\."deleted_at" IS NULL`).
+		WithArgs("10").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := store.CountItems(context.Background(), ItemFilter{MinPrice: v2p("10")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetItems_inputValidation(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -173,7 +274,7 @@ func TestGetItems_inputValidation(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			_, err := (&CatalogStore{}).GetItems(test.pageSize, test.page)
+			_, err := (&CatalogStore{}).GetItems(context.Background(), ItemQuery{PageSize: test.pageSize, Page: test.page})
 			assert.ErrorIs(t, err, ErrInvalidPageParams)
 		})
 	}
@@ -188,31 +289,48 @@ func TestDeleteItem(t *testing.T) {
 
 	tests := []struct {
 		name        string
+		result      driver.Result
+		mockErr     error
 		expectedErr error
 	}{
 		{
-			name: "Successful",
+			name:   "Successful",
+			result: sqlmock.NewResult(1, 1),
 		},
 		{
 			name:        "Unsuccessful - error",
+			mockErr:     errors.New("some err"),
 			expectedErr: errors.New("some err"),
 		},
+		{
+			name:        "Unsuccessful - no rows affected",
+			result:      sqlmock.NewResult(1, 0),
+			expectedErr: gorm.ErrRecordNotFound,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mock.ExpectBegin()
-			if test.expectedErr != nil {
-				mock.ExpectExec(deleteItem).WithArgs(1).WillReturnError(test.expectedErr)
+			if test.mockErr != nil {
+				mock.ExpectExec(deleteItem).WithArgs(sqlmock.AnyArg(), 1).WillReturnError(test.mockErr)
 				mock.ExpectRollback()
 			} else {
-				mock.ExpectExec(deleteItem).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectCommit()
+				mock.ExpectExec(deleteItem).WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(test.result)
+				if test.expectedErr != nil {
+					mock.ExpectRollback()
+				} else {
+					mock.ExpectQuery(`SELECT \* FROM "items" WHERE "items"\."id" = \$1 ORDER BY "items"\."id" LIMIT 1`).
+						WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "version", "deleted_at"}).
+						AddRow(itemID, itemName, itemVersion, time.Now()))
+					expectRecordChange(mock, HistoryDeleted, itemVersion, "system", `{"name":"some name"}`)
+					mock.ExpectCommit()
+				}
 			}
 
-			err := store.DeleteItem(1)
+			err := store.DeleteItem(context.Background(), 1, 0)
 
 			if test.expectedErr != nil {
-				assert.ErrorContains(t, err, "some err")
+				assert.ErrorContains(t, err, test.expectedErr.Error())
 			} else {
 				assert.NoError(t, err)
 			}
@@ -238,7 +356,7 @@ func TestCreateItem(t *testing.T) {
 		{
 			name: "Successful",
 			rows: sqlmock.
-				NewRows([]string{"id", "name", "description", "price", "price_code"}).
+				NewRows([]string{"id", "name", "description", "price_minor", "price_code"}).
 				AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode),
 			expectedRes: Item{
 				ID:          itemID,
@@ -246,12 +364,13 @@ func TestCreateItem(t *testing.T) {
 				Description: &itemDesc,
 				Price:       &itemPrice,
 				PriceCode:   &itemPriceCode,
+				Version:     itemVersion,
 			},
 		},
 		{
 			name: "Unsuccessful - error",
 			rows: sqlmock.
-				NewRows([]string{"id", "name", "description", "price", "price_code"}).
+				NewRows([]string{"id", "name", "description", "price_minor", "price_code"}).
 				AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode),
 			expectedErr: errors.New("some err"),
 		},
@@ -260,14 +379,16 @@ func TestCreateItem(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			mock.ExpectBegin()
 			if test.expectedErr != nil {
-				mock.ExpectQuery(createItem).WithArgs(itemName, itemDesc, itemPrice, itemPriceCode).WillReturnError(test.expectedErr)
+				mock.ExpectQuery(createItem).WithArgs(nil, itemName, itemDesc, itemPrice, itemPriceCode, itemVersion, nil).WillReturnError(test.expectedErr)
 				mock.ExpectRollback()
 			} else {
-				mock.ExpectQuery(createItem).WithArgs(itemName, itemDesc, itemPrice, itemPriceCode).WillReturnRows(test.rows)
+				mock.ExpectQuery(createItem).WithArgs(nil, itemName, itemDesc, itemPrice, itemPriceCode, itemVersion, nil).WillReturnRows(test.rows)
+				expectRecordChange(mock, HistoryCreated, itemVersion, "alice",
+					`{"name":"some name","description":"some desc","price":5000,"priceCode":"EUR"}`)
 				mock.ExpectCommit()
 			}
 
-			item, err := store.CreateItem(Item{
+			item, err := store.CreateItem(WithActor(context.Background(), "alice"), Item{
 				Name:        &itemName,
 				Description: &itemDesc,
 				Price:       &itemPrice,
@@ -294,14 +415,42 @@ func TestUpdateItem(t *testing.T) {
 	store := &CatalogStore{db: db}
 
 	tests := []struct {
-		name      string
-		result    driver.Result
-		mockErr   error
-		expectErr error
+		name        string
+		ifVersion   uint
+		rows        *sqlmock.Rows
+		count       int
+		mockErr     error
+		expectedRes Item
+		expectErr   error
 	}{
 		{
-			name:   "Successful",
-			result: sqlmock.NewResult(1, 1),
+			name: "Successful",
+			rows: sqlmock.
+				NewRows([]string{"id", "name", "description", "price_minor", "price_code", "version"}).
+				AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode, itemVersion+1),
+			expectedRes: Item{
+				ID:          itemID,
+				Name:        &itemName,
+				Description: &itemDesc,
+				Price:       &itemPrice,
+				PriceCode:   &itemPriceCode,
+				Version:     itemVersion + 1,
+			},
+		},
+		{
+			name:      "Successful - expected version matches",
+			ifVersion: itemVersion,
+			rows: sqlmock.
+				NewRows([]string{"id", "name", "description", "price_minor", "price_code", "version"}).
+				AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode, itemVersion+1),
+			expectedRes: Item{
+				ID:          itemID,
+				Name:        &itemName,
+				Description: &itemDesc,
+				Price:       &itemPrice,
+				PriceCode:   &itemPriceCode,
+				Version:     itemVersion + 1,
+			},
 		},
 		{
 			name:      "Unsuccessful - error",
@@ -310,35 +459,146 @@ func TestUpdateItem(t *testing.T) {
 		},
 		{
 			name:      "Unsuccessful - no rows affected",
-			result:    sqlmock.NewResult(1, 0),
+			rows:      sqlmock.NewRows([]string{"id"}),
+			expectErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:      "Unsuccessful - version mismatch",
+			ifVersion: itemVersion,
+			rows:      sqlmock.NewRows([]string{"id"}),
+			count:     1,
+			expectErr: ErrVersionMismatch,
+		},
+		{
+			name:      "Unsuccessful - expected version of missing item",
+			ifVersion: itemVersion,
+			rows:      sqlmock.NewRows([]string{"id"}),
 			expectErr: gorm.ErrRecordNotFound,
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
+			query, args := updateItem, []driver.Value{itemDesc, itemName, itemPriceCode, itemPrice, itemID}
+			if test.ifVersion != 0 {
+				query, args = updateItem+` AND version = \$6`, append(args, test.ifVersion)
+			}
 			mock.ExpectBegin()
 			if test.mockErr != nil {
-				mock.ExpectExec(updateItem).WithArgs(itemName, itemDesc, itemPrice, itemPriceCode, itemID).WillReturnError(test.mockErr)
+				mock.ExpectQuery(query).WithArgs(args...).WillReturnError(test.mockErr)
 				mock.ExpectRollback()
 			} else {
-				mock.ExpectExec(updateItem).WithArgs(itemName, itemDesc, itemPrice, itemPriceCode, itemID).WillReturnResult(test.result)
-				mock.ExpectCommit()
+				mock.ExpectQuery(query).WithArgs(args...).WillReturnRows(test.rows)
+				if test.expectErr != nil {
+					if test.ifVersion != 0 {
+						mock.ExpectQuery(countItem).WithArgs(itemID).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(test.count))
+					}
+					mock.ExpectRollback()
+				} else {
+					expectRecordChange(mock, HistoryUpdated, itemVersion+1, "system",
+						`{"name":"some name","description":"some desc","price":5000,"priceCode":"EUR"}`)
+					mock.ExpectCommit()
+				}
 			}
 
-			err := store.UpdateItem(itemID, Item{
+			item, err := store.UpdateItem(context.Background(), itemID, Item{
 				Name:        &itemName,
 				Description: &itemDesc,
 				Price:       &itemPrice,
 				PriceCode:   &itemPriceCode,
-			})
+			}, test.ifVersion)
 
 			if test.expectErr != nil {
 				assert.ErrorContains(t, err, test.expectErr.Error())
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, test.expectedRes, item)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRestoreItem(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}))
+	require.NoError(t, err)
+	store := &CatalogStore{db: db}
+
+	tests := []struct {
+		name        string
+		rows        *sqlmock.Rows
+		mockErr     error
+		expectedRes Item
+		expectedErr error
+	}{
+		{
+			name: "Successful",
+			rows: sqlmock.
+				NewRows([]string{"id", "name", "version", "deleted_at"}).
+				AddRow(itemID, itemName, itemVersion+1, nil),
+			expectedRes: Item{ID: itemID, Name: &itemName, Version: itemVersion + 1},
+		},
+		{
+			name:        "Unsuccessful - item not in trash",
+			rows:        sqlmock.NewRows([]string{"id"}),
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:        "Unsuccessful - error",
+			mockErr:     errors.New("some err"),
+			expectedErr: errors.New("some err"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := `UPDATE "items" SET "deleted_at"=\$1,"version"=version \+ 1 WHERE id = \$2 AND deleted_at IS NOT NULL RETURNING \*`
+			mock.ExpectBegin()
+			if test.mockErr != nil {
+				mock.ExpectQuery(query).WithArgs(nil, itemID).WillReturnError(test.mockErr)
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectQuery(query).WithArgs(nil, itemID).WillReturnRows(test.rows)
+				if test.expectedErr != nil {
+					mock.ExpectRollback()
+				} else {
+					expectRecordChange(mock, HistoryRestored, itemVersion+1, "system", `{"name":"some name"}`)
+					mock.ExpectCommit()
+				}
 			}
 
+			item, err := store.RestoreItem(context.Background(), itemID)
+
+			if test.expectedErr != nil {
+				assert.ErrorContains(t, err, test.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedRes, item)
+			}
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
+
+func TestPurgeDeletedItems(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}))
+	require.NoError(t, err)
+	store := &CatalogStore{db: db}
+	before := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO media_deletions \(blob_key\) SELECT blob_key FROM item_images WHERE item_id IN ` +
+		`\(SELECT id FROM items WHERE deleted_at < \$1\) ON CONFLICT DO NOTHING`).WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`DELETE FROM "items" WHERE deleted_at < \$1`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	purged, err := store.PurgeDeletedItems(context.Background(), before)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
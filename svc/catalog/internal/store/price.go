@@ -0,0 +1,128 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"sort"
+	"unicode/utf8"
+)
+
+var ErrInvalidPrice = errors.New("invalid price")
+
+// ItemPrice is an explicit price of the item in currency other than its own, e.g. rounded price for USD market
+type ItemPrice struct {
+	ItemID   uint   `gorm:"primaryKey;autoIncrement:false"`
+	Currency string `gorm:"primaryKey"`
+	// Price is expressed in minor units of Currency
+	Price int64 `gorm:"column:price_minor"`
+}
+
+func (ItemPrice) TableName() string {
+	return "item_prices"
+}
+
+// validatePrices checks if every price is non-negative and currencies are not repeated
+func validatePrices(prices []ItemPrice) error {
+	seen := make(map[string]bool, len(prices))
+	for _, price := range prices {
+		if price.Currency == "" || utf8.RuneCountInString(price.Currency) > maxPriceCodeLength {
+			return fmt.Errorf("%w: currency should be between 1 and %d characters long", ErrInvalidPrice,
+				maxPriceCodeLength)
+		}
+		if price.Price < 0 {
+			return fmt.Errorf("%w: price cannot be negative", ErrInvalidPrice)
+		}
+		if seen[price.Currency] {
+			return fmt.Errorf("%w: item can have one price in %s", ErrInvalidPrice, price.Currency)
+		}
+		seen[price.Currency] = true
+	}
+	return nil
+}
+
+// SetItemPrices replaces price list of the item with ID and returns it ordered by currency
+func (s *CatalogStore) SetItemPrices(ctx context.Context, itemID uint, prices []ItemPrice) ([]ItemPrice, error) {
+	if err := validatePrices(prices); err != nil {
+		return nil, err
+	}
+	prices = sortPrices(itemID, prices)
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := reviseItem(tx, itemID, PartPrices); err != nil {
+			return err
+		}
+		if err := tx.Where("item_id = ?", itemID).Delete(&ItemPrice{}).Error; err != nil {
+			return err
+		}
+		if len(prices) == 0 {
+			return nil
+		}
+		return tx.Create(&prices).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while setting prices of item with id %d: %w", itemID, err)
+	}
+	return prices, nil
+}
+
+// GetItemPrices returns price lists of items with provided IDs ordered by item ID and currency
+func (s *CatalogStore) GetItemPrices(ctx context.Context, itemIDs []uint) (prices []ItemPrice, err error) {
+	if len(itemIDs) == 0 {
+		return nil, nil
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err = db.Where("item_id IN ?", itemIDs).Order("item_id").Order("currency").Find(&prices).Error
+	return
+}
+
+// SetItemPrices replaces price list of the item with ID and returns it ordered by currency
+func (s *MemoryStore) SetItemPrices(ctx context.Context, itemID uint, prices []ItemPrice) ([]ItemPrice, error) {
+	if err := validatePrices(prices); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while setting prices of item with id %d: %w", itemID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.live(itemID); !ok {
+		return nil, fmt.Errorf("error while setting prices of item with id %d: %w", itemID, gorm.ErrRecordNotFound)
+	}
+	prices = sortPrices(itemID, prices)
+	s.itemPrices[itemID] = prices
+	s.reviseItem(ctx, itemID, PartPrices)
+	return append([]ItemPrice(nil), prices...), nil
+}
+
+// GetItemPrices returns price lists of items with provided IDs ordered by item ID and currency
+func (s *MemoryStore) GetItemPrices(ctx context.Context, itemIDs []uint) ([]ItemPrice, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := uniqueIDs(itemIDs)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	var prices []ItemPrice
+	for _, id := range ids {
+		prices = append(prices, s.itemPrices[id]...)
+	}
+	return prices, nil
+}
+
+// sortPrices returns copy of prices assigned to the item with ID ordered by currency
+func sortPrices(itemID uint, prices []ItemPrice) []ItemPrice {
+	sorted := make([]ItemPrice, 0, len(prices))
+	for _, price := range prices {
+		price.ItemID = itemID
+		sorted = append(sorted, price)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Currency < sorted[j].Currency })
+	return sorted
+}
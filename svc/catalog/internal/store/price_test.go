@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSetItemPrices(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectBegin()
+	expectReviseItem(mock, PartPrices)
+	mock.ExpectExec(`DELETE FROM "item_prices" WHERE item_id = \$1`).WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "item_prices" \("item_id","currency","price_minor"\) VALUES \(\$1,\$2,\$3\),\(\$4,\$5,\$6\)`).
+		WithArgs(1, "PLN", 4999, 1, "USD", 1299).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	prices, err := store.SetItemPrices(context.Background(), 1,
+		[]ItemPrice{{Currency: "USD", Price: 1299}, {Currency: "PLN", Price: 4999}})
+
+	require.NoError(t, err)
+	assert.Equal(t, []ItemPrice{{ItemID: 1, Currency: "PLN", Price: 4999}, {ItemID: 1, Currency: "USD", Price: 1299}},
+		prices)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetItemPrices_invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []ItemPrice
+	}{
+		{
+			name:   "Negative price",
+			prices: []ItemPrice{{Currency: "USD", Price: -1}},
+		},
+		{
+			name:   "Missing currency",
+			prices: []ItemPrice{{Price: 1}},
+		},
+		{
+			name:   "Repeated currency",
+			prices: []ItemPrice{{Currency: "USD", Price: 1}, {Currency: "USD", Price: 2}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+
+			_, err := store.SetItemPrices(context.Background(), 1, test.prices)
+
+			assert.ErrorIs(t, err, ErrInvalidPrice)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetItemPrices(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectQuery(`SELECT \* FROM "item_prices" WHERE item_id IN \(\$1,\$2\) ORDER BY item_id,currency`).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"item_id", "currency", "price_minor"}).
+			AddRow(1, "USD", 1299).
+			AddRow(2, "PLN", 4999))
+
+	prices, err := store.GetItemPrices(context.Background(), []uint{1, 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, []ItemPrice{{ItemID: 1, Currency: "USD", Price: 1299}, {ItemID: 2, Currency: "PLN", Price: 4999}},
+		prices)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,209 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"math/big"
+	"strings"
+)
+
+const defaultSortField = "id"
+
+var ErrInvalidSort = errors.New("invalid sort parameter")
+
+// sortableFields maps API names of fields which items can be sorted by to db columns. Prices in different currencies
+// are compared by amounts in major units, the same way as by price filter.
+var sortableFields = map[string]clause.Column{
+	"id":        {Name: "id"},
+	"name":      {Name: "name"},
+	"price":     {Name: "price_minor / 10::numeric ^ " + money.ExponentSQL("price_code"), Raw: true},
+	"priceCode": {Name: "price_code"},
+}
+
+// ItemFilter narrows down listed items. Nil fields are not taken into account.
+type ItemFilter struct {
+	// MinPrice and MaxPrice are decimal amounts in major units of the item currency, e.g. "12.50". They are compared
+	// with regular price of the item, scheduled prices are not taken into account.
+	MinPrice  *string
+	MaxPrice  *string
+	PriceCode *string
+	// Name is matched case-insensitively as a substring of item name
+	Name *string
+	// CategoryID narrows down items to the ones assigned to the category, or to any of its descendants when
+	// IncludeDescendants is set
+	CategoryID         *uint
+	IncludeDescendants bool
+}
+
+// ItemQuery describes which page of filtered items should be listed and in which order
+type ItemQuery struct {
+	ItemFilter
+	// Sort is a name of sortable field, optionally prefixed with '-' for descending order. Items are sorted by ID
+	// when empty.
+	Sort string
+	// Cursor continues listing after the page it was issued for, see NextCursor. It cannot be combined with Page.
+	Cursor   string
+	PageSize int
+	Page     int
+}
+
+// SortOrder is parsed and validated ItemQuery.Sort
+type SortOrder struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort validates sort parameter against the whitelist of sortable fields
+func ParseSort(sort string) (SortOrder, error) {
+	order := SortOrder{Field: strings.TrimPrefix(sort, "-"), Desc: strings.HasPrefix(sort, "-")}
+	if sort == "" {
+		order.Field = defaultSortField
+	}
+	if _, ok := sortableFields[order.Field]; !ok {
+		return SortOrder{}, fmt.Errorf("%w: items cannot be sorted by %q", ErrInvalidSort, order.Field)
+	}
+	return order, nil
+}
+
+// validate checks page params, sort field and cursor of the query. Item cursor points at is returned when set.
+func (q ItemQuery) validate() (SortOrder, *Item, error) {
+	if q.Page < 1 || q.PageSize < 1 {
+		return SortOrder{}, nil, ErrInvalidPageParams
+	}
+	if err := q.ItemFilter.validate(); err != nil {
+		return SortOrder{}, nil, err
+	}
+	order, err := ParseSort(q.Sort)
+	if err != nil || q.Cursor == "" {
+		return order, nil, err
+	}
+	if q.Page != 1 {
+		return SortOrder{}, nil, fmt.Errorf("%w: cursor cannot be combined with page", ErrInvalidCursor)
+	}
+	last, err := decodeCursor(q.Cursor, order, q.Sort)
+	if err != nil {
+		return SortOrder{}, nil, err
+	}
+	return order, &last, nil
+}
+
+// scope applies filter conditions to db query
+func (f ItemFilter) scope(db *gorm.DB) *gorm.DB {
+	if f.MinPrice != nil {
+		db = db.Where("price_minor >= CAST(? AS numeric) * 10::numeric ^ "+money.ExponentSQL("price_code"), *f.MinPrice)
+	}
+	if f.MaxPrice != nil {
+		db = db.Where("price_minor <= CAST(? AS numeric) * 10::numeric ^ "+money.ExponentSQL("price_code"), *f.MaxPrice)
+	}
+	if f.PriceCode != nil {
+		db = db.Where("UPPER(price_code) = ?", strings.ToUpper(*f.PriceCode))
+	}
+	if f.Name != nil {
+		db = db.Where("name ILIKE ?", "%"+escapeLike(*f.Name)+"%")
+	}
+	if f.CategoryID != nil && f.IncludeDescendants {
+		db = db.Where("id IN (SELECT item_id FROM item_categories WHERE category_id IN ("+descendantCategories+"))",
+			*f.CategoryID)
+	} else if f.CategoryID != nil {
+		db = db.Where("id IN (SELECT item_id FROM item_categories WHERE category_id = ?)", *f.CategoryID)
+	}
+	return db
+}
+
+// validate checks if price bounds are decimal numbers
+func (f ItemFilter) validate() error {
+	for _, bound := range []*string{f.MinPrice, f.MaxPrice} {
+		if bound == nil {
+			continue
+		}
+		if _, err := money.ParseDecimal(*bound); err != nil {
+			return fmt.Errorf("error while parsing price filter: %w", err)
+		}
+	}
+	return nil
+}
+
+// matches reports if item satisfies filter conditions on its fields. Filter has to be validated first.
+func (f ItemFilter) matches(item Item) bool {
+	price := majorPrice(item)
+	switch {
+	case f.MinPrice != nil && price.Cmp(mustParseDecimal(*f.MinPrice)) < 0:
+		return false
+	case f.MaxPrice != nil && price.Cmp(mustParseDecimal(*f.MaxPrice)) > 0:
+		return false
+	case f.PriceCode != nil && !strings.EqualFold(derefOrZero(item.PriceCode), *f.PriceCode):
+		return false
+	case f.Name != nil && !strings.Contains(strings.ToLower(derefOrZero(item.Name)), strings.ToLower(*f.Name)):
+		return false
+	}
+	return true
+}
+
+// scope applies sort order to db query. ID is always used as a tiebreaker, so the order is stable.
+func (o SortOrder) scope(db *gorm.DB) *gorm.DB {
+	db = db.Order(clause.OrderByColumn{Column: sortableFields[o.Field], Desc: o.Desc})
+	if o.Field != defaultSortField {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: o.Desc})
+	}
+	return db
+}
+
+// less reports if item a should be listed before item b
+func (o SortOrder) less(a, b Item) bool {
+	cmp := 0
+	switch o.Field {
+	case "name":
+		cmp = strings.Compare(derefOrZero(a.Name), derefOrZero(b.Name))
+	case "price":
+		cmp = majorPrice(a).Cmp(majorPrice(b))
+	case "priceCode":
+		cmp = strings.Compare(derefOrZero(a.PriceCode), derefOrZero(b.PriceCode))
+	}
+	if cmp == 0 {
+		cmp = compare(a.ID, b.ID)
+	}
+	if o.Desc {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+// majorPrice returns price of item in major units of its currency
+func majorPrice(item Item) *big.Rat {
+	return money.Amount{Minor: derefOrZero(item.Price), Currency: derefOrZero(item.PriceCode)}.Rat()
+}
+
+// escapeLike escapes wildcard characters of LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func compare[V ~int | ~int64 | ~uint | ~float64](a, b V) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func derefOrZero[V any](v *V) V {
+	var zero V
+	if v == nil {
+		return zero
+	}
+	return *v
+}
+
+// mustParseDecimal parses decimal which was already validated
+func mustParseDecimal(value string) *big.Rat {
+	r, err := money.ParseDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name        string
+		sort        string
+		expectedRes SortOrder
+		expectedErr error
+	}{
+		{
+			name:        "Default sort by id",
+			expectedRes: SortOrder{Field: "id"},
+		},
+		{
+			name:        "Ascending",
+			sort:        "priceCode",
+			expectedRes: SortOrder{Field: "priceCode"},
+		},
+		{
+			name:        "Descending",
+			sort:        "-name",
+			expectedRes: SortOrder{Field: "name", Desc: true},
+		},
+		{
+			name:        "Not whitelisted field",
+			sort:        "description",
+			expectedErr: ErrInvalidSort,
+		},
+		{
+			name:        "Column injection attempt",
+			sort:        "price; DROP TABLE items",
+			expectedErr: ErrInvalidSort,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			order, err := ParseSort(test.sort)
+
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Equal(t, test.expectedRes, order)
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `50\% off \_ \\`, escapeLike(`50% off _ \`))
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"math/big"
+	"time"
+)
+
+// exchangeRates is the only row of exchange rates table, so every instance converts prices with the same rates
+type exchangeRates struct {
+	ID        bool `gorm:"primaryKey"`
+	Base      string
+	Rates     rateValues
+	Source    string
+	UpdatedAt time.Time `gorm:"autoUpdateTime:false"`
+}
+
+func (exchangeRates) TableName() string {
+	return "exchange_rates"
+}
+
+// rateValues are exact decimal rates keyed by currency code, stored as jsonb
+type rateValues map[string]string
+
+// Value stores rates as jsonb
+func (v rateValues) Value() (driver.Value, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// Scan reads rates stored as jsonb
+func (v *rateValues) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		return json.Unmarshal(s, v)
+	case string:
+		return json.Unmarshal([]byte(s), v)
+	}
+	return fmt.Errorf("unsupported type of exchange rates: %T", src)
+}
+
+// SetExchangeRates replaces exchange rates prices are converted with
+func (s *CatalogStore) SetExchangeRates(ctx context.Context, rates money.Rates) error {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	row := exchangeRates{ID: true, Base: rates.Base, Rates: rates.Strings(), Source: rates.Source,
+		UpdatedAt: rates.UpdatedAt}
+	if err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&row).Error; err != nil {
+		return fmt.Errorf("error while setting exchange rates: %w", err)
+	}
+	return nil
+}
+
+// GetExchangeRates returns exchange rates prices are converted with, rates without base are returned when none were set
+func (s *CatalogStore) GetExchangeRates(ctx context.Context) (money.Rates, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var row exchangeRates
+	if err := db.Take(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return money.Rates{}, nil
+		}
+		return money.Rates{}, fmt.Errorf("error while getting exchange rates: %w", err)
+	}
+	rates, err := money.ParseRates(row.Base, row.Rates, row.Source, row.UpdatedAt.UTC())
+	if err != nil {
+		return money.Rates{}, fmt.Errorf("error while getting exchange rates: %w", err)
+	}
+	return rates, nil
+}
+
+// SetExchangeRates replaces exchange rates prices are converted with
+func (s *MemoryStore) SetExchangeRates(ctx context.Context, rates money.Rates) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error while setting exchange rates: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exchangeRates = copyRates(rates)
+	return nil
+}
+
+// GetExchangeRates returns exchange rates prices are converted with, rates without base are returned when none were set
+func (s *MemoryStore) GetExchangeRates(ctx context.Context) (money.Rates, error) {
+	if err := ctx.Err(); err != nil {
+		return money.Rates{}, fmt.Errorf("error while getting exchange rates: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return copyRates(s.exchangeRates), nil
+}
+
+// copyRates returns rates which do not share their map with the original ones
+func copyRates(rates money.Rates) money.Rates {
+	if rates.Rates == nil {
+		return rates
+	}
+	copied := rates
+	copied.Rates = make(map[string]*big.Rat, len(rates.Rates))
+	for code, rate := range rates.Rates {
+		copied.Rates[code] = new(big.Rat).Set(rate)
+	}
+	return copied
+}
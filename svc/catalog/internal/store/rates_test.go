@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSetExchangeRates(t *testing.T) {
+	store, mock := newMockStore(t)
+	updatedAt := time.Date(2022, 6, 1, 16, 0, 0, 0, time.UTC)
+	rates, err := money.ParseRates("EUR", map[string]string{"USD": "1.07"}, "admin", updatedAt)
+	require.NoError(t, err)
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "exchange_rates" \("id","base","rates","source","updated_at"\) VALUES \(\$1,\$2,\$3,\$4,\$5\) ON CONFLICT \("id"\) DO UPDATE SET "base"="excluded"\."base","rates"="excluded"\."rates","source"="excluded"\."source","updated_at"="excluded"\."updated_at"`).
+		WithArgs(true, "EUR", `{"USD":"1.07"}`, "admin", updatedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = store.SetExchangeRates(context.Background(), rates)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExchangeRates(t *testing.T) {
+	store, mock := newMockStore(t)
+	updatedAt := time.Date(2022, 6, 1, 16, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT \* FROM "exchange_rates" LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "base", "rates", "source", "updated_at"}).
+			AddRow(true, "EUR", []byte(`{"USD": "1.0700"}`), "admin", updatedAt))
+
+	rates, err := store.GetExchangeRates(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", rates.Base)
+	assert.Equal(t, map[string]string{"USD": "1.07"}, rates.Strings())
+	assert.Equal(t, "admin", rates.Source)
+	assert.Equal(t, updatedAt, rates.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExchangeRates_notSet(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectQuery(`SELECT \* FROM "exchange_rates" LIMIT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "base", "rates", "source", "updated_at"}))
+
+	rates, err := store.GetExchangeRates(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, rates.Base)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,252 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// ScheduledPrice is a price of the item in currency which is in effect between StartsAt and EndsAt, e.g. during
+// promotion. When windows of scheduled prices overlap, the one which started most recently wins.
+type ScheduledPrice struct {
+	ID       uint
+	ItemID   uint
+	Currency string
+	// Price is expressed in minor units of Currency
+	Price    int64 `gorm:"column:price_minor"`
+	StartsAt time.Time
+	// EndsAt is nil for prices which stay in effect indefinitely. Price which starts later takes precedence while it is
+	// in effect, once it ends the open-ended price is in effect again.
+	EndsAt *time.Time
+}
+
+// ActiveAt reports if price is in effect at provided time
+func (p ScheduledPrice) ActiveAt(at time.Time) bool {
+	return !p.StartsAt.After(at) && (p.EndsAt == nil || p.EndsAt.After(at))
+}
+
+// upcomingAfter reports if price starts or ends after provided time
+func (p ScheduledPrice) upcomingAfter(after time.Time) bool {
+	if p.EndsAt == nil {
+		return p.StartsAt.After(after)
+	}
+	return p.EndsAt.After(after)
+}
+
+// validate checks if scheduled price has currency, non-negative price and window which ends after it starts
+func (p ScheduledPrice) validate() error {
+	switch {
+	case p.Currency == "" || utf8.RuneCountInString(p.Currency) > maxPriceCodeLength:
+		return fmt.Errorf("%w: currency should be between 1 and %d characters long", ErrInvalidPrice, maxPriceCodeLength)
+	case p.Price < 0:
+		return fmt.Errorf("%w: price cannot be negative", ErrInvalidPrice)
+	case p.StartsAt.IsZero():
+		return fmt.Errorf("%w: start of the price window is required", ErrInvalidPrice)
+	case p.EndsAt != nil && !p.EndsAt.After(p.StartsAt):
+		return fmt.Errorf("%w: price window has to end after it starts", ErrInvalidPrice)
+	}
+	return nil
+}
+
+// CreateScheduledPrice schedules price of the item with ID and returns it with assigned ID
+func (s *CatalogStore) CreateScheduledPrice(ctx context.Context, itemID uint, price ScheduledPrice) (ScheduledPrice, error) {
+	if err := price.validate(); err != nil {
+		return ScheduledPrice{}, err
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	price.ID = 0
+	price.ItemID = itemID
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := reviseItem(tx, itemID, PartScheduledPrices); err != nil {
+			return err
+		}
+		return tx.Create(&price).Error
+	})
+	if err != nil {
+		return ScheduledPrice{}, fmt.Errorf("error while scheduling price of item with id %d: %w", itemID, err)
+	}
+	return price, nil
+}
+
+// GetScheduledPrices returns all scheduled prices of the item with ID ordered by start
+func (s *CatalogStore) GetScheduledPrices(ctx context.Context, itemID uint) ([]ScheduledPrice, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	prices := []ScheduledPrice{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&Item{}, itemID).Error; err != nil {
+			return err
+		}
+		return tx.Where("item_id = ?", itemID).Order("starts_at").Order("id").Find(&prices).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while getting scheduled prices of item with id %d: %w", itemID, err)
+	}
+	return prices, nil
+}
+
+// DeleteScheduledPrice removes scheduled price with ID of the item with provided ID
+func (s *CatalogStore) DeleteScheduledPrice(ctx context.Context, itemID, id uint) error {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := reviseItem(tx, itemID, PartScheduledPrices); err != nil {
+			return err
+		}
+		resp := tx.Where("item_id = ?", itemID).Delete(&ScheduledPrice{}, id)
+		if resp.Error == nil && resp.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return resp.Error
+	})
+	if err != nil {
+		return fmt.Errorf("error while deleting scheduled price with id %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetActivePrices returns scheduled prices of items with provided IDs which are in effect at provided time, ordered
+// by item ID and start
+func (s *CatalogStore) GetActivePrices(ctx context.Context, itemIDs []uint, at time.Time) (prices []ScheduledPrice, err error) {
+	if len(itemIDs) == 0 {
+		return nil, nil
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err = db.Where("item_id IN ? AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", itemIDs, at, at).
+		Order("item_id").Order("starts_at").Order("id").Find(&prices).Error
+	return
+}
+
+// GetUpcomingPrices returns requested page of scheduled prices of live items which start or end after provided
+// time, ordered by start
+func (s *CatalogStore) GetUpcomingPrices(ctx context.Context, after time.Time, pageSize, page int) (prices []ScheduledPrice, err error) {
+	if page < 1 || pageSize < 1 {
+		return nil, ErrInvalidPageParams
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err = db.Where("item_id IN (SELECT id FROM items WHERE deleted_at IS NULL)").
+		Where("(ends_at IS NULL AND starts_at > ?) OR ends_at > ?", after, after).
+		Order("starts_at").Order("id").
+		Offset((page - 1) * pageSize).Limit(pageSize).Find(&prices).Error
+	return
+}
+
+// CreateScheduledPrice schedules price of the item with ID and returns it with assigned ID
+func (s *MemoryStore) CreateScheduledPrice(ctx context.Context, itemID uint, price ScheduledPrice) (ScheduledPrice, error) {
+	if err := price.validate(); err != nil {
+		return ScheduledPrice{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return ScheduledPrice{}, fmt.Errorf("error while scheduling price of item with id %d: %w", itemID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.live(itemID); !ok {
+		return ScheduledPrice{}, fmt.Errorf("error while scheduling price of item with id %d: %w", itemID,
+			gorm.ErrRecordNotFound)
+	}
+	s.lastScheduledPriceID++
+	price.ID = s.lastScheduledPriceID
+	price.ItemID = itemID
+	price.EndsAt = clonePtr(price.EndsAt)
+	s.scheduledPrices[price.ID] = price
+	s.reviseItem(ctx, itemID, PartScheduledPrices)
+	return cloneScheduledPrice(price), nil
+}
+
+// GetScheduledPrices returns all scheduled prices of the item with ID ordered by start
+func (s *MemoryStore) GetScheduledPrices(ctx context.Context, itemID uint) ([]ScheduledPrice, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while getting scheduled prices of item with id %d: %w", itemID, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.live(itemID); !ok {
+		return nil, fmt.Errorf("error while getting scheduled prices of item with id %d: %w", itemID,
+			gorm.ErrRecordNotFound)
+	}
+	return s.scheduled(func(price ScheduledPrice) bool { return price.ItemID == itemID }), nil
+}
+
+// DeleteScheduledPrice removes scheduled price with ID of the item with provided ID
+func (s *MemoryStore) DeleteScheduledPrice(ctx context.Context, itemID, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error while deleting scheduled price with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	price, ok := s.scheduledPrices[id]
+	if _, live := s.live(itemID); !live || !ok || price.ItemID != itemID {
+		return fmt.Errorf("error while deleting scheduled price with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	delete(s.scheduledPrices, id)
+	s.reviseItem(ctx, itemID, PartScheduledPrices)
+	return nil
+}
+
+// GetActivePrices returns scheduled prices of items with provided IDs which are in effect at provided time, ordered
+// by item ID and start
+func (s *MemoryStore) GetActivePrices(ctx context.Context, itemIDs []uint, at time.Time) ([]ScheduledPrice, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	prices := s.scheduled(func(price ScheduledPrice) bool {
+		return containsID(itemIDs, price.ItemID) && price.ActiveAt(at)
+	})
+	sort.SliceStable(prices, func(i, j int) bool { return prices[i].ItemID < prices[j].ItemID })
+	return prices, nil
+}
+
+// GetUpcomingPrices returns requested page of scheduled prices of live items which start or end after provided
+// time, ordered by start
+func (s *MemoryStore) GetUpcomingPrices(ctx context.Context, after time.Time, pageSize, page int) ([]ScheduledPrice, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, ErrInvalidPageParams
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	prices := s.scheduled(func(price ScheduledPrice) bool {
+		_, live := s.live(price.ItemID)
+		return live && price.upcomingAfter(after)
+	})
+	return paginate(prices, pageSize, page), nil
+}
+
+// scheduled returns copies of scheduled prices which match, ordered by start. Caller has to hold the lock.
+func (s *MemoryStore) scheduled(matches func(price ScheduledPrice) bool) []ScheduledPrice {
+	prices := []ScheduledPrice{}
+	for _, price := range s.scheduledPrices {
+		if matches(price) {
+			prices = append(prices, cloneScheduledPrice(price))
+		}
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		if !prices[i].StartsAt.Equal(prices[j].StartsAt) {
+			return prices[i].StartsAt.Before(prices[j].StartsAt)
+		}
+		return prices[i].ID < prices[j].ID
+	})
+	return prices
+}
+
+func cloneScheduledPrice(price ScheduledPrice) ScheduledPrice {
+	clone := price
+	clone.EndsAt = clonePtr(price.EndsAt)
+	return clone
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCreateScheduledPrice(t *testing.T) {
+	startsAt := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(24 * time.Hour)
+	store, mock := newMockStore(t)
+	mock.ExpectBegin()
+	expectReviseItem(mock, PartScheduledPrices)
+	mock.ExpectQuery(`INSERT INTO "scheduled_prices" \("item_id","currency","price_minor","starts_at","ends_at"\) `+
+		`VALUES \(\$1,\$2,\$3,\$4,\$5\) RETURNING "id"`).
+		WithArgs(1, "EUR", 999, startsAt, endsAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectCommit()
+
+	price, err := store.CreateScheduledPrice(context.Background(), 1,
+		ScheduledPrice{Currency: "EUR", Price: 999, StartsAt: startsAt, EndsAt: &endsAt})
+
+	require.NoError(t, err)
+	assert.Equal(t, ScheduledPrice{ID: 3, ItemID: 1, Currency: "EUR", Price: 999, StartsAt: startsAt, EndsAt: &endsAt}, price)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateScheduledPrice_invalid(t *testing.T) {
+	startsAt := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		price ScheduledPrice
+	}{
+		{
+			name:  "Negative price",
+			price: ScheduledPrice{Currency: "EUR", Price: -1, StartsAt: startsAt},
+		},
+		{
+			name:  "Missing currency",
+			price: ScheduledPrice{Price: 1, StartsAt: startsAt},
+		},
+		{
+			name:  "Missing start",
+			price: ScheduledPrice{Currency: "EUR", Price: 1},
+		},
+		{
+			name:  "Window ends before it starts",
+			price: ScheduledPrice{Currency: "EUR", Price: 1, StartsAt: startsAt, EndsAt: v2p(startsAt)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+
+			_, err := store.CreateScheduledPrice(context.Background(), 1, test.price)
+
+			assert.ErrorIs(t, err, ErrInvalidPrice)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetActivePrices(t *testing.T) {
+	at := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	startsAt := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	store, mock := newMockStore(t)
+	mock.ExpectQuery(`SELECT \* FROM "scheduled_prices" WHERE item_id IN \(\$1,\$2\) AND starts_at <= \$3 AND `+
+		`\(ends_at IS NULL OR ends_at > \$4\) ORDER BY item_id,starts_at,id`).
+		WithArgs(1, 2, at, at).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "item_id", "currency", "price_minor", "starts_at", "ends_at"}).
+			AddRow(3, 1, "EUR", 999, startsAt, nil))
+
+	prices, err := store.GetActivePrices(context.Background(), []uint{1, 2}, at)
+
+	require.NoError(t, err)
+	assert.Equal(t, []ScheduledPrice{{ID: 3, ItemID: 1, Currency: "EUR", Price: 999, StartsAt: startsAt}}, prices)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUpcomingPrices(t *testing.T) {
+	after := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	store, mock := newMockStore(t)
+	mock.ExpectQuery(`SELECT \* FROM "scheduled_prices" WHERE item_id IN \(SELECT id FROM items WHERE deleted_at IS NULL\) `+
+		`AND \(\(ends_at IS NULL AND starts_at > \$1\) OR ends_at > \$2\) ORDER BY starts_at,id LIMIT 10 OFFSET 10`).
+		WithArgs(after, after).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "item_id", "currency", "price_minor", "starts_at", "ends_at"}))
+
+	prices, err := store.GetUpcomingPrices(context.Background(), after, 10, 2)
+
+	require.NoError(t, err)
+	assert.Empty(t, prices)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,209 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	highlightStart = "<mark>"
+	highlightStop  = "</mark>"
+)
+
+var ErrInvalidSearchQuery = errors.New("search query should contain at least one word")
+
+// searchItems ranks live items matching text with weighted name and description vectors. Highlighted name and
+// description snippet are returned along with the total number of matches, so a single round trip serves the page.
+// Text is HTML-escaped before it is highlighted, so highlights can be rendered as HTML.
+var searchItems = `SELECT items.*,
+	ts_rank(search, query) AS rank,
+	ts_headline('english', ` + escapeHTMLSQL("name") + `, query,
+		'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlight,
+	ts_headline('english', ` + escapeHTMLSQL("description") + `, query,
+		'StartSel=<mark>, StopSel=</mark>, MaxFragments=2') AS description_highlight,
+	count(*) OVER () AS total_count
+FROM items, websearch_to_tsquery('english', ?) query
+WHERE search @@ query AND deleted_at IS NULL
+ORDER BY rank DESC, id
+LIMIT ? OFFSET ?`
+
+// countSearchMatches counts live items matching text, for pages past the last one which have no rows to carry the count
+const countSearchMatches = `SELECT count(*)
+FROM items, websearch_to_tsquery('english', ?) query
+WHERE search @@ query AND deleted_at IS NULL`
+
+// wordPattern splits text into words matched by the in-memory search
+var wordPattern = regexp.MustCompile(`[\pL\pN]+`)
+
+// SearchQuery describes which page of items matching the text should be returned
+type SearchQuery struct {
+	// Text is a web search style query, e.g. `wool sweater -red` or `"winter jacket"`
+	Text     string
+	PageSize int
+	Page     int
+}
+
+// SearchResult is an item matching search query along with its relevance
+type SearchResult struct {
+	Item
+	Rank float64
+	// NameHighlight is HTML-escaped item name with matched words wrapped in <mark> tags
+	NameHighlight string
+	// DescriptionHighlight is HTML-escaped fragment of item description with matched words wrapped in <mark> tags
+	DescriptionHighlight string
+}
+
+// searchRow is a row returned by searchItems query
+type searchRow struct {
+	SearchResult `gorm:"embedded"`
+	TotalCount   int64
+}
+
+// validate checks page params and text of the query
+func (q SearchQuery) validate() error {
+	if q.Page < 1 || q.PageSize < 1 {
+		return ErrInvalidPageParams
+	}
+	if len(wordPattern.FindAllString(q.Text, -1)) == 0 {
+		return ErrInvalidSearchQuery
+	}
+	return nil
+}
+
+// SearchItems returns requested page of items matching full-text query, most relevant first, and the total number
+// of matching items
+func (s *CatalogStore) SearchItems(ctx context.Context, query SearchQuery) ([]SearchResult, int64, error) {
+	if err := query.validate(); err != nil {
+		return nil, 0, err
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var rows []searchRow
+	err := db.Raw(searchItems, query.Text, query.PageSize, (query.Page-1)*query.PageSize).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("error while searching items: %w", err)
+	}
+
+	results := make([]SearchResult, 0, len(rows))
+	var total int64
+	for _, row := range rows {
+		results = append(results, row.SearchResult)
+		total = row.TotalCount
+	}
+	if len(rows) == 0 && query.Page > 1 {
+		if err := db.Raw(countSearchMatches, query.Text).Scan(&total).Error; err != nil {
+			return nil, 0, fmt.Errorf("error while counting search matches: %w", err)
+		}
+	}
+	return results, total, nil
+}
+
+// SearchItems returns requested page of items containing all words of the query, most relevant first, and
+// the total number of matching items. Words matched in name weight more than the ones matched in description.
+// Unlike CatalogStore it doesn't stem words nor supports search operators.
+func (s *MemoryStore) SearchItems(ctx context.Context, query SearchQuery) ([]SearchResult, int64, error) {
+	if err := query.validate(); err != nil {
+		return nil, 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error while searching items: %w", err)
+	}
+	terms := wordPattern.FindAllString(strings.ToLower(query.Text), -1)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var results []SearchResult
+	for _, item := range s.items {
+		if item.DeletedAt.Valid {
+			continue
+		}
+		name, desc := derefOrZero(item.Name), derefOrZero(item.Description)
+		nameHits, descHits := countMatches(name, terms), countMatches(desc, terms)
+		if !containsAll(nameHits, descHits, terms) {
+			continue
+		}
+		results = append(results, SearchResult{
+			Item:                 cloneItem(item),
+			Rank:                 float64(sum(nameHits)) + 0.4*float64(sum(descHits)),
+			NameHighlight:        highlight(name, terms),
+			DescriptionHighlight: highlight(desc, terms),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].ID < results[j].ID
+	})
+	return paginate(results, query.PageSize, query.Page), int64(len(results)), nil
+}
+
+// countMatches returns number of occurrences of every term among words of text
+func countMatches(text string, terms []string) map[string]int {
+	hits := map[string]int{}
+	for _, word := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		for _, term := range terms {
+			if word == term {
+				hits[term]++
+			}
+		}
+	}
+	return hits
+}
+
+func containsAll(nameHits, descHits map[string]int, terms []string) bool {
+	for _, term := range terms {
+		if nameHits[term]+descHits[term] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func sum(hits map[string]int) int {
+	total := 0
+	for _, n := range hits {
+		total += n
+	}
+	return total
+}
+
+// highlight HTML-escapes text and wraps its words matching any of the terms in <mark> tags
+func highlight(text string, terms []string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range wordPattern.FindAllStringIndex(text, -1) {
+		word := text[loc[0]:loc[1]]
+		if !containsTerm(terms, strings.ToLower(word)) {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[last:loc[0]]))
+		b.WriteString(highlightStart + html.EscapeString(word) + highlightStop)
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+func containsTerm(terms []string, word string) bool {
+	for _, term := range terms {
+		if word == term {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeHTMLSQL returns SQL expression escaping text in column the same way as html.EscapeString does
+func escapeHTMLSQL(column string) string {
+	expr := column
+	for _, r := range [][2]string{{"&", "&amp;"}, {"'", "&#39;"}, {"<", "&lt;"}, {">", "&gt;"}, {`"`, "&#34;"}} {
+		expr = fmt.Sprintf("replace(%s, '%s', '%s')", expr, strings.ReplaceAll(r[0], "'", "''"), r[1])
+	}
+	return expr
+}
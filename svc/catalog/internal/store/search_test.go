@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestSearchItems(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}))
+	require.NoError(t, err)
+	store := &CatalogStore{db: db}
+
+	mock.ExpectQuery(`SELECT items\.\*, ts_rank\(search, query\) AS rank, .* AS total_count `+
+		`FROM items, websearch_to_tsquery\('english', \$1\) query WHERE search @@ query AND deleted_at IS NULL `+
+		`ORDER BY rank DESC, id LIMIT \$2 OFFSET \$3`).WithArgs("wool sweater", 10, 10).WillReturnRows(sqlmock.
+		NewRows([]string{"id", "name", "description", "price_minor", "price_code", "search", "rank", "name_highlight",
+			"description_highlight", "total_count"}).
+		AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode, "'sweater':2A 'wool':1A", 0.6,
+			"<mark>some</mark> name", "some desc", 11))
+
+	results, total, err := store.SearchItems(context.Background(), SearchQuery{Text: "wool sweater", PageSize: 10, Page: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), total)
+	require.Len(t, results, 1)
+	assert.Equal(t, uint(itemID), results[0].ID)
+	assert.Equal(t, itemName, *results[0].Name)
+	assert.Equal(t, 0.6, results[0].Rank)
+	assert.Equal(t, "<mark>some</mark> name", results[0].NameHighlight)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchItems_pastLastPage(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectQuery(`SELECT items\.\*, .* LIMIT \$2 OFFSET \$3`).WithArgs("sweater", 10, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "total_count"}))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM items, websearch_to_tsquery\('english', \$1\) query ` +
+		`WHERE search @@ query AND deleted_at IS NULL`).WithArgs("sweater").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+	results, total, err := store.SearchItems(context.Background(), SearchQuery{Text: "sweater", PageSize: 10, Page: 3})
+
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Equal(t, int64(11), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchItems_inputValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       SearchQuery
+		expectedErr error
+	}{
+		{
+			name:        "Invalid page",
+			query:       SearchQuery{Text: "sweater", PageSize: 10},
+			expectedErr: ErrInvalidPageParams,
+		},
+		{
+			name:        "No words in query",
+			query:       SearchQuery{Text: `"" -`, PageSize: 10, Page: 1},
+			expectedErr: ErrInvalidSearchQuery,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := (&CatalogStore{}).SearchItems(context.Background(), test.query)
+
+			assert.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	assert.Equal(t, "<mark>Wool</mark> sweater, 100% <mark>wool</mark>", highlight("Wool sweater, 100% wool", []string{"wool"}))
+	assert.Equal(t, "&lt;img src=x onerror=&#34;<mark>alert</mark>(1)&#34;&gt; &amp; <mark>amp</mark>",
+		highlight(`<img src=x onerror="alert(1)"> & amp`, []string{"alert", "amp"}))
+}
+
+func TestEscapeHTMLSQL(t *testing.T) {
+	assert.Equal(t, `replace(replace(replace(replace(replace(name, '&', '&amp;'), '''', '&#39;'), '<', '&lt;'), `+
+		`'>', '&gt;'), '"', '&#34;')`, escapeHTMLSQL("name"))
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	tracerName   = "github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	spanInstance = "otel:span"
+)
+
+// tracingPlugin is gorm plugin starting child span of the context span for every executed SQL statement.
+// Spans are created by tp or by the global tracer provider when tp is nil.
+type tracingPlugin struct {
+	tp trace.TracerProvider
+}
+
+func (tracingPlugin) Name() string {
+	return "otel"
+}
+
+func (p tracingPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("gorm:create").Register("otel:before_create", p.startSpan("create")),
+		cb.Create().After("gorm:create").Register("otel:after_create", endSpan),
+		cb.Query().Before("gorm:query").Register("otel:before_query", p.startSpan("query")),
+		cb.Query().After("gorm:query").Register("otel:after_query", endSpan),
+		cb.Update().Before("gorm:update").Register("otel:before_update", p.startSpan("update")),
+		cb.Update().After("gorm:update").Register("otel:after_update", endSpan),
+		cb.Delete().Before("gorm:delete").Register("otel:before_delete", p.startSpan("delete")),
+		cb.Delete().After("gorm:delete").Register("otel:after_delete", endSpan),
+		cb.Row().Before("gorm:row").Register("otel:before_row", p.startSpan("row")),
+		cb.Row().After("gorm:row").Register("otel:after_row", endSpan),
+		cb.Raw().Before("gorm:raw").Register("otel:before_raw", p.startSpan("raw")),
+		cb.Raw().After("gorm:raw").Register("otel:after_raw", endSpan),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p tracingPlugin) startSpan(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement == nil || db.Statement.Context == nil {
+			return
+		}
+		tp := p.tp
+		if tp == nil {
+			tp = otel.GetTracerProvider()
+		}
+		ctx, span := tp.Tracer(tracerName).Start(db.Statement.Context, "db."+operation, trace.WithSpanKind(trace.SpanKindClient))
+		span.SetAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationKey.String(operation))
+		db.Statement.Context = ctx
+		db.InstanceSet(spanInstance, span)
+	}
+}
+
+func endSpan(db *gorm.DB) {
+	val, ok := db.InstanceGet(spanInstance)
+	if !ok {
+		return
+	}
+	span, ok := val.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBStatementKey.String(db.Statement.SQL.String()),
+		attribute.Key("db.sql.table").String(db.Statement.Table),
+		attribute.Key("db.rows_affected").Int64(db.RowsAffected),
+	)
+	if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestTracingPlugin(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockErr        error
+		expectedStatus codes.Code
+	}{
+		{
+			name:           "Successful - child span recorded",
+			expectedStatus: codes.Unset,
+		},
+		{
+			name:           "Unsuccessful - error recorded on span",
+			mockErr:        errors.New("some err"),
+			expectedStatus: codes.Error,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}))
+			require.NoError(t, err)
+			require.NoError(t, db.Use(tracingPlugin{tp: tp}))
+			store := &CatalogStore{db: db}
+
+			if test.mockErr != nil {
+				mock.ExpectQuery(getItem).WithArgs(itemID).WillReturnError(test.mockErr)
+			} else {
+				mock.ExpectQuery(getItem).WithArgs(itemID).WillReturnRows(sqlmock.
+					NewRows([]string{"id", "name", "description", "price_minor", "price_code"}).
+					AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode))
+			}
+
+			ctx, parent := tp.Tracer("test").Start(context.Background(), "handler")
+			_, _ = store.GetItem(ctx, itemID)
+			parent.End()
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 2)
+			dbSpan := spans[0]
+			assert.Equal(t, "db.query", dbSpan.Name())
+			assert.Equal(t, parent.SpanContext().SpanID(), dbSpan.Parent().SpanID())
+			assert.Contains(t, dbSpan.Attributes(), semconv.DBSystemPostgreSQL)
+			assert.Equal(t, test.expectedStatus, dbSpan.Status().Code)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}))
+	require.NoError(t, err)
+	store := &CatalogStore{db: db, queryTimeout: 10 * time.Millisecond}
+
+	mock.ExpectQuery(getItem).WithArgs(itemID).WillDelayFor(time.Second).WillReturnRows(sqlmock.
+		NewRows([]string{"id", "name", "description", "price_minor", "price_code"}).
+		AddRow(itemID, itemName, itemDesc, itemPrice, itemPriceCode))
+
+	_, err = store.GetItem(context.Background(), itemID)
+
+	assert.ErrorContains(t, err, "canceling query")
+}
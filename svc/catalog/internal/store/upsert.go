@@ -0,0 +1,161 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+)
+
+// UpsertOutcome tells what upsert did with the item
+type UpsertOutcome string
+
+const (
+	UpsertCreated   UpsertOutcome = "created"
+	UpsertUpdated   UpsertOutcome = "updated"
+	UpsertUnchanged UpsertOutcome = "unchanged"
+)
+
+// upsertByExternalID updates columns of live item with the same external ID instead of creating a new one. Items
+// already in the desired state are left untouched, so their version is not incremented and no change is recorded.
+func upsertByExternalID(columns ...string) clause.OnConflict {
+	current := make([]string, 0, len(columns))
+	excluded := make([]string, 0, len(columns))
+	for _, column := range columns {
+		current = append(current, `"items"."`+column+`"`)
+		excluded = append(excluded, `"excluded"."`+column+`"`)
+	}
+	return clause.OnConflict{
+		Columns:     []clause.Column{{Name: "external_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates: append(clause.AssignmentColumns(columns),
+			clause.Assignment{Column: clause.Column{Name: "version"}, Value: gorm.Expr(`"items"."version" + 1`)}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{
+			SQL: "(" + strings.Join(current, ",") + ") IS DISTINCT FROM (" + strings.Join(excluded, ",") + ")",
+		}}},
+	}
+}
+
+// validateUpsert checks if item has external ID it is upserted by and all fields required to be persisted.
+// Description is optional, as description of the existing item is kept when it is not provided.
+func (i Item) validateUpsert() error {
+	if i.ExternalID == nil {
+		return fmt.Errorf("%w: externalId is required", ErrInvalidItem)
+	}
+	if i.Description == nil {
+		i.Description = new(string)
+	}
+	return i.validateNew()
+}
+
+// UpsertItemByExternalID creates item or replaces fields of the live item with the same external ID. Description of
+// the live item is kept when item has none, created item gets an empty one. It returns new state of the item and
+// whether it was created, updated or left unchanged.
+func (s *CatalogStore) UpsertItemByExternalID(ctx context.Context, item Item) (Item, UpsertOutcome, error) {
+	if err := item.validateUpsert(); err != nil {
+		return Item{}, "", err
+	}
+	upsert := upsertByExternalID("name", "description", "price_minor", "price_code")
+	if item.Description == nil {
+		upsert = upsertByExternalID("name", "price_minor", "price_code")
+		item.Description = new(string)
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var outcome UpsertOutcome
+	err := db.Transaction(func(tx *gorm.DB) error {
+		item.ID = 0
+		item.Version = 1
+		item.DeletedAt = gorm.DeletedAt{}
+		resp := tx.Clauses(upsert, clause.Returning{}).Create(&item)
+		if err := resp.Error; err != nil {
+			return err
+		}
+		if resp.RowsAffected == 0 {
+			outcome = UpsertUnchanged
+			return tx.Where("external_id = ?", *item.ExternalID).Take(&item).Error
+		}
+		operation := HistoryCreated
+		outcome = UpsertCreated
+		if item.Version > 1 {
+			outcome, operation = UpsertUpdated, HistoryUpdated
+		}
+		return recordChange(tx, item, operation)
+	})
+	if err != nil {
+		return Item{}, "", fmt.Errorf("error while upserting item with external id %s: %w", *item.ExternalID, err)
+	}
+	return item, outcome, nil
+}
+
+// GetItemByExternalID returns live item with provided external ID from db
+func (s *CatalogStore) GetItemByExternalID(ctx context.Context, externalID string) (Item, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var item Item
+	if err := db.Where("external_id = ?", externalID).Take(&item).Error; err != nil {
+		return Item{}, fmt.Errorf("error while getting item with external id %s: %w", externalID, err)
+	}
+	return item, nil
+}
+
+// UpsertItemByExternalID creates item or replaces fields of the live item with the same external ID. Description of
+// the live item is kept when item has none, created item gets an empty one. It returns new state of the item and
+// whether it was created, updated or left unchanged.
+func (s *MemoryStore) UpsertItemByExternalID(ctx context.Context, item Item) (Item, UpsertOutcome, error) {
+	if err := item.validateUpsert(); err != nil {
+		return Item{}, "", err
+	}
+	if err := ctx.Err(); err != nil {
+		return Item{}, "", fmt.Errorf("error while upserting item with external id %s: %w", *item.ExternalID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.liveByExternalID(*item.ExternalID)
+	if item.Description == nil {
+		item.Description = new(string)
+		if ok {
+			item.Description = clonePtr(stored.Description)
+		}
+	}
+	if !ok {
+		created, err := s.createItem(ctx, item)
+		return created, UpsertCreated, err
+	}
+	if sameFields(stored, item) {
+		return cloneItem(stored), UpsertUnchanged, nil
+	}
+	updated, err := s.updateItem(ctx, stored.ID, item, 0)
+	return updated, UpsertUpdated, err
+}
+
+// GetItemByExternalID returns live item with provided external ID from memory
+func (s *MemoryStore) GetItemByExternalID(ctx context.Context, externalID string) (Item, error) {
+	if err := ctx.Err(); err != nil {
+		return Item{}, fmt.Errorf("error while getting item with external id %s: %w", externalID, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	item, ok := s.liveByExternalID(externalID)
+	if !ok {
+		return Item{}, fmt.Errorf("error while getting item with external id %s: %w", externalID,
+			gorm.ErrRecordNotFound)
+	}
+	return cloneItem(item), nil
+}
+
+// sameFields reports if upserted fields of items are equal
+func sameFields(a, b Item) bool {
+	return equalPtr(a.Name, b.Name) && equalPtr(a.Description, b.Description) && equalPtr(a.Price, b.Price) &&
+		equalPtr(a.PriceCode, b.PriceCode)
+}
+
+func equalPtr[V comparable](a, b *V) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
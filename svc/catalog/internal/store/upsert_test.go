@@ -0,0 +1,144 @@
+package store
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+)
+
+// upsertItem is insert of the item updating live one with the same external ID when its fields differ
+const upsertItem = `INSERT INTO "items" \("external_id","name","description","price_minor","price_code","version",` +
+	`"deleted_at"\) VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7\) ON CONFLICT \("external_id"\)  WHERE deleted_at IS NULL ` +
+	`DO UPDATE SET "name"="excluded"\."name","description"="excluded"\."description",` +
+	`"price_minor"="excluded"\."price_minor","price_code"="excluded"\."price_code","version"="items"\."version" \+ 1 ` +
+	`WHERE \("items"\."name","items"\."description","items"\."price_minor","items"\."price_code"\) IS DISTINCT FROM ` +
+	`\("excluded"\."name","excluded"\."description","excluded"\."price_minor","excluded"\."price_code"\) RETURNING \*`
+
+func TestUpsertItemByExternalID(t *testing.T) {
+	externalID := "SUP-1"
+	columns := []string{"id", "external_id", "name", "description", "price_minor", "price_code", "version"}
+	tests := []struct {
+		name            string
+		returnedVersion uint
+		expectedOutcome UpsertOutcome
+	}{
+		{name: "Created", returnedVersion: 1, expectedOutcome: UpsertCreated},
+		{name: "Updated", returnedVersion: 3, expectedOutcome: UpsertUpdated},
+		{name: "Unchanged", expectedOutcome: UpsertUnchanged},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectBegin()
+			upsert := mock.ExpectQuery(upsertItem).
+				WithArgs(externalID, itemName, itemDesc, itemPrice, itemPriceCode, itemVersion, nil)
+			if test.expectedOutcome == UpsertUnchanged {
+				upsert.WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectQuery(`SELECT \* FROM "items" WHERE external_id = \$1 AND "items"\."deleted_at" IS NULL LIMIT 1`).
+					WithArgs(externalID).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(itemID, externalID, itemName, itemDesc, itemPrice, itemPriceCode, 2))
+			} else {
+				upsert.WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(itemID, externalID, itemName, itemDesc, itemPrice, itemPriceCode, test.returnedVersion))
+				operation := HistoryCreated
+				if test.expectedOutcome == UpsertUpdated {
+					operation = HistoryUpdated
+				}
+				expectRecordChange(mock, operation, test.returnedVersion, "system", `{"externalId":"SUP-1",`+
+					`"name":"some name","description":"some desc","price":5000,"priceCode":"EUR"}`)
+			}
+			mock.ExpectCommit()
+
+			item, outcome, err := store.UpsertItemByExternalID(context.Background(), Item{
+				ExternalID: &externalID, Name: &itemName, Description: &itemDesc, Price: &itemPrice,
+				PriceCode: &itemPriceCode,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedOutcome, outcome)
+			assert.Equal(t, itemID, item.ID)
+			assert.Equal(t, externalID, *item.ExternalID)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpsertItemByExternalID_keepsDescription(t *testing.T) {
+	externalID := "SUP-1"
+	columns := []string{"id", "external_id", "name", "description", "price_minor", "price_code", "version"}
+	store, mock := newMockStore(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "items" .* ON CONFLICT \("external_id"\)  WHERE deleted_at IS NULL `+
+		`DO UPDATE SET "name"="excluded"\."name","price_minor"="excluded"\."price_minor",`+
+		`"price_code"="excluded"\."price_code","version"="items"\."version" \+ 1 `+
+		`WHERE \("items"\."name","items"\."price_minor","items"\."price_code"\) IS DISTINCT FROM `+
+		`\("excluded"\."name","excluded"\."price_minor","excluded"\."price_code"\) RETURNING \*`).
+		WithArgs(externalID, itemName, "", itemPrice, itemPriceCode, itemVersion, nil).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(itemID, externalID, itemName, itemDesc, itemPrice, itemPriceCode, 2))
+	expectRecordChange(mock, HistoryUpdated, 2, "system", `{"externalId":"SUP-1",`+
+		`"name":"some name","description":"some desc","price":5000,"priceCode":"EUR"}`)
+	mock.ExpectCommit()
+
+	item, outcome, err := store.UpsertItemByExternalID(context.Background(), Item{
+		ExternalID: &externalID, Name: &itemName, Price: &itemPrice, PriceCode: &itemPriceCode,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, UpsertUpdated, outcome)
+	assert.Equal(t, itemDesc, *item.Description)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertItemByExternalID_invalid(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	_, _, err := store.UpsertItemByExternalID(context.Background(), Item{
+		Name: &itemName, Description: &itemDesc, Price: &itemPrice, PriceCode: &itemPriceCode,
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidItem)
+	assert.ErrorContains(t, err, "externalId is required")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetItemByExternalID(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        *sqlmock.Rows
+		expectedErr error
+	}{
+		{
+			name: "Successful - live item is found",
+			rows: sqlmock.NewRows([]string{"id", "external_id", "name"}).AddRow(itemID, "SUP-1", itemName),
+		},
+		{
+			name:        "Failure - item not found",
+			rows:        sqlmock.NewRows([]string{"id"}),
+			expectedErr: gorm.ErrRecordNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectQuery(`SELECT \* FROM "items" WHERE external_id = \$1 AND "items"\."deleted_at" IS NULL LIMIT 1`).
+				WithArgs("SUP-1").
+				WillReturnRows(test.rows)
+
+			item, err := store.GetItemByExternalID(context.Background(), "SUP-1")
+
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, itemID, item.ID)
+				assert.Equal(t, "SUP-1", *item.ExternalID)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	maxOptionLength = 50
+	maxOptions      = 10
+)
+
+var (
+	ErrInvalidVariant  = errors.New("invalid variant")
+	ErrVariantConflict = errors.New("variant conflicts with existing one")
+)
+
+var (
+	skuPattern     = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+	barcodePattern = regexp.MustCompile(`^(\d{8}|\d{12,14})$`)
+)
+
+// VariantOptions maps option names to values distinguishing variant of the item, e.g. size=M, colour=red
+type VariantOptions map[string]string
+
+// Value stores options as jsonb, which compares equal regardless of the order of keys
+func (o VariantOptions) Value() (driver.Value, error) {
+	if o == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(o)
+	return string(b), err
+}
+
+// Scan reads options stored as jsonb
+func (o *VariantOptions) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, o)
+	case string:
+		return json.Unmarshal([]byte(v), o)
+	case nil:
+		*o = nil
+		return nil
+	}
+	return fmt.Errorf("unsupported type of variant options: %T", src)
+}
+
+// equal reports if both sets contain the same options
+func (o VariantOptions) equal(other VariantOptions) bool {
+	if len(o) != len(other) {
+		return false
+	}
+	for name, value := range o {
+		if v, ok := other[name]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Variant represents sellable SKU of the item in underlying db
+type Variant struct {
+	ID      uint
+	ItemID  uint
+	SKU     string `gorm:"column:sku"`
+	Options VariantOptions
+	// Price overrides price of the item when set. It is expressed in minor units of PriceCode currency.
+	Price     *int64 `gorm:"column:price_minor"`
+	PriceCode *string
+	// Barcode is GTIN-8, GTIN-12 (UPC), GTIN-13 (EAN) or GTIN-14 number
+	Barcode *string
+}
+
+// validate checks if variant has valid SKU code, options, price and barcode
+func (v Variant) validate() error {
+	if !skuPattern.MatchString(v.SKU) {
+		return fmt.Errorf("%w: sku should be 1 to 64 letters, digits, '.', '_' or '-'", ErrInvalidVariant)
+	}
+	if len(v.Options) > maxOptions {
+		return fmt.Errorf("%w: variant can have at most %d options", ErrInvalidVariant, maxOptions)
+	}
+	for name, value := range v.Options {
+		if name == "" || value == "" ||
+			utf8.RuneCountInString(name) > maxOptionLength || utf8.RuneCountInString(value) > maxOptionLength {
+			return fmt.Errorf("%w: option names and values should be between 1 and %d characters long",
+				ErrInvalidVariant, maxOptionLength)
+		}
+	}
+	if (v.Price == nil) != (v.PriceCode == nil) {
+		return fmt.Errorf("%w: price and priceCode have to be set together", ErrInvalidVariant)
+	}
+	if v.Price != nil && *v.Price < 0 {
+		return fmt.Errorf("%w: price cannot be negative", ErrInvalidVariant)
+	}
+	if v.PriceCode != nil && utf8.RuneCountInString(*v.PriceCode) > maxPriceCodeLength {
+		return fmt.Errorf("%w: priceCode should be at most %d characters long", ErrInvalidVariant, maxPriceCodeLength)
+	}
+	if v.Barcode != nil && !validBarcode(*v.Barcode) {
+		return fmt.Errorf("%w: barcode should be valid GTIN-8, GTIN-12, GTIN-13 or GTIN-14", ErrInvalidVariant)
+	}
+	return nil
+}
+
+// validBarcode checks length and check digit of GTIN
+func validBarcode(barcode string) bool {
+	if !barcodePattern.MatchString(barcode) {
+		return false
+	}
+	sum := 0
+	for i := len(barcode) - 2; i >= 0; i-- {
+		digit := int(barcode[i] - '0')
+		if (len(barcode)-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return (10-sum%10)%10 == int(barcode[len(barcode)-1]-'0')
+}
+
+// CreateVariant adds variant to the item with ID and returns it with assigned ID. SKU codes are unique across
+// the catalog, option combinations are unique per item, otherwise ErrVariantConflict is returned.
+func (s *CatalogStore) CreateVariant(ctx context.Context, itemID uint, variant Variant) (Variant, error) {
+	if err := variant.validate(); err != nil {
+		return Variant{}, err
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	variant.ID = 0
+	variant.ItemID = itemID
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := reviseItem(tx, itemID, PartVariants); err != nil {
+			return err
+		}
+		return tx.Create(&variant).Error
+	})
+	if err != nil {
+		return Variant{}, fmt.Errorf("error while adding variant to item with id %d: %w", itemID, variantErr(err))
+	}
+	return variant, nil
+}
+
+// GetVariants returns variants of the item with ID ordered by ID
+func (s *CatalogStore) GetVariants(ctx context.Context, itemID uint) ([]Variant, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	variants := []Variant{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&Item{}, itemID).Error; err != nil {
+			return err
+		}
+		return tx.Where("item_id = ?", itemID).Order("id").Find(&variants).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while getting variants of item with id %d: %w", itemID, err)
+	}
+	return variants, nil
+}
+
+// GetVariant returns variant with ID of the item with provided ID
+func (s *CatalogStore) GetVariant(ctx context.Context, itemID, id uint) (variant Variant, err error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err = db.Select("variants.*").Joins("JOIN items ON items.id = variants.item_id AND items.deleted_at IS NULL").
+		Where("variants.item_id = ?", itemID).First(&variant, id).Error
+	return
+}
+
+// UpdateVariant replaces variant with ID of the item with provided ID and returns its new state
+func (s *CatalogStore) UpdateVariant(ctx context.Context, itemID, id uint, variant Variant) (Variant, error) {
+	if err := variant.validate(); err != nil {
+		return Variant{}, err
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	variant.ID = id
+	variant.ItemID = itemID
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := reviseItem(tx, itemID, PartVariants); err != nil {
+			return err
+		}
+		resp := tx.Model(&Variant{}).Where("id = ? AND item_id = ?", id, itemID).
+			Select("sku", "options", "price_minor", "price_code", "barcode").Updates(&variant)
+		if resp.Error == nil && resp.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return resp.Error
+	})
+	if err != nil {
+		return Variant{}, fmt.Errorf("error while updating variant with id %d: %w", id, variantErr(err))
+	}
+	return variant, nil
+}
+
+// DeleteVariant removes variant with ID of the item with provided ID together with its stock
+func (s *CatalogStore) DeleteVariant(ctx context.Context, itemID, id uint) error {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := reviseItem(tx, itemID, PartVariants); err != nil {
+			return err
+		}
+		resp := tx.Where("item_id = ?", itemID).Delete(&Variant{}, id)
+		if resp.Error == nil && resp.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		return tx.Where("item_id = ? AND variant_id = ?", itemID, id).Delete(&Stock{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("error while deleting variant with id %d: %w", id, err)
+	}
+	return nil
+}
+
+// variantErr translates violation of unique constraints into ErrVariantConflict
+func variantErr(err error) error {
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: sku or options are already used", ErrVariantConflict)
+	}
+	return err
+}
+
+// CreateVariant adds variant to the item with ID and returns it with assigned ID. SKU codes are unique across
+// the catalog, option combinations are unique per item, otherwise ErrVariantConflict is returned.
+func (s *MemoryStore) CreateVariant(ctx context.Context, itemID uint, variant Variant) (Variant, error) {
+	if err := variant.validate(); err != nil {
+		return Variant{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return Variant{}, fmt.Errorf("error while adding variant to item with id %d: %w", itemID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	variant.ItemID = itemID
+	if err := s.checkVariant(variant); err != nil {
+		return Variant{}, fmt.Errorf("error while adding variant to item with id %d: %w", itemID, err)
+	}
+	s.lastVariantID++
+	variant.ID = s.lastVariantID
+	s.variants[variant.ID] = cloneVariant(variant)
+	s.reviseItem(ctx, itemID, PartVariants)
+	return cloneVariant(variant), nil
+}
+
+// GetVariants returns variants of the item with ID ordered by ID
+func (s *MemoryStore) GetVariants(ctx context.Context, itemID uint) ([]Variant, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while getting variants of item with id %d: %w", itemID, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.live(itemID); !ok {
+		return nil, fmt.Errorf("error while getting variants of item with id %d: %w", itemID, gorm.ErrRecordNotFound)
+	}
+	variants := []Variant{}
+	for _, variant := range s.variants {
+		if variant.ItemID == itemID {
+			variants = append(variants, cloneVariant(variant))
+		}
+	}
+	sort.Slice(variants, func(i, j int) bool { return variants[i].ID < variants[j].ID })
+	return variants, nil
+}
+
+// GetVariant returns variant with ID of the item with provided ID
+func (s *MemoryStore) GetVariant(ctx context.Context, itemID, id uint) (Variant, error) {
+	if err := ctx.Err(); err != nil {
+		return Variant{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	variant, ok := s.variants[id]
+	if _, live := s.live(itemID); !ok || !live || variant.ItemID != itemID {
+		return Variant{}, gorm.ErrRecordNotFound
+	}
+	return cloneVariant(variant), nil
+}
+
+// UpdateVariant replaces variant with ID of the item with provided ID and returns its new state
+func (s *MemoryStore) UpdateVariant(ctx context.Context, itemID, id uint, variant Variant) (Variant, error) {
+	if err := variant.validate(); err != nil {
+		return Variant{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return Variant{}, fmt.Errorf("error while updating variant with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stored, ok := s.variants[id]; !ok || stored.ItemID != itemID {
+		return Variant{}, fmt.Errorf("error while updating variant with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	variant.ID = id
+	variant.ItemID = itemID
+	if err := s.checkVariant(variant); err != nil {
+		return Variant{}, fmt.Errorf("error while updating variant with id %d: %w", id, err)
+	}
+	s.variants[id] = cloneVariant(variant)
+	s.reviseItem(ctx, itemID, PartVariants)
+	return cloneVariant(variant), nil
+}
+
+// DeleteVariant removes variant with ID of the item with provided ID together with its stock
+func (s *MemoryStore) DeleteVariant(ctx context.Context, itemID, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error while deleting variant with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	variant, ok := s.variants[id]
+	if _, live := s.live(itemID); !ok || !live || variant.ItemID != itemID {
+		return fmt.Errorf("error while deleting variant with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	delete(s.variants, id)
+	s.deleteStock(func(key StockKey) bool { return key == StockKey{ItemID: itemID, VariantID: id} })
+	s.reviseItem(ctx, itemID, PartVariants)
+	return nil
+}
+
+// checkVariant verifies that item of the variant exists and no other variant uses the same SKU or the same options
+// within the item. Caller has to hold the lock.
+func (s *MemoryStore) checkVariant(variant Variant) error {
+	if _, ok := s.live(variant.ItemID); !ok {
+		return gorm.ErrRecordNotFound
+	}
+	for _, other := range s.variants {
+		if other.ID == variant.ID {
+			continue
+		}
+		if other.SKU == variant.SKU || (other.ItemID == variant.ItemID && other.Options.equal(variant.Options)) {
+			return fmt.Errorf("%w: sku or options are already used", ErrVariantConflict)
+		}
+	}
+	return nil
+}
+
+func cloneVariant(variant Variant) Variant {
+	clone := variant
+	clone.Price = clonePtr(variant.Price)
+	clone.PriceCode = clonePtr(variant.PriceCode)
+	clone.Barcode = clonePtr(variant.Barcode)
+	clone.Options = make(VariantOptions, len(variant.Options))
+	for name, value := range variant.Options {
+		clone.Options[name] = value
+	}
+	return clone
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestValidBarcode(t *testing.T) {
+	tests := []struct {
+		barcode  string
+		expected bool
+	}{
+		{barcode: "96385074", expected: true},
+		{barcode: "036000291452", expected: true},
+		{barcode: "4006381333931", expected: true},
+		{barcode: "14006381333938", expected: true},
+		{barcode: "4006381333932"},
+		{barcode: "400638133393"},
+		{barcode: "40063813339a1"},
+		{barcode: ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, validBarcode(test.barcode), test.barcode)
+	}
+}
+
+func TestCreateVariant(t *testing.T) {
+	tests := []struct {
+		name        string
+		insertErr   error
+		expectedErr error
+	}{
+		{
+			name: "Successful - variant created",
+		},
+		{
+			name:        "Conflict - unique constraint violated",
+			insertErr:   &pgconn.PgError{Code: uniqueViolation},
+			expectedErr: ErrVariantConflict,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectBegin()
+			expectReviseItem(mock, PartVariants)
+			insert := mock.ExpectQuery(`INSERT INTO "variants" \("item_id","sku","options","price_minor","price_code","barcode"\) `+
+				`VALUES \(\$1,\$2,\$3,\$4,\$5,\$6\) RETURNING "id"`).
+				WithArgs(1, "SHIRT-M", `{"size":"M"}`, nil, nil, nil)
+			if test.insertErr != nil {
+				insert.WillReturnError(test.insertErr)
+				mock.ExpectRollback()
+			} else {
+				insert.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectCommit()
+			}
+
+			variant, err := store.CreateVariant(context.Background(), 1, Variant{SKU: "SHIRT-M", Options: VariantOptions{"size": "M"}})
+
+			assert.ErrorIs(t, err, test.expectedErr)
+			if test.expectedErr == nil {
+				assert.Equal(t, uint(3), variant.ID)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateVariant(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectBegin()
+	expectReviseItem(mock, PartVariants)
+	mock.ExpectExec(`UPDATE "variants" SET "sku"=\$1,"options"=\$2,"price_minor"=\$3,"price_code"=\$4,"barcode"=\$5 WHERE id = \$6 AND item_id = \$7`).
+		WithArgs("SHIRT-L", `{"size":"L"}`, nil, nil, nil, 3, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err := store.UpdateVariant(context.Background(), 1, 3, Variant{SKU: "SHIRT-L", Options: VariantOptions{"size": "L"}})
+
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetVariant(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectQuery(`SELECT variants\.\* FROM "variants" JOIN items ON items\.id = variants\.item_id AND items\.deleted_at IS NULL `+
+		`WHERE variants\.item_id = \$1 AND "variants"\."id" = \$2 ORDER BY "variants"\."id" LIMIT 1`).WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "item_id", "sku", "options", "price_minor", "price_code", "barcode"}).
+			AddRow(3, 1, "SHIRT-M", []byte(`{"size": "M"}`), 1250, "EUR", nil))
+
+	variant, err := store.GetVariant(context.Background(), 1, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Variant{ID: 3, ItemID: 1, SKU: "SHIRT-M", Options: VariantOptions{"size": "M"},
+		Price: v2p(int64(1250)), PriceCode: v2p("EUR")}, variant)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
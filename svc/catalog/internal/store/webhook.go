@@ -0,0 +1,627 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxWebhookURLLength = 2048
+	minSecretLength     = 16
+	maxSecretLength     = 255
+)
+
+var (
+	ErrInvalidWebhook  = errors.New("invalid webhook")
+	ErrDeliveryPending = errors.New("delivery is still pending")
+)
+
+// nonPublicNetworks are IPv4 networks which are not publicly routable besides private, loopback and link-local ones
+var nonPublicNetworks = []*net.IPNet{
+	{IP: net.IPv4(0, 0, 0, 0), Mask: net.CIDRMask(8, 32)},
+	{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)},
+}
+
+// DeliveryStatus describes lifecycle of the webhook delivery. Only pending deliveries are attempted, dead ones
+// exhausted all attempts and wait for manual redelivery.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryDead      DeliveryStatus = "dead"
+)
+
+// EventTypes is a set of event types stored as jsonb
+type EventTypes []EventType
+
+// Value stores event types as jsonb
+func (t EventTypes) Value() (driver.Value, error) {
+	b, err := json.Marshal(t)
+	return string(b), err
+}
+
+// Scan reads event types stored as jsonb
+func (t *EventTypes) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, t)
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	}
+	return fmt.Errorf("unsupported type of event types: %T", src)
+}
+
+// contains reports if eventType is one of the types
+func (t EventTypes) contains(eventType EventType) bool {
+	for _, subscribed := range t {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook is a subscription of a partner to events of provided types, which are POSTed to URL and signed with Secret
+type Webhook struct {
+	ID         uint
+	URL        string
+	EventTypes EventTypes
+	Secret     string
+	CreatedAt  time.Time
+}
+
+// validate checks if webhook has absolute http(s) URL, known event types and long enough secret
+func (w Webhook) validate() error {
+	u, err := url.Parse(w.URL)
+	switch {
+	case err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+		return fmt.Errorf("%w: url should be an absolute http or https URL", ErrInvalidWebhook)
+	case privateHost(u.Hostname()):
+		return fmt.Errorf("%w: url cannot point at loopback, link-local or private address", ErrInvalidWebhook)
+	case len(w.URL) > maxWebhookURLLength:
+		return fmt.Errorf("%w: url should be at most %d characters long", ErrInvalidWebhook, maxWebhookURLLength)
+	case len(w.EventTypes) == 0:
+		return fmt.Errorf("%w: at least one event type is required", ErrInvalidWebhook)
+	case utf8.RuneCountInString(w.Secret) < minSecretLength || utf8.RuneCountInString(w.Secret) > maxSecretLength:
+		return fmt.Errorf("%w: secret should be between %d and %d characters long", ErrInvalidWebhook,
+			minSecretLength, maxSecretLength)
+	}
+	for _, eventType := range w.EventTypes {
+		if !eventType.valid() {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidWebhook, eventType)
+		}
+	}
+	return nil
+}
+
+// PrivateIP reports if ip is loopback, private, link-local or otherwise not publicly routable. Webhooks cannot reach
+// such addresses, so they cannot be used to call internal services.
+func PrivateIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// privateHost reports if host is localhost or a private IP address. Other hosts are checked once they are resolved
+// on delivery.
+func privateHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && PrivateIP(ip)
+}
+
+// WebhookDelivery is a delivery of the event to the webhook together with outcome of its last attempt
+type WebhookDelivery struct {
+	ID        uint
+	WebhookID uint
+	EventID   uint64
+	EventType EventType
+	// Payload is the request body, exactly as it is signed
+	Payload  string
+	Status   DeliveryStatus
+	Attempts int
+	// NextAttemptAt is when pending delivery is attempted next
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	LastStatusCode *int
+	LastError      *string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// DeliveryAttempt is an outcome of a single attempt to deliver the event
+type DeliveryAttempt struct {
+	At time.Time
+	// StatusCode is not set when no response was received
+	StatusCode *int
+	Error      *string
+	Delivered  bool
+	// RetryAt is when failed delivery is attempted again, it is moved to dead letters when not set
+	RetryAt *time.Time
+}
+
+// DeliveryQuery selects page of deliveries, newest first. Unset fields don't narrow results down.
+type DeliveryQuery struct {
+	WebhookID *uint
+	Status    *DeliveryStatus
+	PageSize  int
+	Page      int
+}
+
+// scope narrows deliveries down to the ones matching the query
+func (q DeliveryQuery) scope(db *gorm.DB) *gorm.DB {
+	if q.WebhookID != nil {
+		db = db.Where("webhook_id = ?", *q.WebhookID)
+	}
+	if q.Status != nil {
+		db = db.Where("status = ?", *q.Status)
+	}
+	return db
+}
+
+// matches reports if delivery matches the query
+func (q DeliveryQuery) matches(delivery WebhookDelivery) bool {
+	return (q.WebhookID == nil || delivery.WebhookID == *q.WebhookID) &&
+		(q.Status == nil || delivery.Status == *q.Status)
+}
+
+// attemptUpdates returns column updates recording the attempt
+func attemptUpdates(attempt DeliveryAttempt) map[string]interface{} {
+	updates := map[string]interface{}{
+		"attempts":         gorm.Expr("attempts + 1"),
+		"last_attempt_at":  attempt.At,
+		"last_status_code": attempt.StatusCode,
+		"last_error":       attempt.Error,
+	}
+	switch {
+	case attempt.Delivered:
+		updates["status"] = DeliveryDelivered
+		updates["delivered_at"] = attempt.At
+	case attempt.RetryAt != nil:
+		updates["next_attempt_at"] = *attempt.RetryAt
+	default:
+		updates["status"] = DeliveryDead
+	}
+	return updates
+}
+
+// CreateWebhook subscribes webhook to events and returns it with assigned ID
+func (s *CatalogStore) CreateWebhook(ctx context.Context, webhook Webhook) (Webhook, error) {
+	if err := webhook.validate(); err != nil {
+		return Webhook{}, err
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	webhook.ID = 0
+	if err := db.Create(&webhook).Error; err != nil {
+		return Webhook{}, fmt.Errorf("error while creating webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// GetWebhooks returns all webhooks ordered by ID
+func (s *CatalogStore) GetWebhooks(ctx context.Context) (webhooks []Webhook, err error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	err = db.Order("id").Find(&webhooks).Error
+	return
+}
+
+// GetWebhook returns webhook with provided ID
+func (s *CatalogStore) GetWebhook(ctx context.Context, id uint) (Webhook, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var webhook Webhook
+	if err := db.First(&webhook, id).Error; err != nil {
+		return Webhook{}, fmt.Errorf("error while getting webhook with id %d: %w", id, err)
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook unsubscribes webhook with ID together with all its deliveries
+func (s *CatalogStore) DeleteWebhook(ctx context.Context, id uint) error {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	resp := db.Delete(&Webhook{}, id)
+	if resp.Error == nil && resp.RowsAffected == 0 {
+		resp.Error = gorm.ErrRecordNotFound
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("error while deleting webhook with id %d: %w", id, resp.Error)
+	}
+	return nil
+}
+
+// CreateDeliveries schedules delivery of the event with payload to every webhook subscribed to its type. Event is
+// delivered to a webhook only once, even when it is passed again. Number of scheduled deliveries is returned.
+func (s *CatalogStore) CreateDeliveries(ctx context.Context, event Event, payload string) (int64, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var webhooks []Webhook
+	if err := db.Order("id").Find(&webhooks).Error; err != nil {
+		return 0, fmt.Errorf("error while getting webhooks: %w", err)
+	}
+	deliveries := newDeliveries(webhooks, event, payload, time.Now())
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+	resp := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries)
+	if err := resp.Error; err != nil {
+		return 0, fmt.Errorf("error while scheduling deliveries of event with id %d: %w", event.ID, err)
+	}
+	return resp.RowsAffected, nil
+}
+
+// ClaimDueDeliveries returns up to limit pending deliveries due at provided time and postpones their next attempt
+// by lease, so they are not attempted concurrently. Deliveries are retried after the lease when the attempt is not
+// recorded in time.
+func (s *CatalogStore) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error) {
+	if limit < 1 {
+		return nil, ErrInvalidPageParams
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var deliveries []WebhookDelivery
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", DeliveryPending, now).
+			Order("next_attempt_at").Order("id").Limit(limit).Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+		ids := make([]uint, 0, len(deliveries))
+		for i := range deliveries {
+			deliveries[i].NextAttemptAt = now.Add(lease)
+			ids = append(ids, deliveries[i].ID)
+		}
+		return tx.Model(&WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while claiming due deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RecordDeliveryAttempt records outcome of the attempt to deliver pending delivery with ID and returns its new state
+func (s *CatalogStore) RecordDeliveryAttempt(ctx context.Context, id uint, attempt DeliveryAttempt) (WebhookDelivery, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var delivery WebhookDelivery
+	resp := db.Model(&delivery).Clauses(clause.Returning{}).Where("id = ? AND status = ?", id, DeliveryPending).
+		Updates(attemptUpdates(attempt))
+	if resp.Error == nil && resp.RowsAffected == 0 {
+		resp.Error = gorm.ErrRecordNotFound
+	}
+	if resp.Error != nil {
+		return WebhookDelivery{}, fmt.Errorf("error while recording attempt of delivery with id %d: %w", id, resp.Error)
+	}
+	return delivery, nil
+}
+
+// GetDeliveries returns requested page of deliveries matching the query, newest first
+func (s *CatalogStore) GetDeliveries(ctx context.Context, query DeliveryQuery) ([]WebhookDelivery, error) {
+	if query.Page < 1 || query.PageSize < 1 {
+		return nil, ErrInvalidPageParams
+	}
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	deliveries := []WebhookDelivery{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if query.WebhookID != nil {
+			if err := tx.Select("id").First(&Webhook{}, *query.WebhookID).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Scopes(query.scope).Order("id DESC").
+			Offset((query.Page - 1) * query.PageSize).Limit(query.PageSize).Find(&deliveries).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while getting deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RedeliverDelivery schedules delivery with ID of the webhook to be attempted again right away, with fresh attempts
+func (s *CatalogStore) RedeliverDelivery(ctx context.Context, webhookID, id uint) (WebhookDelivery, error) {
+	db, cancel := s.conn(ctx)
+	defer cancel()
+	var delivery WebhookDelivery
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("webhook_id = ?", webhookID).
+			First(&delivery, id).Error
+		if err != nil {
+			return err
+		}
+		if delivery.Status == DeliveryPending {
+			return ErrDeliveryPending
+		}
+		return tx.Model(&delivery).Clauses(clause.Returning{}).Updates(redeliveryUpdates(time.Now())).Error
+	})
+	if err != nil {
+		return WebhookDelivery{}, fmt.Errorf("error while redelivering delivery with id %d: %w", id, err)
+	}
+	return delivery, nil
+}
+
+// redeliveryUpdates returns column updates making delivery pending again with fresh attempts
+func redeliveryUpdates(now time.Time) map[string]interface{} {
+	return map[string]interface{}{"status": DeliveryPending, "attempts": 0, "next_attempt_at": now}
+}
+
+// newDeliveries returns pending deliveries of the event to webhooks subscribed to its type
+func newDeliveries(webhooks []Webhook, event Event, payload string, now time.Time) []WebhookDelivery {
+	var deliveries []WebhookDelivery
+	for _, webhook := range webhooks {
+		if !webhook.EventTypes.contains(event.Type) {
+			continue
+		}
+		deliveries = append(deliveries, WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	return deliveries
+}
+
+// CreateWebhook subscribes webhook to events and returns it with assigned ID
+func (s *MemoryStore) CreateWebhook(ctx context.Context, webhook Webhook) (Webhook, error) {
+	if err := webhook.validate(); err != nil {
+		return Webhook{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return Webhook{}, fmt.Errorf("error while creating webhook: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastWebhookID++
+	webhook.ID = s.lastWebhookID
+	webhook.CreatedAt = s.now()
+	webhook.EventTypes = append(EventTypes(nil), webhook.EventTypes...)
+	s.webhooks[webhook.ID] = webhook
+	return cloneWebhook(webhook), nil
+}
+
+// GetWebhooks returns all webhooks ordered by ID
+func (s *MemoryStore) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sortedWebhooks(), nil
+}
+
+// GetWebhook returns webhook with provided ID
+func (s *MemoryStore) GetWebhook(ctx context.Context, id uint) (Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return Webhook{}, fmt.Errorf("error while getting webhook with id %d: %w", id, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	webhook, ok := s.webhooks[id]
+	if !ok {
+		return Webhook{}, fmt.Errorf("error while getting webhook with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	return cloneWebhook(webhook), nil
+}
+
+// DeleteWebhook unsubscribes webhook with ID together with all its deliveries
+func (s *MemoryStore) DeleteWebhook(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error while deleting webhook with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.webhooks[id]; !ok {
+		return fmt.Errorf("error while deleting webhook with id %d: %w", id, gorm.ErrRecordNotFound)
+	}
+	delete(s.webhooks, id)
+	for deliveryID, delivery := range s.deliveries {
+		if delivery.WebhookID == id {
+			delete(s.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+// CreateDeliveries schedules delivery of the event with payload to every webhook subscribed to its type. Event is
+// delivered to a webhook only once, even when it is passed again. Number of scheduled deliveries is returned.
+func (s *MemoryStore) CreateDeliveries(ctx context.Context, event Event, payload string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("error while scheduling deliveries of event with id %d: %w", event.ID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var created int64
+	for _, delivery := range newDeliveries(s.sortedWebhooks(), event, payload, s.now()) {
+		if s.delivered(delivery.WebhookID, delivery.EventID) {
+			continue
+		}
+		s.lastDeliveryID++
+		delivery.ID = s.lastDeliveryID
+		s.deliveries[delivery.ID] = delivery
+		created++
+	}
+	return created, nil
+}
+
+// ClaimDueDeliveries returns up to limit pending deliveries due at provided time and postpones their next attempt
+// by lease, so they are not attempted concurrently
+func (s *MemoryStore) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error) {
+	if limit < 1 {
+		return nil, ErrInvalidPageParams
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while claiming due deliveries: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []WebhookDelivery
+	for _, delivery := range s.deliveries {
+		if delivery.Status == DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for i := range due {
+		due[i].NextAttemptAt = now.Add(lease)
+		s.deliveries[due[i].ID] = due[i]
+		due[i] = cloneDelivery(due[i])
+	}
+	return due, nil
+}
+
+// RecordDeliveryAttempt records outcome of the attempt to deliver pending delivery with ID and returns its new state
+func (s *MemoryStore) RecordDeliveryAttempt(ctx context.Context, id uint, attempt DeliveryAttempt) (WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return WebhookDelivery{}, fmt.Errorf("error while recording attempt of delivery with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delivery, ok := s.deliveries[id]
+	if !ok || delivery.Status != DeliveryPending {
+		return WebhookDelivery{}, fmt.Errorf("error while recording attempt of delivery with id %d: %w", id,
+			gorm.ErrRecordNotFound)
+	}
+	delivery.Attempts++
+	delivery.LastAttemptAt = &attempt.At
+	delivery.LastStatusCode = clonePtr(attempt.StatusCode)
+	delivery.LastError = clonePtr(attempt.Error)
+	switch {
+	case attempt.Delivered:
+		delivery.Status = DeliveryDelivered
+		delivery.DeliveredAt = &attempt.At
+	case attempt.RetryAt != nil:
+		delivery.NextAttemptAt = *attempt.RetryAt
+	default:
+		delivery.Status = DeliveryDead
+	}
+	s.deliveries[id] = delivery
+	return cloneDelivery(delivery), nil
+}
+
+// GetDeliveries returns requested page of deliveries matching the query, newest first
+func (s *MemoryStore) GetDeliveries(ctx context.Context, query DeliveryQuery) ([]WebhookDelivery, error) {
+	if query.Page < 1 || query.PageSize < 1 {
+		return nil, ErrInvalidPageParams
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error while getting deliveries: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if query.WebhookID != nil {
+		if _, ok := s.webhooks[*query.WebhookID]; !ok {
+			return nil, fmt.Errorf("error while getting deliveries: %w", gorm.ErrRecordNotFound)
+		}
+	}
+	deliveries := []WebhookDelivery{}
+	for _, delivery := range s.deliveries {
+		if query.matches(delivery) {
+			deliveries = append(deliveries, cloneDelivery(delivery))
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	return paginate(deliveries, query.PageSize, query.Page), nil
+}
+
+// RedeliverDelivery schedules delivery with ID of the webhook to be attempted again right away, with fresh attempts
+func (s *MemoryStore) RedeliverDelivery(ctx context.Context, webhookID, id uint) (WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return WebhookDelivery{}, fmt.Errorf("error while redelivering delivery with id %d: %w", id, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delivery, ok := s.deliveries[id]
+	if !ok || delivery.WebhookID != webhookID {
+		return WebhookDelivery{}, fmt.Errorf("error while redelivering delivery with id %d: %w", id,
+			gorm.ErrRecordNotFound)
+	}
+	if delivery.Status == DeliveryPending {
+		return WebhookDelivery{}, fmt.Errorf("error while redelivering delivery with id %d: %w", id, ErrDeliveryPending)
+	}
+	delivery.Status = DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = s.now()
+	s.deliveries[id] = delivery
+	return cloneDelivery(delivery), nil
+}
+
+// sortedWebhooks returns copies of all webhooks ordered by ID. Caller has to hold the lock.
+func (s *MemoryStore) sortedWebhooks() []Webhook {
+	webhooks := make([]Webhook, 0, len(s.webhooks))
+	for _, webhook := range s.webhooks {
+		webhooks = append(webhooks, cloneWebhook(webhook))
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks
+}
+
+// delivered reports if there is a delivery of the event to the webhook. Caller has to hold the lock.
+func (s *MemoryStore) delivered(webhookID uint, eventID uint64) bool {
+	for _, delivery := range s.deliveries {
+		if delivery.WebhookID == webhookID && delivery.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneWebhook(webhook Webhook) Webhook {
+	clone := webhook
+	clone.EventTypes = append(EventTypes(nil), webhook.EventTypes...)
+	return clone
+}
+
+func cloneDelivery(delivery WebhookDelivery) WebhookDelivery {
+	clone := delivery
+	clone.LastAttemptAt = clonePtr(delivery.LastAttemptAt)
+	clone.LastStatusCode = clonePtr(delivery.LastStatusCode)
+	clone.LastError = clonePtr(delivery.LastError)
+	clone.DeliveredAt = clonePtr(delivery.DeliveredAt)
+	return clone
+}
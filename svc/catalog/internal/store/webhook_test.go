@@ -0,0 +1,207 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+const webhookSecret = "0123456789abcdef"
+
+func TestCreateWebhook(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "webhooks" \("url","event_types","secret","created_at"\) VALUES \(\$1,\$2,\$3,\$4\) RETURNING "id"`).
+		WithArgs("https://example.com/hook", `["ItemCreated","ItemDeleted"]`, webhookSecret, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+
+	webhook, err := store.CreateWebhook(context.Background(), Webhook{
+		URL: "https://example.com/hook", EventTypes: EventTypes{ItemCreated, ItemDeleted}, Secret: webhookSecret,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), webhook.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateWebhook_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook Webhook
+	}{
+		{
+			name:    "Relative URL",
+			webhook: Webhook{URL: "/hook", EventTypes: EventTypes{ItemCreated}, Secret: webhookSecret},
+		},
+		{
+			name:    "Unsupported scheme",
+			webhook: Webhook{URL: "ftp://example.com", EventTypes: EventTypes{ItemCreated}, Secret: webhookSecret},
+		},
+		{
+			name:    "Localhost URL",
+			webhook: Webhook{URL: "http://localhost:8080/hook", EventTypes: EventTypes{ItemCreated}, Secret: webhookSecret},
+		},
+		{
+			name:    "Link-local URL",
+			webhook: Webhook{URL: "http://169.254.169.254/latest", EventTypes: EventTypes{ItemCreated}, Secret: webhookSecret},
+		},
+		{
+			name:    "Private URL",
+			webhook: Webhook{URL: "https://10.1.2.3/hook", EventTypes: EventTypes{ItemCreated}, Secret: webhookSecret},
+		},
+		{
+			name:    "Loopback IPv6 URL",
+			webhook: Webhook{URL: "http://[::1]:8080/hook", EventTypes: EventTypes{ItemCreated}, Secret: webhookSecret},
+		},
+		{
+			name: "Too long URL",
+			webhook: Webhook{URL: "https://example.com/" + strings.Repeat("a", maxWebhookURLLength),
+				EventTypes: EventTypes{ItemCreated}, Secret: webhookSecret},
+		},
+		{
+			name:    "No event types",
+			webhook: Webhook{URL: "https://example.com", Secret: webhookSecret},
+		},
+		{
+			name:    "Unknown event type",
+			webhook: Webhook{URL: "https://example.com", EventTypes: EventTypes{"PriceChanged"}, Secret: webhookSecret},
+		},
+		{
+			name:    "Too short secret",
+			webhook: Webhook{URL: "https://example.com", EventTypes: EventTypes{ItemCreated}, Secret: "secret"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+
+			_, err := store.CreateWebhook(context.Background(), test.webhook)
+
+			assert.ErrorIs(t, err, ErrInvalidWebhook)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPrivateIP(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.0.0.1", "172.16.5.4", "192.168.1.1", "169.254.169.254", "0.0.0.0",
+		"100.64.0.1", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1"} {
+		assert.True(t, PrivateIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"93.184.216.34", "8.8.8.8", "2606:4700::1111"} {
+		assert.False(t, PrivateIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestCreateDeliveries(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectQuery(`SELECT \* FROM "webhooks" ORDER BY id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "event_types", "secret", "created_at"}).
+			AddRow(1, "https://example.com/a", []byte(`["ItemCreated"]`), webhookSecret, time.Now()).
+			AddRow(2, "https://example.com/b", []byte(`["ItemDeleted"]`), webhookSecret, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "webhook_deliveries" \("webhook_id","event_id","event_type","payload","status",`+
+		`"attempts","next_attempt_at","last_attempt_at","last_status_code","last_error","created_at","delivered_at"\) `+
+		`VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7,\$8,\$9,\$10,\$11,\$12\) ON CONFLICT DO NOTHING RETURNING "id"`).
+		WithArgs(1, 7, ItemCreated, `{"id":7}`, DeliveryPending, 0, sqlmock.AnyArg(), nil, nil, nil, sqlmock.AnyArg(), nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectCommit()
+
+	created, err := store.CreateDeliveries(context.Background(), Event{ID: 7, Type: ItemCreated}, `{"id":7}`)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimDueDeliveries(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	store, mock := newMockStore(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "webhook_deliveries" WHERE status = \$1 AND next_attempt_at <= \$2 `+
+		`ORDER BY next_attempt_at,id LIMIT 10 FOR UPDATE SKIP LOCKED`).
+		WithArgs(DeliveryPending, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "event_id", "status", "next_attempt_at"}).
+			AddRow(3, 1, 7, "pending", now.Add(-time.Second)))
+	mock.ExpectExec(`UPDATE "webhook_deliveries" SET "next_attempt_at"=\$1 WHERE id IN \(\$2\)`).
+		WithArgs(now.Add(time.Minute), 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	deliveries, err := store.ClaimDueDeliveries(context.Background(), now, time.Minute, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, []WebhookDelivery{
+		{ID: 3, WebhookID: 1, EventID: 7, Status: DeliveryPending, NextAttemptAt: now.Add(time.Minute)},
+	}, deliveries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecordDeliveryAttempt(t *testing.T) {
+	at := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	retryAt := at.Add(time.Minute)
+	tests := []struct {
+		name    string
+		attempt DeliveryAttempt
+		query   string
+		args    []driver.Value
+	}{
+		{
+			name:    "Delivered",
+			attempt: DeliveryAttempt{At: at, StatusCode: v2p(200), Delivered: true},
+			query: `UPDATE "webhook_deliveries" SET "attempts"=attempts \+ 1,"delivered_at"=\$1,"last_attempt_at"=\$2,` +
+				`"last_error"=\$3,"last_status_code"=\$4,"status"=\$5 WHERE id = \$6 AND status = \$7 RETURNING \*`,
+			args: []driver.Value{at, at, nil, 200, DeliveryDelivered, 3, DeliveryPending},
+		},
+		{
+			name:    "Retried",
+			attempt: DeliveryAttempt{At: at, StatusCode: v2p(500), Error: v2p("server error"), RetryAt: &retryAt},
+			query: `UPDATE "webhook_deliveries" SET "attempts"=attempts \+ 1,"last_attempt_at"=\$1,"last_error"=\$2,` +
+				`"last_status_code"=\$3,"next_attempt_at"=\$4 WHERE id = \$5 AND status = \$6 RETURNING \*`,
+			args: []driver.Value{at, "server error", 500, retryAt, 3, DeliveryPending},
+		},
+		{
+			name:    "Dead",
+			attempt: DeliveryAttempt{At: at, Error: v2p("timeout")},
+			query: `UPDATE "webhook_deliveries" SET "attempts"=attempts \+ 1,"last_attempt_at"=\$1,"last_error"=\$2,` +
+				`"last_status_code"=\$3,"status"=\$4 WHERE id = \$5 AND status = \$6 RETURNING \*`,
+			args: []driver.Value{at, "timeout", nil, DeliveryDead, 3, DeliveryPending},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newMockStore(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(test.query).WithArgs(test.args...).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "attempts"}).AddRow(3, 1))
+			mock.ExpectCommit()
+
+			delivery, err := store.RecordDeliveryAttempt(context.Background(), 3, test.attempt)
+
+			require.NoError(t, err)
+			assert.Equal(t, 1, delivery.Attempts)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRedeliverDelivery_pending(t *testing.T) {
+	store, mock := newMockStore(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "webhook_deliveries" WHERE webhook_id = \$1 AND "webhook_deliveries"\."id" = \$2 `+
+		`ORDER BY "webhook_deliveries"\."id" LIMIT 1 FOR UPDATE`).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "status"}).AddRow(3, 1, "pending"))
+	mock.ExpectRollback()
+
+	_, err := store.RedeliverDelivery(context.Background(), 1, 3)
+
+	assert.ErrorIs(t, err, ErrDeliveryPending)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
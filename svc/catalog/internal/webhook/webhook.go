@@ -0,0 +1,244 @@
+// Package webhook delivers catalog events to partners subscribed with webhooks
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-Catalog-Event"
+	HeaderDelivery  = "X-Catalog-Delivery"
+	HeaderTimestamp = "X-Catalog-Timestamp"
+	// HeaderSignature holds HMAC-SHA256 of timestamp and payload, see Sign
+	HeaderSignature = "X-Catalog-Signature"
+
+	signaturePrefix = "sha256="
+	// maxResponseBody is how much of the response is read, so the connection can be reused
+	maxResponseBody = 64 << 10
+)
+
+// ErrPrivateAddress is returned when webhook host resolves to an address deliveries are not allowed to reach
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// Sign returns signature of the payload sent at timestamp (in unix seconds). It is a hex encoded HMAC-SHA256 with
+// secret of "<timestamp>.<payload>", prefixed with "sha256=".
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports if signature of the payload sent at timestamp was made with secret
+func Verify(secret string, timestamp int64, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}
+
+// deliveryCreator is implemented by stores which schedule deliveries of events to webhooks
+type deliveryCreator interface {
+	CreateDeliveries(ctx context.Context, event store.Event, payload string) (int64, error)
+}
+
+// Dispatcher is a publisher scheduling delivery of every event to webhooks subscribed to it
+type Dispatcher struct {
+	store deliveryCreator
+}
+
+// NewDispatcher creates Dispatcher scheduling deliveries in s
+func NewDispatcher(s deliveryCreator) *Dispatcher {
+	return &Dispatcher{store: s}
+}
+
+// Publish schedules delivery of the event to subscribed webhooks
+func (d *Dispatcher) Publish(ctx context.Context, event store.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error while encoding event with id %d: %w", event.ID, err)
+	}
+	if _, err := d.store.CreateDeliveries(ctx, event, string(payload)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Store is a set of store methods the worker delivers events with
+type Store interface {
+	GetWebhook(ctx context.Context, id uint) (store.Webhook, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]store.WebhookDelivery, error)
+	RecordDeliveryAttempt(ctx context.Context, id uint, attempt store.DeliveryAttempt) (store.WebhookDelivery, error)
+}
+
+// Config controls how deliveries are attempted
+type Config struct {
+	// MaxAttempts is number of attempts after which delivery is moved to dead letters
+	MaxAttempts int
+	// Backoff is delay before the first retry, every next one is twice as long up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Timeout limits single delivery attempt
+	Timeout   time.Duration
+	BatchSize int
+}
+
+// NewClient creates HTTP client for deliveries, which refuses to connect to loopback, link-local and private
+// addresses. Addresses are checked when connecting, so they cannot be reached by hosts which resolve to them only at
+// delivery time either. Redirects are not followed, the redirect response fails the attempt instead.
+func NewClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: dialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// proxy would connect to webhooks on behalf of the client, out of reach of the check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// dialControl refuses connections to addresses which are not public
+func dialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || store.PrivateIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// Worker POSTs signed payloads of due deliveries to webhooks and retries failed ones with exponential backoff
+type Worker struct {
+	store  Store
+	client *http.Client
+	conf   Config
+	log    *logrus.Logger
+	now    func() time.Time
+}
+
+// NewWorker creates Worker attempting deliveries from s with client
+func NewWorker(s Store, client *http.Client, conf Config, log *logrus.Logger) *Worker {
+	return &Worker{store: s, client: client, conf: conf, log: log, now: time.Now}
+}
+
+// Deliver attempts due deliveries in batches until there are no more due ones
+func (w *Worker) Deliver(ctx context.Context) {
+	// claimed deliveries are attempted one by one, so lease has to outlast the whole batch
+	lease := w.conf.Timeout * time.Duration(w.conf.BatchSize+1)
+	for {
+		deliveries, err := w.store.ClaimDueDeliveries(ctx, w.now(), lease, w.conf.BatchSize)
+		if err != nil {
+			w.log.Errorf("error while claiming webhook deliveries: %s", err)
+			return
+		}
+		webhooks := map[uint]store.Webhook{}
+		for _, delivery := range deliveries {
+			if ctx.Err() != nil {
+				// unattempted deliveries are claimed again once the lease ends
+				return
+			}
+			webhook, ok := webhooks[delivery.WebhookID]
+			if !ok {
+				if webhook, err = w.store.GetWebhook(ctx, delivery.WebhookID); err != nil {
+					if !errors.Is(err, gorm.ErrRecordNotFound) {
+						w.log.Errorf("error while getting webhook: %s", err)
+					}
+					continue
+				}
+				webhooks[webhook.ID] = webhook
+			}
+			w.deliver(ctx, webhook, delivery)
+		}
+		if len(deliveries) < w.conf.BatchSize {
+			return
+		}
+	}
+}
+
+// deliver attempts delivery to the webhook and records its outcome
+func (w *Worker) deliver(ctx context.Context, webhook store.Webhook, delivery store.WebhookDelivery) {
+	attempt := w.attempt(ctx, webhook, delivery)
+	if ctx.Err() != nil {
+		// attempt was interrupted by shutdown, not by the webhook
+		return
+	}
+	if !attempt.Delivered && delivery.Attempts+1 < w.conf.MaxAttempts {
+		retryAt := attempt.At.Add(w.backoff(delivery.Attempts + 1))
+		attempt.RetryAt = &retryAt
+	}
+	recorded, err := w.store.RecordDeliveryAttempt(ctx, delivery.ID, attempt)
+	if err != nil {
+		w.log.Errorf("error while recording webhook delivery attempt: %s", err)
+		return
+	}
+	if recorded.Status == store.DeliveryDead {
+		w.log.Warnf("delivery %d of event %d to webhook %d moved to dead letters after %d attempts: %s",
+			recorded.ID, recorded.EventID, recorded.WebhookID, recorded.Attempts, *attempt.Error)
+	}
+}
+
+// attempt POSTs signed payload of the delivery to the webhook. Only 2xx responses count as delivered.
+func (w *Worker) attempt(ctx context.Context, webhook store.Webhook, delivery store.WebhookDelivery) store.DeliveryAttempt {
+	ctx, cancel := context.WithTimeout(ctx, w.conf.Timeout)
+	defer cancel()
+	attempt := store.DeliveryAttempt{At: w.now()}
+	fail := func(err error) store.DeliveryAttempt {
+		msg := err.Error()
+		attempt.Error = &msg
+		return attempt
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return fail(err)
+	}
+	timestamp := attempt.At.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, []byte(delivery.Payload)))
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fail(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+	attempt.StatusCode = &resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fail(fmt.Errorf("unexpected response status %d", resp.StatusCode))
+	}
+	attempt.Delivered = true
+	return attempt
+}
+
+// backoff returns delay before retry following provided number of failed attempts
+func (w *Worker) backoff(failed int) time.Duration {
+	delay := w.conf.Backoff
+	for i := 1; i < failed && delay < w.conf.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > w.conf.MaxBackoff {
+		return w.conf.MaxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,223 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/konrad945/eCommerce/svc/catalog/internal/store"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const secret = "0123456789abcdef"
+
+// receiver is a local webhook endpoint which verifies signatures and responds with queued statuses
+type receiver struct {
+	t        *testing.T
+	mu       sync.Mutex
+	statuses []int
+	received []store.Event
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	require.NoError(r.t, err)
+	timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(r.t, err)
+	assert.True(r.t, Verify(secret, timestamp, body, req.Header.Get(HeaderSignature)), "signature is valid")
+	assert.NotEmpty(r.t, req.Header.Get(HeaderDelivery))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := http.StatusNoContent
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	if status < 300 {
+		var event store.Event
+		require.NoError(r.t, json.Unmarshal(body, &event))
+		assert.Equal(r.t, string(event.Type), req.Header.Get(HeaderEvent))
+		r.received = append(r.received, event)
+	}
+	w.WriteHeader(status)
+}
+
+func TestSign(t *testing.T) {
+	signature := Sign(secret, 1654084800, []byte(`{"id":1}`))
+
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.True(t, Verify(secret, 1654084800, []byte(`{"id":1}`), signature))
+	assert.False(t, Verify(secret, 1654084801, []byte(`{"id":1}`), signature), "timestamp is signed")
+	assert.False(t, Verify(secret, 1654084800, []byte(`{"id":2}`), signature), "payload is signed")
+	assert.False(t, Verify("fedcba9876543210", 1654084800, []byte(`{"id":1}`), signature), "secret is used")
+}
+
+func TestBackoff(t *testing.T) {
+	w := NewWorker(nil, nil, Config{Backoff: time.Second, MaxBackoff: 10 * time.Second}, logrus.New())
+	tests := []struct {
+		failed   int
+		expected time.Duration
+	}{
+		{failed: 1, expected: time.Second},
+		{failed: 2, expected: 2 * time.Second},
+		{failed: 4, expected: 8 * time.Second},
+		{failed: 5, expected: 10 * time.Second},
+		{failed: 100, expected: 10 * time.Second},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, w.backoff(test.failed), "after %d failed attempts", test.failed)
+	}
+}
+
+// localClient returns client connecting to local ports for any host, as webhooks cannot point at local addresses
+func localClient() *http.Client {
+	dialer := &net.Dialer{}
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			_, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, err
+			}
+			return dialer.DialContext(ctx, network, net.JoinHostPort("127.0.0.1", port))
+		},
+	}}
+}
+
+func TestNewClient(t *testing.T) {
+	server := httptest.NewServer(http.RedirectHandler("http://169.254.169.254/latest", http.StatusFound))
+	defer server.Close()
+
+	t.Run("Private address is refused", func(t *testing.T) {
+		_, err := NewClient().Get(server.URL)
+
+		assert.ErrorIs(t, err, ErrPrivateAddress)
+	})
+
+	t.Run("Redirect is not followed", func(t *testing.T) {
+		client := NewClient()
+		client.Transport = localClient().Transport
+
+		resp, err := client.Get(server.URL)
+
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+	})
+}
+
+func TestDialControl(t *testing.T) {
+	for address, allowed := range map[string]bool{
+		"93.184.216.34:443":         true,
+		"[2606:4700::1111]:443":     true,
+		"127.0.0.1:80":              false,
+		"10.0.0.1:80":               false,
+		"169.254.169.254:80":        false,
+		"[::1]:80":                  false,
+		"[::ffff:192.168.0.1]:8080": false,
+	} {
+		err := dialControl("tcp", address, nil)
+
+		if allowed {
+			assert.NoError(t, err, address)
+		} else {
+			assert.ErrorIs(t, err, ErrPrivateAddress, address)
+		}
+	}
+}
+
+func TestWorker(t *testing.T) {
+	ctx := context.Background()
+	recv := &receiver{t: t}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+	s := store.NewMemoryStore()
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	webhook, err := s.CreateWebhook(ctx, store.Webhook{
+		URL: "http://hooks.example.com:" + port, Secret: secret, EventTypes: store.EventTypes{store.ItemCreated},
+	})
+	require.NoError(t, err)
+	// deliveries are scheduled by the store at current time, so they are due for the worker
+	now := time.Now().Add(time.Second)
+	w := NewWorker(s, localClient(), Config{
+		MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: time.Hour, Timeout: time.Second, BatchSize: 10,
+	}, logrus.New())
+	w.now = func() time.Time { return now }
+	dispatcher := NewDispatcher(s)
+	deliveries := func() []store.WebhookDelivery {
+		deliveries, err := s.GetDeliveries(ctx, store.DeliveryQuery{WebhookID: &webhook.ID, PageSize: 10, Page: 1})
+		require.NoError(t, err)
+		return deliveries
+	}
+
+	t.Run("Event is delivered with signature", func(t *testing.T) {
+		name := "Shirt"
+		event := store.Event{ID: 1, Type: store.ItemCreated, ItemID: 1, Version: 1, Item: store.ItemSnapshot{Name: &name}}
+		require.NoError(t, dispatcher.Publish(ctx, event))
+		require.NoError(t, dispatcher.Publish(ctx, store.Event{ID: 2, Type: store.ItemDeleted, ItemID: 1}))
+
+		w.Deliver(ctx)
+
+		require.Len(t, recv.received, 1)
+		assert.Equal(t, event.ID, recv.received[0].ID)
+		assert.Equal(t, "Shirt", *recv.received[0].Item.Name)
+		delivered := deliveries()
+		require.Len(t, delivered, 1, "only subscribed events are delivered")
+		assert.Equal(t, store.DeliveryDelivered, delivered[0].Status)
+		assert.Equal(t, http.StatusNoContent, *delivered[0].LastStatusCode)
+	})
+
+	t.Run("Failed delivery is retried with backoff until dead", func(t *testing.T) {
+		recv.statuses = []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable}
+		require.NoError(t, dispatcher.Publish(ctx, store.Event{ID: 3, Type: store.ItemCreated, ItemID: 2}))
+
+		w.Deliver(ctx)
+		failed := deliveries()[0]
+		assert.Equal(t, store.DeliveryPending, failed.Status)
+		assert.Equal(t, 1, failed.Attempts)
+		assert.Equal(t, now.Add(time.Minute), failed.NextAttemptAt)
+		w.Deliver(ctx)
+		assert.Equal(t, 1, deliveries()[0].Attempts, "retry is not attempted before backoff")
+
+		now = now.Add(time.Minute)
+		w.Deliver(ctx)
+		assert.Equal(t, now.Add(2*time.Minute), deliveries()[0].NextAttemptAt)
+		now = now.Add(2 * time.Minute)
+		w.Deliver(ctx)
+		dead := deliveries()[0]
+		assert.Equal(t, store.DeliveryDead, dead.Status)
+		assert.Equal(t, 3, dead.Attempts)
+		assert.Equal(t, "unexpected response status 503", *dead.LastError)
+
+		_, err := s.RedeliverDelivery(ctx, webhook.ID, dead.ID)
+		require.NoError(t, err)
+		w.Deliver(ctx)
+		assert.Equal(t, store.DeliveryDelivered, deliveries()[0].Status)
+		assert.Len(t, recv.received, 2)
+	})
+
+	t.Run("Unreachable webhook is retried", func(t *testing.T) {
+		unreachable, err := s.CreateWebhook(ctx, store.Webhook{
+			URL: "http://hooks.example.com:1", Secret: secret, EventTypes: store.EventTypes{store.ItemCreated},
+		})
+		require.NoError(t, err)
+		require.NoError(t, dispatcher.Publish(ctx, store.Event{ID: 4, Type: store.ItemCreated, ItemID: 3}))
+
+		w.Deliver(ctx)
+
+		failed, err := s.GetDeliveries(ctx, store.DeliveryQuery{WebhookID: &unreachable.ID, PageSize: 10, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, failed, 1)
+		assert.Equal(t, store.DeliveryPending, failed[0].Status)
+		assert.Nil(t, failed[0].LastStatusCode)
+		assert.NotNil(t, failed[0].LastError)
+	})
+}